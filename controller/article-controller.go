@@ -20,19 +20,24 @@ type ArticleController interface {
 	UpdateArticle(c *gin.Context)
 	DeleteArticle(c *gin.Context)
 	SearchArticles(c *gin.Context)
+	RestoreArticle(c *gin.Context)
 }
 
 // articleController is the implementation of ArticleController interface
 type articleController struct {
-	articleService service.ArticleService
-	jwtService     service.JWTService
+	articleService  service.ArticleService
+	searchAnalytics service.SearchAnalyticsService
+	jwtService      service.JWTService
+	authService     service.AuthService
 }
 
 // NewArticleController creates a new instance of ArticleController
-func NewArticleController(articleService service.ArticleService, jwtService service.JWTService) ArticleController {
+func NewArticleController(articleService service.ArticleService, searchAnalytics service.SearchAnalyticsService, jwtService service.JWTService, authService service.AuthService) ArticleController {
 	return &articleController{
-		articleService: articleService,
-		jwtService:     jwtService,
+		articleService:  articleService,
+		searchAnalytics: searchAnalytics,
+		jwtService:      jwtService,
+		authService:     authService,
 	}
 }
 
@@ -43,6 +48,32 @@ func (c *articleController) GetAllArticles(ctx *gin.Context) {
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
 	search := ctx.Query("search")
 	
+	// Mode cursor: ?cursor= (halaman pertama) atau ?cursor=<next_cursor|prev_cursor>
+	cursor, cursorMode, err := cursorFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Invalid cursor", err.Error(), nil))
+		return
+	}
+	if cursorMode {
+		articles, pagination, err := c.articleService.GetAllArticlesCursor(cursor, limit, search)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Failed to fetch articles", err.Error(), nil))
+			return
+		}
+		data := map[string]interface{}{
+			"articles":   articles,
+			"pagination": pagination,
+		}
+		if cursor == nil {
+			// Cursor mode has no total, so the first page size is logged
+			if searchID := logSearch(c.searchAnalytics, c.jwtService, c.authService, ctx, entity.SearchSourceArticle, 1, search, int64(len(articles))); searchID != 0 {
+				data["search_id"] = searchID
+			}
+		}
+		ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Articles fetched successfully", data))
+		return
+	}
+	
 	// Get articles from service
 	articles, pagination, err := c.articleService.GetAllArticles(page, limit, search)
 	if err != nil {
@@ -56,6 +87,9 @@ func (c *articleController) GetAllArticles(ctx *gin.Context) {
 		"articles":   articles,
 		"pagination": pagination,
 	}
+	if searchID := logSearch(c.searchAnalytics, c.jwtService, c.authService, ctx, entity.SearchSourceArticle, page, search, pagination.TotalItems); searchID != 0 {
+		data["search_id"] = searchID
+	}
 	response := helper.BuildResponse(true, "Articles fetched successfully", data)
 	ctx.JSON(http.StatusOK, response)
 }
@@ -264,6 +298,11 @@ func (c *articleController) UpdateArticle(ctx *gin.Context) {
 
 // DeleteArticle handles request to delete an article
 func (c *articleController) DeleteArticle(ctx *gin.Context) {
+	// Articles have no owner, so only admins may move them to the trash
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Only admins can delete articles"); !ok {
+		return
+	}
+
 	// Parse article ID
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -311,6 +350,37 @@ func (c *articleController) SearchArticles(ctx *gin.Context) {
 		"articles":   articles,
 		"pagination": pagination,
 	}
+	if searchID := logSearch(c.searchAnalytics, c.jwtService, c.authService, ctx, entity.SearchSourceArticle, pagination.Page, query, pagination.TotalItems); searchID != 0 {
+		data["search_id"] = searchID
+	}
 	response := helper.BuildResponse(true, "Articles searched successfully", data)
 	ctx.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// RestoreArticle handles request to restore an article from the trash
+func (c *articleController) RestoreArticle(ctx *gin.Context) {
+	// Same rule as DeleteArticle: only admins manage the article trash
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Only admins can restore articles"); !ok {
+		return
+	}
+
+	// Parse article ID
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.BuildErrorResponse("Invalid ID format", err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Restore article through service
+	article, err := c.articleService.RestoreArticle(id)
+	if err != nil {
+		response := helper.BuildErrorResponse("Article not found in trash", err.Error(), nil)
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return response
+	response := helper.BuildResponse(true, "Article restored successfully", article)
+	ctx.JSON(http.StatusOK, response)
+}
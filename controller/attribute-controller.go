@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"batik/helper"
+	"batik/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeController interface {
+	GetAttributes(ctx *gin.Context)
+}
+
+type attributeController struct {
+	attributeService service.AttributeService
+}
+
+func NewAttributeController(attributeService service.AttributeService) AttributeController {
+	return &attributeController{
+		attributeService: attributeService,
+	}
+}
+
+// GetAttributes menampilkan definisi atribut produk beserta pilihan nilainya.
+// Query category_id (opsional) mengembalikan skema atribut kategori tersebut:
+// atribut yang berlaku beserta required, pilihan yang diizinkan, dan min/max.
+func (c *attributeController) GetAttributes(ctx *gin.Context) {
+	var (
+		attributes interface{}
+		err        error
+	)
+
+	if categoryIDStr := ctx.Query("category_id"); categoryIDStr != "" {
+		categoryID, convErr := strconv.Atoi(categoryIDStr)
+		if convErr != nil {
+			ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "Category ID tidak valid", nil))
+			return
+		}
+		attributes, err = c.attributeService.GetCategorySchema(categoryID)
+	} else {
+		attributes, err = c.attributeService.GetAttributes()
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal menampilkan atribut", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Berhasil menampilkan atribut", attributes))
+}
@@ -5,6 +5,7 @@ import (
 	"batik/entity"
 	"batik/helper"
 	"batik/service"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -18,13 +19,29 @@ type AuthController interface {
 type authController struct {
 	authService service.AuthService
 	jwtService  service.JWTService
+	viewService service.ProductViewService
+	cartService service.CartService
 }
 
 // New Auth Controller
-func NewAuthController(authService service.AuthService, jwtService service.JWTService) AuthController {
+func NewAuthController(authService service.AuthService, jwtService service.JWTService, viewService service.ProductViewService, cartService service.CartService) AuthController {
 	return &authController{
 		authService: authService,
 		jwtService:  jwtService,
+		viewService: viewService,
+		cartService: cartService,
+	}
+}
+
+// mergeVisitorHistory memindahkan riwayat kunjungan dan keranjang pengunjung
+// anonim di browser ini ke akun user. Kegagalan hanya dicatat ke log agar
+// login tetap berhasil.
+func (c *authController) mergeVisitorHistory(ctx *gin.Context, userID uint64) {
+	if err := c.viewService.MergeVisitor(visitorID(ctx), userID); err != nil {
+		log.Printf("❌ Gagal memindahkan riwayat kunjungan pengunjung ke user %d: %v", userID, err)
+	}
+	if err := c.cartService.MergeVisitor(visitorID(ctx), userID); err != nil {
+		log.Printf("❌ Gagal memindahkan keranjang pengunjung ke user %d: %v", userID, err)
 	}
 }
 
@@ -39,6 +56,7 @@ func (c *authController) Login(ctx *gin.Context) {
 	if v, ok := authResult.(entity.User); ok {
 		generatedToken := c.jwtService.GenerateToken(v.Email)
 		v.Token = generatedToken
+		c.mergeVisitorHistory(ctx, v.ID)
 		response := helper.BuildResponseLogin(true, "OK", v, v.Token)
 		ctx.JSON(http.StatusOK, response)
 		return
@@ -63,6 +81,7 @@ func (c *authController) Register(ctx *gin.Context) {
 		createdUser := c.authService.CreateUser(registerDTO)
 		token := c.jwtService.GenerateToken(createdUser.Email)
 		createdUser.Token = token
+		c.mergeVisitorHistory(ctx, createdUser.ID)
 		response := helper.BuildResponse(true, "OK!", createdUser)
 		ctx.JSON(http.StatusCreated, response)
 	}
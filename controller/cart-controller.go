@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartController melayani keranjang user login maupun pengunjung anonim.
+// Pemilik keranjang ditentukan sama seperti riwayat kunjungan (viewerKey).
+type CartController interface {
+	GetCart(ctx *gin.Context)
+	AddItem(ctx *gin.Context)
+	UpdateItem(ctx *gin.Context)
+	RemoveItem(ctx *gin.Context)
+	ClearCart(ctx *gin.Context)
+}
+
+type cartController struct {
+	cartService service.CartService
+	jwtService  service.JWTService
+	authService service.AuthService
+}
+
+func NewCartController(cartService service.CartService, jwtService service.JWTService, authService service.AuthService) CartController {
+	return &cartController{
+		cartService: cartService,
+		jwtService:  jwtService,
+		authService: authService,
+	}
+}
+
+// cartErrorStatus memetakan error keranjang ke HTTP status
+func cartErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCartItemNotFound), errors.Is(err, service.ErrProductNotAvailable):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func cartItemIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID item keranjang tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetCart menampilkan keranjang beserta validasi harga dan stok terkini
+func (c *cartController) GetCart(ctx *gin.Context) {
+	ownerKey, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+
+	cart, err := c.cartService.GetCart(ownerKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil keranjang", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Keranjang berhasil diambil", cart))
+}
+
+func (c *cartController) AddItem(ctx *gin.Context) {
+	var addDTO dto.AddCartItemDTO
+	if err := ctx.ShouldBindJSON(&addDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data keranjang tidak valid", err.Error(), nil))
+		return
+	}
+
+	ownerKey, userID := viewerKey(c.jwtService, c.authService, ctx, true)
+	cart, err := c.cartService.AddItem(ownerKey, userID, addDTO)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), helper.BuildErrorResponse("Gagal menambahkan ke keranjang", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk ditambahkan ke keranjang", cart))
+}
+
+func (c *cartController) UpdateItem(ctx *gin.Context) {
+	id, ok := cartItemIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var updateDTO dto.UpdateCartItemDTO
+	if err := ctx.ShouldBindJSON(&updateDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data keranjang tidak valid", err.Error(), nil))
+		return
+	}
+
+	ownerKey, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	cart, err := c.cartService.UpdateItem(ownerKey, id, updateDTO)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah keranjang", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Keranjang berhasil diubah", cart))
+}
+
+func (c *cartController) RemoveItem(ctx *gin.Context) {
+	id, ok := cartItemIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	ownerKey, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	cart, err := c.cartService.RemoveItem(ownerKey, id)
+	if err != nil {
+		ctx.JSON(cartErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus item keranjang", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Item keranjang berhasil dihapus", cart))
+}
+
+func (c *cartController) ClearCart(ctx *gin.Context) {
+	ownerKey, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	if err := c.cartService.Clear(ownerKey); err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengosongkan keranjang", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Keranjang berhasil dikosongkan", nil))
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"batik/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cursorFromQuery mengaktifkan mode cursor jika query "cursor" dikirim. Nilai
+// kosong (?cursor=) berarti halaman pertama; selain itu harus token next_cursor
+// atau prev_cursor dari response sebelumnya.
+func cursorFromQuery(ctx *gin.Context) (cursor *utils.Cursor, enabled bool, err error) {
+	token, enabled := ctx.GetQuery("cursor")
+	if !enabled || token == "" {
+		return nil, enabled, nil
+	}
+
+	cursor, err = utils.DecodeCursor(token)
+	return cursor, true, err
+}
@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteController interface {
+	GetFavoriteStatus(ctx *gin.Context)
+	Favorite(ctx *gin.Context)
+	Unfavorite(ctx *gin.Context)
+	GetMyFavorites(ctx *gin.Context)
+	GetStoreFavoriteStats(ctx *gin.Context)
+	GetMyWishlists(ctx *gin.Context)
+	CreateWishlist(ctx *gin.Context)
+	GetWishlist(ctx *gin.Context)
+	UpdateWishlist(ctx *gin.Context)
+	DeleteWishlist(ctx *gin.Context)
+	AddWishlistItem(ctx *gin.Context)
+	RemoveWishlistItem(ctx *gin.Context)
+	GetSharedWishlist(ctx *gin.Context)
+}
+
+type favoriteController struct {
+	favoriteService service.FavoriteService
+	wishlistService service.WishlistService
+	storeService    service.StoreService
+	jwtService      service.JWTService
+	authService     service.AuthService
+}
+
+func NewFavoriteController(favoriteService service.FavoriteService, wishlistService service.WishlistService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) FavoriteController {
+	return &favoriteController{
+		favoriteService: favoriteService,
+		wishlistService: wishlistService,
+		storeService:    storeService,
+		jwtService:      jwtService,
+		authService:     authService,
+	}
+}
+
+// favoriteErrorStatus memetakan error favorit dan wishlist ke HTTP status
+func favoriteErrorStatus(err error) int {
+	if errors.Is(err, service.ErrProductNotAvailable) || errors.Is(err, service.ErrWishlistNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func wishlistIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID wishlist tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetFavoriteStatus memberi tahu apakah produk sudah difavoritkan user
+func (c *favoriteController) GetFavoriteStatus(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	favorited, err := c.favoriteService.IsFavorite(user.ID, ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil status favorit", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Status favorit berhasil diambil", map[string]interface{}{"favorited": favorited}))
+}
+
+func (c *favoriteController) Favorite(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	if err := c.favoriteService.Favorite(user.ID, ctx.Param("slug")); err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal menambah favorit", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk ditambahkan ke favorit", map[string]interface{}{"favorited": true}))
+}
+
+func (c *favoriteController) Unfavorite(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	if err := c.favoriteService.Unfavorite(user.ID, ctx.Param("slug")); err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus favorit", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk dihapus dari favorit", map[string]interface{}{"favorited": false}))
+}
+
+// GetMyFavorites menampilkan produk favorit user dalam bentuk card publik
+func (c *favoriteController) GetMyFavorites(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	products, pagination, err := c.favoriteService.GetFavorites(user.ID, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil favorit", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Favorit berhasil diambil", map[string]interface{}{
+		"products":   products,
+		"pagination": pagination,
+	}))
+}
+
+// GetStoreFavoriteStats menampilkan jumlah favorit dan wishlist per produk toko
+func (c *favoriteController) GetStoreFavoriteStats(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	stats, err := c.favoriteService.GetStoreStats(storeID, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil statistik favorit", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Statistik favorit berhasil diambil", stats))
+}
+
+func (c *favoriteController) GetMyWishlists(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	wishlists, err := c.wishlistService.GetMyWishlists(user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Wishlist berhasil diambil", wishlists))
+}
+
+func (c *favoriteController) CreateWishlist(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	var wishlistDTO dto.CreateWishlistDTO
+	if err := ctx.ShouldBindJSON(&wishlistDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data wishlist tidak valid", err.Error(), nil))
+		return
+	}
+
+	wishlist, err := c.wishlistService.CreateWishlist(user.ID, wishlistDTO)
+	if err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal membuat wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Wishlist berhasil dibuat", wishlist))
+}
+
+// GetWishlist menampilkan wishlist milik user beserta produknya
+func (c *favoriteController) GetWishlist(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := wishlistIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	wishlist, products, pagination, err := c.wishlistService.GetWishlistProducts(id, user.ID, page, limit)
+	if err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Wishlist berhasil diambil", map[string]interface{}{
+		"wishlist":   wishlist,
+		"products":   products,
+		"pagination": pagination,
+	}))
+}
+
+func (c *favoriteController) UpdateWishlist(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := wishlistIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var wishlistDTO dto.UpdateWishlistDTO
+	if err := ctx.ShouldBindJSON(&wishlistDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data wishlist tidak valid", err.Error(), nil))
+		return
+	}
+
+	wishlist, err := c.wishlistService.UpdateWishlist(id, user.ID, wishlistDTO)
+	if err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal mengupdate wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Wishlist berhasil diupdate", wishlist))
+}
+
+func (c *favoriteController) DeleteWishlist(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := wishlistIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.wishlistService.DeleteWishlist(id, user.ID); err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Wishlist berhasil dihapus", nil))
+}
+
+func (c *favoriteController) AddWishlistItem(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := wishlistIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var itemDTO dto.WishlistItemDTO
+	if err := ctx.ShouldBindJSON(&itemDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data produk tidak valid", err.Error(), nil))
+		return
+	}
+
+	if err := c.wishlistService.AddItem(id, user.ID, itemDTO); err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal menambah produk ke wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk ditambahkan ke wishlist", nil))
+}
+
+func (c *favoriteController) RemoveWishlistItem(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := wishlistIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(ctx.Param("product_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID produk tidak valid", nil))
+		return
+	}
+
+	if err := c.wishlistService.RemoveItem(id, user.ID, productID); err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus produk dari wishlist", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk dihapus dari wishlist", nil))
+}
+
+// GetSharedWishlist membuka wishlist yang dibagikan lewat link tanpa login
+func (c *favoriteController) GetSharedWishlist(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	wishlist, products, pagination, err := c.wishlistService.GetSharedWishlist(ctx.Param("token"), page, limit)
+	if err != nil {
+		ctx.JSON(favoriteErrorStatus(err), helper.BuildErrorResponse("Wishlist tidak dapat dibuka", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Wishlist berhasil diambil", map[string]interface{}{
+		"wishlist": map[string]interface{}{
+			"name":       wishlist.Name,
+			"item_count": wishlist.ItemCount,
+		},
+		"products":   products,
+		"pagination": pagination,
+	}))
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/helper"
+	"batik/repository"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryController interface {
+	GetInventory(ctx *gin.Context)
+	AdjustStock(ctx *gin.Context)
+	SetThreshold(ctx *gin.Context)
+	GetLowStock(ctx *gin.Context)
+}
+
+type inventoryController struct {
+	inventoryService service.InventoryService
+	productService   service.ProductService
+	storeService     service.StoreService
+	jwtService       service.JWTService
+	authService      service.AuthService
+}
+
+func NewInventoryController(inventoryService service.InventoryService, productService service.ProductService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) InventoryController {
+	return &inventoryController{
+		inventoryService: inventoryService,
+		productService:   productService,
+		storeService:     storeService,
+		jwtService:       jwtService,
+		authService:      authService,
+	}
+}
+
+// ownedProduct mengambil produk dari slug dan memastikan user adalah pemilik tokonya.
+// Jika gagal, response sudah ditulis dan ok bernilai false.
+func (c *inventoryController) ownedProduct(ctx *gin.Context) (entity.Product, entity.User, bool) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return entity.Product{}, user, false
+	}
+
+	product, err := c.productService.GetProductBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return product, user, false
+	}
+
+	store, err := c.storeService.GetStoreByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Toko tidak ditemukan", nil))
+		return product, user, false
+	}
+
+	if uint64(store.UserID) != user.ID {
+		ctx.JSON(http.StatusForbidden, helper.BuildResponse(false, "Anda tidak memiliki akses ke produk ini", nil))
+		return product, user, false
+	}
+
+	return product, user, true
+}
+
+func (c *inventoryController) GetInventory(ctx *gin.Context) {
+	product, _, ok := c.ownedProduct(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	inventories, err := c.inventoryService.GetInventory(product.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil stok", err.Error(), nil))
+		return
+	}
+
+	adjustments, pagination, err := c.inventoryService.GetAdjustments(product.ID, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil riwayat stok", err.Error(), nil))
+		return
+	}
+
+	data := map[string]interface{}{
+		"inventory":   inventories,
+		"adjustments": adjustments,
+		"pagination":  pagination,
+	}
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Stok produk berhasil diambil", data))
+}
+
+func (c *inventoryController) AdjustStock(ctx *gin.Context) {
+	product, user, ok := c.ownedProduct(ctx)
+	if !ok {
+		return
+	}
+
+	var adjustDTO dto.AdjustStockDTO
+	if err := ctx.ShouldBind(&adjustDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data stok tidak valid", err.Error(), nil))
+		return
+	}
+
+	inventory, err := c.inventoryService.AdjustStock(product.ID, adjustDTO, int(user.ID))
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			ctx.JSON(http.StatusConflict, helper.BuildErrorResponse("Stok tidak mencukupi", err.Error(), nil))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal memperbarui stok", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Stok berhasil diperbarui", inventory))
+}
+
+func (c *inventoryController) SetThreshold(ctx *gin.Context) {
+	product, _, ok := c.ownedProduct(ctx)
+	if !ok {
+		return
+	}
+
+	var thresholdDTO dto.StockThresholdDTO
+	if err := ctx.ShouldBind(&thresholdDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data ambang stok tidak valid", err.Error(), nil))
+		return
+	}
+
+	inventory, err := c.inventoryService.SetThreshold(product.ID, thresholdDTO)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal memperbarui ambang stok", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ambang stok berhasil diperbarui", inventory))
+}
+
+func (c *inventoryController) GetLowStock(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	inventories, err := c.inventoryService.GetLowStockByStore(storeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil stok menipis", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Daftar stok menipis berhasil diambil", inventories))
+}
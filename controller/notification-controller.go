@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"batik/helper"
+	"batik/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController interface {
+	GetMyNotifications(ctx *gin.Context)
+	MarkAsRead(ctx *gin.Context)
+	MarkAllAsRead(ctx *gin.Context)
+}
+
+type notificationController struct {
+	notificationService service.NotificationService
+	jwtService          service.JWTService
+	authService         service.AuthService
+}
+
+func NewNotificationController(notificationService service.NotificationService, jwtService service.JWTService, authService service.AuthService) NotificationController {
+	return &notificationController{
+		notificationService: notificationService,
+		jwtService:          jwtService,
+		authService:         authService,
+	}
+}
+
+func (c *notificationController) GetMyNotifications(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	unreadOnly := ctx.Query("unread") == "true"
+
+	notifications, pagination, err := c.notificationService.GetNotifications(int(user.ID), page, limit, unreadOnly)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil notifikasi", err.Error(), nil))
+		return
+	}
+
+	data := map[string]interface{}{
+		"notifications": notifications,
+		"pagination":    pagination,
+	}
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Notifikasi berhasil diambil", data))
+}
+
+func (c *notificationController) MarkAsRead(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID notifikasi tidak valid", nil))
+		return
+	}
+
+	if err := c.notificationService.MarkAsRead(id, int(user.ID)); err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildErrorResponse("Notifikasi tidak ditemukan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Notifikasi ditandai sudah dibaca", nil))
+}
+
+func (c *notificationController) MarkAllAsRead(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	if err := c.notificationService.MarkAllAsRead(int(user.ID)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal memperbarui notifikasi", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Semua notifikasi ditandai sudah dibaca", nil))
+}
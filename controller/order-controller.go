@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/helper"
+	"batik/repository"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderController interface {
+	Checkout(ctx *gin.Context)
+	GetMyOrders(ctx *gin.Context)
+	GetMyOrder(ctx *gin.Context)
+	UpdateMyOrderStatus(ctx *gin.Context)
+	GetStoreOrders(ctx *gin.Context)
+	GetStoreOrder(ctx *gin.Context)
+	UpdateStoreOrderStatus(ctx *gin.Context)
+	UpdateOrderStatusByAdmin(ctx *gin.Context)
+}
+
+type orderController struct {
+	orderService service.OrderService
+	storeService service.StoreService
+	jwtService   service.JWTService
+	authService  service.AuthService
+}
+
+func NewOrderController(orderService service.OrderService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) OrderController {
+	return &orderController{
+		orderService: orderService,
+		storeService: storeService,
+		jwtService:   jwtService,
+		authService:  authService,
+	}
+}
+
+// orderErrorStatus memetakan error order ke HTTP status
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrVoucherNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrOrderTransition):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrOrderStatusChanged), errors.Is(err, repository.ErrInsufficientStock),
+		errors.Is(err, service.ErrCheckoutCartHasIssues), errors.Is(err, service.ErrVoucherExhausted),
+		errors.Is(err, service.ErrVoucherUserLimit):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+func bindOrderStatus(ctx *gin.Context) (dto.UpdateOrderStatusDTO, bool) {
+	var statusDTO dto.UpdateOrderStatusDTO
+	if err := ctx.ShouldBindJSON(&statusDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data status order tidak valid", err.Error(), nil))
+		return statusDTO, false
+	}
+	return statusDTO, true
+}
+
+// Checkout membuat order dari keranjang user, satu order per toko
+func (c *orderController) Checkout(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	var checkoutDTO dto.CheckoutDTO
+	if err := ctx.ShouldBindJSON(&checkoutDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data checkout tidak valid", err.Error(), nil))
+		return
+	}
+
+	orders, err := c.orderService.Checkout(user, checkoutDTO)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Checkout gagal", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Order berhasil dibuat", orders))
+}
+
+// GetMyOrders mengambil order milik user.
+// Contoh: /api/orders?status=pending_payment&page=1&limit=20
+func (c *orderController) GetMyOrders(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	orders, pagination, err := c.orderService.GetBuyerOrders(user.ID, ctx.Query("status"), page, limit)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Order berhasil diambil", map[string]interface{}{
+		"orders":     orders,
+		"pagination": pagination,
+	}))
+}
+
+func (c *orderController) GetMyOrder(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	order, err := c.orderService.GetBuyerOrder(ctx.Param("number"), user.ID)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Order berhasil diambil", order))
+}
+
+// UpdateMyOrderStatus dipakai buyer untuk membatalkan order yang belum
+// dibayar, mengonfirmasi barang diterima, atau menyelesaikan order
+func (c *orderController) UpdateMyOrderStatus(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	statusDTO, ok := bindOrderStatus(ctx)
+	if !ok {
+		return
+	}
+
+	order, err := c.orderService.GetBuyerOrder(ctx.Param("number"), user.ID)
+	if err == nil {
+		order, err = c.orderService.UpdateStatus(order, entity.OrderActorBuyer, &user.ID, statusDTO)
+	}
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah status order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Status order berhasil diubah", order))
+}
+
+// GetStoreOrders mengambil order yang masuk ke toko.
+// Contoh: /api/my-store/1/orders?status=paid
+func (c *orderController) GetStoreOrders(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	orders, pagination, err := c.orderService.GetStoreOrders(int(store.ID), ctx.Query("status"), page, limit)
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil order toko", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Order toko berhasil diambil", map[string]interface{}{
+		"orders":     orders,
+		"pagination": pagination,
+	}))
+}
+
+func (c *orderController) GetStoreOrder(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	order, err := c.orderService.GetStoreOrder(ctx.Param("number"), int(store.ID))
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Order berhasil diambil", order))
+}
+
+// UpdateStoreOrderStatus dipakai penjual untuk memproses, mengirim, atau
+// membatalkan order
+func (c *orderController) UpdateStoreOrderStatus(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	statusDTO, ok := bindOrderStatus(ctx)
+	if !ok {
+		return
+	}
+
+	order, err := c.orderService.GetStoreOrder(ctx.Param("number"), int(store.ID))
+	if err == nil {
+		order, err = c.orderService.UpdateStatus(order, entity.OrderActorSeller, &user.ID, statusDTO)
+	}
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah status order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Status order berhasil diubah", order))
+}
+
+// UpdateOrderStatusByAdmin dipakai admin untuk membatalkan atau me-refund
+// order saat ada sengketa
+func (c *orderController) UpdateOrderStatusByAdmin(ctx *gin.Context) {
+	user, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengubah order ini")
+	if !ok {
+		return
+	}
+	statusDTO, ok := bindOrderStatus(ctx)
+	if !ok {
+		return
+	}
+
+	order, err := c.orderService.GetOrder(ctx.Param("number"))
+	if err == nil {
+		order, err = c.orderService.UpdateStatus(order, entity.OrderActorAdmin, &user.ID, statusDTO)
+	}
+	if err != nil {
+		ctx.JSON(orderErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah status order", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Status order berhasil diubah", order))
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/payment"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController interface {
+	GetMethods(ctx *gin.Context)
+	CreatePayment(ctx *gin.Context)
+	GetOrderPayments(ctx *gin.Context)
+	Webhook(ctx *gin.Context)
+	Simulate(ctx *gin.Context)
+}
+
+type paymentController struct {
+	paymentService service.PaymentService
+	jwtService     service.JWTService
+	authService    service.AuthService
+}
+
+func NewPaymentController(paymentService service.PaymentService, jwtService service.JWTService, authService service.AuthService) PaymentController {
+	return &paymentController{
+		paymentService: paymentService,
+		jwtService:     jwtService,
+		authService:    authService,
+	}
+}
+
+// paymentErrorStatus memetakan error pembayaran ke HTTP status
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payment.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrPaymentNotFound), errors.Is(err, service.ErrOrderNotFound),
+		errors.Is(err, service.ErrSimulatorDisabled):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrOrderNotPayable):
+		return http.StatusConflict
+	case errors.Is(err, payment.ErrInvalidPayload), errors.Is(err, payment.ErrUnsupportedChannel):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrPaymentUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// GetMethods mengembalikan metode dan channel pembayaran yang tersedia
+func (c *paymentController) GetMethods(ctx *gin.Context) {
+	methods, err := c.paymentService.GetMethods()
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil metode pembayaran", err.Error(), nil))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Metode pembayaran berhasil diambil", methods))
+}
+
+func (c *paymentController) CreatePayment(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	var paymentDTO dto.CreatePaymentDTO
+	if err := ctx.ShouldBindJSON(&paymentDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data pembayaran tidak valid", err.Error(), nil))
+		return
+	}
+
+	p, err := c.paymentService.CreatePayment(user, ctx.Param("number"), paymentDTO)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), helper.BuildErrorResponse("Gagal membuat tagihan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Tagihan berhasil dibuat", p))
+}
+
+func (c *paymentController) GetOrderPayments(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	payments, err := c.paymentService.GetOrderPayments(user, ctx.Param("number"))
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil tagihan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Tagihan berhasil diambil", payments))
+}
+
+// Webhook menerima notifikasi dari payment gateway. Body dibaca mentah karena
+// tanda tangan dihitung dari byte yang persis dikirim gateway. Webhook ganda
+// atau yang tidak cocok tetap dibalas 200 agar gateway berhenti mengirim ulang.
+func (c *paymentController) Webhook(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Webhook tidak valid", err.Error(), nil))
+		return
+	}
+
+	result, err := c.paymentService.HandleWebhook(ctx.Request.Header, body)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), helper.BuildErrorResponse("Webhook gagal diproses", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Webhook diproses", result))
+}
+
+// Simulate mengirim webhook tiruan untuk sebuah tagihan. Hanya untuk admin
+// dan hanya tersedia jika provider pembayaran adalah provider tiruan.
+// Contoh: POST /api/payments/1/simulate {"status": "paid"}
+func (c *paymentController) Simulate(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mensimulasikan pembayaran"); !ok {
+		return
+	}
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID tagihan tidak valid", nil))
+		return
+	}
+
+	var simulateDTO dto.SimulatePaymentDTO
+	if err := ctx.ShouldBindJSON(&simulateDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data simulasi tidak valid", err.Error(), nil))
+		return
+	}
+
+	result, err := c.paymentService.Simulate(id, simulateDTO)
+	if err != nil {
+		ctx.JSON(paymentErrorStatus(err), helper.BuildErrorResponse("Simulasi pembayaran gagal", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Simulasi pembayaran diproses", result))
+}
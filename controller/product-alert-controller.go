@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductAlertController interface {
+	GetPriceHistory(ctx *gin.Context)
+	Subscribe(ctx *gin.Context)
+	GetMyAlerts(ctx *gin.Context)
+	DeleteAlert(ctx *gin.Context)
+}
+
+type productAlertController struct {
+	alertService service.ProductAlertService
+	jwtService   service.JWTService
+	authService  service.AuthService
+}
+
+func NewProductAlertController(alertService service.ProductAlertService, jwtService service.JWTService, authService service.AuthService) ProductAlertController {
+	return &productAlertController{
+		alertService: alertService,
+		jwtService:   jwtService,
+		authService:  authService,
+	}
+}
+
+// alertErrorStatus memetakan error riwayat harga dan alert ke HTTP status
+func alertErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrProductNotAvailable), errors.Is(err, service.ErrAlertNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAlertForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// GetPriceHistory menampilkan riwayat harga produk.
+// Contoh: /api/product/batik-parang/price-history?days=90
+func (c *productAlertController) GetPriceHistory(ctx *gin.Context) {
+	days, _ := strconv.Atoi(ctx.Query("days"))
+
+	history, err := c.alertService.GetPriceHistory(ctx.Param("slug"), days)
+	if err != nil {
+		ctx.JSON(alertErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil riwayat harga", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Riwayat harga berhasil diambil", history))
+}
+
+// Subscribe berlangganan notifikasi harga turun atau stok tersedia kembali
+func (c *productAlertController) Subscribe(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	var alertDTO dto.CreateProductAlertDTO
+	if err := ctx.ShouldBindJSON(&alertDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data alert tidak valid", err.Error(), nil))
+		return
+	}
+
+	alert, err := c.alertService.Subscribe(user, ctx.Param("slug"), alertDTO)
+	if err != nil {
+		ctx.JSON(alertErrorStatus(err), helper.BuildErrorResponse("Gagal menyimpan alert", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Alert berhasil disimpan", alert))
+}
+
+func (c *productAlertController) GetMyAlerts(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	alerts, err := c.alertService.GetMyAlerts(user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil alert", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Alert berhasil diambil", alerts))
+}
+
+func (c *productAlertController) DeleteAlert(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID alert tidak valid", nil))
+		return
+	}
+
+	if err := c.alertService.DeleteAlert(id, user.ID); err != nil {
+		ctx.JSON(alertErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus alert", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Alert berhasil dihapus", nil))
+}
@@ -1,35 +1,185 @@
 package controller
 
 import (
+	"batik/dto"
 	"batik/helper"
 	"batik/service"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ProductCategoryController interface {
 	GetProductCategory(ctx *gin.Context)
+	CreateCategory(ctx *gin.Context)
+	UpdateCategory(ctx *gin.Context)
+	ReorderCategories(ctx *gin.Context)
+	DeleteCategory(ctx *gin.Context)
+	UpdateCategorySchema(ctx *gin.Context)
 }
 
 type productCategoryController struct {
-	pcService service.ProductCategoryService
+	pcService   service.ProductCategoryService
+	jwtService  service.JWTService
+	authService service.AuthService
 }
 
-func NewProductCategoryController(pcService service.ProductCategoryService) ProductCategoryController {
+func NewProductCategoryController(pcService service.ProductCategoryService, jwtService service.JWTService, authService service.AuthService) ProductCategoryController {
 	return &productCategoryController {
-		pcService: pcService,
+		pcService:   pcService,
+		jwtService:  jwtService,
+		authService: authService,
 	}
 }
 
+// GetProductCategory menampilkan kategori beserta product_count.
+// Query tree=true mengembalikan kategori bertingkat (subkategori di children).
 func (c productCategoryController) GetProductCategory(ctx *gin.Context) {
-	products, err := c.pcService.GetProductCategory()
+	tree, _ := strconv.ParseBool(ctx.Query("tree"))
+	products, err := c.pcService.GetProductCategory(tree)
 
 	if err != nil {
 		res := helper.BuildErrorResponse("Gagal menampilkan data", err.Error(), nil)
-		ctx.JSON(http.StatusNotFound, res)
+		ctx.JSON(http.StatusInternalServerError, res)
+		return
 	}
 
 	res := helper.BuildResponse(true, "Berhasil menampilkan data", products)
 	ctx.JSON(http.StatusOK, res)
-}
\ No newline at end of file
+}
+
+// CreateCategory menambah kategori (multipart form, ikon opsional di field "icon")
+func (c productCategoryController) CreateCategory(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kategori"); !ok {
+		return
+	}
+
+	var categoryDTO dto.CreateCategoryDTO
+	if err := ctx.ShouldBind(&categoryDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data kategori tidak valid", err.Error(), nil))
+		return
+	}
+
+	icon, _ := ctx.FormFile("icon")
+	category, err := c.pcService.CreateCategory(ctx, categoryDTO, icon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal menambah kategori", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Kategori berhasil ditambahkan", category))
+}
+
+// UpdateCategory mengubah nama, induk, urutan, atau ikon kategori
+func (c productCategoryController) UpdateCategory(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kategori"); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID kategori tidak valid", nil))
+		return
+	}
+
+	var categoryDTO dto.UpdateCategoryDTO
+	if err := ctx.ShouldBind(&categoryDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data kategori tidak valid", err.Error(), nil))
+		return
+	}
+
+	icon, _ := ctx.FormFile("icon")
+	category, err := c.pcService.UpdateCategory(ctx, id, categoryDTO, icon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal mengupdate kategori", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kategori berhasil diupdate", category))
+}
+
+// ReorderCategories mengatur urutan tampil kategori
+func (c productCategoryController) ReorderCategories(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kategori"); !ok {
+		return
+	}
+
+	var reorderDTO dto.ReorderCategoriesDTO
+	if err := ctx.ShouldBindJSON(&reorderDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data urutan tidak valid", err.Error(), nil))
+		return
+	}
+
+	if err := c.pcService.ReorderCategories(reorderDTO.CategoryIDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal mengurutkan kategori", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Urutan kategori berhasil disimpan", nil))
+}
+
+// DeleteCategory menghapus kategori. Query reassign_to menentukan kategori
+// tujuan produknya; tanpa itu produk dipindah ke kategori induk.
+func (c productCategoryController) DeleteCategory(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kategori"); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID kategori tidak valid", nil))
+		return
+	}
+
+	reassignTo := 0
+	if raw := ctx.Query("reassign_to"); raw != "" {
+		reassignTo, err = strconv.Atoi(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID kategori tujuan tidak valid", nil))
+			return
+		}
+	}
+
+	moved, err := c.pcService.DeleteCategory(id, reassignTo)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal menghapus kategori", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kategori berhasil dihapus", map[string]interface{}{"reassigned_products": moved}))
+}
+
+// UpdateCategorySchema mengganti skema atribut kategori: atribut yang berlaku,
+// wajib atau tidak, nilai yang diizinkan, dan batas nilai angka
+func (c productCategoryController) UpdateCategorySchema(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kategori"); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID kategori tidak valid", nil))
+		return
+	}
+
+	var schemaDTO dto.UpdateCategorySchemaDTO
+	if err := ctx.ShouldBindJSON(&schemaDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data skema atribut tidak valid", err.Error(), nil))
+		return
+	}
+
+	fields, err := c.pcService.UpdateCategorySchema(id, schemaDTO)
+	if err != nil {
+		var attrErr *service.AttributeValidationError
+		if errors.As(err, &attrErr) {
+			ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Skema atribut tidak valid", err.Error(), attrErr.Fields))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal menyimpan skema atribut", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Skema atribut kategori berhasil disimpan", fields))
+}
@@ -3,15 +3,20 @@ package controller
 
 import (
 	"batik/dto"
+	"batik/entity"
 	"batik/helper"
+	"batik/repository"
 	"batik/service"
+	"batik/utils"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
@@ -27,25 +32,33 @@ type ProductController interface {
 	AddProductImage(c *gin.Context)
 	DeleteProductImage(c *gin.Context)
 	GetAllPublicProduct(c *gin.Context)
+	SearchProducts(c *gin.Context)
 	GetLatestProduct(c *gin.Context)
 	GetDetailProduct(c *gin.Context)
 	GetAllPublicProductByCategory(c *gin.Context)
 	GetPublicProductsByStoreID(c *gin.Context) 
+	ChangeProductStatus(c *gin.Context)
+	GetProductTrash(c *gin.Context)
+	RestoreProduct(c *gin.Context)
 }
 
 type productController struct {
-	productService service.ProductService
-	storeService   service.StoreService
-	jwtService     service.JWTService
-	authService    service.AuthService
+	productService  service.ProductService
+	storeService    service.StoreService
+	viewService     service.ProductViewService
+	searchAnalytics service.SearchAnalyticsService
+	jwtService      service.JWTService
+	authService     service.AuthService
 }
 
-func NewProductController(productService service.ProductService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) ProductController {
+func NewProductController(productService service.ProductService, storeService service.StoreService, viewService service.ProductViewService, searchAnalytics service.SearchAnalyticsService, jwtService service.JWTService, authService service.AuthService) ProductController {
 	return &productController{
-		productService: productService,
-		storeService:   storeService,
-		jwtService:     jwtService,
-		authService:    authService,
+		productService:  productService,
+		storeService:    storeService,
+		viewService:     viewService,
+		searchAnalytics: searchAnalytics,
+		jwtService:      jwtService,
+		authService:     authService,
 	}
 }
 
@@ -84,9 +97,10 @@ func (ctrl *productController) GetProductsByStoreID(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "12"))
 	search := c.Query("search")
+	status := c.Query("status") // kosong = semua status
 	
 	// Ambil produk dengan pagination
-	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search)
+	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search, status)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.BuildResponse(false, "Gagal mendapatkan produk", nil))
 		return
@@ -103,6 +117,7 @@ func (ctrl *productController) GetProductsByStoreID(c *gin.Context) {
 			Harga:       product.Harga,
 			StoreID:     product.StoreID,
 			Thumbnail:   product.Thumbnail,
+			Status:      product.Status,
 			CreatedAt:   product.CreatedAt,
 		})
 	}
@@ -132,7 +147,7 @@ func (ctrl *productController) GetProductsByStoreIDPublic(c *gin.Context) {
 	search := c.Query("search")
 	
 	// Ambil produk dengan pagination
-	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search)
+	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search, entity.ProductStatusPublished)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.BuildResponse(false, "Gagal mendapatkan produk", nil))
 		return
@@ -258,10 +273,17 @@ func (ctrl *productController) CreateProduct(c *gin.Context) {
 	// Panggil service untuk membuat produk
 	product, err := ctrl.productService.CreateProduct(c, productDTO, files)
 	if err != nil {
+		var attrErr *service.AttributeValidationError
+		if errors.As(err, &attrErr) {
+			c.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Atribut produk tidak valid", err.Error(), attrErr.Fields))
+			return
+		}
 		c.JSON(http.StatusBadRequest, helper.BuildResponse(false, err.Error(), nil))
 		return
 	}
 	
+	attributes, _ := ctrl.productService.GetProductAttributes(product.ID)
+	
 	// Konversi entity ke DTO response
 	var images []dto.ProductImageDTO
 	for _, img := range product.Images {
@@ -280,6 +302,13 @@ func (ctrl *productController) CreateProduct(c *gin.Context) {
 		StoreID:     product.StoreID,
 		CategoryID:  product.CategoryID,
 		Thumbnail:   product.Thumbnail,
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		Attributes:  attributes,
+		Weight:      product.Weight,
+		Length:      product.Length,
+		Width:       product.Width,
+		Height:      product.Height,
 		Images:      images,
 		CreatedAt:   product.CreatedAt,
 		UpdatedAt:   product.UpdatedAt,
@@ -299,6 +328,8 @@ func (ctrl *productController) GetProductBySlug(c *gin.Context) {
 		return
 	}
 	
+	attributes, _ := ctrl.productService.GetProductAttributes(product.ID)
+	
 	// Konversi entity ke DTO response
 	var images []dto.ProductImageDTO
 	for _, img := range product.Images {
@@ -317,6 +348,13 @@ func (ctrl *productController) GetProductBySlug(c *gin.Context) {
 		StoreID:     product.StoreID,
 		CategoryID:  product.CategoryID,
 		Thumbnail:   product.Thumbnail,
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		Attributes:  attributes,
+		Weight:      product.Weight,
+		Length:      product.Length,
+		Width:       product.Width,
+		Height:      product.Height,
 		Images:      images,
 		CreatedAt:   product.CreatedAt,
 		UpdatedAt:   product.UpdatedAt,
@@ -364,6 +402,9 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 	description := strings.TrimSpace(c.PostForm("description"))
 	hargaStr := strings.TrimSpace(c.PostForm("harga"))
 	categoryIDStr := strings.TrimSpace(c.PostForm("category_id"))
+	status := strings.TrimSpace(c.PostForm("status"))
+	publishAtStr := strings.TrimSpace(c.PostForm("publish_at"))
+	attributesJSON := strings.TrimSpace(c.PostForm("attributes"))
 	
 	log.Printf("📥 UPDATE - Received form data:")
 	log.Printf("  name: '%s'", name)
@@ -406,6 +447,40 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 		}
 	}
 	
+	// Berat (gram) dan dimensi (cm) opsional; kosong berarti tidak diubah
+	dimensions := map[string]int{}
+	for field, max := range map[string]int{"weight": 50000, "length": 300, "width": 300, "height": 300} {
+		raw := strings.TrimSpace(c.PostForm(field))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 || value > max {
+			c.JSON(http.StatusBadRequest, helper.BuildResponse(false, fmt.Sprintf("%s harus berupa angka 1 sampai %d", field, max), nil))
+			return
+		}
+		dimensions[field] = value
+	}
+	
+	// Status opsional; publish_at wajib untuk status scheduled (format RFC3339)
+	var publishAt *time.Time
+	if status != "" {
+		switch status {
+		case entity.ProductStatusDraft, entity.ProductStatusPublished, entity.ProductStatusArchived, entity.ProductStatusScheduled:
+		default:
+			c.JSON(http.StatusBadRequest, helper.BuildResponse(false, "Status produk tidak valid", nil))
+			return
+		}
+	}
+	if publishAtStr != "" {
+		parsed, err := time.Parse(time.RFC3339, publishAtStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, helper.BuildResponse(false, "Format publish_at tidak valid (gunakan RFC3339)", nil))
+			return
+		}
+		publishAt = &parsed
+	}
+	
 	// ✅ ENHANCED: Handle imagesToDelete with better error handling
 	var imagesToDelete []string
 	imagesToDeleteJSON := strings.TrimSpace(c.PostForm("imagesToDelete"))
@@ -446,6 +521,13 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 		Description: description,
 		Harga:       harga,
 		CategoryID:  categoryID,
+		Status:      status,
+		PublishAt:   publishAt,
+		Attributes:  attributesJSON,
+		Weight:      dimensions["weight"],
+		Length:      dimensions["length"],
+		Width:       dimensions["width"],
+		Height:      dimensions["height"],
 	}
 	
 	log.Printf("📝 Update DTO: %+v", updateDTO)
@@ -494,7 +576,10 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 		log.Printf("❌ Service error: %v", err)
 		
 		// ✅ Better error categorization
-		if strings.Contains(err.Error(), "not found") {
+		var attrErr *service.AttributeValidationError
+		if errors.As(err, &attrErr) {
+			c.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Atribut produk tidak valid", err.Error(), attrErr.Fields))
+		} else if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
 		} else if strings.Contains(err.Error(), "validation") {
 			c.JSON(http.StatusBadRequest, helper.BuildResponse(false, err.Error(), nil))
@@ -507,6 +592,8 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 	}
 	
 	// ✅ ENHANCED: Build response with fresh data
+	attributes, _ := ctrl.productService.GetProductAttributes(updatedProduct.ID)
+	
 	var images []dto.ProductImageDTO
 	for _, img := range updatedProduct.Images {
 		images = append(images, dto.ProductImageDTO{
@@ -524,6 +611,13 @@ func (ctrl *productController) UpdateProduct(c *gin.Context) {
 		StoreID:     updatedProduct.StoreID,
 		CategoryID:  updatedProduct.CategoryID,
 		Thumbnail:   updatedProduct.Thumbnail,
+		Status:      updatedProduct.Status,
+		PublishAt:   updatedProduct.PublishAt,
+		Attributes:  attributes,
+		Weight:      updatedProduct.Weight,
+		Length:      updatedProduct.Length,
+		Width:       updatedProduct.Width,
+		Height:      updatedProduct.Height,
 		Images:      images,
 		CreatedAt:   updatedProduct.CreatedAt,
 		UpdatedAt:   updatedProduct.UpdatedAt,
@@ -695,8 +789,39 @@ func (ctrl *productController) GetAllPublicProduct(c *gin.Context) {
 	// Contoh URL: /api/products?search=kemeja
 	search := c.Query("search")
 
-	// Teruskan 'search' ke pemanggilan service
-	products, pagination, err := ctrl.productService.GetAllPublicProduct(page, limit, search)
+	// Teruskan 'search' dan filter lain ke pemanggilan service
+	filter := productFilterFromQuery(c)
+	filter.Search = search
+
+	// Mode cursor: ?cursor= (halaman pertama) atau ?cursor=<next_cursor|prev_cursor>.
+	// Seed dan filter harus sama dengan request sebelumnya.
+	cursor, cursorMode, err := cursorFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Cursor tidak valid", err.Error(), nil))
+		return
+	}
+	if cursorMode {
+		products, pagination, err := ctrl.productService.GetAllPublicProductCursor(cursor, limit, filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal mengambil produk dengan detail", err.Error(), nil))
+			return
+		}
+		data := map[string]interface{}{
+			"products":   products,
+			"pagination": pagination,
+			"seed":       filter.Seed,
+		}
+		if cursor == nil {
+			// Mode cursor tidak menghitung total, jumlah hasil halaman pertama yang dicatat
+			if searchID := logSearch(ctrl.searchAnalytics, ctrl.jwtService, ctrl.authService, c, entity.SearchSourceProduct, 1, filter.Search, int64(len(products))); searchID != 0 {
+				data["search_id"] = searchID
+			}
+		}
+		c.JSON(http.StatusOK, helper.BuildResponse(true, "Produk dengan detail berhasil diambil", data))
+		return
+	}
+
+	products, pagination, err := ctrl.productService.GetAllPublicProduct(page, limit, filter)
 	if err != nil {
 		response := helper.BuildErrorResponse("Gagal mengambil produk dengan detail", err.Error(), nil)
 		c.JSON(http.StatusInternalServerError, response)
@@ -706,6 +831,10 @@ func (ctrl *productController) GetAllPublicProduct(c *gin.Context) {
 	data := map[string]interface{}{
 		"products":   products,
 		"pagination": pagination,
+		"seed":       filter.Seed,
+	}
+	if searchID := logSearch(ctrl.searchAnalytics, ctrl.jwtService, ctrl.authService, c, entity.SearchSourceProduct, page, filter.Search, pagination.TotalItems); searchID != 0 {
+		data["search_id"] = searchID
 	}
 
 	if len(products) == 0 {
@@ -737,8 +866,14 @@ func (ctrl *productController) GetDetailProduct(c *gin.Context) {
 	if err != nil {
 		res := helper.BuildErrorResponse("Gagal menampilkan data", err.Error(), nil)
 		c.JSON(http.StatusNotFound, res)
+		return
 	}
 
+	// Kunjungan dicatat untuk riwayat "terakhir dilihat", baik user login
+	// maupun pengunjung anonim
+	key, userID := viewerKey(ctrl.jwtService, ctrl.authService, c, true)
+	ctrl.viewService.RecordView(products, key, userID)
+
 	res := helper.BuildResponse(true, "Berhasil menampilkan data", products)
 	c.JSON(http.StatusOK, res)
 }
@@ -754,7 +889,8 @@ func (ctrl *productController) GetAllPublicProductByCategory(c *gin.Context) {
 		limit = 40 // Batas default
 	}
 
-	products, pagination, err := ctrl.productService.GetAllPublicProductByCategory(slug, page, limit)
+	filter := productFilterFromQuery(c)
+	products, pagination, err := ctrl.productService.GetAllPublicProductByCategory(slug, page, limit, filter)
 	if err != nil {
 		response := helper.BuildErrorResponse("Gagal mengambil produk dengan detail", err.Error(), nil)
 		c.JSON(http.StatusInternalServerError, response)
@@ -764,6 +900,7 @@ func (ctrl *productController) GetAllPublicProductByCategory(c *gin.Context) {
     data := map[string]interface{}{
 		"products":   products,
 		"pagination": pagination,
+		"seed":       filter.Seed,
 	}
 
 	if len(products) == 0 {
@@ -799,7 +936,7 @@ func (ctrl *productController) GetPublicProductsByStoreID(c *gin.Context) {
 	search := c.Query("search")
 	
 	// Ambil produk dengan pagination
-	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search)
+	products, pagination, err := ctrl.productService.GetAllProductByStore(storeID, page, limit, search, entity.ProductStatusPublished)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, helper.BuildResponse(false, "Gagal mendapatkan produk", nil))
 		return
@@ -828,4 +965,222 @@ func (ctrl *productController) GetPublicProductsByStoreID(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, helper.BuildResponse(true, "Daftar produk toko berhasil diambil", data))
-}
\ No newline at end of file
+}
+
+// ChangeProductStatus mengubah status produk (draft, published, archived, scheduled)
+func (ctrl *productController) ChangeProductStatus(c *gin.Context) {
+	slug := c.Param("slug")
+	
+	// Dapatkan user ID dari token
+	userID, err := ctrl.getUserFromToken(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helper.BuildResponse(false, "User tidak terautentikasi: "+err.Error(), nil))
+		return
+	}
+	
+	// Ambil produk untuk validasi kepemilikan
+	product, err := ctrl.productService.GetProductBySlug(slug)
+	if err != nil {
+		c.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return
+	}
+	
+	store, err := ctrl.storeService.GetStoreByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, helper.BuildResponse(false, "Toko tidak ditemukan", nil))
+		return
+	}
+	
+	if strconv.Itoa(store.UserID) != userID {
+		c.JSON(http.StatusForbidden, helper.BuildResponse(false, "Anda tidak memiliki akses untuk mengubah produk ini", nil))
+		return
+	}
+	
+	var statusDTO dto.ProductStatusDTO
+	if err := c.ShouldBind(&statusDTO); err != nil {
+		c.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data status tidak valid", err.Error(), nil))
+		return
+	}
+	
+	updatedProduct, err := ctrl.productService.ChangeProductStatus(slug, statusDTO)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helper.BuildResponse(false, err.Error(), nil))
+		return
+	}
+	
+	c.JSON(http.StatusOK, helper.BuildResponse(true, "Status produk berhasil diubah", dto.ProductCardResponse{
+		ID:        updatedProduct.ID,
+		Slug:      updatedProduct.Slug,
+		Name:      updatedProduct.Name,
+		Harga:     updatedProduct.Harga,
+		StoreID:   updatedProduct.StoreID,
+		Thumbnail: updatedProduct.Thumbnail,
+		Status:    updatedProduct.Status,
+		CreatedAt: updatedProduct.CreatedAt,
+	}))
+}
+
+// GetProductTrash menampilkan produk toko yang ada di trash (khusus pemilik toko)
+func (ctrl *productController) GetProductTrash(c *gin.Context) {
+	user, ok := currentUser(c, ctrl.jwtService, ctrl.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(c, ctrl.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+	
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "12"))
+	
+	products, pagination, err := ctrl.productService.GetProductTrash(storeID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helper.BuildResponse(false, "Gagal mendapatkan trash produk", nil))
+		return
+	}
+	
+	data := map[string]interface{}{
+		"products":   products,
+		"pagination": pagination,
+	}
+	
+	c.JSON(http.StatusOK, helper.BuildResponse(true, "Trash produk berhasil diambil", data))
+}
+
+// RestoreProduct mengembalikan produk dari trash
+func (ctrl *productController) RestoreProduct(c *gin.Context) {
+	slug := c.Param("slug")
+	
+	userID, err := ctrl.getUserFromToken(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helper.BuildResponse(false, "User tidak terautentikasi: "+err.Error(), nil))
+		return
+	}
+	
+	product, err := ctrl.productService.GetDeletedProductBySlug(slug)
+	if err != nil {
+		c.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan di trash", nil))
+		return
+	}
+	
+	store, err := ctrl.storeService.GetStoreByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, helper.BuildResponse(false, "Toko tidak ditemukan", nil))
+		return
+	}
+	
+	if strconv.Itoa(store.UserID) != userID {
+		c.JSON(http.StatusForbidden, helper.BuildResponse(false, "Anda tidak memiliki akses untuk mengembalikan produk ini", nil))
+		return
+	}
+	
+	restored, err := ctrl.productService.RestoreProduct(slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helper.BuildResponse(false, err.Error(), nil))
+		return
+	}
+	
+	c.JSON(http.StatusOK, helper.BuildResponse(true, "Produk berhasil dikembalikan", restored))
+}
+
+// SearchProducts adalah pencarian produk dengan filter, urutan, dan facet.
+// Contoh: /api/search/products?q=kemeja&min_price=100000&category=kemeja-pria&region=pekalongan&attr[technique]=tulis&sort=price_asc
+func (ctrl *productController) SearchProducts(c *gin.Context) {
+	page, errPage := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if errPage != nil || page < 1 {
+		page = 1
+	}
+	limit, errLimit := strconv.Atoi(c.DefaultQuery("limit", "40"))
+	if errLimit != nil || limit < 1 {
+		limit = 40
+	}
+
+	filter := productFilterFromQuery(c)
+	filter.Search = strings.TrimSpace(c.DefaultQuery("q", c.Query("search")))
+	if filter.Sort == "" {
+		// Tanpa sort eksplisit: relevansi jika ada kata kunci, selain itu terbaru
+		filter.Sort = repository.ProductSortNewest
+		if filter.Search != "" {
+			filter.Sort = repository.ProductSortRelevance
+		}
+	}
+
+	products, pagination, facets, err := ctrl.productService.SearchProducts(page, limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mencari produk", err.Error(), nil))
+		return
+	}
+
+	data := map[string]interface{}{
+		"products":   products,
+		"pagination": pagination,
+		"facets":     facets,
+		"sort":       filter.Sort,
+	}
+	if searchID := logSearch(ctrl.searchAnalytics, ctrl.jwtService, ctrl.authService, c, entity.SearchSourceProduct, page, filter.Search, pagination.TotalItems); searchID != 0 {
+		data["search_id"] = searchID
+	}
+
+	if len(products) == 0 {
+		c.JSON(http.StatusOK, helper.BuildResponse(true, "Tidak ada produk yang ditemukan", data))
+		return
+	}
+
+	c.JSON(http.StatusOK, helper.BuildResponse(true, "Produk berhasil dicari", data))
+}
+
+// productFilterFromQuery membaca filter listing publik dari query string.
+// Contoh: ?in_stock=true&min_price=50000&max_price=500000&category=kain,kemeja
+// &store_id=3&region=pekalongan,solo&attr[technique]=tulis,cap&sort=price_asc
+func productFilterFromQuery(c *gin.Context) repository.ProductFilter {
+	filter := repository.ProductFilter{
+		// in_stock=true menyembunyikan produk yang stoknya habis
+		InStockOnly:   c.Query("in_stock") == "true",
+		CategorySlugs: splitQueryValues(c.Query("category")),
+		Regions:       splitQueryValues(c.Query("region")),
+		// seed dikembalikan di response agar client bisa memakainya untuk halaman berikutnya
+		Seed: utils.ShuffleSeed(c.Query("seed")),
+	}
+
+	if minPrice, err := strconv.ParseFloat(c.Query("min_price"), 64); err == nil && minPrice > 0 {
+		filter.MinPrice = minPrice
+	}
+	if maxPrice, err := strconv.ParseFloat(c.Query("max_price"), 64); err == nil && maxPrice > 0 {
+		filter.MaxPrice = maxPrice
+	}
+	if storeID, err := strconv.Atoi(c.Query("store_id")); err == nil && storeID > 0 {
+		filter.StoreID = storeID
+	}
+
+	switch sort := c.Query("sort"); sort {
+	case repository.ProductSortNewest, repository.ProductSortPriceAsc, repository.ProductSortPriceDesc,
+		repository.ProductSortPopularity, repository.ProductSortRelevance, repository.ProductSortRating:
+		filter.Sort = sort
+	}
+
+	for code, raw := range c.QueryMap("attr") {
+		values := splitQueryValues(raw)
+		if len(values) == 0 {
+			continue
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string][]string)
+		}
+		filter.Attributes[code] = values
+	}
+
+	return filter
+}
+
+// splitQueryValues memecah nilai query "a,b,c" menjadi slice huruf kecil tanpa nilai kosong
+func splitQueryValues(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"batik/helper"
+	"batik/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductViewController interface {
+	GetRecentlyViewed(ctx *gin.Context)
+	ClearRecentlyViewed(ctx *gin.Context)
+	GetStoreViewReport(ctx *gin.Context)
+}
+
+type productViewController struct {
+	viewService  service.ProductViewService
+	storeService service.StoreService
+	jwtService   service.JWTService
+	authService  service.AuthService
+}
+
+func NewProductViewController(viewService service.ProductViewService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) ProductViewController {
+	return &productViewController{
+		viewService:  viewService,
+		storeService: storeService,
+		jwtService:   jwtService,
+		authService:  authService,
+	}
+}
+
+// GetRecentlyViewed menampilkan produk yang terakhir dilihat user login atau
+// pengunjung anonim (cookie batik_visitor).
+// Contoh: /api/me/recently-viewed?limit=20
+func (c *productViewController) GetRecentlyViewed(ctx *gin.Context) {
+	key, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	if key == "" {
+		ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Riwayat produk berhasil diambil", []interface{}{}))
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	products, err := c.viewService.GetRecentlyViewed(key, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil riwayat produk", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Riwayat produk berhasil diambil", products))
+}
+
+func (c *productViewController) ClearRecentlyViewed(ctx *gin.Context) {
+	key, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	if key != "" {
+		if err := c.viewService.ClearHistory(key); err != nil {
+			ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal menghapus riwayat produk", err.Error(), nil))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Riwayat produk berhasil dihapus", nil))
+}
+
+// GetStoreViewReport menampilkan jumlah kunjungan produk untuk pemilik toko.
+// Query: days (default 30), limit (default 20)
+func (c *productViewController) GetStoreViewReport(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	days, _ := strconv.Atoi(ctx.Query("days"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	report, err := c.viewService.GetStoreReport(storeID, days, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil laporan kunjungan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan kunjungan berhasil diambil", report))
+}
@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionController interface {
+	GetStorePromotions(ctx *gin.Context)
+	CreateStorePromotion(ctx *gin.Context)
+	UpdateStorePromotion(ctx *gin.Context)
+	DeleteStorePromotion(ctx *gin.Context)
+	GetRunningCampaigns(ctx *gin.Context)
+	GetCampaigns(ctx *gin.Context)
+	CreateCampaign(ctx *gin.Context)
+	UpdateCampaign(ctx *gin.Context)
+	DeleteCampaign(ctx *gin.Context)
+}
+
+type promotionController struct {
+	promotionService service.PromotionService
+	storeService     service.StoreService
+	jwtService       service.JWTService
+	authService      service.AuthService
+}
+
+func NewPromotionController(promotionService service.PromotionService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) PromotionController {
+	return &promotionController{
+		promotionService: promotionService,
+		storeService:     storeService,
+		jwtService:       jwtService,
+		authService:      authService,
+	}
+}
+
+// promotionErrorStatus memetakan error promosi ke HTTP status
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPromotionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPromotionForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+func promotionIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID promosi tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetStorePromotions menampilkan semua promosi toko untuk pemilik toko
+func (c *promotionController) GetStorePromotions(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	promotions, err := c.promotionService.GetStorePromotions(int(store.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil promosi", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Promosi berhasil diambil", promotions))
+}
+
+// CreateStorePromotion membuat diskon toko untuk produk tertentu, satu
+// kategori, atau seluruh toko
+func (c *promotionController) CreateStorePromotion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	var promotionDTO dto.PromotionDTO
+	if err := ctx.ShouldBindJSON(&promotionDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data promosi tidak valid", err.Error(), nil))
+		return
+	}
+
+	promotion, err := c.promotionService.CreateStorePromotion(store, user, promotionDTO)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal membuat promosi", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Promosi berhasil dibuat", promotion))
+}
+
+func (c *promotionController) UpdateStorePromotion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := promotionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var promotionDTO dto.PromotionDTO
+	if err := ctx.ShouldBindJSON(&promotionDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data promosi tidak valid", err.Error(), nil))
+		return
+	}
+
+	promotion, err := c.promotionService.UpdateStorePromotion(id, user, promotionDTO)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah promosi", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Promosi berhasil diubah", promotion))
+}
+
+func (c *promotionController) DeleteStorePromotion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := promotionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.promotionService.DeleteStorePromotion(id, user); err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus promosi", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Promosi berhasil dihapus", nil))
+}
+
+// GetRunningCampaigns menampilkan kampanye platform yang sedang berjalan,
+// misalnya Hari Batik Nasional
+func (c *promotionController) GetRunningCampaigns(ctx *gin.Context) {
+	campaigns, err := c.promotionService.GetRunningCampaigns()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil kampanye", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kampanye berhasil diambil", campaigns))
+}
+
+// GetCampaigns menampilkan semua kampanye platform untuk admin
+func (c *promotionController) GetCampaigns(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kampanye"); !ok {
+		return
+	}
+
+	campaigns, err := c.promotionService.GetCampaigns()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil kampanye", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kampanye berhasil diambil", campaigns))
+}
+
+func (c *promotionController) CreateCampaign(ctx *gin.Context) {
+	user, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kampanye")
+	if !ok {
+		return
+	}
+
+	var promotionDTO dto.PromotionDTO
+	if err := ctx.ShouldBindJSON(&promotionDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data kampanye tidak valid", err.Error(), nil))
+		return
+	}
+
+	campaign, err := c.promotionService.CreateCampaign(user, promotionDTO)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal membuat kampanye", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Kampanye berhasil dibuat", campaign))
+}
+
+func (c *promotionController) UpdateCampaign(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kampanye"); !ok {
+		return
+	}
+	id, ok := promotionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var promotionDTO dto.PromotionDTO
+	if err := ctx.ShouldBindJSON(&promotionDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data kampanye tidak valid", err.Error(), nil))
+		return
+	}
+
+	campaign, err := c.promotionService.UpdateCampaign(id, promotionDTO)
+	if err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah kampanye", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kampanye berhasil diubah", campaign))
+}
+
+func (c *promotionController) DeleteCampaign(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola kampanye"); !ok {
+		return
+	}
+	id, ok := promotionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.promotionService.DeleteCampaign(id); err != nil {
+		ctx.JSON(promotionErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus kampanye", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Kampanye berhasil dihapus", nil))
+}
@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/helper"
+	"batik/repository"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionController interface {
+	GetProductQuestions(ctx *gin.Context)
+	AskQuestion(ctx *gin.Context)
+	AnswerQuestion(ctx *gin.Context)
+	DeleteQuestion(ctx *gin.Context)
+	Upvote(ctx *gin.Context)
+	RemoveUpvote(ctx *gin.Context)
+	FlagQuestion(ctx *gin.Context)
+	GetStoreQuestions(ctx *gin.Context)
+	GetModerationQueue(ctx *gin.Context)
+	ModerateQuestion(ctx *gin.Context)
+}
+
+type questionController struct {
+	questionService service.QuestionService
+	productService  service.ProductService
+	storeService    service.StoreService
+	jwtService      service.JWTService
+	authService     service.AuthService
+}
+
+func NewQuestionController(questionService service.QuestionService, productService service.ProductService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) QuestionController {
+	return &questionController{
+		questionService: questionService,
+		productService:  productService,
+		storeService:    storeService,
+		jwtService:      jwtService,
+		authService:     authService,
+	}
+}
+
+// questionErrorStatus memetakan error pertanyaan ke HTTP status
+func questionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrQuestionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrQuestionForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrQuestionAlreadyFlagged):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// questionFilterFromQuery membaca filter daftar pertanyaan: status
+// (answered, unanswered) dan sort (top, newest)
+func questionFilterFromQuery(ctx *gin.Context) repository.QuestionFilter {
+	var filter repository.QuestionFilter
+	switch status := ctx.Query("status"); status {
+	case repository.QuestionAnswered, repository.QuestionUnanswered:
+		filter.Answered = status
+	}
+	switch sort := ctx.Query("sort"); sort {
+	case repository.QuestionSortTop, repository.QuestionSortNewest:
+		filter.Sort = sort
+	}
+	return filter
+}
+
+func questionIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID pertanyaan tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetProductQuestions menampilkan pertanyaan publik produk.
+// Contoh: /api/product/batik-parang/questions?page=1&limit=10&status=answered&sort=top
+func (c *questionController) GetProductQuestions(ctx *gin.Context) {
+	product, err := c.productService.GetProductBySlug(ctx.Param("slug"))
+	if err != nil || product.Status != entity.ProductStatusPublished {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	questions, pagination, err := c.questionService.GetProductQuestions(product.ID, questionFilterFromQuery(ctx), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pertanyaan berhasil diambil", map[string]interface{}{
+		"questions":  questions,
+		"pagination": pagination,
+	}))
+}
+
+func (c *questionController) AskQuestion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	product, err := c.productService.GetProductBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return
+	}
+
+	var questionDTO dto.CreateQuestionDTO
+	if err := ctx.ShouldBindJSON(&questionDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data pertanyaan tidak valid", err.Error(), nil))
+		return
+	}
+
+	question, err := c.questionService.AskQuestion(product, user, questionDTO)
+	if err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal menyimpan pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Pertanyaan berhasil dikirim", question))
+}
+
+// AnswerQuestion menyimpan jawaban pemilik toko untuk pertanyaan produknya
+func (c *questionController) AnswerQuestion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var answerDTO dto.AnswerQuestionDTO
+	if err := ctx.ShouldBindJSON(&answerDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data jawaban tidak valid", err.Error(), nil))
+		return
+	}
+
+	question, err := c.questionService.AnswerQuestion(id, user, answerDTO)
+	if err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal menyimpan jawaban", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Jawaban berhasil disimpan", question))
+}
+
+func (c *questionController) DeleteQuestion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.questionService.DeleteQuestion(id, user); err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pertanyaan berhasil dihapus", nil))
+}
+
+func (c *questionController) Upvote(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	question, err := c.questionService.Upvote(id, user)
+	if err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal memberi upvote", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Upvote berhasil disimpan", question))
+}
+
+func (c *questionController) RemoveUpvote(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	question, err := c.questionService.RemoveUpvote(id, user)
+	if err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus upvote", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Upvote berhasil dihapus", question))
+}
+
+// FlagQuestion melaporkan pertanyaan yang tidak pantas untuk dimoderasi admin
+func (c *questionController) FlagQuestion(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var flagDTO dto.QuestionFlagDTO
+	if err := ctx.ShouldBindJSON(&flagDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data laporan tidak valid", err.Error(), nil))
+		return
+	}
+
+	if err := c.questionService.FlagQuestion(id, user, flagDTO); err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal melaporkan pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan berhasil dikirim", nil))
+}
+
+// GetStoreQuestions menampilkan pertanyaan semua produk toko untuk pemilik
+// toko; tanpa sort, pertanyaan yang belum dijawab tampil dulu
+func (c *questionController) GetStoreQuestions(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	questions, pagination, err := c.questionService.GetStoreQuestions(storeID, questionFilterFromQuery(ctx), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pertanyaan berhasil diambil", map[string]interface{}{
+		"questions":  questions,
+		"pagination": pagination,
+	}))
+}
+
+// GetModerationQueue menampilkan pertanyaan untuk admin berdasarkan status
+// (pending default, hidden, published, flagged)
+func (c *questionController) GetModerationQueue(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat memoderasi pertanyaan"); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	questions, pagination, err := c.questionService.GetModerationQueue(ctx.Query("status"), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal mengambil pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pertanyaan berhasil diambil", map[string]interface{}{
+		"questions":  questions,
+		"pagination": pagination,
+	}))
+}
+
+// ModerateQuestion menampilkan kembali (published) atau menyembunyikan (hidden) pertanyaan
+func (c *questionController) ModerateQuestion(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat memoderasi pertanyaan"); !ok {
+		return
+	}
+	id, ok := questionIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var moderateDTO dto.ModerateQuestionDTO
+	if err := ctx.ShouldBindJSON(&moderateDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Status moderasi tidak valid", err.Error(), nil))
+		return
+	}
+
+	question, err := c.questionService.ModerateQuestion(id, moderateDTO)
+	if err != nil {
+		ctx.JSON(questionErrorStatus(err), helper.BuildErrorResponse("Gagal memoderasi pertanyaan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pertanyaan berhasil dimoderasi", question))
+}
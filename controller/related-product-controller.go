@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RelatedProductController interface {
+	GetRelated(ctx *gin.Context)
+}
+
+type relatedProductController struct {
+	relatedService service.RelatedProductService
+}
+
+func NewRelatedProductController(relatedService service.RelatedProductService) RelatedProductController {
+	return &relatedProductController{
+		relatedService: relatedService,
+	}
+}
+
+// GetRelated menampilkan produk terkait untuk halaman detail.
+// Contoh: /api/product/batik-parang/related?limit=12
+func (c *relatedProductController) GetRelated(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "12"))
+
+	products, err := c.relatedService.GetRelated(ctx.Param("slug"), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrProductNotAvailable) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, helper.BuildErrorResponse("Gagal mengambil produk terkait", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Produk terkait berhasil diambil", products))
+}
@@ -0,0 +1,322 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/helper"
+	"batik/repository"
+	"batik/service"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewController interface {
+	GetProductReviews(ctx *gin.Context)
+	CreateReview(ctx *gin.Context)
+	UpdateReview(ctx *gin.Context)
+	DeleteReview(ctx *gin.Context)
+	ReplyReview(ctx *gin.Context)
+	FlagReview(ctx *gin.Context)
+	GetStoreReviews(ctx *gin.Context)
+	GetModerationQueue(ctx *gin.Context)
+	ModerateReview(ctx *gin.Context)
+}
+
+type reviewController struct {
+	reviewService  service.ReviewService
+	productService service.ProductService
+	storeService   service.StoreService
+	jwtService     service.JWTService
+	authService    service.AuthService
+}
+
+func NewReviewController(reviewService service.ReviewService, productService service.ProductService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) ReviewController {
+	return &reviewController{
+		reviewService:  reviewService,
+		productService: productService,
+		storeService:   storeService,
+		jwtService:     jwtService,
+		authService:    authService,
+	}
+}
+
+// reviewErrorStatus memetakan error ulasan ke HTTP status
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrReviewForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrReviewExists), errors.Is(err, repository.ErrReviewAlreadyFlagged):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// reviewFilterFromQuery membaca filter daftar ulasan: rating (1-5),
+// with_photos=true, dan sort (newest, highest, lowest)
+func reviewFilterFromQuery(ctx *gin.Context) repository.ReviewFilter {
+	var filter repository.ReviewFilter
+	if rating, err := strconv.Atoi(ctx.Query("rating")); err == nil && rating >= 1 && rating <= 5 {
+		filter.Rating = rating
+	}
+	filter.WithPhotos, _ = strconv.ParseBool(ctx.Query("with_photos"))
+	switch sort := ctx.Query("sort"); sort {
+	case repository.ReviewSortNewest, repository.ReviewSortHighest, repository.ReviewSortLowest:
+		filter.Sort = sort
+	}
+	return filter
+}
+
+func reviewIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID ulasan tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetProductReviews menampilkan ringkasan rating dan ulasan produk.
+// Contoh: /api/product/batik-parang/reviews?page=1&limit=10&rating=5&with_photos=true&sort=newest
+func (c *reviewController) GetProductReviews(ctx *gin.Context) {
+	product, err := c.productService.GetProductBySlug(ctx.Param("slug"))
+	if err != nil || product.Status != entity.ProductStatusPublished {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	summary, err := c.reviewService.GetReviewSummary(product.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil ulasan", err.Error(), nil))
+		return
+	}
+
+	reviews, pagination, err := c.reviewService.GetProductReviews(product.ID, reviewFilterFromQuery(ctx), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil diambil", map[string]interface{}{
+		"summary":    summary,
+		"reviews":    reviews,
+		"pagination": pagination,
+	}))
+}
+
+// CreateReview menambah ulasan (multipart form: rating, body, images[])
+func (c *reviewController) CreateReview(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	product, err := c.productService.GetProductBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Produk tidak ditemukan", nil))
+		return
+	}
+
+	var reviewDTO dto.CreateReviewDTO
+	if err := ctx.ShouldBind(&reviewDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data ulasan tidak valid", err.Error(), nil))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := ctx.MultipartForm(); err == nil {
+		files = form.File["images"]
+	}
+
+	review, err := c.reviewService.CreateReview(ctx, product, user, reviewDTO, files)
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal menyimpan ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Ulasan berhasil disimpan", review))
+}
+
+// UpdateReview mengubah ulasan milik user (multipart form: rating, body,
+// images[], delete_images)
+func (c *reviewController) UpdateReview(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := reviewIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var reviewDTO dto.UpdateReviewDTO
+	if err := ctx.ShouldBind(&reviewDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data ulasan tidak valid", err.Error(), nil))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := ctx.MultipartForm(); err == nil {
+		files = form.File["images"]
+	}
+
+	review, err := c.reviewService.UpdateReview(ctx, id, user, reviewDTO, files)
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal mengupdate ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil diupdate", review))
+}
+
+func (c *reviewController) DeleteReview(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := reviewIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.reviewService.DeleteReview(id, user); err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil dihapus", nil))
+}
+
+// ReplyReview menyimpan balasan penjual untuk ulasan produk tokonya
+func (c *reviewController) ReplyReview(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := reviewIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var replyDTO dto.ReviewReplyDTO
+	if err := ctx.ShouldBindJSON(&replyDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data balasan tidak valid", err.Error(), nil))
+		return
+	}
+
+	review, err := c.reviewService.ReplyReview(id, user, replyDTO)
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal menyimpan balasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Balasan berhasil disimpan", review))
+}
+
+// FlagReview melaporkan ulasan yang tidak pantas untuk dimoderasi admin
+func (c *reviewController) FlagReview(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := reviewIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var flagDTO dto.ReviewFlagDTO
+	if err := ctx.ShouldBindJSON(&flagDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data laporan tidak valid", err.Error(), nil))
+		return
+	}
+
+	if err := c.reviewService.FlagReview(id, user, flagDTO); err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal melaporkan ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan berhasil dikirim", nil))
+}
+
+// GetStoreReviews menampilkan ulasan semua produk toko untuk pemilik toko
+func (c *reviewController) GetStoreReviews(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	reviews, pagination, err := c.reviewService.GetStoreReviews(storeID, reviewFilterFromQuery(ctx), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil diambil", map[string]interface{}{
+		"reviews":    reviews,
+		"pagination": pagination,
+	}))
+}
+
+// GetModerationQueue menampilkan ulasan untuk admin berdasarkan status
+// (pending default, hidden, published, flagged)
+func (c *reviewController) GetModerationQueue(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat memoderasi ulasan"); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	reviews, pagination, err := c.reviewService.GetModerationQueue(ctx.Query("status"), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Gagal mengambil ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil diambil", map[string]interface{}{
+		"reviews":    reviews,
+		"pagination": pagination,
+	}))
+}
+
+// ModerateReview menampilkan kembali (published) atau menyembunyikan (hidden) ulasan
+func (c *reviewController) ModerateReview(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat memoderasi ulasan"); !ok {
+		return
+	}
+	id, ok := reviewIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var moderateDTO dto.ModerateReviewDTO
+	if err := ctx.ShouldBindJSON(&moderateDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Status moderasi tidak valid", err.Error(), nil))
+		return
+	}
+
+	review, err := c.reviewService.ModerateReview(id, moderateDTO)
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), helper.BuildErrorResponse("Gagal memoderasi ulasan", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ulasan berhasil dimoderasi", review))
+}
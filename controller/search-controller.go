@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/helper"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController interface {
+	Suggest(ctx *gin.Context)
+	RecordClick(ctx *gin.Context)
+	GetAdminReport(ctx *gin.Context)
+	GetStoreReport(ctx *gin.Context)
+}
+
+type searchController struct {
+	suggestService   service.SuggestService
+	analyticsService service.SearchAnalyticsService
+	storeService     service.StoreService
+	jwtService       service.JWTService
+	authService      service.AuthService
+}
+
+func NewSearchController(suggestService service.SuggestService, analyticsService service.SearchAnalyticsService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) SearchController {
+	return &searchController{
+		suggestService:   suggestService,
+		analyticsService: analyticsService,
+		storeService:     storeService,
+		jwtService:       jwtService,
+		authService:      authService,
+	}
+}
+
+// Suggest menampilkan pelengkap kata kunci, koreksi ejaan, dan pencarian populer.
+// Contoh: /api/search/suggest?q=megam&limit=8
+func (c *searchController) Suggest(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "8"))
+	if err != nil {
+		limit = 8
+	}
+
+	suggestions := c.suggestService.Suggest(ctx.Query("q"), limit)
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Berhasil menampilkan saran pencarian", suggestions))
+}
+
+// RecordClick dipanggil frontend saat buyer membuka produk/artikel dari hasil pencarian
+func (c *searchController) RecordClick(ctx *gin.Context) {
+	var clickDTO dto.SearchClickDTO
+	if err := ctx.ShouldBind(&clickDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data klik tidak valid", err.Error(), nil))
+		return
+	}
+
+	actor := searchActor(c.jwtService, c.authService, ctx)
+	if err := c.analyticsService.RecordClick(actor, clickDTO); err != nil {
+		ctx.JSON(searchClickErrorStatus(err), helper.BuildErrorResponse("Gagal mencatat klik", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Klik berhasil dicatat", nil))
+}
+
+// searchClickErrorStatus memetakan error klik pencarian ke status HTTP
+func searchClickErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSearchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrSearchExpired):
+		return http.StatusGone
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetAdminReport menampilkan laporan pencarian untuk admin.
+// Query: source (product/article, default product), days (default 30), limit (default 20)
+func (c *searchController) GetAdminReport(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat melihat laporan pencarian"); !ok {
+		return
+	}
+
+	source := ctx.DefaultQuery("source", entity.SearchSourceProduct)
+	if source != entity.SearchSourceProduct && source != entity.SearchSourceArticle {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "Source tidak valid (product, article)", nil))
+		return
+	}
+
+	days, _ := strconv.Atoi(ctx.Query("days"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	report, err := c.analyticsService.GetReport(source, days, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil laporan pencarian", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan pencarian berhasil diambil", report))
+}
+
+// GetStoreReport menampilkan laporan pencarian produk untuk pemilik toko
+func (c *searchController) GetStoreReport(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+	storeID := int(store.ID)
+
+	days, _ := strconv.Atoi(ctx.Query("days"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	report, err := c.analyticsService.GetStoreReport(storeID, days, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil laporan pencarian", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan pencarian berhasil diambil", report))
+}
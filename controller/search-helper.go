@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"batik/service"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// searchActor menentukan pelaku pencarian untuk dedupe search log dan validasi
+// klik: akun user jika login, selain itu hash alamat IP. Cookie pengunjung
+// tidak dipakai karena mudah dibuang oleh script.
+func searchActor(jwtService service.JWTService, authService service.AuthService, ctx *gin.Context) string {
+	if user, ok := optionalUser(jwtService, authService, ctx); ok {
+		return service.UserViewerKey(user.ID)
+	}
+	sum := sha256.Sum256([]byte(ctx.ClientIP()))
+	return "ip:" + hex.EncodeToString(sum[:16])
+}
+
+// logSearch mencatat pencarian ke search analytics dan mengembalikan search_id
+// yang dikirim frontend saat buyer mengklik hasil. Hanya halaman pertama yang
+// dicatat agar paginasi tidak terhitung sebagai pencarian baru.
+func logSearch(analytics service.SearchAnalyticsService, jwtService service.JWTService, authService service.AuthService, ctx *gin.Context, source string, page int, query string, total int64) uint64 {
+	if page != 1 || query == "" {
+		return 0
+	}
+	return analytics.LogSearch(source, query, total, searchActor(jwtService, authService, ctx))
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/service"
+	"batik/shipping"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShippingController menampilkan ongkir untuk halaman produk dan keranjang.
+// Keduanya publik agar pengunjung bisa melihat ongkir sebelum login.
+type ShippingController interface {
+	GetProductRates(ctx *gin.Context)
+	GetCartRates(ctx *gin.Context)
+}
+
+type shippingController struct {
+	shippingService service.ShippingService
+	jwtService      service.JWTService
+	authService     service.AuthService
+}
+
+func NewShippingController(shippingService service.ShippingService, jwtService service.JWTService, authService service.AuthService) ShippingController {
+	return &shippingController{
+		shippingService: shippingService,
+		jwtService:      jwtService,
+		authService:     authService,
+	}
+}
+
+// shippingErrorStatus memetakan error ongkir ke HTTP status
+func shippingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrProductNotAvailable):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrStoreOriginMissing), errors.Is(err, shipping.ErrNoService):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// GetProductRates menghitung ongkir produk ke alamat buyer.
+// Contoh: /api/product/batik-tulis-pekalongan/shipping-rates?province=Jawa Barat&city=Bandung&quantity=2
+func (c *shippingController) GetProductRates(ctx *gin.Context) {
+	var query dto.ProductShippingQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Alamat tujuan tidak valid", err.Error(), nil))
+		return
+	}
+
+	rates, err := c.shippingService.QuoteProduct(ctx.Param("slug"), query)
+	if err != nil {
+		ctx.JSON(shippingErrorStatus(err), helper.BuildErrorResponse("Gagal menghitung ongkir", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ongkir berhasil dihitung", rates))
+}
+
+// GetCartRates menghitung ongkir per toko di keranjang.
+// Contoh: /api/cart/shipping-rates?province=DKI Jakarta&city=Jakarta Selatan
+func (c *shippingController) GetCartRates(ctx *gin.Context) {
+	var destination dto.ShippingDestinationDTO
+	if err := ctx.ShouldBindQuery(&destination); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Alamat tujuan tidak valid", err.Error(), nil))
+		return
+	}
+
+	ownerKey, _ := viewerKey(c.jwtService, c.authService, ctx, false)
+	rates, err := c.shippingService.QuoteCart(ownerKey, destination)
+	if err != nil {
+		ctx.JSON(shippingErrorStatus(err), helper.BuildErrorResponse("Gagal menghitung ongkir", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Ongkir berhasil dihitung", rates))
+}
@@ -23,6 +23,8 @@ type StoreController interface {
 	GetStoreByUserID(c *gin.Context)
 	GetAllStores(c *gin.Context)
 	GetAllStoreData(ctx *gin.Context)
+	DeleteStore(ctx *gin.Context)
+	RestoreStore(ctx *gin.Context)
 	// DeleteStore(c *gin.Context)
 	// GetAllStores(c *gin.Context)
 	// UploadStoreImage(c *gin.Context) 
@@ -296,6 +298,26 @@ func (c *storeController) GetAllStoreData(ctx *gin.Context) {
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
 	search := ctx.Query("search")
 	
+	// Mode cursor: ?cursor= (halaman pertama) atau ?cursor=<next_cursor|prev_cursor>
+	cursor, cursorMode, err := cursorFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Invalid cursor", err.Error(), nil))
+		return
+	}
+	if cursorMode {
+		stores, pagination, err := c.storeService.GetAllStoreDataCursor(cursor, limit, search)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Failed to fetch stores", err.Error(), nil))
+			return
+		}
+		data := map[string]interface{}{
+			"stores":     stores,
+			"pagination": pagination,
+		}
+		ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Stores fetched successfully", data))
+		return
+	}
+	
 	// Get users from service
 	stores, pagination, err := c.storeService.GetAllStoreData(page, limit, search)
 	if err != nil {
@@ -313,6 +335,45 @@ func (c *storeController) GetAllStoreData(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// DeleteStore moves the store to the trash (owner only)
+func (c *storeController) DeleteStore(ctx *gin.Context) {
+	storeID := ctx.Param("id")
+
+	user, err := userFromToken(c.jwtService, c.authService, ctx.GetHeader("Authorization"))
+	if err != nil {
+		response := helper.BuildErrorResponse("Failed to process token", err.Error(), nil)
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := c.storeService.DeleteStore(storeID, strconv.FormatUint(user.ID, 10)); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Toko berhasil dipindahkan ke trash", nil))
+}
+
+// RestoreStore restores a store from the trash (owner or admin)
+func (c *storeController) RestoreStore(ctx *gin.Context) {
+	storeID := ctx.Param("id")
+
+	user, err := userFromToken(c.jwtService, c.authService, ctx.GetHeader("Authorization"))
+	if err != nil {
+		response := helper.BuildErrorResponse("Failed to process token", err.Error(), nil)
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	store, err := c.storeService.RestoreStore(storeID, user)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Toko berhasil dikembalikan", store))
+}
+
 // // GetStoreByUserID handles request to get a store by UserID
 // func (c *storeController) GetStoreByUserID(ctx *gin.Context) {
 // 	// Get userID from path
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"batik/entity"
+	"batik/helper"
+	"batik/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+// userFromToken memvalidasi header Authorization dan mengembalikan user pemilik token
+func userFromToken(jwtService service.JWTService, authService service.AuthService, authHeader string) (entity.User, error) {
+	token, err := jwtService.ValidateToken(authHeader)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return entity.User{}, fmt.Errorf("failed to parse claims")
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		return entity.User{}, fmt.Errorf("email not found in token claims")
+	}
+
+	user := authService.FindByEmail(email)
+	if user.ID == 0 {
+		return entity.User{}, fmt.Errorf("user not found")
+	}
+
+	return user, nil
+}
+
+// currentUser mengambil user dari token. Jika gagal, response 401 sudah
+// dikirim dan handler cukup return.
+func currentUser(ctx *gin.Context, jwtService service.JWTService, authService service.AuthService) (entity.User, bool) {
+	user, err := userFromToken(jwtService, authService, ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helper.BuildResponse(false, "User tidak terautentikasi: "+err.Error(), nil))
+		return user, false
+	}
+	return user, true
+}
+
+// requireAdmin seperti currentUser, tetapi juga membalas 403 dengan pesan
+// forbidden jika user bukan admin
+func requireAdmin(ctx *gin.Context, jwtService service.JWTService, authService service.AuthService, forbidden string) (entity.User, bool) {
+	user, ok := currentUser(ctx, jwtService, authService)
+	if !ok {
+		return user, false
+	}
+	if user.Role != entity.RoleAdmin {
+		ctx.JSON(http.StatusForbidden, helper.BuildResponse(false, forbidden, nil))
+		return user, false
+	}
+	return user, true
+}
+
+// ownedStore mengambil toko dari parameter :id dan memastikan milik user
+func ownedStore(ctx *gin.Context, storeService service.StoreService, user entity.User) (entity.Store, bool) {
+	storeID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID toko tidak valid", nil))
+		return entity.Store{}, false
+	}
+	return requireStoreOwner(ctx, storeService, storeID, user.ID)
+}
+
+// requireStoreOwner memastikan toko dengan ID tersebut ada dan milik user
+func requireStoreOwner(ctx *gin.Context, storeService service.StoreService, storeID int, userID uint64) (entity.Store, bool) {
+	store, err := storeService.GetStoreByID(strconv.Itoa(storeID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, helper.BuildResponse(false, "Toko tidak ditemukan", nil))
+		return entity.Store{}, false
+	}
+
+	if uint64(store.UserID) != userID {
+		ctx.JSON(http.StatusForbidden, helper.BuildResponse(false, "Anda tidak memiliki akses ke toko ini", nil))
+		return entity.Store{}, false
+	}
+	return store, true
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"batik/helper"
+	"batik/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController interface {
+	GetAdminTrash(ctx *gin.Context)
+}
+
+type trashController struct {
+	productService service.ProductService
+	storeService   service.StoreService
+	articleService service.ArticleService
+	jwtService     service.JWTService
+	authService    service.AuthService
+}
+
+func NewTrashController(productService service.ProductService, storeService service.StoreService, articleService service.ArticleService, jwtService service.JWTService, authService service.AuthService) TrashController {
+	return &trashController{
+		productService: productService,
+		storeService:   storeService,
+		articleService: articleService,
+		jwtService:     jwtService,
+		authService:    authService,
+	}
+}
+
+// GetAdminTrash menampilkan isi trash untuk admin.
+// Query: type=products|stores|articles, store_id (opsional untuk products)
+func (c *trashController) GetAdminTrash(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat melihat trash"); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	trashType := ctx.DefaultQuery("type", "products")
+
+	var items interface{}
+	var pagination interface{}
+	var err error
+
+	switch trashType {
+	case "products":
+		storeID, _ := strconv.Atoi(ctx.Query("store_id"))
+		items, pagination, err = c.productService.GetProductTrash(storeID, page, limit)
+	case "stores":
+		items, pagination, err = c.storeService.GetStoreTrash(page, limit)
+	case "articles":
+		items, pagination, err = c.articleService.GetArticleTrash(page, limit)
+	default:
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "Tipe trash tidak valid (products, stores, articles)", nil))
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil trash", err.Error(), nil))
+		return
+	}
+
+	data := map[string]interface{}{
+		"type":       trashType,
+		"items":      items,
+		"pagination": pagination,
+	}
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Trash berhasil diambil", data))
+}
@@ -31,6 +31,26 @@ func (c userController) GetAllUser(ctx *gin.Context) {
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
 	search := ctx.Query("search")
 	
+	// Mode cursor: ?cursor= (halaman pertama) atau ?cursor=<next_cursor|prev_cursor>
+	cursor, cursorMode, err := cursorFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Invalid cursor", err.Error(), nil))
+		return
+	}
+	if cursorMode {
+		users, pagination, err := c.userService.GetAllUserCursor(cursor, limit, search)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Failed to fetch users", err.Error(), nil))
+			return
+		}
+		data := map[string]interface{}{
+			"users":      users,
+			"pagination": pagination,
+		}
+		ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Users fetched successfully", data))
+		return
+	}
+	
 	// Get users from service
 	users, pagination, err := c.userService.GetAllUser(page, limit, search)
 	if err != nil {
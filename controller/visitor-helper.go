@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"batik/entity"
+	"batik/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// visitorCookie menyimpan ID pengunjung anonim selama satu tahun
+const (
+	visitorCookie       = "batik_visitor"
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// visitorID mengembalikan ID pengunjung anonim dari cookie tanpa membuat ID baru
+func visitorID(ctx *gin.Context) string {
+	id, err := ctx.Cookie(visitorCookie)
+	if err != nil {
+		return ""
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return ""
+	}
+	return id
+}
+
+// ensureVisitorID mengembalikan ID pengunjung anonim, membuat cookie baru jika belum ada
+func ensureVisitorID(ctx *gin.Context) string {
+	if id := visitorID(ctx); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(visitorCookie, id, visitorCookieMaxAge, "/", "", false, true)
+	return id
+}
+
+// optionalUser mengembalikan user jika request membawa token yang valid.
+// Dipakai endpoint publik yang perilakunya berbeda untuk user login.
+func optionalUser(jwtService service.JWTService, authService service.AuthService, ctx *gin.Context) (entity.User, bool) {
+	if ctx.GetHeader("Authorization") == "" {
+		return entity.User{}, false
+	}
+	user, err := userFromToken(jwtService, authService, ctx.GetHeader("Authorization"))
+	if err != nil {
+		return entity.User{}, false
+	}
+	return user, true
+}
+
+// viewerKey menentukan pemilik riwayat kunjungan: akun user jika login,
+// selain itu cookie pengunjung anonim
+func viewerKey(jwtService service.JWTService, authService service.AuthService, ctx *gin.Context, createVisitor bool) (string, *uint64) {
+	if user, ok := optionalUser(jwtService, authService, ctx); ok {
+		return service.UserViewerKey(user.ID), &user.ID
+	}
+
+	id := visitorID(ctx)
+	if id == "" && createVisitor {
+		id = ensureVisitorID(ctx)
+	}
+	if id == "" {
+		return "", nil
+	}
+	return service.VisitorViewerKey(id), nil
+}
@@ -0,0 +1,317 @@
+package controller
+
+import (
+	"batik/dto"
+	"batik/helper"
+	"batik/repository"
+	"batik/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherController interface {
+	ValidateVoucher(ctx *gin.Context)
+	GetStoreVouchers(ctx *gin.Context)
+	CreateStoreVoucher(ctx *gin.Context)
+	UpdateStoreVoucher(ctx *gin.Context)
+	DeleteStoreVoucher(ctx *gin.Context)
+	GetRedemptions(ctx *gin.Context)
+	GetStoreReport(ctx *gin.Context)
+	GetPlatformVouchers(ctx *gin.Context)
+	CreatePlatformVoucher(ctx *gin.Context)
+	UpdatePlatformVoucher(ctx *gin.Context)
+	DeletePlatformVoucher(ctx *gin.Context)
+	GetPlatformReport(ctx *gin.Context)
+}
+
+type voucherController struct {
+	voucherService service.VoucherService
+	storeService   service.StoreService
+	jwtService     service.JWTService
+	authService    service.AuthService
+}
+
+func NewVoucherController(voucherService service.VoucherService, storeService service.StoreService, jwtService service.JWTService, authService service.AuthService) VoucherController {
+	return &voucherController{
+		voucherService: voucherService,
+		storeService:   storeService,
+		jwtService:     jwtService,
+		authService:    authService,
+	}
+}
+
+// voucherErrorStatus memetakan error voucher ke HTTP status
+func voucherErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrVoucherNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrVoucherForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrVoucherExhausted), errors.Is(err, service.ErrVoucherUserLimit),
+		errors.Is(err, repository.ErrRedemptionReleased):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+func voucherIDParam(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildResponse(false, "ID voucher tidak valid", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+// ValidateVoucher memeriksa kode voucher untuk belanja di satu toko dan
+// mengembalikan potongannya tanpa memakai kuota
+func (c *voucherController) ValidateVoucher(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+
+	var validateDTO dto.ValidateVoucherDTO
+	if err := ctx.ShouldBindJSON(&validateDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data voucher tidak valid", err.Error(), nil))
+		return
+	}
+
+	quote, err := c.voucherService.ValidateVoucher(user, validateDTO)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Voucher tidak dapat dipakai", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher dapat dipakai", quote))
+}
+
+func (c *voucherController) GetStoreVouchers(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	vouchers, err := c.voucherService.GetStoreVouchers(int(store.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil diambil", vouchers))
+}
+
+func (c *voucherController) CreateStoreVoucher(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	var voucherDTO dto.VoucherDTO
+	if err := ctx.ShouldBindJSON(&voucherDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data voucher tidak valid", err.Error(), nil))
+		return
+	}
+
+	voucher, err := c.voucherService.CreateStoreVoucher(store, user, voucherDTO)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal membuat voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Voucher berhasil dibuat", voucher))
+}
+
+func (c *voucherController) UpdateStoreVoucher(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := voucherIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var voucherDTO dto.VoucherDTO
+	if err := ctx.ShouldBindJSON(&voucherDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data voucher tidak valid", err.Error(), nil))
+		return
+	}
+
+	voucher, err := c.voucherService.UpdateStoreVoucher(id, user, voucherDTO)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil diubah", voucher))
+}
+
+func (c *voucherController) DeleteStoreVoucher(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := voucherIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.voucherService.DeleteStoreVoucher(id, user); err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil dihapus", nil))
+}
+
+// GetRedemptions menampilkan riwayat pemakaian voucher untuk pemilik toko
+// (voucher toko) atau admin (voucher platform)
+func (c *voucherController) GetRedemptions(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	id, ok := voucherIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	redemptions, pagination, err := c.voucherService.GetRedemptions(id, user, page, limit)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal mengambil pemakaian voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Pemakaian voucher berhasil diambil", map[string]interface{}{
+		"redemptions": redemptions,
+		"pagination":  pagination,
+	}))
+}
+
+// GetStoreReport merangkum pemakaian voucher toko.
+// Contoh: /api/my-store/1/voucher-report?days=30
+func (c *voucherController) GetStoreReport(ctx *gin.Context) {
+	user, ok := currentUser(ctx, c.jwtService, c.authService)
+	if !ok {
+		return
+	}
+	store, ok := ownedStore(ctx, c.storeService, user)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(ctx.Query("days"))
+	report, err := c.voucherService.GetStoreReport(int(store.ID), days)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil laporan voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan voucher berhasil diambil", report))
+}
+
+func (c *voucherController) GetPlatformVouchers(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola voucher platform"); !ok {
+		return
+	}
+
+	vouchers, err := c.voucherService.GetPlatformVouchers()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil diambil", vouchers))
+}
+
+func (c *voucherController) CreatePlatformVoucher(ctx *gin.Context) {
+	user, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola voucher platform")
+	if !ok {
+		return
+	}
+
+	var voucherDTO dto.VoucherDTO
+	if err := ctx.ShouldBindJSON(&voucherDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data voucher tidak valid", err.Error(), nil))
+		return
+	}
+
+	voucher, err := c.voucherService.CreatePlatformVoucher(user, voucherDTO)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal membuat voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.BuildResponse(true, "Voucher berhasil dibuat", voucher))
+}
+
+func (c *voucherController) UpdatePlatformVoucher(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola voucher platform"); !ok {
+		return
+	}
+	id, ok := voucherIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var voucherDTO dto.VoucherDTO
+	if err := ctx.ShouldBindJSON(&voucherDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.BuildErrorResponse("Data voucher tidak valid", err.Error(), nil))
+		return
+	}
+
+	voucher, err := c.voucherService.UpdatePlatformVoucher(id, voucherDTO)
+	if err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal mengubah voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil diubah", voucher))
+}
+
+func (c *voucherController) DeletePlatformVoucher(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola voucher platform"); !ok {
+		return
+	}
+	id, ok := voucherIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.voucherService.DeletePlatformVoucher(id); err != nil {
+		ctx.JSON(voucherErrorStatus(err), helper.BuildErrorResponse("Gagal menghapus voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Voucher berhasil dihapus", nil))
+}
+
+func (c *voucherController) GetPlatformReport(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.jwtService, c.authService, "Hanya admin yang dapat mengelola voucher platform"); !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(ctx.Query("days"))
+	report, err := c.voucherService.GetPlatformReport(days)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, helper.BuildErrorResponse("Gagal mengambil laporan voucher", err.Error(), nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.BuildResponse(true, "Laporan voucher berhasil diambil", report))
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+type AddCartItemDTO struct {
+	ProductID int    `json:"product_id" binding:"required"`
+	Variant   string `json:"variant" binding:"max=100"`
+	Quantity  int    `json:"quantity" binding:"required,min=1,max=99"`
+}
+
+type UpdateCartItemDTO struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=99"`
+}
+
+// CartItemResponse adalah item keranjang yang sudah divalidasi ulang. Price
+// adalah harga efektif saat ini; PreviousPrice terisi jika harga berubah
+// sejak item ditambahkan. Available kosong untuk produk yang stoknya tidak dilacak.
+type CartItemResponse struct {
+	ID            uint64    `json:"id"`
+	ProductID     int       `json:"product_id"`
+	Slug          string    `json:"slug,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Thumbnail     string    `json:"thumbnail,omitempty"`
+	Variant       string    `json:"variant"`
+	Quantity      int       `json:"quantity"`
+	Price         float64   `json:"price"`
+	OriginalPrice float64   `json:"original_price"`
+	PromoBadge    string    `json:"promo_badge,omitempty"`
+	PreviousPrice *float64  `json:"previous_price,omitempty"`
+	Available     *int      `json:"available,omitempty"`
+	Status        string    `json:"status"`
+	LineTotal     float64   `json:"line_total"`
+	AddedAt       time.Time `json:"added_at"`
+}
+
+// CartStoreGroup mengelompokkan item keranjang per toko. Subtotal hanya
+// menghitung item yang masih bisa dibeli.
+type CartStoreGroup struct {
+	StoreID   int                `json:"store_id"`
+	StoreName string             `json:"store_name"`
+	Items     []CartItemResponse `json:"items"`
+	Subtotal  float64            `json:"subtotal"`
+}
+
+// CartResponse adalah isi keranjang. Produk yang sudah tidak dijual masuk
+// Unavailable. HasIssues true jika ada item yang harga atau stoknya berubah
+// dan perlu diperhatikan buyer sebelum checkout.
+type CartResponse struct {
+	Stores      []CartStoreGroup   `json:"stores"`
+	Unavailable []CartItemResponse `json:"unavailable,omitempty"`
+	ItemCount   int                `json:"item_count"`
+	Subtotal    float64            `json:"subtotal"`
+	HasIssues   bool               `json:"has_issues"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// CreateCategoryDTO dikirim sebagai multipart form; ikon diupload di field "icon".
+// ParentID 0 berarti kategori induk.
+type CreateCategoryDTO struct {
+	CategoryName string `json:"category_name" form:"category_name" binding:"required,min=2,max=100"`
+	ParentID     int    `json:"parent_id" form:"parent_id" binding:"min=0"`
+	SortOrder    *int   `json:"sort_order" form:"sort_order"`
+}
+
+// UpdateCategoryDTO hanya mengubah field yang dikirim. ParentID 0 memindahkan
+// kategori menjadi kategori induk.
+type UpdateCategoryDTO struct {
+	CategoryName string `json:"category_name" form:"category_name" binding:"omitempty,min=2,max=100"`
+	ParentID     *int   `json:"parent_id" form:"parent_id" binding:"omitempty,min=0"`
+	SortOrder    *int   `json:"sort_order" form:"sort_order"`
+}
+
+// ReorderCategoriesDTO mengatur urutan tampil; sort_order mengikuti posisi ID di daftar
+type ReorderCategoriesDTO struct {
+	CategoryIDs []int `json:"category_ids" binding:"required,min=1"`
+}
+
+// CategorySchemaFieldDTO adalah aturan satu atribut pada skema kategori.
+// AllowedValues kosong berarti semua pilihan atribut; Min/Max hanya untuk atribut angka.
+type CategorySchemaFieldDTO struct {
+	Code          string   `json:"code" binding:"required"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+	Min           *float64 `json:"min"`
+	Max           *float64 `json:"max"`
+	SortOrder     *int     `json:"sort_order"`
+}
+
+// UpdateCategorySchemaDTO mengganti seluruh skema atribut kategori
+type UpdateCategorySchemaDTO struct {
+	Attributes []CategorySchemaFieldDTO `json:"attributes" binding:"dive"`
+}
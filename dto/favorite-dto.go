@@ -0,0 +1,20 @@
+package dto
+
+// CreateWishlistDTO membuat wishlist bernama. Shared true membuat wishlist
+// dapat dibuka siapa saja lewat link share_token.
+type CreateWishlistDTO struct {
+	Name   string `json:"name" binding:"required,min=1,max=100"`
+	Shared bool   `json:"shared"`
+}
+
+// UpdateWishlistDTO hanya mengubah field yang dikirim. RegenerateLink membuat
+// share_token baru sehingga link lama tidak berlaku.
+type UpdateWishlistDTO struct {
+	Name           string `json:"name" binding:"omitempty,min=1,max=100"`
+	Shared         *bool  `json:"shared"`
+	RegenerateLink bool   `json:"regenerate_link"`
+}
+
+type WishlistItemDTO struct {
+	ProductSlug string `json:"product_slug" binding:"required"`
+}
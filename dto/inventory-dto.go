@@ -0,0 +1,24 @@
+package dto
+
+type AdjustStockDTO struct {
+	Variant string `json:"variant" form:"variant"`
+	Change  int    `json:"change" form:"change" binding:"required,ne=0"`
+	Reason  string `json:"reason" form:"reason" binding:"required,oneof=restock sale correction damage return"`
+	Note    string `json:"note" form:"note" binding:"max=255"`
+}
+
+type StockThresholdDTO struct {
+	Variant   string `json:"variant" form:"variant"`
+	Threshold int    `json:"threshold" form:"threshold" binding:"min=0"`
+}
+
+type InventoryResponse struct {
+	ID                int    `json:"id"`
+	ProductID         int    `json:"product_id"`
+	Variant           string `json:"variant"`
+	Quantity          int    `json:"quantity"`
+	Reserved          int    `json:"reserved"`
+	Available         int    `json:"available"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+	LowStock          bool   `json:"low_stock"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+// CheckoutVoucherDTO memakai satu voucher untuk order di satu toko
+type CheckoutVoucherDTO struct {
+	StoreID int    `json:"store_id" binding:"required"`
+	Code    string `json:"code" binding:"required,max=32"`
+}
+
+// CheckoutCourierDTO memilih layanan kurir untuk order di satu toko,
+// mis. {"store_id": 1, "courier": "jne", "service": "REG"}
+type CheckoutCourierDTO struct {
+	StoreID int    `json:"store_id" binding:"required"`
+	Courier string `json:"courier" binding:"required,max=20"`
+	Service string `json:"service" binding:"required,max=20"`
+}
+
+// CheckoutDTO membuat order dari keranjang user. StoreIDs kosong berarti
+// semua toko di keranjang; setiap toko menjadi satu order dan wajib punya
+// pilihan kurir di Couriers.
+type CheckoutDTO struct {
+	StoreIDs        []int                  `json:"store_ids"`
+	Vouchers        []CheckoutVoucherDTO   `json:"vouchers" binding:"dive"`
+	Couriers        []CheckoutCourierDTO   `json:"couriers" binding:"required,min=1,dive"`
+	RecipientName   string                 `json:"recipient_name" binding:"required,max=100"`
+	RecipientPhone  string                 `json:"recipient_phone" binding:"required,min=8,max=20"`
+	ShippingAddress string                 `json:"shipping_address" binding:"required,min=10,max=500"`
+	Destination     ShippingDestinationDTO `json:"destination" binding:"required"`
+	Note            string                 `json:"note" binding:"max=500"`
+}
+
+// UpdateOrderStatusDTO mengubah status order. TrackingNumber wajib saat
+// penjual mengubah status menjadi shipped.
+type UpdateOrderStatusDTO struct {
+	Status         string `json:"status" binding:"required"`
+	Note           string `json:"note" binding:"max=500"`
+	TrackingNumber string `json:"tracking_number" binding:"max=64"`
+}
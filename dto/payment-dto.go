@@ -0,0 +1,23 @@
+package dto
+
+// CreatePaymentDTO meminta tagihan untuk order, misalnya
+// {"method": "virtual_account", "channel": "bca"}
+type CreatePaymentDTO struct {
+	Method  string `json:"method" binding:"required"`
+	Channel string `json:"channel" binding:"required"`
+}
+
+// SimulatePaymentDTO mengirim webhook tiruan untuk tagihan. Amount kosong
+// berarti nominal tagihan; isi nominal lain untuk menguji pembayaran kurang.
+type SimulatePaymentDTO struct {
+	Status string   `json:"status" binding:"required,oneof=paid expired failed"`
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// WebhookResult adalah balasan untuk payment gateway setelah webhook diproses
+type WebhookResult struct {
+	EventID       string `json:"event_id"`
+	Result        string `json:"result"`
+	OrderNumber   string `json:"order_number"`
+	PaymentStatus string `json:"payment_status"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// CreateProductAlertDTO berlangganan notifikasi harga turun (target_price
+// opsional) atau stok tersedia kembali
+type CreateProductAlertDTO struct {
+	Type        string   `json:"type" binding:"required,oneof=price_drop back_in_stock"`
+	TargetPrice *float64 `json:"target_price" binding:"omitempty,gt=0"`
+}
+
+type PricePoint struct {
+	Price     float64   `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// PriceHistoryResponse berisi harga dalam periode Days. Titik pertama adalah
+// harga yang berlaku di awal periode.
+type PriceHistoryResponse struct {
+	Days         int          `json:"days"`
+	CurrentPrice float64      `json:"current_price"`
+	LowestPrice  float64      `json:"lowest_price"`
+	HighestPrice float64      `json:"highest_price"`
+	History      []PricePoint `json:"history"`
+}
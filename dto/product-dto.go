@@ -9,6 +9,13 @@ type CreateProductDTO struct {
 	Harga       float64 `form:"harga" binding:"required,gt=0"`
 	StoreID     int     `form:"store_id" binding:"required"`
 	CategoryID  int		`form:"category_id" binding:"required"`
+	Status      string     `form:"status" binding:"omitempty,oneof=draft published archived scheduled"`
+	PublishAt   *time.Time `form:"publish_at" time_format:"2006-01-02T15:04:05Z07:00"`
+	Attributes  string     `form:"attributes"` // Objek JSON code -> value, mis. {"technique":"tulis"}
+	Weight      int        `form:"weight" binding:"omitempty,min=1,max=50000"` // gram
+	Length      int        `form:"length" binding:"omitempty,min=1,max=300"`   // cm
+	Width       int        `form:"width" binding:"omitempty,min=1,max=300"`
+	Height      int        `form:"height" binding:"omitempty,min=1,max=300"`
 }
 
 type UpdateProductDTO struct {
@@ -16,6 +23,19 @@ type UpdateProductDTO struct {
 	Description string  `json:"description" form:"description"`
 	Harga       float64 `json:"harga" form:"harga" binding:"omitempty,gt=0"`
 	CategoryID  int     `json:"category_id" form:"category_id"` 
+	Status      string     `json:"status" form:"status"`
+	PublishAt   *time.Time `json:"publish_at" form:"publish_at"`
+	Attributes  string     `json:"attributes" form:"attributes"`
+	Weight      int        `json:"weight" form:"weight" binding:"omitempty,min=1,max=50000"` // gram, 0 = tidak diubah
+	Length      int        `json:"length" form:"length" binding:"omitempty,min=1,max=300"`   // cm
+	Width       int        `json:"width" form:"width" binding:"omitempty,min=1,max=300"`
+	Height      int        `json:"height" form:"height" binding:"omitempty,min=1,max=300"`
+}
+
+// ProductStatusDTO dipakai untuk mengubah status produk (draft, published, archived, scheduled)
+type ProductStatusDTO struct {
+	Status    string     `json:"status" form:"status" binding:"required,oneof=draft published archived scheduled"`
+	PublishAt *time.Time `json:"publish_at" form:"publish_at" time_format:"2006-01-02T15:04:05Z07:00"`
 }
 
 type ProductImageDTO struct {
@@ -32,6 +52,13 @@ type ProductResponse struct {
 	StoreID     int               `json:"store_id"`
 	CategoryID  int				  `json:"category_id"`
 	Thumbnail   string            `json:"thumbnail"`
+	Status      string            `json:"status"`
+	PublishAt   *time.Time        `json:"publish_at"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Weight      int               `json:"weight"`
+	Length      int               `json:"length"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
 	Images      []ProductImageDTO `json:"images"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
@@ -46,6 +73,7 @@ type ProductCardResponse struct {
 	Harga       float64   `json:"harga"`
 	StoreID     int       `json:"store_id"`
 	Thumbnail   string    `json:"thumbnail"`
+	Status      string    `json:"status,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -61,5 +89,14 @@ type PublicProductCard struct {
 	CategoryName string    `json:"category_name,omitempty"`
 	CategorySlug string    `json:"category_slug,omitempty"`
 	Thumbnail    string    `json:"thumbnail"`
+	OutOfStock   bool      `json:"out_of_stock"`
+	RatingAvg    float64   `json:"rating_avg"`
+	RatingCount  int       `json:"rating_count"`
+	FavoriteCount int      `json:"favorite_count"`
+	EffectivePrice float64 `json:"effective_price"`
+	OriginalPrice float64  `json:"original_price"`
+	PromoBadge   string    `json:"promo_badge,omitempty"`
+	PromoEndsAt  *time.Time `json:"promo_ends_at,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 }
\ No newline at end of file
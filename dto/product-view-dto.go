@@ -0,0 +1,18 @@
+package dto
+
+type ProductViewStat struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Views     int64  `json:"views"`
+	Viewers   int64  `json:"viewers"`
+}
+
+// StoreViewReport merangkum kunjungan halaman produk toko. Viewers adalah
+// jumlah pengunjung unik (user login atau pengunjung anonim).
+type StoreViewReport struct {
+	Days        int               `json:"days"`
+	Views       int64             `json:"views"`
+	Viewers     int64             `json:"viewers"`
+	TopProducts []ProductViewStat `json:"top_products"`
+}
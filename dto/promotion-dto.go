@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// PromotionDTO dipakai untuk membuat dan mengubah promosi toko maupun
+// kampanye platform. Waktu memakai format RFC3339.
+type PromotionDTO struct {
+	Name          string    `json:"name" binding:"required,min=3,max=100"`
+	Badge         string    `json:"badge" binding:"max=50"`
+	DiscountType  string    `json:"discount_type" binding:"required,oneof=percent fixed"`
+	DiscountValue float64   `json:"discount_value" binding:"required,gt=0"`
+	MaxDiscount   *float64  `json:"max_discount" binding:"omitempty,gt=0"`
+	Scope         string    `json:"scope" binding:"required,oneof=product category store all"`
+	CategoryID    *int      `json:"category_id"`
+	ProductIDs    []int     `json:"product_ids" binding:"max=500"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	EndsAt        time.Time `json:"ends_at" binding:"required"`
+	Active        *bool     `json:"active"`
+}
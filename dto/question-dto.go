@@ -0,0 +1,19 @@
+package dto
+
+type CreateQuestionDTO struct {
+	Body string `json:"body" binding:"required,min=5,max=1000"`
+}
+
+// AnswerQuestionDTO adalah jawaban toko; answer kosong menghapus jawaban
+type AnswerQuestionDTO struct {
+	Answer string `json:"answer" binding:"max=2000"`
+}
+
+type QuestionFlagDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=255"`
+}
+
+// ModerateQuestionDTO menetapkan status pertanyaan (published atau hidden)
+type ModerateQuestionDTO struct {
+	Status string `json:"status" binding:"required,oneof=published hidden"`
+}
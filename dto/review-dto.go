@@ -0,0 +1,37 @@
+package dto
+
+// CreateReviewDTO dikirim sebagai multipart form; foto diupload di field "images"
+type CreateReviewDTO struct {
+	Rating int    `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	Body   string `json:"body" form:"body" binding:"max=2000"`
+}
+
+// UpdateReviewDTO mengganti rating dan teks ulasan. Foto baru diupload di
+// field "images"; delete_images berisi ID foto yang dihapus, dipisah koma.
+type UpdateReviewDTO struct {
+	Rating       int    `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	Body         string `json:"body" form:"body" binding:"max=2000"`
+	DeleteImages string `json:"delete_images" form:"delete_images"`
+}
+
+// ReviewReplyDTO adalah balasan penjual; reply kosong menghapus balasan
+type ReviewReplyDTO struct {
+	Reply string `json:"reply" binding:"max=1000"`
+}
+
+type ReviewFlagDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=255"`
+}
+
+// ModerateReviewDTO menetapkan status ulasan (published atau hidden)
+type ModerateReviewDTO struct {
+	Status string `json:"status" binding:"required,oneof=published hidden"`
+}
+
+// ReviewSummary merangkum rating produk untuk halaman detail. Distribution
+// berisi jumlah ulasan per bintang ("1" sampai "5").
+type ReviewSummary struct {
+	Average      float64          `json:"average"`
+	Count        int64            `json:"count"`
+	Distribution map[string]int64 `json:"distribution"`
+}
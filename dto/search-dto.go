@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type SuggestionItem struct {
+	Text string `json:"text"`
+	Kind string `json:"kind"`
+}
+
+type SuggestResponse struct {
+	Query          string           `json:"query"`
+	Completions    []SuggestionItem `json:"completions"`
+	DidYouMean     string           `json:"did_you_mean,omitempty"`
+	PopularQueries []string         `json:"popular_queries"`
+}
+
+// SearchClickDTO merujuk ke search_id yang dikembalikan endpoint pencarian
+type SearchClickDTO struct {
+	SearchID uint64 `json:"search_id" form:"search_id" binding:"required"`
+	TargetID uint64 `json:"target_id" form:"target_id" binding:"required"`
+}
+
+type SearchQueryStat struct {
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	Searchers      int64     `json:"searchers"`
+	AvgResults     float64   `json:"avg_results"`
+	Clicks         int64     `json:"clicks"`
+	ClickThrough   float64   `json:"click_through"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+type SearchReport struct {
+	Source            string            `json:"source"`
+	Days              int               `json:"days"`
+	TopQueries        []SearchQueryStat `json:"top_queries"`
+	ZeroResultQueries []SearchQueryStat `json:"zero_result_queries"`
+	StoreQueries      []SearchQueryStat `json:"store_queries,omitempty"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// ShippingDestinationDTO adalah alamat tujuan untuk menghitung ongkir
+type ShippingDestinationDTO struct {
+	Province   string `json:"province" form:"province" binding:"required,max=100"`
+	City       string `json:"city" form:"city" binding:"required,max=100"`
+	PostalCode string `json:"postal_code" form:"postal_code" binding:"omitempty,numeric,len=5"`
+}
+
+// ProductShippingQuery menghitung ongkir satu produk dari halaman produk.
+// Contoh: ?province=Jawa Barat&city=Bandung&quantity=2
+type ProductShippingQuery struct {
+	ShippingDestinationDTO
+	Quantity int `form:"quantity" binding:"omitempty,min=1,max=99"`
+}
+
+// ShippingQuote adalah satu pilihan layanan kurir
+type ShippingQuote struct {
+	Courier     string  `json:"courier"`
+	CourierName string  `json:"courier_name"`
+	Service     string  `json:"service"`
+	Description string  `json:"description"`
+	Cost        float64 `json:"cost"`
+	EtdMinDays  int     `json:"etd_min_days"`
+	EtdMaxDays  int     `json:"etd_max_days"`
+}
+
+// StoreShippingQuotes adalah pilihan kurir untuk paket dari satu toko.
+// Weight adalah berat tagih dalam gram (berat asli atau berat volume).
+// Error diisi jika ongkir toko ini tidak bisa dihitung.
+type StoreShippingQuotes struct {
+	StoreID    int             `json:"store_id"`
+	StoreName  string          `json:"store_name"`
+	OriginCity string          `json:"origin_city"`
+	Weight     int             `json:"weight"`
+	Quotes     []ShippingQuote `json:"quotes"`
+	Error      string          `json:"error,omitempty"`
+}
@@ -5,6 +5,10 @@ type StoreDTO struct {
     Description string `json:"description" form:"description" binding:"required,min=10,max=500"`
     Whatsapp    string `json:"whatsapp" form:"whatsapp" binding:"required,min=8,max=15,e164"`
     Alamat      string `json:"alamat" form:"alamat" binding:"required,min=10,max=200"`
+    Province    string `json:"province" form:"province" binding:"omitempty,max=100"`
+    City        string `json:"city" form:"city" binding:"omitempty,max=100"`
+    District    string `json:"district" form:"district" binding:"omitempty,max=100"`
+    PostalCode  string `json:"postal_code" form:"postal_code" binding:"omitempty,numeric,len=5"`
     UserID      int `json:"user_id" form:"user_id"`
 }
 
@@ -14,6 +18,10 @@ type UpdateStoreDTO struct {
 	Description string `form:"description"`
 	Whatsapp    string `form:"whatsapp"`
 	Alamat      string `form:"alamat"`
+	Province    string `form:"province" binding:"omitempty,max=100"`
+	City        string `form:"city" binding:"omitempty,max=100"`
+	District    string `form:"district" binding:"omitempty,max=100"`
+	PostalCode  string `form:"postal_code" binding:"omitempty,numeric,len=5"`
 }
 
 // StoreImageDTO adalah data gambar yang diupload
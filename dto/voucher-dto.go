@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// VoucherDTO dipakai untuk membuat dan mengubah voucher toko maupun platform.
+// Kode diubah ke huruf besar; per_user_limit kosong berarti 1 dan usage_limit
+// kosong berarti tanpa batas total.
+type VoucherDTO struct {
+	Code          string    `json:"code" binding:"required,min=4,max=32,alphanum"`
+	Description   string    `json:"description" binding:"max=255"`
+	DiscountType  string    `json:"discount_type" binding:"required,oneof=percent fixed"`
+	DiscountValue float64   `json:"discount_value" binding:"required,gt=0"`
+	MaxDiscount   *float64  `json:"max_discount" binding:"omitempty,gt=0"`
+	MinSpend      float64   `json:"min_spend" binding:"gte=0"`
+	PerUserLimit  int       `json:"per_user_limit" binding:"omitempty,gt=0"`
+	UsageLimit    *int      `json:"usage_limit" binding:"omitempty,gt=0"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	ExpiresAt     time.Time `json:"expires_at" binding:"required"`
+	Active        *bool     `json:"active"`
+}
+
+// ValidateVoucherDTO memeriksa voucher untuk belanja di satu toko. Subtotal
+// adalah total belanja di toko tersebut sebelum potongan voucher.
+type ValidateVoucherDTO struct {
+	Code     string  `json:"code" binding:"required"`
+	StoreID  int     `json:"store_id" binding:"required"`
+	Subtotal float64 `json:"subtotal" binding:"required,gt=0"`
+}
+
+// VoucherQuote adalah hasil pemeriksaan voucher. Belum mengurangi kuota;
+// kuota baru terpakai saat order dibuat.
+type VoucherQuote struct {
+	Code     string  `json:"code"`
+	StoreID  *int    `json:"store_id,omitempty"`
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
+}
+
+type VoucherStat struct {
+	VoucherID      uint64     `json:"voucher_id"`
+	Code           string     `json:"code"`
+	Redemptions    int64      `json:"redemptions"`
+	Buyers         int64      `json:"buyers"`
+	TotalDiscount  float64    `json:"total_discount"`
+	TotalSubtotal  float64    `json:"total_subtotal"`
+	LastRedeemedAt *time.Time `json:"last_redeemed_at,omitempty"`
+}
+
+// VoucherReport merangkum pemakaian voucher dalam periode tertentu. Buyers
+// adalah jumlah pembeli unik.
+type VoucherReport struct {
+	Days          int           `json:"days"`
+	Redemptions   int64         `json:"redemptions"`
+	TotalDiscount float64       `json:"total_discount"`
+	Vouchers      []VoucherStat `json:"vouchers"`
+}
@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Article struct {
     ID          uint64    `json:"id" gorm:"column:id"`
@@ -11,4 +15,5 @@ type Article struct {
     ImageURL    string    `json:"imageUrl" gorm:"column:image_url"`  // Tambahkan tag gorm
     CreatedAt   time.Time `json:"created_At" gorm:"column:created_at"`
     UpdatedAt   time.Time `json:"updated_At" gorm:"column:updated_at"`
+    DeletedAt   gorm.DeletedAt `json:"deleted_At,omitempty" gorm:"column:deleted_at;index"`
 }
@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+// Tipe nilai atribut
+const (
+	AttributeTypeEnum      = "enum"
+	AttributeTypeMultiEnum = "multi_enum" // beberapa pilihan, disimpan dipisah koma
+	AttributeTypeText      = "text"
+	AttributeTypeNumber    = "number"
+)
+
+// Attribute adalah definisi atribut produk, misalnya teknik, motif, atau jenis pewarna
+type Attribute struct {
+	ID         int       `json:"id" gorm:"column:id;primaryKey"`
+	Code       string    `json:"code" gorm:"column:code;uniqueIndex"`
+	Name       string    `json:"name" gorm:"column:name"`
+	Type       string    `json:"type" gorm:"column:type"`
+	Options    []string  `json:"options,omitempty" gorm:"column:options;serializer:json"`
+	Unit       string    `json:"unit,omitempty" gorm:"column:unit"`
+	Filterable bool      `json:"filterable" gorm:"column:filterable"`
+	SortOrder  int       `json:"sort_order" gorm:"column:sort_order"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// HasOption mengecek apakah value termasuk pilihan atribut enum
+func (a Attribute) HasOption(value string) bool {
+	for _, opt := range a.Options {
+		if opt == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryAttribute menentukan atribut apa saja yang berlaku untuk sebuah
+// kategori beserta aturannya (skema atribut kategori). AllowedValues membatasi
+// pilihan enum kategori ini; kosong berarti semua Options atribut.
+type CategoryAttribute struct {
+	ID            int       `json:"id" gorm:"column:id;primaryKey"`
+	CategoryID    int       `json:"category_id" gorm:"column:category_id;uniqueIndex:idx_category_attribute"`
+	AttributeID   int       `json:"attribute_id" gorm:"column:attribute_id;uniqueIndex:idx_category_attribute"`
+	Required      bool      `json:"required" gorm:"column:required"`
+	AllowedValues []string  `json:"allowed_values,omitempty" gorm:"column:allowed_values;serializer:json"`
+	MinValue      *float64  `json:"min_value,omitempty" gorm:"column:min_value"`
+	MaxValue      *float64  `json:"max_value,omitempty" gorm:"column:max_value"`
+	SortOrder     int       `json:"sort_order" gorm:"column:sort_order"`
+	Attribute     Attribute `json:"attribute" gorm:"foreignKey:AttributeID"`
+}
+
+// AttributeField adalah atribut dengan aturan skema kategori yang sudah
+// diterapkan, dipakai untuk validasi dan form produk
+type AttributeField struct {
+	Attribute
+	Required bool     `json:"required"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+}
+
+// Field menggabungkan atribut dengan aturan kategorinya
+func (ca CategoryAttribute) Field() AttributeField {
+	field := AttributeField{
+		Attribute: ca.Attribute,
+		Required:  ca.Required,
+		Min:       ca.MinValue,
+		Max:       ca.MaxValue,
+	}
+	if len(ca.AllowedValues) > 0 {
+		field.Options = ca.AllowedValues
+	}
+	return field
+}
+
+// ProductAttribute adalah nilai atribut sebuah produk. Code diduplikasi dari
+// Attribute agar filter facet tidak perlu join ke tabel attributes.
+type ProductAttribute struct {
+	ID          int    `json:"id" gorm:"column:id;primaryKey"`
+	ProductID   int    `json:"product_id" gorm:"column:product_id;uniqueIndex:idx_product_attribute"`
+	AttributeID int    `json:"attribute_id" gorm:"column:attribute_id;uniqueIndex:idx_product_attribute"`
+	Code        string `json:"code" gorm:"column:code;index:idx_product_attribute_code_value"`
+	Value       string `json:"value" gorm:"column:value;index:idx_product_attribute_code_value"`
+}
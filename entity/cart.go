@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Status item keranjang setelah divalidasi ulang terhadap produk dan stok
+const (
+	CartItemOK                = "ok"
+	CartItemPriceChanged      = "price_changed"
+	CartItemInsufficientStock = "insufficient_stock"
+	CartItemOutOfStock        = "out_of_stock"
+	CartItemUnavailable       = "unavailable"
+)
+
+// CartItem adalah satu produk/varian di keranjang. OwnerKey memakai format
+// yang sama dengan riwayat kunjungan: "u:<id user>" untuk user login dan
+// "v:<id pengunjung>" untuk pengunjung anonim.
+type CartItem struct {
+	ID            uint64    `json:"id" gorm:"column:id;primaryKey"`
+	OwnerKey      string    `json:"-" gorm:"column:owner_key"`
+	UserID        *uint64   `json:"-" gorm:"column:user_id"`
+	ProductID     int       `json:"product_id" gorm:"column:product_id"`
+	Variant       string    `json:"variant" gorm:"column:variant"`
+	Quantity      int       `json:"quantity" gorm:"column:quantity"`
+	PriceSnapshot float64   `json:"price_snapshot" gorm:"column:price_snapshot"` // Harga efektif saat terakhir ditambah/diubah
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// ProductFavorite menandai produk yang disukai buyer
+type ProductFavorite struct {
+	ID        uint64    `json:"id" gorm:"column:id;primaryKey"`
+	UserID    uint64    `json:"user_id" gorm:"column:user_id"`
+	ProductID int       `json:"product_id" gorm:"column:product_id"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// Wishlist adalah daftar produk bernama milik buyer. Wishlist yang dibagikan
+// (Shared) dapat dibuka siapa saja lewat ShareToken.
+type Wishlist struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey"`
+	UserID     uint64    `json:"user_id" gorm:"column:user_id"`
+	Name       string    `json:"name" gorm:"column:name"`
+	ShareToken string    `json:"share_token" gorm:"column:share_token"`
+	Shared     bool      `json:"shared" gorm:"column:shared"`
+	ItemCount  int64     `json:"item_count" gorm:"column:item_count;->"` // Dihitung dari wishlist_items
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+type WishlistItem struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey"`
+	WishlistID uint64    `json:"wishlist_id" gorm:"column:wishlist_id"`
+	ProductID  int       `json:"product_id" gorm:"column:product_id"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// ProductFavoriteStat adalah jumlah favorit dan wishlist per produk untuk analitik penjual
+type ProductFavoriteStat struct {
+	ProductID     int    `json:"product_id" gorm:"column:product_id"`
+	Slug          string `json:"slug" gorm:"column:slug"`
+	Name          string `json:"name" gorm:"column:name"`
+	Status        string `json:"status" gorm:"column:status"`
+	FavoriteCount int64  `json:"favorite_count" gorm:"column:favorite_count"`
+	WishlistCount int64  `json:"wishlist_count" gorm:"column:wishlist_count"`
+}
@@ -0,0 +1,73 @@
+package entity
+
+import "time"
+
+// Alasan perubahan stok yang dicatat di ledger
+const (
+	StockReasonRestock    = "restock"
+	StockReasonSale       = "sale"
+	StockReasonCorrection = "correction"
+	StockReasonDamage     = "damage"
+	StockReasonReturn     = "return"
+)
+
+// Status reservasi stok
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// ProductInventory menyimpan stok per produk/varian. Variant kosong berarti
+// produk tanpa varian.
+type ProductInventory struct {
+	ID                 int        `json:"id" gorm:"column:id;primaryKey"`
+	ProductID          int        `json:"product_id" gorm:"column:product_id;uniqueIndex:idx_inventory_product_variant"`
+	Variant            string     `json:"variant" gorm:"column:variant;uniqueIndex:idx_inventory_product_variant"`
+	Quantity           int        `json:"quantity" gorm:"column:quantity"`
+	Reserved           int        `json:"reserved" gorm:"column:reserved"`
+	LowStockThreshold  int        `json:"low_stock_threshold" gorm:"column:low_stock_threshold"`
+	LowStockNotifiedAt *time.Time `json:"low_stock_notified_at,omitempty" gorm:"column:low_stock_notified_at"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// Available adalah stok yang masih bisa dijual (belum direservasi)
+func (inv ProductInventory) Available() int {
+	return inv.Quantity - inv.Reserved
+}
+
+// IsLowStock true jika stok tersedia sudah mencapai ambang batas
+func (inv ProductInventory) IsLowStock() bool {
+	return inv.LowStockThreshold > 0 && inv.Available() <= inv.LowStockThreshold
+}
+
+// InventoryAdjustment adalah satu baris ledger perubahan stok
+type InventoryAdjustment struct {
+	ID          int       `json:"id" gorm:"column:id;primaryKey"`
+	InventoryID int       `json:"inventory_id" gorm:"column:inventory_id;index"`
+	ProductID   int       `json:"product_id" gorm:"column:product_id;index"`
+	Variant     string    `json:"variant" gorm:"column:variant"`
+	Change      int       `json:"change" gorm:"column:quantity_change"`
+	Balance     int       `json:"balance" gorm:"column:balance"`
+	Reason      string    `json:"reason" gorm:"column:reason"`
+	Note        string    `json:"note" gorm:"column:note"`
+	UserID      int       `json:"user_id" gorm:"column:user_id"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// StockReservation menahan stok sementara sampai di-commit, dilepas, atau kedaluwarsa
+type StockReservation struct {
+	ID          int       `json:"id" gorm:"column:id;primaryKey"`
+	InventoryID int       `json:"inventory_id" gorm:"column:inventory_id;index"`
+	ProductID   int       `json:"product_id" gorm:"column:product_id"`
+	Variant     string    `json:"variant" gorm:"column:variant"`
+	Quantity    int       `json:"quantity" gorm:"column:quantity"`
+	Reference   string    `json:"reference" gorm:"column:reference;index"`
+	UserID      int       `json:"user_id" gorm:"column:user_id"`
+	Status      string    `json:"status" gorm:"column:status;index"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"column:expires_at;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+const (
+	NotificationLowStock = "low_stock"
+	// Dikirim migrasi 046 ke toko yang belum mengisi alamat asal pengiriman
+	NotificationStoreAddress = "store_address"
+)
+
+type Notification struct {
+	ID        uint64     `json:"id" gorm:"column:id;primaryKey"`
+	UserID    int        `json:"user_id" gorm:"column:user_id;index"`
+	Type      string     `json:"type" gorm:"column:type"`
+	Title     string     `json:"title" gorm:"column:title"`
+	Message   string     `json:"message" gorm:"column:message"`
+	Link      string     `json:"link" gorm:"column:link"`
+	ReadAt    *time.Time `json:"read_at" gorm:"column:read_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,161 @@
+package entity
+
+import "time"
+
+// Status order
+const (
+	OrderPendingPayment = "pending_payment"
+	OrderPaid           = "paid"
+	OrderProcessing     = "processing"
+	OrderShipped        = "shipped"
+	OrderDelivered      = "delivered"
+	OrderCompleted      = "completed"
+	OrderCancelled      = "cancelled"
+	OrderRefunded       = "refunded"
+)
+
+// Pelaku perubahan status order
+const (
+	OrderActorBuyer  = "buyer"
+	OrderActorSeller = "seller"
+	OrderActorAdmin  = "admin"
+	OrderActorSystem = "system"
+)
+
+// Notifikasi order
+const (
+	NotificationNewOrder    = "new_order"
+	NotificationOrderStatus = "order_status"
+)
+
+// orderTransitions adalah state machine order: status asal, status tujuan,
+// dan pelaku yang boleh melakukannya. Status yang tidak punya tujuan
+// (completed, cancelled, refunded) adalah status akhir.
+var orderTransitions = map[string]map[string][]string{
+	OrderPendingPayment: {
+		OrderPaid:      {OrderActorSystem},
+		OrderCancelled: {OrderActorBuyer, OrderActorSeller, OrderActorAdmin, OrderActorSystem},
+	},
+	OrderPaid: {
+		OrderProcessing: {OrderActorSeller},
+		OrderRefunded:   {OrderActorSeller, OrderActorAdmin},
+	},
+	OrderProcessing: {
+		OrderShipped:  {OrderActorSeller},
+		OrderRefunded: {OrderActorSeller, OrderActorAdmin},
+	},
+	OrderShipped: {
+		OrderDelivered: {OrderActorSeller, OrderActorBuyer, OrderActorSystem},
+	},
+	OrderDelivered: {
+		OrderCompleted: {OrderActorBuyer, OrderActorSystem},
+		OrderRefunded:  {OrderActorAdmin},
+	},
+}
+
+// CanTransitionOrder menandai apakah actor boleh mengubah status from ke to
+func CanTransitionOrder(from, to, actor string) bool {
+	for _, allowed := range orderTransitions[from][to] {
+		if allowed == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidOrderStatus menandai status order yang dikenal
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case OrderPendingPayment, OrderPaid, OrderProcessing, OrderShipped,
+		OrderDelivered, OrderCompleted, OrderCancelled, OrderRefunded:
+		return true
+	}
+	return false
+}
+
+// Order adalah pesanan buyer ke satu toko. Satu checkout keranjang yang
+// berisi beberapa toko menghasilkan satu order per toko.
+type Order struct {
+	ID                 uint64       `json:"id" gorm:"column:id;primaryKey"`
+	Number             string       `json:"number" gorm:"column:number"`
+	UserID             uint64       `json:"user_id" gorm:"column:user_id"`
+	StoreID            int          `json:"store_id" gorm:"column:store_id"`
+	StoreName          string       `json:"store_name,omitempty" gorm:"column:store_name;->"`
+	BuyerName          string       `json:"buyer_name,omitempty" gorm:"column:buyer_name;->"`
+	Status             string       `json:"status" gorm:"column:status"`
+	Subtotal           float64      `json:"subtotal" gorm:"column:subtotal"`
+	Discount           float64      `json:"discount" gorm:"column:discount"`
+	ShippingCost       float64      `json:"shipping_cost" gorm:"column:shipping_cost"`
+	Total              float64      `json:"total" gorm:"column:total"`
+	VoucherCode        string       `json:"voucher_code,omitempty" gorm:"column:voucher_code"`
+	RecipientName      string       `json:"recipient_name" gorm:"column:recipient_name"`
+	RecipientPhone     string       `json:"recipient_phone" gorm:"column:recipient_phone"`
+	ShippingAddress    string       `json:"shipping_address" gorm:"column:shipping_address"`
+	ShippingProvince   string       `json:"shipping_province" gorm:"column:shipping_province"`
+	ShippingCity       string       `json:"shipping_city" gorm:"column:shipping_city"`
+	ShippingPostalCode string       `json:"shipping_postal_code" gorm:"column:shipping_postal_code"`
+	Courier            string       `json:"courier" gorm:"column:courier"`
+	CourierService     string       `json:"courier_service" gorm:"column:courier_service"`
+	ShippingWeight     int          `json:"shipping_weight" gorm:"column:shipping_weight"` // Berat tagih dalam gram
+	Note               string       `json:"note,omitempty" gorm:"column:note"`
+	TrackingNumber     string       `json:"tracking_number,omitempty" gorm:"column:tracking_number"`
+	PaymentDueAt       time.Time    `json:"payment_due_at" gorm:"column:payment_due_at"`
+	PaidAt             *time.Time   `json:"paid_at,omitempty" gorm:"column:paid_at"`
+	ShippedAt          *time.Time   `json:"shipped_at,omitempty" gorm:"column:shipped_at"`
+	DeliveredAt        *time.Time   `json:"delivered_at,omitempty" gorm:"column:delivered_at"`
+	CompletedAt        *time.Time   `json:"completed_at,omitempty" gorm:"column:completed_at"`
+	CancelledAt        *time.Time   `json:"cancelled_at,omitempty" gorm:"column:cancelled_at"`
+	Items              []OrderItem  `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+	Events             []OrderEvent `json:"events,omitempty" gorm:"foreignKey:OrderID"`
+	CreatedAt          time.Time    `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt          time.Time    `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// OrderItem menyimpan salinan nama, harga, dan gambar produk saat checkout
+// sehingga order tidak berubah jika produk diubah atau dihapus
+type OrderItem struct {
+	ID            uint64  `json:"id" gorm:"column:id;primaryKey"`
+	OrderID       uint64  `json:"-" gorm:"column:order_id"`
+	ProductID     int     `json:"product_id" gorm:"column:product_id"`
+	Variant       string  `json:"variant" gorm:"column:variant"`
+	ProductName   string  `json:"product_name" gorm:"column:product_name"`
+	ProductSlug   string  `json:"product_slug" gorm:"column:product_slug"`
+	ImageURL      string  `json:"image_url" gorm:"column:image_url"`
+	Price         float64 `json:"price" gorm:"column:price"`
+	OriginalPrice float64 `json:"original_price" gorm:"column:original_price"`
+	Quantity      int     `json:"quantity" gorm:"column:quantity"`
+	LineTotal     float64 `json:"line_total" gorm:"column:line_total"`
+}
+
+// OrderEvent adalah satu baris timeline order
+type OrderEvent struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey"`
+	OrderID    uint64    `json:"-" gorm:"column:order_id"`
+	FromStatus string    `json:"from_status,omitempty" gorm:"column:from_status"`
+	ToStatus   string    `json:"to_status" gorm:"column:to_status"`
+	Actor      string    `json:"actor" gorm:"column:actor"`
+	ActorID    *uint64   `json:"actor_id,omitempty" gorm:"column:actor_id"`
+	Note       string    `json:"note,omitempty" gorm:"column:note"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// Efek samping perubahan status order yang dicatat di outbox
+// order_settlements dan diulang sampai berhasil
+const (
+	SettlementCommitStock    = "commit_stock"
+	SettlementReleaseStock   = "release_stock"
+	SettlementReleaseVoucher = "release_voucher"
+)
+
+// OrderSettlement adalah satu efek samping order yang belum berhasil
+// dijalankan. UserID dipakai untuk mencatat penjualan stok.
+type OrderSettlement struct {
+	ID          uint64    `json:"id" gorm:"column:id;primaryKey"`
+	OrderNumber string    `json:"order_number" gorm:"column:order_number"`
+	UserID      uint64    `json:"user_id" gorm:"column:user_id"`
+	Action      string    `json:"action" gorm:"column:action"`
+	Attempts    int       `json:"attempts" gorm:"column:attempts"`
+	LastError   string    `json:"last_error" gorm:"column:last_error"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
@@ -0,0 +1,67 @@
+package entity
+
+import "testing"
+
+func TestCanTransitionOrder(t *testing.T) {
+	cases := []struct {
+		from, to, actor string
+		want            bool
+	}{
+		{OrderPendingPayment, OrderPaid, OrderActorSystem, true},
+		{OrderPendingPayment, OrderPaid, OrderActorBuyer, false},
+		{OrderPendingPayment, OrderPaid, OrderActorSeller, false},
+		{OrderPendingPayment, OrderCancelled, OrderActorBuyer, true},
+		{OrderPendingPayment, OrderCancelled, OrderActorSystem, true},
+		{OrderPaid, OrderProcessing, OrderActorSeller, true},
+		{OrderPaid, OrderProcessing, OrderActorBuyer, false},
+		{OrderPaid, OrderCancelled, OrderActorBuyer, false},
+		{OrderPaid, OrderRefunded, OrderActorAdmin, true},
+		{OrderProcessing, OrderShipped, OrderActorSeller, true},
+		{OrderProcessing, OrderShipped, OrderActorAdmin, false},
+		{OrderShipped, OrderDelivered, OrderActorBuyer, true},
+		{OrderShipped, OrderRefunded, OrderActorSeller, false},
+		{OrderDelivered, OrderCompleted, OrderActorSystem, true},
+		{OrderDelivered, OrderCompleted, OrderActorSeller, false},
+		{OrderDelivered, OrderRefunded, OrderActorAdmin, true},
+		// Status tidak boleh dilompati
+		{OrderPendingPayment, OrderShipped, OrderActorSeller, false},
+		{OrderPaid, OrderCompleted, OrderActorSystem, false},
+		// Pelaku atau status yang tidak dikenal
+		{OrderPaid, OrderProcessing, "guest", false},
+		{"unknown", OrderPaid, OrderActorSystem, false},
+	}
+	for _, tc := range cases {
+		if got := CanTransitionOrder(tc.from, tc.to, tc.actor); got != tc.want {
+			t.Errorf("CanTransitionOrder(%s, %s, %s) = %v, ingin %v", tc.from, tc.to, tc.actor, got, tc.want)
+		}
+	}
+}
+
+func TestFinalOrderStatusHasNoTransition(t *testing.T) {
+	actors := []string{OrderActorBuyer, OrderActorSeller, OrderActorAdmin, OrderActorSystem}
+	statuses := []string{OrderPendingPayment, OrderPaid, OrderProcessing, OrderShipped,
+		OrderDelivered, OrderCompleted, OrderCancelled, OrderRefunded}
+
+	for _, from := range []string{OrderCompleted, OrderCancelled, OrderRefunded} {
+		for _, to := range statuses {
+			for _, actor := range actors {
+				if CanTransitionOrder(from, to, actor) {
+					t.Errorf("status akhir %s tidak boleh berubah ke %s oleh %s", from, to, actor)
+				}
+			}
+		}
+	}
+}
+
+func TestIsValidOrderStatus(t *testing.T) {
+	for _, status := range []string{OrderPendingPayment, OrderPaid, OrderRefunded} {
+		if !IsValidOrderStatus(status) {
+			t.Errorf("%s harus valid", status)
+		}
+	}
+	for _, status := range []string{"", "pending", "PAID"} {
+		if IsValidOrderStatus(status) {
+			t.Errorf("%q tidak boleh valid", status)
+		}
+	}
+}
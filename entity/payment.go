@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// Hasil pencocokan webhook dengan tagihan dan order
+const (
+	WebhookApplied         = "applied"
+	WebhookDuplicate       = "duplicate"
+	WebhookIgnored         = "ignored"
+	WebhookAmountMismatch  = "amount_mismatch"
+	WebhookOrderNotPayable = "order_not_payable"
+)
+
+// Payment adalah satu tagihan untuk order. Satu order bisa punya beberapa
+// tagihan jika buyer mengganti metode pembayaran; yang lunas lebih dulu
+// menandai order dibayar. OrderSyncPending menandai tagihan lunas yang
+// ordernya belum berhasil ditandai dibayar; NeedsRefund menandai tagihan
+// lunas untuk order yang sudah tidak menunggu pembayaran.
+type Payment struct {
+	ID               uint64     `json:"id" gorm:"column:id;primaryKey"`
+	OrderID          uint64     `json:"order_id" gorm:"column:order_id"`
+	OrderNumber      string     `json:"order_number" gorm:"column:order_number"`
+	UserID           uint64     `json:"user_id" gorm:"column:user_id"`
+	Provider         string     `json:"provider" gorm:"column:provider"`
+	ProviderRef      string     `json:"provider_ref" gorm:"column:provider_ref"`
+	Method           string     `json:"method" gorm:"column:method"`
+	Channel          string     `json:"channel" gorm:"column:channel"`
+	Amount           float64    `json:"amount" gorm:"column:amount"`
+	Status           string     `json:"status" gorm:"column:status"` // Sama dengan payment.Status*
+	VANumber         string     `json:"va_number,omitempty" gorm:"column:va_number"`
+	QRString         string     `json:"qr_string,omitempty" gorm:"column:qr_string"`
+	CheckoutURL      string     `json:"checkout_url,omitempty" gorm:"column:checkout_url"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"column:expires_at"`
+	PaidAt           *time.Time `json:"paid_at,omitempty" gorm:"column:paid_at"`
+	OrderSyncPending bool       `json:"-" gorm:"column:order_sync_pending"`
+	NeedsRefund      bool       `json:"needs_refund" gorm:"column:needs_refund"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// PaymentWebhook mencatat setiap webhook yang sudah diverifikasi beserta
+// hasil pencocokannya. EventID unik per provider sehingga webhook yang
+// dikirim ulang tidak diproses dua kali.
+type PaymentWebhook struct {
+	ID          uint64    `json:"id" gorm:"column:id;primaryKey"`
+	Provider    string    `json:"provider" gorm:"column:provider"`
+	EventID     string    `json:"event_id" gorm:"column:event_id"`
+	PaymentID   *uint64   `json:"payment_id,omitempty" gorm:"column:payment_id"`
+	ProviderRef string    `json:"provider_ref" gorm:"column:provider_ref"`
+	Status      string    `json:"status" gorm:"column:status"`
+	Amount      float64   `json:"amount" gorm:"column:amount"`
+	Result      string    `json:"result" gorm:"column:result"`
+	Payload     string    `json:"-" gorm:"column:payload"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Jenis alert produk yang bisa dilanggan buyer
+const (
+	ProductAlertPriceDrop   = "price_drop"
+	ProductAlertBackInStock = "back_in_stock"
+)
+
+const (
+	NotificationPriceDrop   = "price_drop"
+	NotificationBackInStock = "back_in_stock"
+)
+
+// ProductPriceHistory mencatat harga produk setiap kali berubah.
+// PreviousPrice kosong untuk harga awal.
+type ProductPriceHistory struct {
+	ID            uint64    `json:"-" gorm:"column:id;primaryKey"`
+	ProductID     int       `json:"-" gorm:"column:product_id"`
+	Price         float64   `json:"price" gorm:"column:price"`
+	PreviousPrice *float64  `json:"previous_price,omitempty" gorm:"column:previous_price"`
+	ChangedAt     time.Time `json:"changed_at" gorm:"column:changed_at"`
+}
+
+func (ProductPriceHistory) TableName() string {
+	return "product_price_history"
+}
+
+// ProductAlert adalah langganan notifikasi buyer untuk satu produk. Alert
+// price_drop dibandingkan dengan harga efektif (setelah promosi); tanpa
+// TargetPrice, alert dikirim saat harga efektif turun di bawah ReferencePrice,
+// yaitu harga efektif saat buyer berlangganan.
+type ProductAlert struct {
+	ID             uint64     `json:"id" gorm:"column:id;primaryKey"`
+	UserID         uint64     `json:"user_id" gorm:"column:user_id"`
+	ProductID      int        `json:"product_id" gorm:"column:product_id"`
+	ProductName    string     `json:"product_name" gorm:"column:product_name;->"` // Diisi dari JOIN products
+	ProductSlug    string     `json:"product_slug" gorm:"column:product_slug;->"`
+	Type           string     `json:"type" gorm:"column:type"`
+	TargetPrice    *float64   `json:"target_price,omitempty" gorm:"column:target_price"`
+	ReferencePrice *float64   `json:"reference_price,omitempty" gorm:"column:reference_price"`
+	CurrentPrice   float64    `json:"current_price" gorm:"column:current_price;->"` // Harga efektif dari JOIN products
+	NotifiedAt     *time.Time `json:"notified_at,omitempty" gorm:"column:notified_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
@@ -1,11 +1,47 @@
 package entity
 
+import "time"
+
 type ProductCategory struct {
-	ID int `json:"id" gorm:"column:id"`
-	CategoryName string `json:"category_name" gorm:"column:category_name"`
-	Slug string `json:"slug" gorm:"column:slug"`
+	ID           int       `json:"id" gorm:"column:id"`
+	CategoryName string    `json:"category_name" gorm:"column:category_name"`
+	Slug         string    `json:"slug" gorm:"column:slug"`
+	ParentID     *int      `json:"parent_id" gorm:"column:parent_id"`
+	Icon         string    `json:"icon" gorm:"column:icon"`
+	SortOrder    int       `json:"sort_order" gorm:"column:sort_order"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"column:updated_at"`
+	// ProductCount adalah jumlah produk publik di kategori ini dan semua subkategorinya
+	ProductCount int64             `json:"product_count" gorm:"-"`
+	Children     []ProductCategory `json:"children,omitempty" gorm:"-"`
 }
 
 func (pc ProductCategory) TableName() string {
 	return "category_catalog"
-}
\ No newline at end of file
+}
+
+// CategoryDescendants mengembalikan ID roots beserta semua turunannya.
+// Relasi parent yang membentuk siklus tidak membuat perulangan tanpa akhir.
+func CategoryDescendants(categories []ProductCategory, roots []int) []int {
+	children := make(map[int][]int)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	seen := make(map[int]bool)
+	var result []int
+	queue := append([]int{}, roots...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+		queue = append(queue, children[id]...)
+	}
+	return result
+}
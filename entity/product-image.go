@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ProductImage struct {
 	ID        int       `json:"id" gorm:"column:id;primaryKey"`
@@ -8,4 +12,5 @@ type ProductImage struct {
 	ProductID int       `json:"product_id" gorm:"column:product_id"`
 	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deleted_at;index"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// ProductView mencatat satu kunjungan ke halaman detail produk. ViewerKey
+// adalah "u:<user_id>" untuk user login atau "v:<visitor_id>" untuk
+// pengunjung anonim.
+type ProductView struct {
+	ID        uint64    `json:"id" gorm:"column:id;primaryKey"`
+	ProductID int       `json:"product_id" gorm:"column:product_id"`
+	StoreID   int       `json:"store_id" gorm:"column:store_id"`
+	ViewerKey string    `json:"-" gorm:"column:viewer_key"`
+	UserID    *uint64   `json:"user_id,omitempty" gorm:"column:user_id"`
+	ViewedAt  time.Time `json:"viewed_at" gorm:"column:viewed_at"`
+}
+
+// ProductViewStat adalah jumlah kunjungan per produk untuk laporan toko
+type ProductViewStat struct {
+	ProductID int    `gorm:"column:product_id"`
+	Name      string `gorm:"column:name"`
+	Slug      string `gorm:"column:slug"`
+	Views     int64  `gorm:"column:views"`
+	Viewers   int64  `gorm:"column:viewers"`
+}
@@ -1,6 +1,18 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status siklus hidup produk
+const (
+	ProductStatusDraft     = "draft"
+	ProductStatusPublished = "published"
+	ProductStatusArchived  = "archived"
+	ProductStatusScheduled = "scheduled"
+)
 
 type Product struct {
 	ID int `json:"id" gorm:"column:id"`
@@ -11,9 +23,16 @@ type Product struct {
 	StoreID int `json:"store_id" gorm:"column:store_id"`
 	CategoryID int `json:"category_id" gorm:"column:category_id"`
 	Thumbnail string `json:"thumbnail" gorm:"column:thumbnail"`
+	Status string `json:"status" gorm:"column:status;default:published"`
+	PublishAt *time.Time `json:"publish_at" gorm:"column:publish_at"`
+	Weight int `json:"weight" gorm:"column:weight"` // gram, untuk ongkir
+	Length int `json:"length" gorm:"column:length"` // cm
+	Width int `json:"width" gorm:"column:width"`    // cm
+	Height int `json:"height" gorm:"column:height"` // cm
 	Images      []ProductImage `json:"images" gorm:"foreignKey:ProductID"`
 	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt   time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"column:deleted_at;index"`
 }
 
 
@@ -29,11 +48,29 @@ type ProductCard struct {
 	CategoryName string          `json:"category_name,omitempty" gorm:"column:category_name"`
 	CategorySlug  string          `json:"category_slug,omitempty" gorm:"column:slug"`
 	Thumbnail    string          `json:"thumbnail" gorm:"column:thumbnail"`
+	Status       string          `json:"status" gorm:"column:status"`
+	PublishAt    *time.Time      `json:"publish_at,omitempty" gorm:"column:publish_at"`
+	Weight       int             `json:"weight" gorm:"column:weight"` // gram
+	Length       int             `json:"length" gorm:"column:length"` // cm
+	Width        int             `json:"width" gorm:"column:width"`
+	Height       int             `json:"height" gorm:"column:height"`
+	OutOfStock   bool            `json:"out_of_stock" gorm:"column:out_of_stock;->"` // Dihitung dari products.available_stock
+	RatingAvg    float64         `json:"rating_avg" gorm:"column:rating_avg;->"`     // Diperbarui setiap ada perubahan ulasan
+	RatingCount  int             `json:"rating_count" gorm:"column:rating_count;->"`
+	FavoriteCount int            `json:"favorite_count" gorm:"column:favorite_count;->"`
+	EffectivePrice float64       `json:"effective_price" gorm:"column:effective_price;->"` // Harga setelah promosi pemenang
+	OriginalPrice  float64       `json:"original_price" gorm:"column:original_price;->"`
+	PromoBadge     string        `json:"promo_badge,omitempty" gorm:"column:promo_badge;->"`
+	PromoEndsAt    *time.Time    `json:"promo_ends_at,omitempty" gorm:"column:promo_ends_at;->"`
+	SortKey      float64         `json:"-" gorm:"column:sort_key;->"`                 // Nilai urutan terhitung (acak, popularitas, relevansi)
 	Images       []ProductImage  `json:"images" gorm:"foreignKey:ProductID"`
+	Attributes   []ProductAttribute `json:"attributes,omitempty" gorm:"foreignKey:ProductID"`
+	Questions    []ProductQuestion  `json:"questions,omitempty" gorm:"foreignKey:ProductID"` // Hanya diisi di detail produk
 	Category     ProductCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID;references:ID"`
 	Store        Store           `json:"store,omitempty" gorm:"foreignKey:StoreID;references:ID"` // Relasi GORM ke Store
 	CreatedAt    time.Time       `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt    time.Time       `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt  `json:"-" gorm:"column:deleted_at"`
 }
 
 
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Jenis potongan promosi
+const (
+	PromotionTypePercent = "percent"
+	PromotionTypeFixed   = "fixed"
+)
+
+// Cakupan promosi. Promosi toko memakai product, category, atau store;
+// kampanye platform (StoreID kosong) memakai category atau all.
+const (
+	PromotionScopeProduct  = "product"
+	PromotionScopeCategory = "category"
+	PromotionScopeStore    = "store"
+	PromotionScopeAll      = "all"
+)
+
+// MaxDiscountRate membatasi potongan promosi apa pun, termasuk potongan
+// nominal, agar harga efektif tidak pernah kurang dari 10% harga asli
+const MaxDiscountRate = 0.9
+
+// Promotion adalah diskon otomatis dalam rentang waktu tertentu. Promosi toko
+// dibuat penjual; kampanye platform (misalnya Hari Batik Nasional) dibuat admin.
+type Promotion struct {
+	ID            uint64             `json:"id" gorm:"column:id;primaryKey"`
+	StoreID       *int               `json:"store_id,omitempty" gorm:"column:store_id"`
+	Name          string             `json:"name" gorm:"column:name"`
+	Badge         string             `json:"badge" gorm:"column:badge"`
+	DiscountType  string             `json:"discount_type" gorm:"column:discount_type"`
+	DiscountValue float64            `json:"discount_value" gorm:"column:discount_value"`
+	MaxDiscount   *float64           `json:"max_discount,omitempty" gorm:"column:max_discount"`
+	Scope         string             `json:"scope" gorm:"column:scope"`
+	CategoryID    *int               `json:"category_id,omitempty" gorm:"column:category_id"`
+	StartsAt      time.Time          `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt        time.Time          `json:"ends_at" gorm:"column:ends_at"`
+	Active        bool               `json:"active" gorm:"column:active"`
+	CreatedBy     uint64             `json:"created_by" gorm:"column:created_by"`
+	Products      []PromotionProduct `json:"products,omitempty" gorm:"foreignKey:PromotionID"`
+	CreatedAt     time.Time          `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// PromotionProduct adalah produk yang termasuk promosi bercakupan product
+type PromotionProduct struct {
+	ID          uint64 `json:"-" gorm:"column:id;primaryKey"`
+	PromotionID uint64 `json:"-" gorm:"column:promotion_id"`
+	ProductID   int    `json:"product_id" gorm:"column:product_id"`
+}
+
+// IsPlatform menandai kampanye platform yang dibuat admin
+func (p Promotion) IsPlatform() bool {
+	return p.StoreID == nil
+}
+
+// IsRunning menandai promosi aktif yang sedang berjalan pada waktu now
+func (p Promotion) IsRunning(now time.Time) bool {
+	return p.Active && !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
+
+// Discount menghitung potongan untuk harga tertentu, dibulatkan ke rupiah
+// dan dibatasi MaxDiscount serta MaxDiscountRate
+func (p Promotion) Discount(price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+
+	discount := p.DiscountValue
+	if p.DiscountType == PromotionTypePercent {
+		discount = price * p.DiscountValue / 100
+	}
+	if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+		discount = *p.MaxDiscount
+	}
+	if limit := price * MaxDiscountRate; discount > limit {
+		discount = limit
+	}
+	return math.Round(discount)
+}
+
+// Specificity mengurutkan cakupan dari yang paling spesifik. Dipakai sebagai
+// penentu saat dua promosi memberi potongan yang sama.
+func (p Promotion) Specificity() int {
+	switch p.Scope {
+	case PromotionScopeProduct:
+		return 3
+	case PromotionScopeCategory:
+		return 2
+	case PromotionScopeStore:
+		return 1
+	}
+	return 0
+}
+
+// ProductPromotion adalah harga efektif produk dari promosi yang menang,
+// dihitung ulang oleh background job. Produk tanpa promosi tidak memiliki baris.
+type ProductPromotion struct {
+	ProductID      int       `gorm:"column:product_id;primaryKey"`
+	PromotionID    uint64    `gorm:"column:promotion_id"`
+	OriginalPrice  float64   `gorm:"column:original_price"`
+	EffectivePrice float64   `gorm:"column:effective_price"`
+	Badge          string    `gorm:"column:badge"`
+	EndsAt         time.Time `gorm:"column:ends_at"`
+	ComputedAt     time.Time `gorm:"column:computed_at"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Status moderasi pertanyaan mengikuti ulasan: pertanyaan yang dilaporkan
+// berkali-kali menjadi pending sampai diputuskan admin.
+const (
+	QuestionStatusPublished = "published"
+	QuestionStatusPending   = "pending"
+	QuestionStatusHidden    = "hidden"
+)
+
+const (
+	NotificationNewQuestion      = "new_question"
+	NotificationQuestionAnswered = "question_answered"
+)
+
+// ProductQuestion adalah pertanyaan publik buyer tentang produk beserta
+// jawaban dari pemilik toko
+type ProductQuestion struct {
+	ID          uint64     `json:"id" gorm:"column:id;primaryKey"`
+	ProductID   int        `json:"product_id" gorm:"column:product_id"`
+	UserID      uint64     `json:"user_id" gorm:"column:user_id"`
+	AskerName   string     `json:"asker_name" gorm:"column:asker_name;->"` // Diisi dari JOIN users
+	Body        string     `json:"body" gorm:"column:body"`
+	Status      string     `json:"status" gorm:"column:status;default:published"`
+	Answer      string     `json:"answer,omitempty" gorm:"column:answer"`
+	AnsweredBy  *uint64    `json:"answered_by,omitempty" gorm:"column:answered_by"`
+	AnsweredAt  *time.Time `json:"answered_at,omitempty" gorm:"column:answered_at"`
+	UpvoteCount int        `json:"upvote_count" gorm:"column:upvote_count"`
+	FlagCount   int        `json:"flag_count" gorm:"column:flag_count"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty" gorm:"column:moderated_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+type ProductQuestionVote struct {
+	ID         uint64    `gorm:"column:id;primaryKey"`
+	QuestionID uint64    `gorm:"column:question_id"`
+	UserID     uint64    `gorm:"column:user_id"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+// ProductQuestionFlag adalah laporan pengguna atas pertanyaan yang tidak pantas
+type ProductQuestionFlag struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey"`
+	QuestionID uint64    `json:"question_id" gorm:"column:question_id"`
+	UserID     uint64    `json:"user_id" gorm:"column:user_id"`
+	Reason     string    `json:"reason" gorm:"column:reason"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// RelatedProduct adalah satu produk terkait hasil perhitungan offline
+// (lihat search.ComputeRelated)
+type RelatedProduct struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey"`
+	ProductID  int       `json:"product_id" gorm:"column:product_id"`
+	RelatedID  int       `json:"related_id" gorm:"column:related_id"`
+	Score      float64   `json:"score" gorm:"column:score"`
+	ComputedAt time.Time `json:"computed_at" gorm:"column:computed_at"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Status moderasi ulasan. Ulasan yang dilaporkan berkali-kali menjadi pending
+// (disembunyikan) sampai admin memutuskan ditampilkan kembali atau disembunyikan.
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusPending   = "pending"
+	ReviewStatusHidden    = "hidden"
+)
+
+const NotificationNewReview = "new_review"
+
+// ProductReview adalah ulasan buyer untuk sebuah produk. Satu buyer hanya
+// dapat memberi satu ulasan per produk.
+type ProductReview struct {
+	ID              uint64               `json:"id" gorm:"column:id;primaryKey"`
+	ProductID       int                  `json:"product_id" gorm:"column:product_id"`
+	UserID          uint64               `json:"user_id" gorm:"column:user_id"`
+	ReviewerName    string               `json:"reviewer_name" gorm:"column:reviewer_name;->"` // Diisi dari JOIN users
+	Rating          int                  `json:"rating" gorm:"column:rating"`
+	Body            string               `json:"body" gorm:"column:body"`
+	Status          string               `json:"status" gorm:"column:status;default:published"`
+	FlagCount       int                  `json:"flag_count" gorm:"column:flag_count"`
+	SellerReply     string               `json:"seller_reply,omitempty" gorm:"column:seller_reply"`
+	SellerRepliedAt *time.Time           `json:"seller_replied_at,omitempty" gorm:"column:seller_replied_at"`
+	ModeratedAt     *time.Time           `json:"moderated_at,omitempty" gorm:"column:moderated_at"`
+	Images          []ProductReviewImage `json:"images" gorm:"foreignKey:ReviewID"`
+	CreatedAt       time.Time            `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt       time.Time            `json:"updated_at" gorm:"column:updated_at"`
+}
+
+type ProductReviewImage struct {
+	ID       uint64 `json:"id" gorm:"column:id;primaryKey"`
+	ReviewID uint64 `json:"review_id" gorm:"column:review_id"`
+	Image    string `json:"image" gorm:"column:image"`
+}
+
+// ProductReviewFlag adalah laporan pengguna atas ulasan yang tidak pantas
+type ProductReviewFlag struct {
+	ID        uint64    `json:"id" gorm:"column:id;primaryKey"`
+	ReviewID  uint64    `json:"review_id" gorm:"column:review_id"`
+	UserID    uint64    `json:"user_id" gorm:"column:user_id"`
+	Reason    string    `json:"reason" gorm:"column:reason"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// RatingCount adalah jumlah ulasan tampil untuk satu nilai rating
+type RatingCount struct {
+	Rating int   `gorm:"column:rating"`
+	Count  int64 `gorm:"column:count"`
+}
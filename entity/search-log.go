@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Sumber pencarian yang dicatat di search log
+const (
+	SearchSourceProduct = "product"
+	SearchSourceArticle = "article"
+)
+
+// SearchLog mencatat satu pencarian beserta jumlah hasilnya. ActorKey adalah
+// akun user atau hash IP pencari, dipakai untuk dedupe dan validasi klik.
+type SearchLog struct {
+	ID              uint64    `json:"id" gorm:"column:id;primaryKey"`
+	Source          string    `json:"source" gorm:"column:source"`
+	Query           string    `json:"query" gorm:"column:query"`
+	NormalizedQuery string    `json:"normalized_query" gorm:"column:normalized_query"`
+	ResultCount     int64     `json:"result_count" gorm:"column:result_count"`
+	ActorKey        string    `json:"-" gorm:"column:actor_key"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// SearchClick mencatat klik pada hasil pencarian. TargetID adalah ID produk
+// atau artikel; StoreID diisi untuk klik produk agar bisa dilaporkan per toko.
+// SearchLogID merujuk ke pencarian yang menampilkan hasil tersebut.
+type SearchClick struct {
+	ID              uint64    `json:"id" gorm:"column:id;primaryKey"`
+	SearchLogID     uint64    `json:"search_log_id" gorm:"column:search_log_id"`
+	Source          string    `json:"source" gorm:"column:source"`
+	NormalizedQuery string    `json:"normalized_query" gorm:"column:normalized_query"`
+	TargetID        uint64    `json:"target_id" gorm:"column:target_id"`
+	StoreID         int       `json:"store_id" gorm:"column:store_id"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// SearchQueryStat adalah agregat pencarian per kata kunci
+type SearchQueryStat struct {
+	Query          string    `gorm:"column:query"`
+	Searches       int64     `gorm:"column:searches"`
+	Searchers      int64     `gorm:"column:searchers"`
+	AvgResults     float64   `gorm:"column:avg_results"`
+	Clicks         int64     `gorm:"column:clicks"`
+	LastSearchedAt time.Time `gorm:"column:last_searched_at"`
+}
@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Store struct {
 	ID uint64 `json:"id" gorm:"column:id"`
@@ -8,9 +12,15 @@ type Store struct {
 	Description string `json:"description" gorm:"column:description"`
 	Whatsapp string `json:"whatsapp" gorm:"column:whatsapp"`
 	Alamat string `json:"alamat" gorm:"column:alamat"`
+	// Alamat terstruktur, dipakai sebagai asal pengiriman untuk ongkir
+	Province string `json:"province" gorm:"column:province"`
+	City string `json:"city" gorm:"column:city"`
+	District string `json:"district" gorm:"column:district"`
+	PostalCode string `json:"postal_code" gorm:"column:postal_code"`
 	UserID int `json:"user_id" gorm:"column:user_id"`
 	Avatar string `json:"avatar" gorm:"column:avatar"`
 	Banner string `json:"banner" gorm:"column:banner"`
 	CreatedAt   time.Time `json:"created_At" gorm:"column:created_at"`
     UpdatedAt   time.Time `json:"updated_At" gorm:"column:updated_at"`
+    DeletedAt   gorm.DeletedAt `json:"deleted_At,omitempty" gorm:"column:deleted_at;index"`
 }
\ No newline at end of file
@@ -2,6 +2,12 @@ package entity
 
 import "time"
 
+// Role user
+const (
+	RoleAdmin  = "admin"
+	RoleSeller = "penjual"
+)
+
 type User struct {
 	ID        uint64    `json:"id"`
 	Name      string    `json:"name"`
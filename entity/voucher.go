@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Status pemakaian voucher
+const (
+	RedemptionRedeemed = "redeemed"
+	RedemptionReleased = "released"
+)
+
+// Voucher adalah kode potongan yang dimasukkan buyer saat checkout. Voucher
+// toko (StoreID terisi) hanya berlaku untuk belanja di toko itu; voucher
+// platform (StoreID kosong) berlaku untuk semua toko.
+type Voucher struct {
+	ID            uint64    `json:"id" gorm:"column:id;primaryKey"`
+	Code          string    `json:"code" gorm:"column:code"`
+	StoreID       *int      `json:"store_id,omitempty" gorm:"column:store_id"`
+	Description   string    `json:"description" gorm:"column:description"`
+	DiscountType  string    `json:"discount_type" gorm:"column:discount_type"` // percent atau fixed, sama dengan Promotion
+	DiscountValue float64   `json:"discount_value" gorm:"column:discount_value"`
+	MaxDiscount   *float64  `json:"max_discount,omitempty" gorm:"column:max_discount"`
+	MinSpend      float64   `json:"min_spend" gorm:"column:min_spend"`
+	PerUserLimit  int       `json:"per_user_limit" gorm:"column:per_user_limit"`
+	UsageLimit    *int      `json:"usage_limit,omitempty" gorm:"column:usage_limit"` // Kosong berarti tanpa batas total
+	UsedCount     int       `json:"used_count" gorm:"column:used_count"`
+	StartsAt      time.Time `json:"starts_at" gorm:"column:starts_at"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"column:expires_at"`
+	Active        bool      `json:"active" gorm:"column:active"`
+	CreatedBy     uint64    `json:"created_by" gorm:"column:created_by"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// IsPlatform menandai voucher platform yang dibuat admin
+func (v Voucher) IsPlatform() bool {
+	return v.StoreID == nil
+}
+
+// Discount menghitung potongan untuk subtotal belanja, dibulatkan ke rupiah.
+// Potongan tidak pernah melebihi subtotal.
+func (v Voucher) Discount(subtotal float64) float64 {
+	if subtotal <= 0 {
+		return 0
+	}
+
+	discount := v.DiscountValue
+	if v.DiscountType == PromotionTypePercent {
+		discount = subtotal * v.DiscountValue / 100
+	}
+	if v.MaxDiscount != nil && discount > *v.MaxDiscount {
+		discount = *v.MaxDiscount
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+	return math.Round(discount)
+}
+
+// VoucherRedemption mencatat satu pemakaian voucher. Reference menunjuk
+// transaksi pemakai (misalnya nomor order) sehingga pemakaian bisa dilepas
+// jika transaksi batal.
+type VoucherRedemption struct {
+	ID         uint64     `json:"id" gorm:"column:id;primaryKey"`
+	VoucherID  uint64     `json:"voucher_id" gorm:"column:voucher_id"`
+	UserID     uint64     `json:"user_id" gorm:"column:user_id"`
+	Reference  string     `json:"reference" gorm:"column:reference"`
+	Subtotal   float64    `json:"subtotal" gorm:"column:subtotal"`
+	Discount   float64    `json:"discount" gorm:"column:discount"`
+	Status     string     `json:"status" gorm:"column:status"`
+	RedeemedAt time.Time  `json:"redeemed_at" gorm:"column:redeemed_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty" gorm:"column:released_at"`
+	UserName   string     `json:"user_name,omitempty" gorm:"column:user_name;->"`
+}
+
+// VoucherStat adalah ringkasan pemakaian satu voucher untuk laporan
+type VoucherStat struct {
+	VoucherID      uint64     `gorm:"column:voucher_id"`
+	Code           string     `gorm:"column:code"`
+	Redemptions    int64      `gorm:"column:redemptions"`
+	Buyers         int64      `gorm:"column:buyers"`
+	TotalDiscount  float64    `gorm:"column:total_discount"`
+	TotalSubtotal  float64    `gorm:"column:total_subtotal"`
+	LastRedeemedAt *time.Time `gorm:"column:last_redeemed_at"`
+}
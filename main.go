@@ -4,9 +4,15 @@ import (
 	"batik/config"
 	"batik/controller"
 	"batik/middleware"
+	"batik/payment"
 	"batik/repository"
+	"batik/search"
 	"batik/service"
+	"batik/shipping"
 	"batik/utils"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,30 +21,96 @@ import (
 var (
 	db *gorm.DB = config.SetupDatabaseConnection()
 
+	// Search index
+	productSearchIndex search.SearchIndex = search.NewIndex(search.NewIndonesianAnalyzer())
+	articleSearchIndex search.SearchIndex = search.NewIndex(search.NewIndonesianAnalyzer())
+
+	// Payment gateway dipilih lewat PAYMENT_PROVIDER. Baru tersedia provider
+	// tiruan (mock); provider sungguhan cukup mengimplementasikan
+	// payment.PaymentProvider dan didaftarkan di payment.NewProvider.
+	paymentProvider payment.PaymentProvider = newPaymentProvider()
+
+	// Ongkir dihitung dari tabel tarif lokal
+	shippingRateProvider shipping.ShippingRateProvider = shipping.NewTableRateProvider(nil)
+
 	// Repo
 	userRepository    repository.UserRepository    = repository.NewUserRepository(db)
-	articleRepository repository.ArticleRepository = repository.NewArticleRepository(db)
+	articleRepository repository.ArticleRepository = repository.NewArticleRepository(db, articleSearchIndex)
 	storeRepository repository.StoreRepository = repository.NewStoreRepository(db)
-	productRepository repository.ProductRepository = repository.NewProductRepository(db)
+	productRepository repository.ProductRepository = repository.NewProductRepository(db, productSearchIndex)
 	productImageRepository repository.ProductImageRepository = repository.NewProductImageRepository(db)
 	productCategoryRepository repository.ProductCategoryRepository = repository.NewProductCategoryRepository(db)
+	inventoryRepository repository.InventoryRepository = repository.NewInventoryRepository(db)
+	notificationRepository repository.NotificationRepository = repository.NewNotificationRepository(db)
+	attributeRepository repository.AttributeRepository = repository.NewAttributeRepository(db)
+	suggestionRepository repository.SuggestionRepository = repository.NewSuggestionRepository(db)
+	searchLogRepository repository.SearchLogRepository = repository.NewSearchLogRepository(db)
+	reviewRepository repository.ReviewRepository = repository.NewReviewRepository(db)
+	favoriteRepository repository.FavoriteRepository = repository.NewFavoriteRepository(db)
+	wishlistRepository repository.WishlistRepository = repository.NewWishlistRepository(db)
+	questionRepository repository.QuestionRepository = repository.NewQuestionRepository(db)
+	relatedProductRepository repository.RelatedProductRepository = repository.NewRelatedProductRepository(db)
+	productViewRepository repository.ProductViewRepository = repository.NewProductViewRepository(db)
+	productAlertRepository repository.ProductAlertRepository = repository.NewProductAlertRepository(db)
+	promotionRepository repository.PromotionRepository = repository.NewPromotionRepository(db)
+	voucherRepository repository.VoucherRepository = repository.NewVoucherRepository(db)
+	cartRepository repository.CartRepository = repository.NewCartRepository(db)
+	orderRepository repository.OrderRepository = repository.NewOrderRepository(db)
+	paymentRepository repository.PaymentRepository = repository.NewPaymentRepository(db)
 
 	// Service
 	jwtService     service.JWTService     = service.NewJWTService()
 	userService    service.UserService    = service.NewUserService(userRepository)
 	authService    service.AuthService    = service.NewAuthServie(userRepository)
-	articleService service.ArticleService = service.NewArticleService(articleRepository)
-	storeService service.StoreService = service.NewStoreService(storeRepository)
-	productService service.ProductService = service.NewProductService(productRepository, productImageRepository)
-	productCategoryService service.ProductCategoryService = service.NewProductCategoryService(productCategoryRepository)
+	suggestService service.SuggestService = service.NewSuggestService(suggestionRepository, searchLogRepository)
+	searchAnalyticsService service.SearchAnalyticsService = service.NewSearchAnalyticsService(searchLogRepository, productRepository)
+	searchIndexService service.SearchIndexService = service.NewSearchIndexService(productSearchIndex, articleSearchIndex, productRepository, articleRepository)
+	articleService service.ArticleService = service.NewArticleService(articleRepository, searchIndexService)
+	storeService service.StoreService = service.NewStoreService(storeRepository, searchIndexService)
+	attributeService service.AttributeService = service.NewAttributeService(attributeRepository)
+	productService service.ProductService = service.NewProductService(productRepository, productImageRepository, attributeService, searchIndexService, productAlertService)
+	productCategoryService service.ProductCategoryService = service.NewProductCategoryService(productCategoryRepository, attributeService, searchIndexService)
+	notificationService service.NotificationService = service.NewNotificationService(notificationRepository)
+	inventoryService service.InventoryService = service.NewInventoryService(inventoryRepository, productRepository, storeRepository, notificationService, productAlertService)
+	reviewService service.ReviewService = service.NewReviewService(reviewRepository, productRepository, storeRepository, notificationService)
+	favoriteService service.FavoriteService = service.NewFavoriteService(favoriteRepository, productRepository)
+	wishlistService service.WishlistService = service.NewWishlistService(wishlistRepository, productRepository)
+	questionService service.QuestionService = service.NewQuestionService(questionRepository, productRepository, storeRepository, notificationService)
+	relatedProductService service.RelatedProductService = service.NewRelatedProductService(relatedProductRepository, productRepository)
+	productViewService service.ProductViewService = service.NewProductViewService(productViewRepository)
+	productAlertService service.ProductAlertService = service.NewProductAlertService(productAlertRepository, productRepository, inventoryRepository, notificationService)
+	promotionService service.PromotionService = service.NewPromotionService(promotionRepository, storeRepository, productCategoryRepository, productAlertService)
+	voucherService service.VoucherService = service.NewVoucherService(voucherRepository, storeRepository)
+	cartService service.CartService = service.NewCartService(cartRepository, productRepository, inventoryRepository, storeRepository)
+	shippingService service.ShippingService = service.NewShippingService(shippingRateProvider, productRepository, storeRepository, cartService)
+	orderService service.OrderService = service.NewOrderService(orderRepository, storeRepository, cartService, inventoryService, voucherService, shippingService, notificationService)
+	paymentService service.PaymentService = service.NewPaymentService(paymentRepository, orderService, paymentProvider)
+	retentionService service.RetentionService = service.NewRetentionService(productService, productRepository, productImageRepository, storeRepository, articleRepository)
 
 	// Controller
 	userController    controller.UserController    = controller.NewUserController(userService, jwtService)
-	authController    controller.AuthController    = controller.NewAuthController(authService, jwtService)
-	articleController controller.ArticleController = controller.NewArticleController(articleService, jwtService)
+	authController    controller.AuthController    = controller.NewAuthController(authService, jwtService, productViewService, cartService)
+	articleController controller.ArticleController = controller.NewArticleController(articleService, searchAnalyticsService, jwtService, authService)
 	storeController controller.StoreController = controller.NewStoreController(storeService, jwtService, authService)
-	productController controller.ProductController = controller.NewProductController(productService, storeService, jwtService, authService)
-	productCategoryController controller.ProductCategoryController = controller.NewProductCategoryController(productCategoryService)
+	productController controller.ProductController = controller.NewProductController(productService, storeService, productViewService, searchAnalyticsService, jwtService, authService)
+	productCategoryController controller.ProductCategoryController = controller.NewProductCategoryController(productCategoryService, jwtService, authService)
+	inventoryController controller.InventoryController = controller.NewInventoryController(inventoryService, productService, storeService, jwtService, authService)
+	notificationController controller.NotificationController = controller.NewNotificationController(notificationService, jwtService, authService)
+	trashController controller.TrashController = controller.NewTrashController(productService, storeService, articleService, jwtService, authService)
+	attributeController controller.AttributeController = controller.NewAttributeController(attributeService)
+	searchController controller.SearchController = controller.NewSearchController(suggestService, searchAnalyticsService, storeService, jwtService, authService)
+	reviewController controller.ReviewController = controller.NewReviewController(reviewService, productService, storeService, jwtService, authService)
+	favoriteController controller.FavoriteController = controller.NewFavoriteController(favoriteService, wishlistService, storeService, jwtService, authService)
+	questionController controller.QuestionController = controller.NewQuestionController(questionService, productService, storeService, jwtService, authService)
+	relatedProductController controller.RelatedProductController = controller.NewRelatedProductController(relatedProductService)
+	productViewController controller.ProductViewController = controller.NewProductViewController(productViewService, storeService, jwtService, authService)
+	productAlertController controller.ProductAlertController = controller.NewProductAlertController(productAlertService, jwtService, authService)
+	promotionController controller.PromotionController = controller.NewPromotionController(promotionService, storeService, jwtService, authService)
+	voucherController controller.VoucherController = controller.NewVoucherController(voucherService, storeService, jwtService, authService)
+	cartController controller.CartController = controller.NewCartController(cartService, jwtService, authService)
+	orderController controller.OrderController = controller.NewOrderController(orderService, storeService, jwtService, authService)
+	paymentController controller.PaymentController = controller.NewPaymentController(paymentService, jwtService, authService)
+	shippingController controller.ShippingController = controller.NewShippingController(shippingService, jwtService, authService)
 
 )
 
@@ -76,9 +148,58 @@ func CORSMiddleware() gin.HandlerFunc {
 
 func main() {
 	defer config.CloseDatabaseConnection(db)
+	if err := utils.CheckCursorSecret(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 	r := gin.Default()
 	r.Use(CORSMiddleware())
 
+	// Search index dibangun dari database saat start, lalu diperbarui per
+	// perubahan data dan dibangun ulang berkala untuk menutup selisih
+	if err := searchIndexService.Rebuild(); err != nil {
+		log.Printf("❌ Gagal membangun search index: %v", err)
+	}
+	if err := suggestService.Refresh(); err != nil {
+		log.Printf("❌ Gagal membangun saran pencarian: %v", err)
+	}
+	// Produk terkait dihitung di background; selama belum selesai, detail
+	// produk memakai produk terbaru di kategori yang sama
+	go func() {
+		if err := relatedProductService.Refresh(); err != nil {
+			log.Printf("❌ Gagal menghitung produk terkait: %v", err)
+		}
+	}()
+
+	if err := promotionService.Refresh(); err != nil {
+		log.Printf("❌ Gagal menghitung harga promosi: %v", err)
+	}
+	if err := productService.RefreshShuffleKeys(); err != nil {
+		log.Printf("❌ Gagal menyimpan kunci urutan acak produk: %v", err)
+	}
+
+	// Background jobs
+	utils.RunEvery("expire-stock-reservations", time.Minute, func() error {
+		_, err := inventoryService.ExpireReservations()
+		return err
+	})
+	utils.RunEvery("publish-scheduled-products", time.Minute, func() error {
+		_, err := productService.PublishScheduledProducts()
+		return err
+	})
+	utils.RunEvery("refresh-shuffle-keys", 10*time.Minute, productService.RefreshShuffleKeys)
+	utils.RunEvery("purge-trash", 6*time.Hour, retentionService.PurgeExpired)
+	utils.RunEvery("rebuild-search-index", 6*time.Hour, searchIndexService.Rebuild)
+	utils.RunEvery("refresh-search-suggestions", 30*time.Minute, suggestService.Refresh)
+	utils.RunEvery("refresh-related-products", 6*time.Hour, relatedProductService.Refresh)
+	utils.RunEvery("flush-product-views", 5*time.Second, productViewService.Flush)
+	utils.RunEvery("purge-product-views", 24*time.Hour, productViewService.PurgeOld)
+	utils.RunEvery("refresh-promotion-prices", time.Minute, promotionService.Refresh)
+	utils.RunEvery("purge-visitor-carts", 24*time.Hour, cartService.PurgeStale)
+	utils.RunEvery("cancel-unpaid-orders", time.Minute, orderService.CancelOverdue)
+	utils.RunEvery("settle-orders", time.Minute, orderService.SettlePending)
+	utils.RunEvery("complete-delivered-orders", 6*time.Hour, orderService.CompleteDelivered)
+	utils.RunEvery("expire-payments", time.Minute, paymentService.ExpirePending)
+	utils.RunEvery("sync-paid-orders", time.Minute, paymentService.SyncPaidOrders)
 
 	// Serve static files (images)
 	// r.Static("/uploads", "./uploads")
@@ -128,6 +249,8 @@ func main() {
 			protected.POST("/store", storeController.CreateStore)
 			protected.PUT("/store/:id", storeController.UpdateStore)
 			protected.GET("/store/:id", storeController.GetStoreByID)
+			protected.DELETE("/store/:id", storeController.DeleteStore)
+			protected.POST("/store/:id/restore", storeController.RestoreStore)
 		}
 	}
 
@@ -140,6 +263,16 @@ func main() {
 		productRoutes.GET("/product/:slug", productController.GetDetailProduct)
 		productRoutes.GET("/products/category/:slug", productController.GetAllPublicProductByCategory)
 		productRoutes.GET("/products/store/:id", productController.GetPublicProductsByStoreID)
+		productRoutes.GET("/product/:slug/reviews", reviewController.GetProductReviews)
+		productRoutes.GET("/product/:slug/questions", questionController.GetProductQuestions)
+		productRoutes.GET("/product/:slug/related", relatedProductController.GetRelated)
+		productRoutes.GET("/product/:slug/price-history", productAlertController.GetPriceHistory)
+		productRoutes.GET("/campaigns", promotionController.GetRunningCampaigns)
+		productRoutes.GET("/payment-methods", paymentController.GetMethods)
+		productRoutes.GET("/product/:slug/shipping-rates", shippingController.GetProductRates)
+
+		// Webhook payment gateway diverifikasi lewat tanda tangan, bukan JWT
+		productRoutes.POST("/payments/webhook", paymentController.Webhook)
 
 		protected := productRoutes.Group("", middleware.AuthorizeJWT(jwtService))
 		{
@@ -149,14 +282,173 @@ func main() {
 			protected.GET("/my-store/:id/products", productController.GetProductsByStoreID)
 			protected.PUT("/product/:slug", productController.UpdateProduct)
 			protected.DELETE("/product/:slug", productController.DeleteProduct)
+			protected.PUT("/product/:slug/status", productController.ChangeProductStatus)
+			protected.POST("/product/:slug/restore", productController.RestoreProduct)
+			protected.GET("/my-store/:id/trash", productController.GetProductTrash)
 			protected.POST("/product/image", productController.AddProductImage)
 			protected.DELETE("/product/image/:id", productController.DeleteProductImage)
+
+			// Inventory
+			protected.GET("/product/:slug/inventory", inventoryController.GetInventory)
+			protected.POST("/product/:slug/inventory/adjust", inventoryController.AdjustStock)
+			protected.PUT("/product/:slug/inventory/threshold", inventoryController.SetThreshold)
+			protected.GET("/my-store/:id/low-stock", inventoryController.GetLowStock)
+
+			// Search analytics
+			protected.GET("/my-store/:id/search-report", searchController.GetStoreReport)
+			protected.GET("/my-store/:id/view-report", productViewController.GetStoreViewReport)
+
+			// Ulasan produk
+			protected.POST("/product/:slug/reviews", reviewController.CreateReview)
+			protected.PUT("/reviews/:id", reviewController.UpdateReview)
+			protected.DELETE("/reviews/:id", reviewController.DeleteReview)
+			protected.PUT("/reviews/:id/reply", reviewController.ReplyReview)
+			protected.POST("/reviews/:id/flag", reviewController.FlagReview)
+			protected.GET("/my-store/:id/reviews", reviewController.GetStoreReviews)
+
+			// Tanya jawab produk
+			protected.POST("/product/:slug/questions", questionController.AskQuestion)
+			protected.DELETE("/questions/:id", questionController.DeleteQuestion)
+			protected.PUT("/questions/:id/answer", questionController.AnswerQuestion)
+			protected.POST("/questions/:id/upvote", questionController.Upvote)
+			protected.DELETE("/questions/:id/upvote", questionController.RemoveUpvote)
+			protected.POST("/questions/:id/flag", questionController.FlagQuestion)
+			protected.GET("/my-store/:id/questions", questionController.GetStoreQuestions)
+
+			// Favorit
+			protected.GET("/product/:slug/favorite", favoriteController.GetFavoriteStatus)
+			protected.POST("/product/:slug/favorite", favoriteController.Favorite)
+			protected.DELETE("/product/:slug/favorite", favoriteController.Unfavorite)
+			protected.GET("/my-store/:id/favorites", favoriteController.GetStoreFavoriteStats)
+
+			// Alert harga turun dan stok tersedia kembali
+			protected.POST("/product/:slug/alerts", productAlertController.Subscribe)
+
+			// Promosi toko
+			protected.GET("/my-store/:id/promotions", promotionController.GetStorePromotions)
+			protected.POST("/my-store/:id/promotions", promotionController.CreateStorePromotion)
+			protected.PUT("/promotions/:id", promotionController.UpdateStorePromotion)
+			protected.DELETE("/promotions/:id", promotionController.DeleteStorePromotion)
+
+			// Voucher
+			protected.POST("/vouchers/validate", voucherController.ValidateVoucher)
+			protected.GET("/my-store/:id/vouchers", voucherController.GetStoreVouchers)
+			protected.POST("/my-store/:id/vouchers", voucherController.CreateStoreVoucher)
+			protected.GET("/my-store/:id/voucher-report", voucherController.GetStoreReport)
+			protected.PUT("/vouchers/:id", voucherController.UpdateStoreVoucher)
+			protected.DELETE("/vouchers/:id", voucherController.DeleteStoreVoucher)
+			protected.GET("/vouchers/:id/redemptions", voucherController.GetRedemptions)
+
+			// Order
+			protected.POST("/orders/checkout", orderController.Checkout)
+			protected.GET("/orders", orderController.GetMyOrders)
+			protected.GET("/orders/:number", orderController.GetMyOrder)
+			protected.PUT("/orders/:number/status", orderController.UpdateMyOrderStatus)
+			protected.GET("/my-store/:id/orders", orderController.GetStoreOrders)
+			protected.GET("/my-store/:id/orders/:number", orderController.GetStoreOrder)
+			protected.PUT("/my-store/:id/orders/:number/status", orderController.UpdateStoreOrderStatus)
+
+			// Pembayaran
+			protected.POST("/orders/:number/payments", paymentController.CreatePayment)
+			protected.GET("/orders/:number/payments", paymentController.GetOrderPayments)
+			// Simulasi webhook hanya ada jika provider tiruan dipilih
+			if _, ok := paymentProvider.(payment.Simulator); ok {
+				protected.POST("/payments/:id/simulate", paymentController.Simulate)
+			}
 		}
 	}
 
+	adminRoutes := r.Group("api/admin", middleware.AuthorizeJWT(jwtService))
+	{
+		adminRoutes.GET("/trash", trashController.GetAdminTrash)
+		adminRoutes.GET("/search-report", searchController.GetAdminReport)
+		adminRoutes.GET("/reviews", reviewController.GetModerationQueue)
+		adminRoutes.PUT("/reviews/:id/moderate", reviewController.ModerateReview)
+		adminRoutes.GET("/questions", questionController.GetModerationQueue)
+		adminRoutes.PUT("/questions/:id/moderate", questionController.ModerateQuestion)
+
+		// Kampanye platform
+		adminRoutes.GET("/campaigns", promotionController.GetCampaigns)
+		adminRoutes.POST("/campaigns", promotionController.CreateCampaign)
+		adminRoutes.PUT("/campaigns/:id", promotionController.UpdateCampaign)
+		adminRoutes.DELETE("/campaigns/:id", promotionController.DeleteCampaign)
+
+		// Voucher platform
+		adminRoutes.GET("/vouchers", voucherController.GetPlatformVouchers)
+		adminRoutes.POST("/vouchers", voucherController.CreatePlatformVoucher)
+		adminRoutes.PUT("/vouchers/:id", voucherController.UpdatePlatformVoucher)
+		adminRoutes.DELETE("/vouchers/:id", voucherController.DeletePlatformVoucher)
+		adminRoutes.GET("/voucher-report", voucherController.GetPlatformReport)
+
+		// Order
+		adminRoutes.PUT("/orders/:number/status", orderController.UpdateOrderStatusByAdmin)
+
+		// Kategori
+		adminRoutes.POST("/categories", productCategoryController.CreateCategory)
+		adminRoutes.PUT("/categories/reorder", productCategoryController.ReorderCategories)
+		adminRoutes.PUT("/categories/:id", productCategoryController.UpdateCategory)
+		adminRoutes.DELETE("/categories/:id", productCategoryController.DeleteCategory)
+		adminRoutes.PUT("/categories/:id/attributes", productCategoryController.UpdateCategorySchema)
+	}
+
+	meRoutes := r.Group("api/me", middleware.AuthorizeJWT(jwtService))
+	{
+		meRoutes.GET("/favorites", favoriteController.GetMyFavorites)
+		meRoutes.GET("/wishlists", favoriteController.GetMyWishlists)
+		meRoutes.POST("/wishlists", favoriteController.CreateWishlist)
+		meRoutes.GET("/wishlists/:id", favoriteController.GetWishlist)
+		meRoutes.PUT("/wishlists/:id", favoriteController.UpdateWishlist)
+		meRoutes.DELETE("/wishlists/:id", favoriteController.DeleteWishlist)
+		meRoutes.POST("/wishlists/:id/items", favoriteController.AddWishlistItem)
+		meRoutes.DELETE("/wishlists/:id/items/:product_id", favoriteController.RemoveWishlistItem)
+		meRoutes.GET("/alerts", productAlertController.GetMyAlerts)
+		meRoutes.DELETE("/alerts/:id", productAlertController.DeleteAlert)
+	}
+
+	// Riwayat terakhir dilihat juga tersedia untuk pengunjung anonim (cookie),
+	// jadi tidak memakai middleware JWT
+	recentRoutes := r.Group("api/me")
+	{
+		recentRoutes.GET("/recently-viewed", productViewController.GetRecentlyViewed)
+		recentRoutes.DELETE("/recently-viewed", productViewController.ClearRecentlyViewed)
+	}
+
+	// Keranjang untuk user login maupun pengunjung anonim (cookie)
+	cartRoutes := r.Group("api/cart")
+	{
+		cartRoutes.GET("", cartController.GetCart)
+		cartRoutes.DELETE("", cartController.ClearCart)
+		cartRoutes.POST("/items", cartController.AddItem)
+		cartRoutes.PUT("/items/:id", cartController.UpdateItem)
+		cartRoutes.DELETE("/items/:id", cartController.RemoveItem)
+		cartRoutes.GET("/shipping-rates", shippingController.GetCartRates)
+	}
+
+	wishlistRoutes := r.Group("api")
+	{
+		wishlistRoutes.GET("/wishlists/shared/:token", favoriteController.GetSharedWishlist)
+	}
+
+	notificationRoutes := r.Group("api", middleware.AuthorizeJWT(jwtService))
+	{
+		notificationRoutes.GET("/notifications", notificationController.GetMyNotifications)
+		notificationRoutes.PUT("/notifications/read-all", notificationController.MarkAllAsRead)
+		notificationRoutes.PUT("/notifications/:id/read", notificationController.MarkAsRead)
+	}
+
+	// Batas request per IP untuk endpoint pencarian publik
+	searchRateLimit := middleware.RateLimit(60, time.Minute)
+	searchRoutes := r.Group("api/search")
+	{
+		searchRoutes.GET("/products", searchRateLimit, productController.SearchProducts)
+		searchRoutes.GET("/suggest", middleware.RateLimit(120, time.Minute), searchController.Suggest)
+		searchRoutes.POST("/click", middleware.RateLimit(30, time.Minute), searchController.RecordClick)
+	}
+
 	productCategory := r.Group("api")
 	{
 		productCategory.GET("/product-category", productCategoryController.GetProductCategory)
+		productCategory.GET("/product-attributes", attributeController.GetAttributes)
 	}
 
 
@@ -168,7 +460,7 @@ func main() {
 		articleRoutes.GET("/latest-articles", articleController.GetLatestArticles)
 		articleRoutes.GET("/articles/:id", articleController.GetArticleByID)
 		articleRoutes.GET("/articles/slug/:slug", articleController.GetArticleBySlug)
-		articleRoutes.GET("/articles/search", articleController.SearchArticles)
+		articleRoutes.GET("/articles/search", searchRateLimit, articleController.SearchArticles)
 		
 		// Protected routes (require JWT authentication)
 		protected := articleRoutes.Group("", middleware.AuthorizeJWT(jwtService))
@@ -176,9 +468,22 @@ func main() {
 			protected.POST("/articles", articleController.CreateArticle)
 			protected.PUT("/articles/:id", articleController.UpdateArticle)
 			protected.DELETE("/articles/:id", articleController.DeleteArticle)
+			protected.POST("/articles/:id/restore", articleController.RestoreArticle)
 			protected.POST("/upload", utils.UploadImage)
 		}
 	}
 
 	r.Run(":1815")
 }
+
+// newPaymentProvider membuat payment gateway dari PAYMENT_PROVIDER dan
+// PAYMENT_WEBHOOK_SECRET. Jika salah satunya kosong atau tidak dikenal,
+// aplikasi tetap berjalan dan endpoint pembayaran membalas 503.
+func newPaymentProvider() payment.PaymentProvider {
+	provider, err := payment.NewProvider(os.Getenv("PAYMENT_PROVIDER"), os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	if err != nil {
+		log.Printf("⚠️ Pembayaran dinonaktifkan: %v", err)
+		return nil
+	}
+	return provider
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"batik/helper"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow adalah hitungan request satu IP dalam satu jendela waktu
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter menghitung request per IP dengan jendela waktu tetap. Data
+// disimpan di memori, jadi batasnya berlaku per instance server.
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+		now:     time.Now,
+	}
+}
+
+// allow mencatat satu request dan mengembalikan false beserta sisa waktu
+// tunggu jika batas jendela saat ini sudah terlampaui
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= l.window {
+		// Buang jendela yang sudah lewat agar map tidak tumbuh terus
+		for k, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.clients[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.clients[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// RateLimit membatasi jumlah request per IP klien dalam satu jendela waktu.
+// Request yang melewati batas dijawab 429 dengan header Retry-After.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		ok, retryAfter := limiter.allow(c.ClientIP())
+		if ok {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		response := helper.BuildErrorResponse("Terlalu banyak permintaan", "Coba lagi beberapa saat lagi", nil)
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+	}
+}
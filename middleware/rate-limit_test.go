@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("1.1.1.1"); !ok {
+			t.Fatalf("request ke-%d harus diizinkan", i+1)
+		}
+	}
+	ok, retryAfter := limiter.allow("1.1.1.1")
+	if ok || retryAfter != time.Minute {
+		t.Errorf("request ke-3: ok=%v retryAfter=%v, ingin ditolak dengan tunggu 1 menit", ok, retryAfter)
+	}
+	if ok, _ := limiter.allow("2.2.2.2"); !ok {
+		t.Error("IP lain tidak boleh ikut dibatasi")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := limiter.allow("1.1.1.1"); !ok {
+		t.Error("jendela baru harus mengizinkan request lagi")
+	}
+	if _, exists := limiter.clients["2.2.2.2"]; exists {
+		t.Error("jendela yang sudah lewat harus dibuang")
+	}
+}
@@ -0,0 +1,59 @@
+-- Inventori per produk/varian, ledger perubahan stok, reservasi, dan notifikasi
+
+CREATE TABLE IF NOT EXISTS product_inventories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    variant VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INT NOT NULL DEFAULT 0,
+    reserved INT NOT NULL DEFAULT 0,
+    low_stock_threshold INT NOT NULL DEFAULT 0,
+    low_stock_notified_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE KEY idx_inventory_product_variant (product_id, variant),
+    CONSTRAINT fk_inventory_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS inventory_adjustments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    inventory_id INT NOT NULL,
+    product_id INT NOT NULL,
+    variant VARCHAR(100) NOT NULL DEFAULT '',
+    quantity_change INT NOT NULL,
+    balance INT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INT NOT NULL DEFAULT 0,
+    created_at DATETIME NULL,
+    INDEX idx_inventory_adjustments_inventory (inventory_id),
+    INDEX idx_inventory_adjustments_product (product_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    inventory_id INT NOT NULL,
+    product_id INT NOT NULL,
+    variant VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    user_id INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    INDEX idx_stock_reservations_inventory (inventory_id),
+    INDEX idx_stock_reservations_reference (reference),
+    INDEX idx_stock_reservations_status_expires (status, expires_at)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT,
+    link VARCHAR(255) NOT NULL DEFAULT '',
+    read_at DATETIME NULL,
+    created_at DATETIME NULL,
+    INDEX idx_notifications_user (user_id, read_at)
+);
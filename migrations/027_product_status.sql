@@ -0,0 +1,6 @@
+-- Status siklus hidup produk. Produk lama dianggap sudah published.
+
+ALTER TABLE products
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' AFTER thumbnail,
+    ADD COLUMN publish_at DATETIME NULL AFTER status,
+    ADD INDEX idx_products_status_publish_at (status, publish_at);
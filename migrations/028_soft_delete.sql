@@ -0,0 +1,8 @@
+-- Soft delete untuk produk, gambar produk, toko, dan artikel.
+-- Baris dengan deleted_at terisi dihapus permanen oleh retention job
+-- setelah TRASH_RETENTION_DAYS hari (default 30).
+
+ALTER TABLE products ADD COLUMN deleted_at DATETIME NULL, ADD INDEX idx_products_deleted_at (deleted_at);
+ALTER TABLE product_images ADD COLUMN deleted_at DATETIME NULL, ADD INDEX idx_product_images_deleted_at (deleted_at);
+ALTER TABLE stores ADD COLUMN deleted_at DATETIME NULL, ADD INDEX idx_stores_deleted_at (deleted_at);
+ALTER TABLE articles ADD COLUMN deleted_at DATETIME NULL, ADD INDEX idx_articles_deleted_at (deleted_at);
@@ -0,0 +1,47 @@
+-- Atribut batik terstruktur (teknik, motif, pewarna, asal daerah, bahan)
+-- yang bisa dipakai sebagai filter facet.
+
+CREATE TABLE attributes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'enum',
+    options JSON NULL,
+    filterable TINYINT(1) NOT NULL DEFAULT 1,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY idx_attributes_code (code)
+);
+
+CREATE TABLE category_attributes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    category_id INT NOT NULL,
+    attribute_id INT NOT NULL,
+    UNIQUE KEY idx_category_attribute (category_id, attribute_id),
+    CONSTRAINT fk_category_attributes_attribute FOREIGN KEY (attribute_id) REFERENCES attributes (id) ON DELETE CASCADE
+);
+
+CREATE TABLE product_attributes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    attribute_id INT NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    value VARCHAR(100) NOT NULL,
+    UNIQUE KEY idx_product_attribute (product_id, attribute_id),
+    INDEX idx_product_attribute_code_value (code, value),
+    CONSTRAINT fk_product_attributes_attribute FOREIGN KEY (attribute_id) REFERENCES attributes (id) ON DELETE CASCADE
+);
+
+INSERT INTO attributes (code, name, type, options, filterable, sort_order) VALUES
+    ('technique', 'Teknik', 'enum', '["tulis", "cap", "kombinasi", "printing"]', 1, 1),
+    ('motif', 'Motif', 'enum', '["parang", "kawung", "mega-mendung", "truntum", "sekar-jagad", "sidomukti", "sidoluhur", "lereng", "ceplok", "tujuh-rupa", "lainnya"]', 1, 2),
+    ('dye_type', 'Jenis Pewarna', 'enum', '["alami", "sintetis"]', 1, 3),
+    ('origin_region', 'Asal Daerah', 'text', NULL, 1, 4),
+    ('fabric', 'Bahan', 'enum', '["katun", "primisima", "prima", "sutra", "rayon", "dobby", "linen"]', 1, 5);
+
+-- Semua atribut berlaku untuk semua kategori yang sudah ada
+INSERT INTO category_attributes (category_id, attribute_id)
+SELECT category_catalog.id, attributes.id
+FROM category_catalog
+CROSS JOIN attributes;
@@ -0,0 +1,24 @@
+-- Log pencarian produk/artikel dan klik dari hasil pencarian untuk laporan
+-- kata kunci teratas, kata kunci tanpa hasil, dan click-through
+
+CREATE TABLE IF NOT EXISTS search_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    query VARCHAR(255) NOT NULL,
+    normalized_query VARCHAR(255) NOT NULL,
+    result_count INT NOT NULL DEFAULT 0,
+    created_at DATETIME NULL,
+    INDEX idx_search_logs_query (source, normalized_query, created_at),
+    INDEX idx_search_logs_created (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS search_clicks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    normalized_query VARCHAR(255) NOT NULL,
+    target_id BIGINT UNSIGNED NOT NULL,
+    store_id INT NOT NULL DEFAULT 0,
+    created_at DATETIME NULL,
+    INDEX idx_search_clicks_query (source, normalized_query, created_at),
+    INDEX idx_search_clicks_store (store_id, created_at)
+);
@@ -0,0 +1,5 @@
+-- Kategori bertingkat: produk di subkategori ikut tampil di kategori induknya
+
+ALTER TABLE category_catalog
+    ADD COLUMN parent_id INT NULL,
+    ADD INDEX idx_category_catalog_parent (parent_id);
@@ -0,0 +1,11 @@
+-- Kategori dikelola admin lewat API: ikon, urutan tampil, dan timestamp
+
+ALTER TABLE category_catalog
+    ADD COLUMN icon VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at DATETIME NULL,
+    ADD COLUMN updated_at DATETIME NULL;
+
+UPDATE category_catalog SET sort_order = id, created_at = NOW(), updated_at = NOW();
+
+ALTER TABLE category_catalog ADD UNIQUE INDEX idx_category_catalog_slug (slug);
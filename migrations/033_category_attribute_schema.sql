@@ -0,0 +1,44 @@
+-- Skema atribut per kategori: wajib/tidak, nilai yang diizinkan, dan batas
+-- nilai angka. Menambah atribut khusus kategori: panjang kain, ukuran kemeja,
+-- dan dimensi tas.
+
+ALTER TABLE attributes ADD COLUMN unit VARCHAR(20) NOT NULL DEFAULT '';
+
+ALTER TABLE category_attributes
+    ADD COLUMN required TINYINT(1) NOT NULL DEFAULT 0,
+    ADD COLUMN allowed_values JSON NULL,
+    ADD COLUMN min_value DECIMAL(12,2) NULL,
+    ADD COLUMN max_value DECIMAL(12,2) NULL,
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0;
+
+UPDATE category_attributes
+JOIN attributes ON attributes.id = category_attributes.attribute_id
+SET category_attributes.sort_order = attributes.sort_order;
+
+INSERT INTO attributes (code, name, type, options, unit, filterable, sort_order) VALUES
+    ('fabric_length', 'Panjang Kain', 'number', NULL, 'm', 0, 10),
+    ('sizes', 'Ukuran', 'multi_enum', '["xs", "s", "m", "l", "xl", "xxl", "xxxl"]', '', 0, 11),
+    ('bag_length', 'Panjang Tas', 'number', NULL, 'cm', 0, 12),
+    ('bag_width', 'Lebar Tas', 'number', NULL, 'cm', 0, 13),
+    ('bag_height', 'Tinggi Tas', 'number', NULL, 'cm', 0, 14);
+
+-- Kain wajib mencantumkan panjang (meter)
+INSERT INTO category_attributes (category_id, attribute_id, required, min_value, max_value, sort_order)
+SELECT category_catalog.id, attributes.id, 1, 0.5, 20, attributes.sort_order
+FROM category_catalog
+JOIN attributes ON attributes.code = 'fabric_length'
+WHERE category_catalog.slug = 'kain' OR category_catalog.slug LIKE 'kain-%' OR category_catalog.slug LIKE '%-kain' OR category_catalog.slug LIKE '%-kain-%';
+
+-- Kemeja wajib mencantumkan ukuran yang tersedia
+INSERT INTO category_attributes (category_id, attribute_id, required, sort_order)
+SELECT category_catalog.id, attributes.id, 1, attributes.sort_order
+FROM category_catalog
+JOIN attributes ON attributes.code = 'sizes'
+WHERE category_catalog.slug LIKE '%kemeja%';
+
+-- Tas wajib mencantumkan dimensi (cm)
+INSERT INTO category_attributes (category_id, attribute_id, required, min_value, max_value, sort_order)
+SELECT category_catalog.id, attributes.id, 1, 1, 200, attributes.sort_order
+FROM category_catalog
+JOIN attributes ON attributes.code IN ('bag_length', 'bag_width', 'bag_height')
+WHERE category_catalog.slug = 'tas' OR category_catalog.slug LIKE 'tas-%' OR category_catalog.slug LIKE '%-tas' OR category_catalog.slug LIKE '%-tas-%';
@@ -0,0 +1,42 @@
+-- Ulasan produk: rating 1-5, teks, foto, balasan penjual, dan laporan
+-- (flag) dari pengguna untuk moderasi. Rata-rata rating dan jumlah ulasan
+-- disimpan di products agar listing bisa menampilkan dan mengurutkannya.
+
+ALTER TABLE products
+    ADD COLUMN rating_avg DECIMAL(3,2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0,
+    ADD INDEX idx_products_rating (rating_avg, rating_count);
+
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    rating TINYINT NOT NULL,
+    body TEXT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'published',
+    flag_count INT NOT NULL DEFAULT 0,
+    seller_reply TEXT NULL,
+    seller_replied_at DATETIME NULL,
+    moderated_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_product_reviews_user (product_id, user_id),
+    INDEX idx_product_reviews_status (product_id, status, created_at),
+    INDEX idx_product_reviews_flagged (status, flag_count)
+);
+
+CREATE TABLE IF NOT EXISTS product_review_images (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    review_id BIGINT UNSIGNED NOT NULL,
+    image VARCHAR(255) NOT NULL,
+    INDEX idx_product_review_images_review (review_id)
+);
+
+CREATE TABLE IF NOT EXISTS product_review_flags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    review_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_product_review_flags_user (review_id, user_id)
+);
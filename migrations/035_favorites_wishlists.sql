@@ -0,0 +1,35 @@
+-- Favorit dan wishlist buyer. Jumlah favorit disimpan di products untuk
+-- analitik penjual dan sebagai sinyal popularitas di urutan katalog.
+
+ALTER TABLE products
+    ADD COLUMN favorite_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS product_favorites (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    product_id INT NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_product_favorites_user (user_id, product_id),
+    INDEX idx_product_favorites_product (product_id)
+);
+
+CREATE TABLE IF NOT EXISTS wishlists (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    share_token VARCHAR(32) NOT NULL,
+    shared TINYINT(1) NOT NULL DEFAULT 0,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_wishlists_share_token (share_token),
+    INDEX idx_wishlists_user (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    wishlist_id BIGINT UNSIGNED NOT NULL,
+    product_id INT NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_wishlist_items_product (wishlist_id, product_id),
+    INDEX idx_wishlist_items_product_id (product_id)
+);
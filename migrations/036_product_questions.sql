@@ -0,0 +1,38 @@
+-- Tanya jawab produk: pertanyaan publik dari buyer, dijawab pemilik toko,
+-- dengan upvote dan laporan untuk moderasi admin. Status mengikuti ulasan:
+-- published, pending (disembunyikan karena banyak laporan), hidden
+
+CREATE TABLE IF NOT EXISTS product_questions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'published',
+    answer TEXT NULL,
+    answered_by BIGINT UNSIGNED NULL,
+    answered_at DATETIME NULL,
+    upvote_count INT NOT NULL DEFAULT 0,
+    flag_count INT NOT NULL DEFAULT 0,
+    moderated_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    INDEX idx_product_questions_product (product_id, status, answered_at),
+    INDEX idx_product_questions_flagged (flag_count)
+);
+
+CREATE TABLE IF NOT EXISTS product_question_votes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    question_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_product_question_votes_user (question_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS product_question_flags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    question_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_product_question_flags_user (question_id, user_id)
+);
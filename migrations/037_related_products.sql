@@ -0,0 +1,12 @@
+-- Produk terkait dihitung offline oleh background job dan disimpan per
+-- produk, supaya halaman detail cukup membaca satu tabel
+
+CREATE TABLE IF NOT EXISTS related_products (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    related_id INT NOT NULL,
+    score DECIMAL(8,3) NOT NULL DEFAULT 0,
+    computed_at DATETIME NULL,
+    UNIQUE INDEX idx_related_products_pair (product_id, related_id),
+    INDEX idx_related_products_score (product_id, score)
+);
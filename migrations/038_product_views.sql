@@ -0,0 +1,17 @@
+-- Riwayat produk yang dilihat buyer. viewer_key berisi "u:<user_id>" untuk
+-- user login atau "v:<visitor_id>" dari cookie pengunjung anonim; riwayat
+-- anonim dipindahkan ke akun saat login. Data ini juga dipakai sebagai
+-- sinyal produk terkait dan laporan kunjungan toko.
+
+CREATE TABLE IF NOT EXISTS product_views (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    store_id INT NOT NULL,
+    viewer_key VARCHAR(64) NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    viewed_at DATETIME NOT NULL,
+    INDEX idx_product_views_viewer (viewer_key, viewed_at),
+    INDEX idx_product_views_product (product_id, viewed_at),
+    INDEX idx_product_views_store (store_id, viewed_at),
+    INDEX idx_product_views_viewed (viewed_at)
+);
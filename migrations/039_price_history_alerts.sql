@@ -0,0 +1,29 @@
+-- Riwayat harga produk dan langganan notifikasi buyer (harga turun di bawah
+-- target, stok tersedia kembali). Setiap alert hanya dikirim sekali; buyer
+-- bisa berlangganan ulang setelahnya.
+
+CREATE TABLE IF NOT EXISTS product_price_history (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    price DECIMAL(15,2) NOT NULL,
+    previous_price DECIMAL(15,2) NULL,
+    changed_at DATETIME NOT NULL,
+    INDEX idx_product_price_history_product (product_id, changed_at)
+);
+
+-- Harga saat ini menjadi titik awal riwayat
+INSERT INTO product_price_history (product_id, price, previous_price, changed_at)
+SELECT id, harga, NULL, COALESCE(created_at, NOW()) FROM products;
+
+CREATE TABLE IF NOT EXISTS product_alerts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    product_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    target_price DECIMAL(15,2) NULL,
+    notified_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_product_alerts_user (user_id, product_id, type),
+    INDEX idx_product_alerts_pending (product_id, type, notified_at)
+);
@@ -0,0 +1,41 @@
+-- Promosi toko dan kampanye platform. Harga efektif per produk dihitung
+-- background job ke product_promotions supaya listing cukup melakukan JOIN.
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    store_id INT NULL,
+    name VARCHAR(100) NOT NULL,
+    badge VARCHAR(50) NOT NULL,
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value DECIMAL(15,2) NOT NULL,
+    max_discount DECIMAL(15,2) NULL,
+    scope VARCHAR(20) NOT NULL,
+    category_id INT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    active TINYINT(1) NOT NULL DEFAULT 1,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    INDEX idx_promotions_store (store_id, ends_at),
+    INDEX idx_promotions_window (active, starts_at, ends_at)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_products (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    promotion_id BIGINT UNSIGNED NOT NULL,
+    product_id INT NOT NULL,
+    UNIQUE INDEX idx_promotion_products_pair (promotion_id, product_id),
+    INDEX idx_promotion_products_product (product_id)
+);
+
+CREATE TABLE IF NOT EXISTS product_promotions (
+    product_id INT NOT NULL PRIMARY KEY,
+    promotion_id BIGINT UNSIGNED NOT NULL,
+    original_price DECIMAL(15,2) NOT NULL,
+    effective_price DECIMAL(15,2) NOT NULL,
+    badge VARCHAR(50) NOT NULL,
+    ends_at DATETIME NOT NULL,
+    computed_at DATETIME NOT NULL,
+    INDEX idx_product_promotions_promotion (promotion_id)
+);
@@ -0,0 +1,39 @@
+-- Voucher kode potongan toko dan platform beserta catatan pemakaiannya.
+-- used_count hanya menghitung pemakaian berstatus redeemed.
+
+CREATE TABLE IF NOT EXISTS vouchers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    store_id INT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value DECIMAL(15,2) NOT NULL,
+    max_discount DECIMAL(15,2) NULL,
+    min_spend DECIMAL(15,2) NOT NULL DEFAULT 0,
+    per_user_limit INT NOT NULL DEFAULT 1,
+    usage_limit INT NULL,
+    used_count INT NOT NULL DEFAULT 0,
+    starts_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    active TINYINT(1) NOT NULL DEFAULT 1,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_vouchers_code (code),
+    INDEX idx_vouchers_store (store_id)
+);
+
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    voucher_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    subtotal DECIMAL(15,2) NOT NULL,
+    discount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    redeemed_at DATETIME NOT NULL,
+    released_at DATETIME NULL,
+    UNIQUE INDEX idx_voucher_redemptions_reference (voucher_id, reference),
+    INDEX idx_voucher_redemptions_user (voucher_id, user_id, status),
+    INDEX idx_voucher_redemptions_ref (reference)
+);
@@ -0,0 +1,16 @@
+-- Keranjang belanja untuk user login dan pengunjung anonim. owner_key memakai
+-- format viewer_key ("u:<id>" atau "v:<uuid>").
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    owner_key VARCHAR(64) NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    product_id INT NOT NULL,
+    variant VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    price_snapshot DECIMAL(15,2) NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_cart_items_owner_product (owner_key, product_id, variant),
+    INDEX idx_cart_items_updated (updated_at)
+);
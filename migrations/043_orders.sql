@@ -0,0 +1,59 @@
+-- Order per toko hasil checkout keranjang, salinan item, dan timeline status
+
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    number VARCHAR(32) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    store_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    subtotal DECIMAL(15,2) NOT NULL,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    shipping_cost DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total DECIMAL(15,2) NOT NULL,
+    voucher_code VARCHAR(32) NOT NULL DEFAULT '',
+    recipient_name VARCHAR(100) NOT NULL,
+    recipient_phone VARCHAR(20) NOT NULL,
+    shipping_address TEXT NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(64) NOT NULL DEFAULT '',
+    payment_due_at DATETIME NOT NULL,
+    paid_at DATETIME NULL,
+    shipped_at DATETIME NULL,
+    delivered_at DATETIME NULL,
+    completed_at DATETIME NULL,
+    cancelled_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_orders_number (number),
+    INDEX idx_orders_user (user_id, created_at),
+    INDEX idx_orders_store (store_id, status, created_at),
+    INDEX idx_orders_status_due (status, payment_due_at)
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT UNSIGNED NOT NULL,
+    product_id INT NOT NULL,
+    variant VARCHAR(100) NOT NULL DEFAULT '',
+    product_name VARCHAR(255) NOT NULL,
+    product_slug VARCHAR(255) NOT NULL,
+    image_url VARCHAR(500) NOT NULL DEFAULT '',
+    price DECIMAL(15,2) NOT NULL,
+    original_price DECIMAL(15,2) NOT NULL,
+    quantity INT NOT NULL,
+    line_total DECIMAL(15,2) NOT NULL,
+    INDEX idx_order_items_order (order_id),
+    INDEX idx_order_items_product (product_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT UNSIGNED NOT NULL,
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(10) NOT NULL,
+    actor_id BIGINT UNSIGNED NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    INDEX idx_order_events_order (order_id, created_at)
+);
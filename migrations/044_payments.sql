@@ -0,0 +1,39 @@
+-- Tagihan pembayaran order dan catatan webhook payment gateway
+
+CREATE TABLE IF NOT EXISTS payments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT UNSIGNED NOT NULL,
+    order_number VARCHAR(32) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    provider_ref VARCHAR(100) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    va_number VARCHAR(32) NOT NULL DEFAULT '',
+    qr_string TEXT NULL,
+    checkout_url VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME NOT NULL,
+    paid_at DATETIME NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_payments_provider_ref (provider, provider_ref),
+    INDEX idx_payments_order (order_id, created_at),
+    INDEX idx_payments_status_expires (status, expires_at)
+);
+
+CREATE TABLE IF NOT EXISTS payment_webhooks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    payment_id BIGINT UNSIGNED NULL,
+    provider_ref VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at DATETIME NULL,
+    UNIQUE INDEX idx_payment_webhooks_event (provider, event_id),
+    INDEX idx_payment_webhooks_result (result, created_at)
+);
@@ -0,0 +1,21 @@
+-- Berat dan dimensi produk, alamat terstruktur toko, dan kurir pada order
+
+ALTER TABLE products
+    ADD COLUMN weight INT NOT NULL DEFAULT 0 AFTER publish_at,
+    ADD COLUMN length INT NOT NULL DEFAULT 0 AFTER weight,
+    ADD COLUMN width INT NOT NULL DEFAULT 0 AFTER length,
+    ADD COLUMN height INT NOT NULL DEFAULT 0 AFTER width;
+
+ALTER TABLE stores
+    ADD COLUMN province VARCHAR(100) NOT NULL DEFAULT '' AFTER alamat,
+    ADD COLUMN city VARCHAR(100) NOT NULL DEFAULT '' AFTER province,
+    ADD COLUMN district VARCHAR(100) NOT NULL DEFAULT '' AFTER city,
+    ADD COLUMN postal_code VARCHAR(10) NOT NULL DEFAULT '' AFTER district;
+
+ALTER TABLE orders
+    ADD COLUMN shipping_province VARCHAR(100) NOT NULL DEFAULT '' AFTER shipping_address,
+    ADD COLUMN shipping_city VARCHAR(100) NOT NULL DEFAULT '' AFTER shipping_province,
+    ADD COLUMN shipping_postal_code VARCHAR(10) NOT NULL DEFAULT '' AFTER shipping_city,
+    ADD COLUMN courier VARCHAR(20) NOT NULL DEFAULT '' AFTER shipping_postal_code,
+    ADD COLUMN courier_service VARCHAR(20) NOT NULL DEFAULT '' AFTER courier,
+    ADD COLUMN shipping_weight INT NOT NULL DEFAULT 0 AFTER courier_service;
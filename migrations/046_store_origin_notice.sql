@@ -0,0 +1,18 @@
+-- Alamat asal pengiriman toko lama.
+--
+-- 045_shipping menambah province dan city pada stores dengan nilai kosong.
+-- Kolom alamat lama berupa teks bebas sehingga tidak bisa diisi otomatis
+-- dengan andal; tanpa province dan city, checkout untuk toko tersebut ditolak
+-- (ErrStoreOriginMissing). Pemilik toko diberi notifikasi untuk melengkapi
+-- alamat lewat PUT /api/store/:id.
+
+INSERT INTO notifications (user_id, type, title, message, link, created_at)
+SELECT s.user_id,
+       'store_address',
+       'Lengkapi alamat pengiriman toko',
+       CONCAT('Toko ', s.name, ' belum bisa menerima pesanan sampai provinsi dan kota asal pengiriman diisi.'),
+       CONCAT('/my-store/', s.id),
+       NOW()
+FROM stores s
+WHERE s.deleted_at IS NULL
+  AND (s.province = '' OR s.city = '');
@@ -0,0 +1,14 @@
+-- Total stok tersedia disimpan di products agar listing dan detail produk
+-- tidak mengagregasi seluruh product_inventories di setiap request.
+-- NULL berarti stok produk tidak dilacak (belum punya baris inventori).
+
+ALTER TABLE products
+    ADD COLUMN available_stock INT NULL AFTER favorite_count;
+
+UPDATE products
+JOIN (
+    SELECT product_id, SUM(quantity - reserved) AS available
+    FROM product_inventories
+    GROUP BY product_id
+) AS inventory ON inventory.product_id = products.id
+SET products.available_stock = inventory.available;
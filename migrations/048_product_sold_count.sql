@@ -0,0 +1,14 @@
+-- Jumlah unit terjual disimpan di products seperti favorite_count, sehingga
+-- urutan popularitas tidak mengagregasi inventory_adjustments per request
+
+ALTER TABLE products
+    ADD COLUMN sold_count INT NOT NULL DEFAULT 0 AFTER favorite_count;
+
+UPDATE products
+JOIN (
+    SELECT product_id, SUM(-quantity_change) AS sold
+    FROM inventory_adjustments
+    WHERE reason = 'sale'
+    GROUP BY product_id
+) AS popularity ON popularity.product_id = products.id
+SET products.sold_count = popularity.sold;
@@ -0,0 +1,12 @@
+-- Kunci urutan acak katalog per seed harian. Diisi dan dirotasi oleh job
+-- refresh-shuffle-keys sehingga listing default tidak menghitung CRC32 untuk
+-- seluruh produk di setiap request.
+
+CREATE TABLE IF NOT EXISTS product_shuffle_keys (
+    seed VARCHAR(16) NOT NULL,
+    product_id INT NOT NULL,
+    shuffle_key INT UNSIGNED NOT NULL,
+    PRIMARY KEY (seed, product_id),
+    INDEX idx_product_shuffle_keys_order (seed, shuffle_key, product_id),
+    CONSTRAINT fk_product_shuffle_keys_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- Pencarian dan klik dicatat bersama pelakunya (akun user atau hash IP).
+-- Pencarian yang sama dari pelaku yang sama dalam jendela waktu tertentu
+-- hanya dicatat sekali, dan klik harus merujuk ke search log yang dibuat
+-- server agar tidak bisa dikirim sembarangan.
+
+ALTER TABLE search_logs
+    ADD COLUMN actor_key VARCHAR(64) NOT NULL DEFAULT '' AFTER result_count,
+    ADD INDEX idx_search_logs_actor (actor_key, source, normalized_query, created_at);
+
+ALTER TABLE search_clicks
+    ADD COLUMN search_log_id BIGINT UNSIGNED NULL AFTER id,
+    ADD UNIQUE INDEX uq_search_clicks_target (search_log_id, target_id);
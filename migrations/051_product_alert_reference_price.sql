@@ -0,0 +1,12 @@
+-- Alert harga turun dievaluasi terhadap harga efektif (setelah promosi).
+-- reference_price adalah harga efektif saat buyer berlangganan, dipakai
+-- sebagai pembanding untuk alert tanpa target harga.
+
+ALTER TABLE product_alerts
+    ADD COLUMN reference_price DECIMAL(15,2) NULL AFTER target_price;
+
+UPDATE product_alerts
+JOIN products ON products.id = product_alerts.product_id
+LEFT JOIN product_promotions ON product_promotions.product_id = products.id
+SET product_alerts.reference_price = COALESCE(product_promotions.effective_price, products.harga)
+WHERE product_alerts.type = 'price_drop';
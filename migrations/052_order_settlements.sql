@@ -0,0 +1,17 @@
+-- Outbox efek samping order: stok menjadi penjualan saat dibayar, stok dan
+-- voucher dilepas saat batal, voucher dilepas saat refund. Baris ditulis
+-- dalam transaksi yang sama dengan perubahan status dan dihapus setelah efek
+-- sampingnya berhasil; yang gagal diulang job settle-orders.
+
+CREATE TABLE IF NOT EXISTS order_settlements (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_number VARCHAR(32) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    action VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    UNIQUE INDEX idx_order_settlements_action (order_number, action),
+    INDEX idx_order_settlements_updated (updated_at)
+);
@@ -0,0 +1,15 @@
+-- Tagihan yang lunas menandai order dibayar setelah transaksi webhook.
+-- order_sync_pending ditulis dalam transaksi webhook dan dihapus setelah
+-- order berhasil ditandai; yang gagal diulang job sync-paid-orders.
+-- needs_refund menandai tagihan lunas untuk order yang tidak bisa dibayar lagi.
+
+ALTER TABLE payments
+    ADD COLUMN order_sync_pending BOOLEAN NOT NULL DEFAULT FALSE AFTER paid_at,
+    ADD COLUMN needs_refund BOOLEAN NOT NULL DEFAULT FALSE AFTER order_sync_pending,
+    ADD INDEX idx_payments_order_sync (order_sync_pending, updated_at);
+
+-- Tagihan lunas yang ordernya belum sempat ditandai dibayar
+UPDATE payments
+JOIN orders ON orders.id = payments.order_id
+SET payments.order_sync_pending = TRUE
+WHERE payments.status = 'paid' AND orders.status = 'pending_payment';
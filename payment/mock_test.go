@@ -0,0 +1,141 @@
+package payment
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMockWebhookRoundTrip(t *testing.T) {
+	provider := NewMockProvider("secret-uji")
+	header, body, err := provider.(Simulator).SimulateWebhook(Notification{
+		ProviderRef: "mock_abc",
+		Reference:   "ORD-1",
+		Status:      StatusPaid,
+		Amount:      150000,
+	})
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+
+	n, err := provider.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if n.EventID == "" || n.OccurredAt.IsZero() {
+		t.Errorf("EventID dan OccurredAt harus terisi otomatis: %+v", n)
+	}
+	if n.ProviderRef != "mock_abc" || n.Reference != "ORD-1" || n.Status != StatusPaid || n.Amount != 150000 {
+		t.Errorf("isi webhook = %+v", n)
+	}
+}
+
+func TestMockWebhookRejectsBadSignature(t *testing.T) {
+	provider := NewMockProvider("secret-uji")
+	header, body, err := provider.(Simulator).SimulateWebhook(Notification{
+		EventID:     "evt_1",
+		ProviderRef: "mock_abc",
+		Status:      StatusPaid,
+		Amount:      150000,
+	})
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+
+	tampered := []byte(string(body))
+	copy(tampered[len(tampered)-8:], "99999999")
+
+	otherHeader, _, _ := NewMockProvider("secret-lain").(Simulator).SimulateWebhook(Notification{
+		EventID:     "evt_1",
+		ProviderRef: "mock_abc",
+		Status:      StatusPaid,
+		Amount:      150000,
+	})
+
+	cases := map[string]struct {
+		header http.Header
+		body   []byte
+	}{
+		"tanpa tanda tangan":       {http.Header{}, body},
+		"tanda tangan bukan hex":   {http.Header{SignatureHeader: {"zz"}}, body},
+		"body diubah":              {header, tampered},
+		"secret berbeda":           {otherHeader, body},
+		"tanda tangan terpotong":   {http.Header{SignatureHeader: {header.Get(SignatureHeader)[:10]}}, body},
+		"tanda tangan body kosong": {http.Header{SignatureHeader: {hex.EncodeToString(provider.(*mockProvider).sign(nil))}}, body},
+	}
+	for name, tc := range cases {
+		if _, err := provider.ParseWebhook(tc.header, tc.body); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, ingin ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestMockWebhookRejectsInvalidPayload(t *testing.T) {
+	provider := NewMockProvider("secret-uji")
+	simulator := provider.(Simulator)
+
+	cases := map[string]Notification{
+		"tanpa provider_ref":   {EventID: "evt_1", Status: StatusPaid},
+		"status pending":       {EventID: "evt_2", ProviderRef: "mock_abc", Status: StatusPending},
+		"status tidak dikenal": {EventID: "evt_3", ProviderRef: "mock_abc", Status: "refunded"},
+	}
+	for name, n := range cases {
+		header, body, err := simulator.SimulateWebhook(n)
+		if err != nil {
+			t.Fatalf("%s: SimulateWebhook: %v", name, err)
+		}
+		if _, err := provider.ParseWebhook(header, body); !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("%s: err = %v, ingin ErrInvalidPayload", name, err)
+		}
+	}
+
+	// Body bukan JSON tetapi ditandatangani dengan benar
+	body := []byte("bukan json")
+	header := http.Header{SignatureHeader: {hex.EncodeToString(provider.(*mockProvider).sign(body))}}
+	if _, err := provider.ParseWebhook(header, body); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("body bukan JSON: err = %v, ingin ErrInvalidPayload", err)
+	}
+}
+
+func TestMockCreateCharge(t *testing.T) {
+	provider := NewMockProvider("secret-uji")
+	expires := time.Now().Add(time.Hour)
+
+	charge, err := provider.CreateCharge(ChargeRequest{
+		Reference: "ORD-1",
+		Amount:    50000,
+		Method:    MethodVirtualAccount,
+		Channel:   "bca",
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge: %v", err)
+	}
+	if charge.Status != StatusPending || len(charge.VANumber) != len(vaPrefix["bca"])+11 || charge.VANumber[:5] != vaPrefix["bca"] {
+		t.Errorf("tagihan VA = %+v", charge)
+	}
+
+	if _, err := provider.CreateCharge(ChargeRequest{Amount: 50000, Method: MethodVirtualAccount, Channel: "ovo"}); !errors.Is(err, ErrUnsupportedChannel) {
+		t.Errorf("channel salah: err = %v, ingin ErrUnsupportedChannel", err)
+	}
+	if _, err := provider.CreateCharge(ChargeRequest{Amount: 0, Method: MethodQRIS, Channel: "qris"}); err == nil {
+		t.Error("nominal 0 harus ditolak")
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	if _, err := NewProvider(ProviderMock, ""); !errors.Is(err, ErrWebhookSecretMissing) {
+		t.Errorf("tanpa secret: err = %v, ingin ErrWebhookSecretMissing", err)
+	}
+	for _, name := range []string{"", "xendit", "MOCK"} {
+		if _, err := NewProvider(name, "secret"); !errors.Is(err, ErrUnknownProvider) {
+			t.Errorf("provider %q: err = %v, ingin ErrUnknownProvider", name, err)
+		}
+	}
+	provider, err := NewProvider(ProviderMock, "secret")
+	if err != nil || provider.Name() != ProviderMock {
+		t.Errorf("provider mock: provider=%v err=%v", provider, err)
+	}
+}
@@ -0,0 +1,117 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Metode pembayaran
+const (
+	MethodVirtualAccount = "virtual_account"
+	MethodQRIS           = "qris"
+	MethodEWallet        = "ewallet"
+)
+
+// Status tagihan di sisi payment gateway
+const (
+	StatusPending = "pending"
+	StatusPaid    = "paid"
+	StatusExpired = "expired"
+	StatusFailed  = "failed"
+)
+
+var (
+	ErrInvalidSignature   = errors.New("tanda tangan webhook tidak valid")
+	ErrInvalidPayload     = errors.New("isi webhook tidak valid")
+	ErrUnsupportedChannel = errors.New("metode atau channel pembayaran tidak didukung")
+
+	ErrWebhookSecretMissing = errors.New("PAYMENT_WEBHOOK_SECRET wajib diisi")
+	ErrUnknownProvider      = errors.New("PAYMENT_PROVIDER tidak dikenal")
+)
+
+// ProviderMock adalah nama provider tiruan. Provider ini tidak menagih uang
+// sungguhan sehingga harus dipilih secara eksplisit lewat PAYMENT_PROVIDER.
+const ProviderMock = "mock"
+
+// ChargeRequest adalah permintaan tagihan baru. Reference adalah nomor order
+// sehingga tagihan bisa dicocokkan kembali saat webhook masuk.
+type ChargeRequest struct {
+	Reference     string
+	Amount        float64
+	Method        string
+	Channel       string
+	CustomerName  string
+	CustomerEmail string
+	ExpiresAt     time.Time
+}
+
+// Charge adalah tagihan yang dibuat gateway. Hanya salah satu dari VANumber,
+// QRString, atau CheckoutURL yang terisi sesuai metodenya.
+type Charge struct {
+	ProviderRef string
+	Method      string
+	Channel     string
+	Amount      float64
+	Status      string
+	VANumber    string
+	QRString    string
+	CheckoutURL string
+	ExpiresAt   time.Time
+}
+
+// Notification adalah isi webhook yang sudah diverifikasi. EventID unik per
+// pengiriman dari gateway dan dipakai untuk menolak webhook ganda.
+type Notification struct {
+	EventID     string    `json:"event_id"`
+	ProviderRef string    `json:"provider_ref"`
+	Reference   string    `json:"reference"`
+	Status      string    `json:"status"`
+	Amount      float64   `json:"amount"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// PaymentProvider adalah payment gateway yang menerbitkan tagihan virtual
+// account, QRIS, dan e-wallet lalu mengabarkan hasilnya lewat webhook
+type PaymentProvider interface {
+	// Name dipakai untuk mencatat asal tagihan dan webhook
+	Name() string
+	// Channels adalah channel yang didukung per metode, misalnya bank untuk
+	// virtual account atau nama dompet untuk e-wallet
+	Channels() map[string][]string
+	// CreateCharge membuat tagihan baru
+	CreateCharge(req ChargeRequest) (Charge, error)
+	// ParseWebhook memverifikasi tanda tangan lalu membaca isi webhook.
+	// Mengembalikan ErrInvalidSignature jika tanda tangan tidak cocok.
+	ParseWebhook(header http.Header, body []byte) (Notification, error)
+}
+
+// Simulator adalah provider yang bisa membuat webhook bertanda tangan sendiri,
+// dipakai untuk menguji alur pembayaran tanpa gateway sungguhan
+type Simulator interface {
+	SimulateWebhook(n Notification) (http.Header, []byte, error)
+}
+
+// NewProvider membuat payment gateway sesuai nama provider. Secret webhook
+// wajib diisi agar tanda tangan webhook tidak bisa ditebak.
+func NewProvider(name, webhookSecret string) (PaymentProvider, error) {
+	if webhookSecret == "" {
+		return nil, ErrWebhookSecretMissing
+	}
+	switch name {
+	case ProviderMock:
+		return NewMockProvider(webhookSecret), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+}
+
+// SupportsChannel memeriksa apakah provider mendukung metode dan channel tersebut
+func SupportsChannel(p PaymentProvider, method, channel string) bool {
+	for _, c := range p.Channels()[method] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
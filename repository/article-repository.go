@@ -2,6 +2,10 @@ package repository
 
 import (
 	"batik/entity"
+	"batik/search"
+	"batik/utils"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,6 +13,7 @@ import (
 // ArticleRepository interface represents the article repository contract
 type ArticleRepository interface {
 	GetAllArticles(page, limit int, search string) ([]entity.Article, int64, error)
+	GetAllArticlesCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Article, *utils.CursorPagination, error)
 	GetLatestArticles() ([]entity.Article, error)
 	GetArticleByID(id uint64) (entity.Article, error)
 	GetArticleBySlug(slug string) (entity.Article, error)
@@ -17,17 +22,25 @@ type ArticleRepository interface {
 	DeleteArticle(id uint64) error
 	SearchArticles(query string, page, limit int) ([]entity.Article, int64, error)
 	SlugExists(slug string) bool
+	RestoreArticle(id uint64) error
+	GetDeletedArticleByID(id uint64) (entity.Article, error)
+	GetTrash(page, limit int) ([]entity.Article, int64, error)
+	FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Article, error)
+	ForceDeleteArticle(id uint64) error
+	GetIndexableArticles(afterID uint64, limit int) ([]entity.Article, error)
 }
 
 // articleRepository is the implementation of ArticleRepository interface
 type articleRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	index search.SearchIndex
 }
 
 // NewArticleRepository creates a new instance of ArticleRepository
-func NewArticleRepository(db *gorm.DB) ArticleRepository {
+func NewArticleRepository(db *gorm.DB, index search.SearchIndex) ArticleRepository {
 	return &articleRepository{
-		db: db,
+		db:    db,
+		index: index,
 	}
 }
 
@@ -40,7 +53,7 @@ func (r *articleRepository) GetAllArticles(page, limit int, search string) ([]en
 	
 	// Apply search filter if provided
 	if search != "" {
-		query = query.Where("title LIKE ? OR description LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where("id IN ?", r.searchIDs(search))
 	}
 	
 	// Count total records
@@ -57,6 +70,36 @@ func (r *articleRepository) GetAllArticles(page, limit int, search string) ([]en
 	return articles, total, nil
 }
 
+// articleKeyset adalah urutan listing artikel: terbaru dulu
+var articleKeyset = utils.Keyset{
+	{Expr: "created_at", Desc: true},
+	{Expr: "id", Desc: true},
+}
+
+// GetAllArticlesCursor retrieves articles with keyset (cursor) pagination
+func (r *articleRepository) GetAllArticlesCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Article, *utils.CursorPagination, error) {
+	var articles []entity.Article
+
+	query := r.db.Model(&entity.Article{})
+	if search != "" {
+		query = query.Where("id IN ?", r.searchIDs(search))
+	}
+
+	scope := utils.CursorScope("articles", search)
+	query, err := articleKeyset.Apply(query, cursor, scope, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, nil, err
+	}
+
+	articles, pagination := utils.NewCursorPagination(articles, limit, cursor, scope, func(a entity.Article) []interface{} {
+		return []interface{}{a.CreatedAt, a.ID}
+	})
+	return articles, pagination, nil
+}
+
 // Get Latest Articles
 func (r *articleRepository) GetLatestArticles() ([]entity.Article, error) {
 	var articles []entity.Article
@@ -117,19 +160,27 @@ func (r *articleRepository) SearchArticles(query string, page, limit int) ([]ent
 	var articles []entity.Article
 	var total int64
 	
-	// Search in title, description, and excerpt
-	searchQuery := r.db.Model(&entity.Article{}).
-		Where("title LIKE ? OR description LIKE ? OR excerpt LIKE ?", 
-			"%"+query+"%", "%"+query+"%", "%"+query+"%")
+	// Search in title, excerpt, and description via full-text index
+	hits := r.index.Search(query, maxArticleSearchHits)
+	if len(hits) == 0 {
+		return []entity.Article{}, 0, nil
+	}
+
+	ids := make([]interface{}, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	searchQuery := r.db.Model(&entity.Article{}).Where("id IN ?", ids)
 	
-	// Count total matching records
+	// Count total matching records (artikel di trash tidak ikut terhitung)
 	if err := searchQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 	
-	// Apply pagination
+	// Apply pagination, diurutkan sesuai skor BM25
 	offset := (page - 1) * limit
-	if err := searchQuery.Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
+	rank := "FIELD(id" + strings.Repeat(", ?", len(ids)) + ") AS search_rank"
+	if err := searchQuery.Select("*, "+rank, ids...).Offset(offset).Limit(limit).Order("search_rank ASC").Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 	
@@ -139,11 +190,81 @@ func (r *articleRepository) SearchArticles(query string, page, limit int) ([]ent
 // SlugExists checks if a slug already exists
 func (r *articleRepository) SlugExists(slug string) bool {
 	var count int64
-	r.db.Model(&entity.Article{}).Where("slug = ?", slug).Count(&count)
+	// Unscoped: slug artikel di trash tetap dianggap terpakai
+	r.db.Unscoped().Model(&entity.Article{}).Where("slug = ?", slug).Count(&count)
 	return count > 0
 }
 
 
+// RestoreArticle mengembalikan artikel dari trash
+func (r *articleRepository) RestoreArticle(id uint64) error {
+	return r.db.Unscoped().Model(&entity.Article{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// GetDeletedArticleByID retrieves an article from the trash
+func (r *articleRepository) GetDeletedArticleByID(id uint64) (entity.Article, error) {
+	var article entity.Article
+
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&article).Error; err != nil {
+		return entity.Article{}, err
+	}
+
+	return article, nil
+}
+
+// GetTrash retrieves soft-deleted articles with pagination
+func (r *articleRepository) GetTrash(page, limit int) ([]entity.Article, int64, error) {
+	var articles []entity.Article
+	var total int64
+
+	query := r.db.Unscoped().Model(&entity.Article{}).Where("deleted_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// FindPurgeable retrieves articles that have been in the trash since before deletedBefore
+func (r *articleRepository) FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// maxArticleSearchHits membatasi jumlah hasil full-text yang dipakai untuk filter SQL
+const maxArticleSearchHits = 1000
+
+// searchIDs mencari ID artikel di full-text index
+func (r *articleRepository) searchIDs(query string) []int {
+	hits := r.index.Search(query, maxArticleSearchHits)
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+// GetIndexableArticles mengambil artikel per batch berdasarkan ID untuk membangun ulang index
+func (r *articleRepository) GetIndexableArticles(afterID uint64, limit int) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// ForceDeleteArticle permanently removes an article
+func (r *articleRepository) ForceDeleteArticle(id uint64) error {
+	return r.db.Unscoped().Delete(&entity.Article{}, id).Error
+}
 
 // type ArticleRepository interface {
 // 	GetAllArticle() []entity.Article
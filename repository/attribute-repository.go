@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"batik/entity"
+
+	"gorm.io/gorm"
+)
+
+type AttributeRepository interface {
+	GetAll() ([]entity.Attribute, error)
+	GetCategorySchema(categoryID int) ([]entity.CategoryAttribute, error)
+	ReplaceCategorySchema(categoryID int, schema []entity.CategoryAttribute) error
+	GetProductAttributes(productID int) ([]entity.ProductAttribute, error)
+	ReplaceProductAttributes(productID int, attributes []entity.ProductAttribute) error
+}
+
+type attributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) AttributeRepository {
+	return &attributeRepository{
+		db: db,
+	}
+}
+
+func (r *attributeRepository) GetAll() ([]entity.Attribute, error) {
+	var attributes []entity.Attribute
+	err := r.db.Order("sort_order ASC, id ASC").Find(&attributes).Error
+	return attributes, err
+}
+
+// GetCategorySchema mengembalikan atribut yang berlaku untuk kategori beserta aturannya
+func (r *attributeRepository) GetCategorySchema(categoryID int) ([]entity.CategoryAttribute, error) {
+	var schema []entity.CategoryAttribute
+	err := r.db.Preload("Attribute").
+		Where("category_id = ?", categoryID).
+		Order("sort_order ASC, id ASC").
+		Find(&schema).Error
+	return schema, err
+}
+
+// ReplaceCategorySchema mengganti skema atribut kategori. Nilai atribut produk
+// di kategori ini yang atributnya tidak lagi berlaku ikut dihapus.
+func (r *attributeRepository) ReplaceCategorySchema(categoryID int, schema []entity.CategoryAttribute) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", categoryID).Delete(&entity.CategoryAttribute{}).Error; err != nil {
+			return err
+		}
+
+		attributeIDs := []int{0}
+		for i := range schema {
+			schema[i].ID = 0
+			schema[i].CategoryID = categoryID
+			attributeIDs = append(attributeIDs, schema[i].AttributeID)
+		}
+		if len(schema) > 0 {
+			if err := tx.Omit("Attribute").Create(&schema).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec("DELETE FROM product_attributes WHERE attribute_id NOT IN ? AND product_id IN "+
+			"(SELECT id FROM products WHERE category_id = ?)", attributeIDs, categoryID).Error
+	})
+}
+
+func (r *attributeRepository) GetProductAttributes(productID int) ([]entity.ProductAttribute, error) {
+	var attributes []entity.ProductAttribute
+	err := r.db.Where("product_id = ?", productID).Order("id ASC").Find(&attributes).Error
+	return attributes, err
+}
+
+// ReplaceProductAttributes mengganti seluruh atribut produk dalam satu transaksi
+func (r *attributeRepository) ReplaceProductAttributes(productID int, attributes []entity.ProductAttribute) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&entity.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+
+		if len(attributes) == 0 {
+			return nil
+		}
+
+		for i := range attributes {
+			attributes[i].ID = 0
+			attributes[i].ProductID = productID
+		}
+		return tx.Create(&attributes).Error
+	})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"batik/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CartRepository interface {
+	GetItems(ownerKey string) ([]entity.CartItem, error)
+	FindItem(ownerKey string, id uint64) (entity.CartItem, error)
+	FindByProduct(ownerKey string, productID int, variant string) (entity.CartItem, error)
+	CountItems(ownerKey string) (int64, error)
+	Create(item entity.CartItem) (entity.CartItem, error)
+	UpdateQuantity(id uint64, quantity int, price float64) error
+	Delete(ownerKey string, id uint64) error
+	Clear(ownerKey string) error
+	DeleteItems(ownerKey string, ids []uint64) error
+	Merge(fromKey, toKey string, userID uint64, maxQuantity int) error
+	DeleteStale(ownerPrefix string, before time.Time) (int64, error)
+}
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{
+		db: db,
+	}
+}
+
+func (r *cartRepository) GetItems(ownerKey string) ([]entity.CartItem, error) {
+	var items []entity.CartItem
+	err := r.db.Where("owner_key = ?", ownerKey).Order("created_at ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *cartRepository) FindItem(ownerKey string, id uint64) (entity.CartItem, error) {
+	var item entity.CartItem
+	err := r.db.Where("owner_key = ? AND id = ?", ownerKey, id).First(&item).Error
+	return item, err
+}
+
+func (r *cartRepository) FindByProduct(ownerKey string, productID int, variant string) (entity.CartItem, error) {
+	var item entity.CartItem
+	err := r.db.Where("owner_key = ? AND product_id = ? AND variant = ?", ownerKey, productID, variant).First(&item).Error
+	return item, err
+}
+
+func (r *cartRepository) CountItems(ownerKey string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.CartItem{}).Where("owner_key = ?", ownerKey).Count(&count).Error
+	return count, err
+}
+
+func (r *cartRepository) Create(item entity.CartItem) (entity.CartItem, error) {
+	err := r.db.Create(&item).Error
+	return item, err
+}
+
+// UpdateQuantity menyimpan jumlah baru beserta harga efektif saat ini
+func (r *cartRepository) UpdateQuantity(id uint64, quantity int, price float64) error {
+	return r.db.Model(&entity.CartItem{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"quantity":       quantity,
+			"price_snapshot": price,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+func (r *cartRepository) Delete(ownerKey string, id uint64) error {
+	return r.db.Where("owner_key = ? AND id = ?", ownerKey, id).Delete(&entity.CartItem{}).Error
+}
+
+func (r *cartRepository) Clear(ownerKey string) error {
+	return r.db.Where("owner_key = ?", ownerKey).Delete(&entity.CartItem{}).Error
+}
+
+func (r *cartRepository) DeleteItems(ownerKey string, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("owner_key = ? AND id IN ?", ownerKey, ids).Delete(&entity.CartItem{}).Error
+}
+
+// Merge memindahkan keranjang pengunjung anonim ke keranjang user. Produk yang
+// sudah ada di keranjang user dijumlahkan, dibatasi maxQuantity.
+func (r *cartRepository) Merge(fromKey, toKey string, userID uint64, maxQuantity int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var items []entity.CartItem
+		if err := tx.Where("owner_key = ?", fromKey).Find(&items).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			var existing entity.CartItem
+			err := tx.Where("owner_key = ? AND product_id = ? AND variant = ?", toKey, item.ProductID, item.Variant).
+				First(&existing).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err := tx.Model(&entity.CartItem{}).Where("id = ?", item.ID).
+					Updates(map[string]interface{}{
+						"owner_key":  toKey,
+						"user_id":    userID,
+						"updated_at": time.Now(),
+					}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			quantity := existing.Quantity + item.Quantity
+			if quantity > maxQuantity {
+				quantity = maxQuantity
+			}
+			err = tx.Model(&entity.CartItem{}).Where("id = ?", existing.ID).
+				Updates(map[string]interface{}{
+					"quantity":   quantity,
+					"updated_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Delete(&entity.CartItem{}, item.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteStale menghapus seluruh keranjang dengan prefix owner_key tertentu
+// yang tidak ada item diubah sejak before
+func (r *cartRepository) DeleteStale(ownerPrefix string, before time.Time) (int64, error) {
+	// Subquery dibungkus derived table karena MySQL tidak mengizinkan DELETE
+	// membaca tabel yang sama secara langsung
+	result := r.db.Where("owner_key IN (SELECT owner_key FROM ("+
+		"SELECT owner_key FROM cart_items WHERE owner_key LIKE ? GROUP BY owner_key HAVING MAX(updated_at) < ?"+
+		") AS stale)", ownerPrefix+"%", before).
+		Delete(&entity.CartItem{})
+	return result.RowsAffected, result.Error
+}
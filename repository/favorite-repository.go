@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FavoriteRepository interface {
+	IsFavorite(userID uint64, productID int) (bool, error)
+	Add(userID uint64, productID int) error
+	Remove(userID uint64, productID int) error
+	GetFavoriteProducts(userID uint64, page, limit int) ([]entity.ProductCard, int64, error)
+	GetStoreStats(storeID, limit int) ([]entity.ProductFavoriteStat, error)
+}
+
+type favoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepository{
+		db: db,
+	}
+}
+
+func (r *favoriteRepository) IsFavorite(userID uint64, productID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.ProductFavorite{}).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Add menandai produk sebagai favorit. Menandai ulang produk yang sudah
+// favorit tidak dianggap error.
+func (r *favoriteRepository) Add(userID uint64, productID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("INSERT IGNORE INTO product_favorites (user_id, product_id, created_at) VALUES (?, ?, ?)",
+			userID, productID, time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return refreshFavoriteCount(tx, productID)
+	})
+}
+
+func (r *favoriteRepository) Remove(userID uint64, productID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND product_id = ?", userID, productID).
+			Delete(&entity.ProductFavorite{}).Error
+		if err != nil {
+			return err
+		}
+		return refreshFavoriteCount(tx, productID)
+	})
+}
+
+// GetFavoriteProducts mengembalikan produk favorit user yang masih tampil di
+// katalog, yang terakhir disukai lebih dulu
+func (r *favoriteRepository) GetFavoriteProducts(userID uint64, page, limit int) ([]entity.ProductCard, int64, error) {
+	var (
+		products []entity.ProductCard
+		total    int64
+	)
+
+	query := func() *gorm.DB {
+		return publicProductCards(r.db).
+			Joins("JOIN product_favorites ON product_favorites.product_id = products.id").
+			Where("product_favorites.user_id = ?", userID)
+	}
+
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query().
+		Preload("Attributes").
+		Order("product_favorites.created_at DESC, product_favorites.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&products).Error
+	return products, total, err
+}
+
+// GetStoreStats mengembalikan jumlah favorit dan wishlist per produk toko,
+// terbanyak dulu
+func (r *favoriteRepository) GetStoreStats(storeID, limit int) ([]entity.ProductFavoriteStat, error) {
+	var stats []entity.ProductFavoriteStat
+	err := r.db.Model(&entity.Product{}).
+		Select("products.id AS product_id, products.slug, products.name, products.status, products.favorite_count, "+
+			"COALESCE(wishlisted.count, 0) AS wishlist_count").
+		Joins("LEFT JOIN (SELECT product_id, COUNT(*) AS count FROM wishlist_items GROUP BY product_id) AS wishlisted "+
+			"ON wishlisted.product_id = products.id").
+		Where("products.store_id = ?", storeID).
+		Order("products.favorite_count DESC, wishlist_count DESC, products.id DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// refreshFavoriteCount menghitung ulang jumlah favorit yang disimpan di products
+func refreshFavoriteCount(tx *gorm.DB, productID int) error {
+	return tx.Exec("UPDATE products SET favorite_count = "+
+		"(SELECT COUNT(*) FROM product_favorites WHERE product_id = ?) WHERE id = ?",
+		productID, productID).Error
+}
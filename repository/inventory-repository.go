@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"batik/entity"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock    = errors.New("stok tidak mencukupi")
+	ErrReservationNotActive = errors.New("reservasi sudah tidak aktif")
+)
+
+type InventoryRepository interface {
+	FindByProductID(productID int) ([]entity.ProductInventory, error)
+	FindByProductIDs(productIDs []int) ([]entity.ProductInventory, error)
+	FindOne(productID int, variant string) (entity.ProductInventory, error)
+	Adjust(productID int, variant string, change int, reason, note string, userID int) (entity.ProductInventory, error)
+	SetThreshold(productID int, variant string, threshold int) (entity.ProductInventory, error)
+	GetAdjustments(productID, page, limit int) ([]entity.InventoryAdjustment, int64, error)
+	GetLowStockByStore(storeID int) ([]entity.ProductInventory, error)
+	SetLowStockNotified(id int, notifiedAt *time.Time) error
+	Reserve(productID int, variant string, quantity int, reference string, userID int, expiresAt time.Time) (entity.StockReservation, entity.ProductInventory, error)
+	ReleaseReservation(id int, status string) (entity.ProductInventory, error)
+	CommitReservation(id int, userID int) (entity.ProductInventory, error)
+	FindReservationsByReference(reference string) ([]entity.StockReservation, error)
+	FindExpiredReservations(now time.Time, limit int) ([]entity.StockReservation, error)
+}
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{
+		db: db,
+	}
+}
+
+func (r *inventoryRepository) FindByProductID(productID int) ([]entity.ProductInventory, error) {
+	var inventories []entity.ProductInventory
+	err := r.db.Where("product_id = ?", productID).Order("variant ASC").Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) FindByProductIDs(productIDs []int) ([]entity.ProductInventory, error) {
+	var inventories []entity.ProductInventory
+	if len(productIDs) == 0 {
+		return inventories, nil
+	}
+	err := r.db.Where("product_id IN ?", productIDs).Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) FindOne(productID int, variant string) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+	err := r.db.Where("product_id = ? AND variant = ?", productID, variant).First(&inventory).Error
+	return inventory, err
+}
+
+// lockInventory mengambil baris inventori dengan SELECT ... FOR UPDATE di dalam
+// transaksi, dan membuatnya terlebih dahulu jika belum ada.
+func lockInventory(tx *gorm.DB, productID int, variant string) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND variant = ?", productID, variant).
+		First(&inventory).Error
+	if err == nil {
+		return inventory, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return inventory, err
+	}
+
+	// Baris belum ada; INSERT IGNORE agar aman jika dua request membuat bersamaan
+	inventory = entity.ProductInventory{ProductID: productID, Variant: variant}
+	if err := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&inventory).Error; err != nil {
+		return inventory, err
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND variant = ?", productID, variant).
+		First(&inventory).Error
+	return inventory, err
+}
+
+// refreshAvailableStock menghitung ulang total stok tersedia yang disimpan di
+// products.available_stock agar listing tidak perlu mengagregasi inventori.
+// Produk tanpa baris inventori bernilai NULL (stok tidak dilacak).
+func refreshAvailableStock(tx *gorm.DB, productID int) error {
+	return tx.Exec("UPDATE products SET available_stock = "+
+		"(SELECT SUM(quantity - reserved) FROM product_inventories WHERE product_id = ?) WHERE id = ?",
+		productID, productID).Error
+}
+
+// refreshSoldCount menghitung ulang jumlah unit terjual yang disimpan di
+// products.sold_count dari ledger stok, dipakai untuk urutan popularitas
+func refreshSoldCount(tx *gorm.DB, productID int) error {
+	return tx.Exec("UPDATE products SET sold_count = "+
+		"(SELECT COALESCE(SUM(-quantity_change), 0) FROM inventory_adjustments WHERE product_id = ? AND reason = ?) WHERE id = ?",
+		productID, entity.StockReasonSale, productID).Error
+}
+
+func (r *inventoryRepository) Adjust(productID int, variant string, change int, reason, note string, userID int) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		inventory, err = lockInventory(tx, productID, variant)
+		if err != nil {
+			return err
+		}
+
+		// Stok tidak boleh turun di bawah jumlah yang sedang direservasi
+		if inventory.Quantity+change < inventory.Reserved {
+			return ErrInsufficientStock
+		}
+
+		inventory.Quantity += change
+		if err := tx.Model(&inventory).Update("quantity", inventory.Quantity).Error; err != nil {
+			return err
+		}
+		if err := refreshAvailableStock(tx, productID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.InventoryAdjustment{
+			InventoryID: inventory.ID,
+			ProductID:   productID,
+			Variant:     variant,
+			Change:      change,
+			Balance:     inventory.Quantity,
+			Reason:      reason,
+			Note:        note,
+			UserID:      userID,
+		}).Error; err != nil {
+			return err
+		}
+		if reason == entity.StockReasonSale {
+			return refreshSoldCount(tx, productID)
+		}
+		return nil
+	})
+
+	return inventory, err
+}
+
+func (r *inventoryRepository) SetThreshold(productID int, variant string, threshold int) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		inventory, err = lockInventory(tx, productID, variant)
+		if err != nil {
+			return err
+		}
+
+		inventory.LowStockThreshold = threshold
+		if err := tx.Model(&inventory).Update("low_stock_threshold", threshold).Error; err != nil {
+			return err
+		}
+		// Baris inventori bisa baru dibuat di sini, sehingga stok mulai dilacak
+		return refreshAvailableStock(tx, productID)
+	})
+
+	return inventory, err
+}
+
+func (r *inventoryRepository) GetAdjustments(productID, page, limit int) ([]entity.InventoryAdjustment, int64, error) {
+	var adjustments []entity.InventoryAdjustment
+	var total int64
+
+	query := r.db.Model(&entity.InventoryAdjustment{}).Where("product_id = ?", productID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&adjustments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return adjustments, total, nil
+}
+
+func (r *inventoryRepository) GetLowStockByStore(storeID int) ([]entity.ProductInventory, error) {
+	var inventories []entity.ProductInventory
+
+	err := r.db.Model(&entity.ProductInventory{}).
+		Select("product_inventories.*").
+		Joins("JOIN products ON products.id = product_inventories.product_id").
+		Where("products.store_id = ?", storeID).
+		Where("product_inventories.low_stock_threshold > 0").
+		Where("product_inventories.quantity - product_inventories.reserved <= product_inventories.low_stock_threshold").
+		Order("product_inventories.quantity - product_inventories.reserved ASC").
+		Find(&inventories).Error
+
+	return inventories, err
+}
+
+func (r *inventoryRepository) SetLowStockNotified(id int, notifiedAt *time.Time) error {
+	return r.db.Model(&entity.ProductInventory{}).Where("id = ?", id).Update("low_stock_notified_at", notifiedAt).Error
+}
+
+func (r *inventoryRepository) Reserve(productID int, variant string, quantity int, reference string, userID int, expiresAt time.Time) (entity.StockReservation, entity.ProductInventory, error) {
+	var reservation entity.StockReservation
+	var inventory entity.ProductInventory
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		inventory, err = lockInventory(tx, productID, variant)
+		if err != nil {
+			return err
+		}
+
+		if inventory.Available() < quantity {
+			return ErrInsufficientStock
+		}
+
+		inventory.Reserved += quantity
+		if err := tx.Model(&inventory).Update("reserved", inventory.Reserved).Error; err != nil {
+			return err
+		}
+		if err := refreshAvailableStock(tx, productID); err != nil {
+			return err
+		}
+
+		reservation = entity.StockReservation{
+			InventoryID: inventory.ID,
+			ProductID:   productID,
+			Variant:     variant,
+			Quantity:    quantity,
+			Reference:   reference,
+			UserID:      userID,
+			Status:      entity.ReservationActive,
+			ExpiresAt:   expiresAt,
+		}
+		return tx.Create(&reservation).Error
+	})
+
+	return reservation, inventory, err
+}
+
+// lockActiveReservation mengunci reservasi beserta baris inventorinya
+func lockActiveReservation(tx *gorm.DB, id int) (entity.StockReservation, entity.ProductInventory, error) {
+	var reservation entity.StockReservation
+	var inventory entity.ProductInventory
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation).Error; err != nil {
+		return reservation, inventory, err
+	}
+	if reservation.Status != entity.ReservationActive {
+		return reservation, inventory, ErrReservationNotActive
+	}
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", reservation.InventoryID).First(&inventory).Error
+	return reservation, inventory, err
+}
+
+func (r *inventoryRepository) ReleaseReservation(id int, status string) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reservation, inv, err := lockActiveReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		inventory = inv
+
+		inventory.Reserved -= reservation.Quantity
+		if inventory.Reserved < 0 {
+			inventory.Reserved = 0
+		}
+		if err := tx.Model(&inventory).Update("reserved", inventory.Reserved).Error; err != nil {
+			return err
+		}
+		if err := refreshAvailableStock(tx, reservation.ProductID); err != nil {
+			return err
+		}
+
+		return tx.Model(&reservation).Update("status", status).Error
+	})
+
+	return inventory, err
+}
+
+func (r *inventoryRepository) CommitReservation(id int, userID int) (entity.ProductInventory, error) {
+	var inventory entity.ProductInventory
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reservation, inv, err := lockActiveReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		inventory = inv
+
+		inventory.Quantity -= reservation.Quantity
+		inventory.Reserved -= reservation.Quantity
+		if inventory.Quantity < 0 || inventory.Reserved < 0 {
+			return fmt.Errorf("inventori %d tidak konsisten", inventory.ID)
+		}
+		if err := tx.Model(&inventory).Updates(map[string]interface{}{
+			"quantity": inventory.Quantity,
+			"reserved": inventory.Reserved,
+		}).Error; err != nil {
+			return err
+		}
+		if err := refreshAvailableStock(tx, reservation.ProductID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&reservation).Update("status", entity.ReservationCommitted).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.InventoryAdjustment{
+			InventoryID: inventory.ID,
+			ProductID:   reservation.ProductID,
+			Variant:     reservation.Variant,
+			Change:      -reservation.Quantity,
+			Balance:     inventory.Quantity,
+			Reason:      entity.StockReasonSale,
+			Note:        reservation.Reference,
+			UserID:      userID,
+		}).Error; err != nil {
+			return err
+		}
+		return refreshSoldCount(tx, reservation.ProductID)
+	})
+
+	return inventory, err
+}
+
+func (r *inventoryRepository) FindReservationsByReference(reference string) ([]entity.StockReservation, error) {
+	var reservations []entity.StockReservation
+	err := r.db.Where("reference = ?", reference).Order("id ASC").Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *inventoryRepository) FindExpiredReservations(now time.Time, limit int) ([]entity.StockReservation, error) {
+	var reservations []entity.StockReservation
+	err := r.db.Where("status = ? AND expires_at <= ?", entity.ReservationActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&reservations).Error
+	return reservations, err
+}
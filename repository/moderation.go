@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// moderatedContent menjelaskan konten buatan pengguna yang bisa dilaporkan dan
+// dimoderasi admin, yaitu ulasan dan pertanyaan produk. Tabel konten harus
+// punya kolom status, flag_count, dan moderated_at; tabel laporan menyimpan
+// ID konten di flagColumn.
+type moderatedContent struct {
+	model          interface{}
+	flagModel      interface{}
+	flagColumn     string
+	published      string
+	pending        string
+	alreadyFlagged error
+}
+
+var reviewModeration = moderatedContent{
+	model:          &entity.ProductReview{},
+	flagModel:      &entity.ProductReviewFlag{},
+	flagColumn:     "review_id",
+	published:      entity.ReviewStatusPublished,
+	pending:        entity.ReviewStatusPending,
+	alreadyFlagged: ErrReviewAlreadyFlagged,
+}
+
+var questionModeration = moderatedContent{
+	model:          &entity.ProductQuestion{},
+	flagModel:      &entity.ProductQuestionFlag{},
+	flagColumn:     "question_id",
+	published:      entity.QuestionStatusPublished,
+	pending:        entity.QuestionStatusPending,
+	alreadyFlagged: ErrQuestionAlreadyFlagged,
+}
+
+// addFlag menyimpan laporan (pointer ke entity laporan) dan menaikkan
+// flag_count konten di dalam transaksi tx. Setelah jumlah laporan mencapai
+// hideThreshold, konten yang masih tampil menjadi pending sampai dimoderasi
+// admin. hidden bernilai true jika konten baru saja disembunyikan.
+func (m moderatedContent) addFlag(tx *gorm.DB, contentID, userID uint64, flag interface{}, hideThreshold int) (hidden bool, err error) {
+	var exists int64
+	err = tx.Model(m.flagModel).
+		Where(m.flagColumn+" = ? AND user_id = ?", contentID, userID).
+		Count(&exists).Error
+	if err != nil {
+		return false, err
+	}
+	if exists > 0 {
+		return false, m.alreadyFlagged
+	}
+
+	if err := tx.Create(flag).Error; err != nil {
+		return false, err
+	}
+
+	err = tx.Model(m.model).Where("id = ?", contentID).
+		Update("flag_count", gorm.Expr("flag_count + 1")).Error
+	if err != nil {
+		return false, err
+	}
+
+	// Konten yang sudah disetujui admin (moderated_at terisi) tidak
+	// disembunyikan otomatis lagi oleh laporan berikutnya
+	result := tx.Model(m.model).
+		Where("id = ? AND status = ? AND flag_count >= ? AND moderated_at IS NULL",
+			contentID, m.published, hideThreshold).
+		Update("status", m.pending)
+	return result.RowsAffected > 0, result.Error
+}
+
+// moderate menetapkan status konten oleh admin di dalam transaksi tx.
+// Menyetujui konten (published) menghapus laporan yang sudah ada.
+func (m moderatedContent) moderate(tx *gorm.DB, contentID uint64, status string) error {
+	updates := map[string]interface{}{
+		"status":       status,
+		"moderated_at": time.Now(),
+	}
+	if status == m.published {
+		updates["flag_count"] = 0
+		if err := tx.Where(m.flagColumn+" = ?", contentID).Delete(m.flagModel).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Model(m.model).Where("id = ?", contentID).Updates(updates).Error
+}
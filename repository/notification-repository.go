@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification entity.Notification) (entity.Notification, error)
+	GetByUserID(userID, page, limit int, unreadOnly bool) ([]entity.Notification, int64, error)
+	MarkAsRead(id uint64, userID int) error
+	MarkAllAsRead(userID int) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) Create(notification entity.Notification) (entity.Notification, error) {
+	err := r.db.Create(&notification).Error
+	return notification, err
+}
+
+func (r *notificationRepository) GetByUserID(userID, page, limit int, unreadOnly bool) ([]entity.Notification, int64, error) {
+	var notifications []entity.Notification
+	var total int64
+
+	query := r.db.Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) MarkAsRead(id uint64, userID int) error {
+	result := r.db.Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllAsRead(userID int) error {
+	return r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
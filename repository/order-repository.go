@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"batik/entity"
+	"batik/payment"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrOrderStatusChanged = errors.New("status order sudah berubah, muat ulang order")
+
+type OrderRepository interface {
+	FindByNumber(number string) (entity.Order, error)
+	GetByUser(userID uint64, status string, page, limit int) ([]entity.Order, int64, error)
+	GetByStore(storeID int, status string, page, limit int) ([]entity.Order, int64, error)
+	Create(order entity.Order) (entity.Order, error)
+	Transition(order entity.Order, to string, event entity.OrderEvent, updates map[string]interface{}, settlements []entity.OrderSettlement) ([]entity.OrderSettlement, error)
+	FindOverdueUnpaid(now time.Time, limit int) ([]entity.Order, error)
+	FindDeliveredBefore(before time.Time, limit int) ([]entity.Order, error)
+	AddSettlements(settlements []entity.OrderSettlement) error
+	FindPendingSettlements(before time.Time, limit int) ([]entity.OrderSettlement, error)
+	CompleteSettlement(id uint64) error
+	FailSettlement(id uint64, reason string) error
+}
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{
+		db: db,
+	}
+}
+
+// orderQuery memilih order beserta nama toko dan nama buyer
+func (r *orderRepository) orderQuery() *gorm.DB {
+	return r.db.Model(&entity.Order{}).
+		Select("orders.*, stores.name AS store_name, users.name AS buyer_name").
+		Joins("LEFT JOIN stores ON stores.id = orders.store_id").
+		Joins("LEFT JOIN users ON users.id = orders.user_id").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		})
+}
+
+// FindByNumber mengambil order lengkap dengan item dan timeline
+func (r *orderRepository) FindByNumber(number string) (entity.Order, error) {
+	var order entity.Order
+	err := r.orderQuery().
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Where("orders.number = ?", number).
+		First(&order).Error
+	return order, err
+}
+
+func (r *orderRepository) GetByUser(userID uint64, status string, page, limit int) ([]entity.Order, int64, error) {
+	return r.paginate(r.db.Where("orders.user_id = ?", userID), status, page, limit)
+}
+
+func (r *orderRepository) GetByStore(storeID int, status string, page, limit int) ([]entity.Order, int64, error) {
+	return r.paginate(r.db.Where("orders.store_id = ?", storeID), status, page, limit)
+}
+
+func (r *orderRepository) paginate(conds *gorm.DB, status string, page, limit int) ([]entity.Order, int64, error) {
+	var (
+		orders []entity.Order
+		total  int64
+	)
+
+	apply := func(query *gorm.DB) *gorm.DB {
+		query = query.Where(conds)
+		if status != "" {
+			query = query.Where("orders.status = ?", status)
+		}
+		return query
+	}
+
+	if err := apply(r.db.Model(&entity.Order{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := apply(r.orderQuery()).
+		Order("orders.created_at DESC, orders.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// Create menyimpan order beserta item dan event timeline pertamanya
+func (r *orderRepository) Create(order entity.Order) (entity.Order, error) {
+	err := r.db.Create(&order).Error
+	return order, err
+}
+
+// Transition mengubah status order secara atomik. Perubahan hanya berhasil
+// jika status di database masih sama dengan order.Status, sehingga dua
+// perubahan bersamaan tidak bisa sama-sama berhasil. Event timeline disimpan
+// di transaksi yang sama.
+func (r *orderRepository) Transition(order entity.Order, to string, event entity.OrderEvent, updates map[string]interface{}, settlements []entity.OrderSettlement) ([]entity.OrderSettlement, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		values := map[string]interface{}{
+			"status":     to,
+			"updated_at": time.Now(),
+		}
+		for column, value := range updates {
+			values[column] = value
+		}
+
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ?", order.ID, order.Status).
+			Updates(values)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderStatusChanged
+		}
+
+		event.OrderID = order.ID
+		event.FromStatus = order.Status
+		event.ToStatus = to
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		// Efek samping dicatat bersama status supaya tidak hilang jika
+		// proses berhenti sebelum efek sampingnya selesai
+		if len(settlements) == 0 {
+			return nil
+		}
+		return tx.Create(&settlements).Error
+	})
+	return settlements, err
+}
+
+// FindOverdueUnpaid mengambil order yang melewati batas waktu pembayaran.
+// Order dengan tagihan lunas yang belum sempat ditandai dibayar dilewati.
+func (r *orderRepository) FindOverdueUnpaid(now time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := r.db.Where("status = ? AND payment_due_at < ?", entity.OrderPendingPayment, now).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id AND payments.status = ?)", payment.StatusPaid).
+		Order("payment_due_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindDeliveredBefore mengambil order terkirim yang belum dikonfirmasi buyer
+func (r *orderRepository) FindDeliveredBefore(before time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := r.db.Where("status = ? AND delivered_at < ?", entity.OrderDelivered, before).
+		Order("delivered_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// AddSettlements mencatat efek samping yang gagal di luar perubahan status,
+// misalnya pelepasan stok saat checkout gagal
+func (r *orderRepository) AddSettlements(settlements []entity.OrderSettlement) error {
+	if len(settlements) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at"}),
+	}).Create(&settlements).Error
+}
+
+// FindPendingSettlements mengambil efek samping yang belum berhasil dan tidak
+// disentuh sejak waktu tertentu, supaya job tidak berebut dengan request yang
+// baru saja mengubah status
+func (r *orderRepository) FindPendingSettlements(before time.Time, limit int) ([]entity.OrderSettlement, error) {
+	var settlements []entity.OrderSettlement
+	err := r.db.Where("updated_at < ?", before).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&settlements).Error
+	return settlements, err
+}
+
+func (r *orderRepository) CompleteSettlement(id uint64) error {
+	return r.db.Delete(&entity.OrderSettlement{}, id).Error
+}
+
+// FailSettlement mencatat percobaan yang gagal; updated_at yang baru menunda
+// percobaan berikutnya sampai job berjalan lagi
+func (r *orderRepository) FailSettlement(id uint64, reason string) error {
+	return r.db.Model(&entity.OrderSettlement{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+			"updated_at": time.Now(),
+		}).Error
+}
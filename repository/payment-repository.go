@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"batik/entity"
+	"batik/payment"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookApply memutuskan hasil webhook untuk tagihan dan order yang sudah
+// dikunci. Status kosong berarti status tagihan tidak berubah.
+type WebhookApply func(p entity.Payment, order entity.Order) (status, result string)
+
+type PaymentRepository interface {
+	FindByID(id uint64) (entity.Payment, error)
+	FindByProviderRef(provider, ref string) (entity.Payment, error)
+	GetByOrder(orderID uint64) ([]entity.Payment, error)
+	FindPending(orderID uint64, method, channel string, now time.Time) (entity.Payment, error)
+	Create(p entity.Payment) (entity.Payment, error)
+	ApplyWebhook(webhook entity.PaymentWebhook, apply WebhookApply) (entity.Payment, entity.PaymentWebhook, error)
+	ExpirePending(now time.Time) (int64, error)
+	FindOrderSyncPending(before time.Time, limit int) ([]entity.Payment, error)
+	MarkOrderSynced(id uint64, needsRefund bool) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+func (r *paymentRepository) FindByID(id uint64) (entity.Payment, error) {
+	var p entity.Payment
+	err := r.db.First(&p, id).Error
+	return p, err
+}
+
+func (r *paymentRepository) FindByProviderRef(provider, ref string) (entity.Payment, error) {
+	var p entity.Payment
+	err := r.db.Where("provider = ? AND provider_ref = ?", provider, ref).First(&p).Error
+	return p, err
+}
+
+func (r *paymentRepository) GetByOrder(orderID uint64) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.Where("order_id = ?", orderID).Order("created_at DESC, id DESC").Find(&payments).Error
+	return payments, err
+}
+
+// FindPending mengambil tagihan yang masih bisa dibayar untuk metode dan
+// channel yang sama, agar permintaan ulang tidak membuat tagihan baru
+func (r *paymentRepository) FindPending(orderID uint64, method, channel string, now time.Time) (entity.Payment, error) {
+	var p entity.Payment
+	err := r.db.Where("order_id = ? AND method = ? AND channel = ? AND status = ? AND expires_at > ?",
+		orderID, method, channel, payment.StatusPending, now).
+		Order("id DESC").
+		First(&p).Error
+	return p, err
+}
+
+func (r *paymentRepository) Create(p entity.Payment) (entity.Payment, error) {
+	err := r.db.Create(&p).Error
+	return p, err
+}
+
+// ApplyWebhook mencatat webhook dan mengubah status tagihan dalam satu
+// transaksi. Baris tagihan dan ordernya dikunci agar dua webhook untuk
+// tagihan yang sama diproses bergantian dan status order yang dicocokkan
+// tidak basi; webhook dengan EventID yang sudah tercatat dikembalikan dengan
+// hasil WebhookDuplicate tanpa mengubah apa pun. Tagihan yang lunas untuk
+// order yang masih menunggu pembayaran ditandai OrderSyncPending.
+func (r *paymentRepository) ApplyWebhook(webhook entity.PaymentWebhook, apply WebhookApply) (entity.Payment, entity.PaymentWebhook, error) {
+	var p entity.Payment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND provider_ref = ?", webhook.Provider, webhook.ProviderRef).
+			First(&p).Error; err != nil {
+			return err
+		}
+
+		var existing entity.PaymentWebhook
+		err := tx.Where("provider = ? AND event_id = ?", webhook.Provider, webhook.EventID).First(&existing).Error
+		if err == nil {
+			webhook = existing
+			webhook.Result = entity.WebhookDuplicate
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", p.OrderID).
+			First(&order).Error; err != nil {
+			return err
+		}
+
+		status, result := apply(p, order)
+		if status != "" && status != p.Status {
+			values := map[string]interface{}{
+				"status":     status,
+				"updated_at": time.Now(),
+			}
+			if status == payment.StatusPaid {
+				now := time.Now()
+				values["paid_at"] = now
+				values["order_sync_pending"] = result == entity.WebhookApplied
+				values["needs_refund"] = result == entity.WebhookOrderNotPayable
+				p.PaidAt = &now
+				p.OrderSyncPending = result == entity.WebhookApplied
+				p.NeedsRefund = result == entity.WebhookOrderNotPayable
+			}
+			if err := tx.Model(&p).Updates(values).Error; err != nil {
+				return err
+			}
+			p.Status = status
+		}
+
+		webhook.PaymentID = &p.ID
+		webhook.Result = result
+		return tx.Create(&webhook).Error
+	})
+	return p, webhook, err
+}
+
+// ExpirePending menandai tagihan yang lewat batas waktu sebagai kedaluwarsa
+func (r *paymentRepository) ExpirePending(now time.Time) (int64, error) {
+	result := r.db.Model(&entity.Payment{}).
+		Where("status = ? AND expires_at < ?", payment.StatusPending, now).
+		Updates(map[string]interface{}{"status": payment.StatusExpired, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// FindOrderSyncPending mengambil tagihan lunas yang ordernya belum berhasil
+// ditandai dibayar dan tidak disentuh sejak waktu tertentu
+func (r *paymentRepository) FindOrderSyncPending(before time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.Where("order_sync_pending = ? AND updated_at < ?", true, before).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	return payments, err
+}
+
+// MarkOrderSynced menghapus tanda OrderSyncPending setelah order ditandai
+// dibayar, atau setelah ternyata order tidak bisa dibayar (needsRefund)
+func (r *paymentRepository) MarkOrderSynced(id uint64, needsRefund bool) error {
+	return r.db.Model(&entity.Payment{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"order_sync_pending": false,
+			"needs_refund":       needsRefund,
+			"updated_at":         time.Now(),
+		}).Error
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductAlertRepository interface {
+	RecordPrice(history entity.ProductPriceHistory) error
+	GetPriceHistory(productID int, since time.Time) ([]entity.ProductPriceHistory, error)
+	GetPriceBefore(productID int, before time.Time) (entity.ProductPriceHistory, error)
+	Upsert(alert entity.ProductAlert) (entity.ProductAlert, error)
+	FindByID(id uint64) (entity.ProductAlert, error)
+	GetByUser(userID uint64) ([]entity.ProductAlert, error)
+	Delete(id uint64) error
+	GetTriggeredPriceDrops(productIDs []int) ([]entity.ProductAlert, error)
+	GetPendingBackInStock(productID int) ([]entity.ProductAlert, error)
+	Claim(id uint64, notifiedAt time.Time) (bool, error)
+}
+
+type productAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewProductAlertRepository(db *gorm.DB) ProductAlertRepository {
+	return &productAlertRepository{
+		db: db,
+	}
+}
+
+func (r *productAlertRepository) RecordPrice(history entity.ProductPriceHistory) error {
+	return r.db.Create(&history).Error
+}
+
+// GetPriceHistory mengembalikan perubahan harga sejak waktu tertentu, yang
+// terlama dulu
+func (r *productAlertRepository) GetPriceHistory(productID int, since time.Time) ([]entity.ProductPriceHistory, error) {
+	var history []entity.ProductPriceHistory
+	err := r.db.Where("product_id = ? AND changed_at >= ?", productID, since).
+		Order("changed_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
+
+// GetPriceBefore mengembalikan harga yang berlaku tepat sebelum waktu tertentu
+func (r *productAlertRepository) GetPriceBefore(productID int, before time.Time) (entity.ProductPriceHistory, error) {
+	var history entity.ProductPriceHistory
+	err := r.db.Where("product_id = ? AND changed_at < ?", productID, before).
+		Order("changed_at DESC, id DESC").
+		First(&history).Error
+	return history, err
+}
+
+// alertPriceExpr adalah harga efektif untuk alert. Harga promosi yang dihitung
+// dari harga lama (produk baru diubah, job promosi belum berjalan) diabaikan
+// agar alert tidak terkirim dari harga yang sudah tidak berlaku.
+const alertPriceExpr = "CASE WHEN product_promotions.original_price = products.harga " +
+	"THEN product_promotions.effective_price ELSE products.harga END"
+
+// alertQuery memilih alert beserta nama, slug, dan harga efektif produknya
+func (r *productAlertRepository) alertQuery() *gorm.DB {
+	return r.db.Model(&entity.ProductAlert{}).
+		Select("product_alerts.*, products.name AS product_name, products.slug AS product_slug, " +
+			alertPriceExpr + " AS current_price").
+		Joins("JOIN products ON products.id = product_alerts.product_id").
+		Joins("LEFT JOIN product_promotions ON product_promotions.product_id = products.id")
+}
+
+// Upsert membuat alert atau memperbarui target alert yang sudah ada. Alert
+// yang sudah pernah dikirim diaktifkan kembali.
+func (r *productAlertRepository) Upsert(alert entity.ProductAlert) (entity.ProductAlert, error) {
+	now := time.Now()
+	alert.CreatedAt = now
+	alert.UpdatedAt = now
+	alert.NotifiedAt = nil
+
+	err := r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"target_price", "reference_price", "notified_at", "updated_at"}),
+	}).Create(&alert).Error
+	if err != nil {
+		return entity.ProductAlert{}, err
+	}
+
+	var saved entity.ProductAlert
+	err = r.alertQuery().
+		Where("product_alerts.user_id = ? AND product_alerts.product_id = ? AND product_alerts.type = ?", alert.UserID, alert.ProductID, alert.Type).
+		First(&saved).Error
+	return saved, err
+}
+
+func (r *productAlertRepository) FindByID(id uint64) (entity.ProductAlert, error) {
+	var alert entity.ProductAlert
+	err := r.alertQuery().Where("product_alerts.id = ?", id).First(&alert).Error
+	return alert, err
+}
+
+func (r *productAlertRepository) GetByUser(userID uint64) ([]entity.ProductAlert, error) {
+	var alerts []entity.ProductAlert
+	err := r.alertQuery().
+		Where("product_alerts.user_id = ?", userID).
+		Order("product_alerts.updated_at DESC, product_alerts.id DESC").
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *productAlertRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ProductAlert{}, id).Error
+}
+
+// GetTriggeredPriceDrops mengembalikan alert harga turun yang belum dikirim
+// dan terpenuhi oleh harga efektif produk published saat ini. productIDs
+// kosong berarti semua produk.
+func (r *productAlertRepository) GetTriggeredPriceDrops(productIDs []int) ([]entity.ProductAlert, error) {
+	var alerts []entity.ProductAlert
+	query := r.alertQuery().
+		Where("product_alerts.type = ? AND product_alerts.notified_at IS NULL", entity.ProductAlertPriceDrop).
+		Where("products.status = ? AND products.deleted_at IS NULL", entity.ProductStatusPublished).
+		Where("(product_alerts.target_price IS NOT NULL AND " + alertPriceExpr + " <= product_alerts.target_price) OR " +
+			"(product_alerts.target_price IS NULL AND " + alertPriceExpr + " < product_alerts.reference_price)")
+	if len(productIDs) > 0 {
+		query = query.Where("product_alerts.product_id IN ?", productIDs)
+	}
+	err := query.Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *productAlertRepository) GetPendingBackInStock(productID int) ([]entity.ProductAlert, error) {
+	var alerts []entity.ProductAlert
+	err := r.db.Where("product_id = ? AND type = ? AND notified_at IS NULL", productID, entity.ProductAlertBackInStock).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// Claim menandai alert sudah dikirim. Mengembalikan false jika alert sudah
+// diklaim proses lain, supaya notifikasi tidak terkirim dua kali.
+func (r *productAlertRepository) Claim(id uint64, notifiedAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.ProductAlert{}).
+		Where("id = ? AND notified_at IS NULL", id).
+		Update("notified_at", notifiedAt)
+	return result.RowsAffected > 0, result.Error
+}
@@ -2,12 +2,27 @@ package repository
 
 import (
 	"batik/entity"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrCategoryHasProducts dikembalikan saat kategori yang masih memiliki produk
+// dihapus tanpa kategori tujuan
+var ErrCategoryHasProducts = errors.New("kategori masih memiliki produk, tentukan kategori tujuan (reassign_to)")
+
 type ProductCategoryRepository interface {
 	GetProductCategory() ([]entity.ProductCategory, error)
+	CountPublicProducts() (map[int]int64, error)
+	FindByID(id int) (entity.ProductCategory, error)
+	SlugExists(slug string) bool
+	NextSortOrder(parentID *int) (int, error)
+	Create(category entity.ProductCategory) (entity.ProductCategory, error)
+	Update(category entity.ProductCategory) (entity.ProductCategory, error)
+	UpdateSortOrder(categoryIDs []int) error
+	Delete(category entity.ProductCategory, targetID int) ([]int, error)
+	GetProductIDs(categoryIDs []int) ([]int, error)
 }
 
 type productCategoryRepository struct {
@@ -23,9 +38,184 @@ func NewProductCategoryRepository(db *gorm.DB) ProductCategoryRepository {
 func (r *productCategoryRepository) GetProductCategory() ([]entity.ProductCategory, error) {
 	var articles []entity.ProductCategory
 
-	if err := r.db.Find(&articles).Error; err != nil {
+	if err := r.db.Order("sort_order ASC, id ASC").Find(&articles).Error; err != nil {
 		return nil, err
 	}
 
 	return articles, nil
-}
\ No newline at end of file
+}
+
+// CountPublicProducts menghitung produk publik per kategori (tanpa subkategori)
+func (r *productCategoryRepository) CountPublicProducts() (map[int]int64, error) {
+	var rows []struct {
+		CategoryID int
+		Count      int64
+	}
+	err := r.db.Model(&entity.ProductCard{}).
+		Select("products.category_id AS category_id, COUNT(*) AS count").
+		Joins(storeJoin).
+		Where("products.status = ?", entity.ProductStatusPublished).
+		Group("products.category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *productCategoryRepository) FindByID(id int) (entity.ProductCategory, error) {
+	var category entity.ProductCategory
+	err := r.db.First(&category, id).Error
+	return category, err
+}
+
+func (r *productCategoryRepository) SlugExists(slug string) bool {
+	var count int64
+	r.db.Model(&entity.ProductCategory{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+// NextSortOrder mengembalikan sort_order untuk kategori baru di akhir daftar saudaranya
+func (r *productCategoryRepository) NextSortOrder(parentID *int) (int, error) {
+	var max *int
+	query := r.db.Model(&entity.ProductCategory{}).Select("MAX(sort_order)")
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	if err := query.Scan(&max).Error; err != nil {
+		return 0, err
+	}
+	if max == nil {
+		return 0, nil
+	}
+	return *max + 1, nil
+}
+
+// Create menyimpan kategori baru. Subkategori mewarisi skema atribut kategori
+// induknya; kategori induk baru mendapat atribut umum.
+func (r *productCategoryRepository) Create(category entity.ProductCategory) (entity.ProductCategory, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+
+		if category.ParentID != nil {
+			return tx.Exec("INSERT INTO category_attributes "+
+				"(category_id, attribute_id, required, allowed_values, min_value, max_value, sort_order) "+
+				"SELECT ?, attribute_id, required, allowed_values, min_value, max_value, sort_order "+
+				"FROM category_attributes WHERE category_id = ?",
+				category.ID, *category.ParentID).Error
+		}
+		// Kategori induk baru mendapat atribut umum batik (yang bisa difilter);
+		// atribut khusus seperti ukuran atau dimensi diatur lewat skema kategori
+		return tx.Exec("INSERT INTO category_attributes (category_id, attribute_id, sort_order) "+
+			"SELECT ?, id, sort_order FROM attributes WHERE filterable = ?",
+			category.ID, true).Error
+	})
+	return category, err
+}
+
+func (r *productCategoryRepository) Update(category entity.ProductCategory) (entity.ProductCategory, error) {
+	err := r.db.Model(&category).Select("category_name", "slug", "parent_id", "icon", "sort_order", "updated_at").
+		Updates(&category).Error
+	return category, err
+}
+
+// UpdateSortOrder mengisi sort_order sesuai posisi ID di daftar
+func (r *productCategoryRepository) UpdateSortOrder(categoryIDs []int) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range categoryIDs {
+			err := tx.Model(&entity.ProductCategory{}).Where("id = ?", id).
+				Updates(map[string]interface{}{"sort_order": i, "updated_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete menghapus kategori setelah memindahkan semua produknya (termasuk yang
+// ada di trash) ke targetID. targetID 0 hanya boleh untuk kategori tanpa produk. Subkategori dipindah ke induk kategori yang dihapus.
+// Atribut produk yang tidak berlaku di kategori tujuan ikut dihapus.
+// Mengembalikan ID produk yang dipindahkan.
+func (r *productCategoryRepository) Delete(category entity.ProductCategory, targetID int) ([]int, error) {
+	var productIDs []int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Product{}).Unscoped().
+			Where("category_id = ?", category.ID).
+			Pluck("id", &productIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if len(productIDs) > 0 && targetID == 0 {
+			return ErrCategoryHasProducts
+		}
+
+		if len(productIDs) > 0 {
+			err = tx.Exec("DELETE FROM product_attributes WHERE product_id IN ? AND attribute_id NOT IN "+
+				"(SELECT attribute_id FROM category_attributes WHERE category_id = ?)", productIDs, targetID).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&entity.Product{}).Unscoped().
+				Where("id IN ?", productIDs).
+				Updates(map[string]interface{}{"category_id": targetID, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&entity.ProductCategory{}).
+			Where("parent_id = ?", category.ID).
+			Update("parent_id", category.ParentID).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM category_attributes WHERE category_id = ?", category.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.ProductCategory{}, category.ID).Error
+	})
+	return productIDs, err
+}
+
+// GetProductIDs mengembalikan ID produk (yang belum dihapus) di kategori tersebut
+func (r *productCategoryRepository) GetProductIDs(categoryIDs []int) ([]int, error) {
+	var ids []int
+	err := r.db.Model(&entity.Product{}).Where("category_id IN ?", categoryIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// categoryDescendantIDs mengembalikan ID kategori dengan slug yang diberikan
+// beserta semua subkategorinya
+func categoryDescendantIDs(db *gorm.DB, slugs []string) ([]int, error) {
+	var categories []entity.ProductCategory
+	if err := db.Select("id, parent_id, slug").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		wanted[slug] = true
+	}
+
+	var roots []int
+	for _, c := range categories {
+		if wanted[c.Slug] {
+			roots = append(roots, c.ID)
+		}
+	}
+	return entity.CategoryDescendants(categories, roots), nil
+}
@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"batik/entity"
+	"batik/search"
+	"batik/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Urutan listing produk publik. Sort kosong memakai urutan acak ber-seed.
+const (
+	ProductSortNewest     = "newest"
+	ProductSortPriceAsc   = "price_asc"
+	ProductSortPriceDesc  = "price_desc"
+	ProductSortPopularity = "popularity"
+	ProductSortRelevance  = "relevance"
+	ProductSortRating     = "rating"
+)
+
+// RegionAttributeCode adalah atribut yang dipakai untuk filter dan facet daerah asal batik
+const RegionAttributeCode = "origin_region"
+
+// Dimensi filter, dipakai untuk mengecualikan filter dimensi itu sendiri saat
+// menghitung facet (disjunctive faceting)
+const (
+	facetCategory  = "category"
+	facetStore     = "store"
+	facetRegion    = "region"
+	facetPrice     = "price"
+	facetAttribute = "attr:"
+)
+
+// ProductFilter adalah filter untuk listing produk publik
+type ProductFilter struct {
+	Search        string
+	InStockOnly   bool
+	MinPrice      float64
+	MaxPrice      float64
+	CategorySlugs []string
+	StoreID       int
+	Regions       []string
+	// Attributes berisi code atribut -> daftar nilai yang diterima (OR di
+	// dalam satu atribut, AND antar atribut)
+	Attributes map[string][]string
+	Sort       string
+	// searchHits adalah hasil full-text index untuk Search, diisi resolveFilter
+	// sekali per request agar count, listing, dan facet memakai hasil yang sama
+	searchHits     []search.Hit
+	searchResolved bool
+	// categoryIDs adalah ID kategori CategorySlugs beserta semua subkategorinya,
+	// diisi resolveFilter
+	categoryIDs        []int
+	categoriesResolved bool
+	// attributesResolved menandai Attributes sudah disaring resolveFilter
+	// sehingga hanya berisi atribut yang boleh dipakai sebagai filter
+	attributesResolved bool
+	// Seed menentukan urutan acak saat Sort kosong; seed yang sama selalu
+	// menghasilkan urutan yang sama sehingga halaman tidak saling tumpang tindih
+	Seed string
+}
+
+// cursorScope mengikat cursor listing produk ke urutan, seed, dan semua
+// filter request. Field tak diekspor (hasil resolveFilter) tidak ikut di-hash.
+func (f ProductFilter) cursorScope() string {
+	return utils.CursorScope("products", f)
+}
+
+// FacetCount adalah jumlah produk untuk satu nilai facet
+type FacetCount struct {
+	Value string `json:"value" gorm:"column:value"`
+	Label string `json:"label,omitempty" gorm:"column:label"`
+	Count int64  `json:"count" gorm:"column:count"`
+}
+
+// PriceRange adalah rentang harga untuk facet harga. Max 0 berarti tanpa batas atas.
+type PriceRange struct {
+	Key   string  `json:"key"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+	Count int64   `json:"count"`
+}
+
+// ProductFacets berisi jumlah produk per nilai filter
+type ProductFacets struct {
+	Categories  []FacetCount            `json:"categories"`
+	Stores      []FacetCount            `json:"stores"`
+	Regions     []FacetCount            `json:"regions"`
+	Attributes  map[string][]FacetCount `json:"attributes"`
+	PriceRanges []PriceRange            `json:"price_ranges"`
+}
+
+// priceRanges adalah bucket harga untuk facet harga
+var priceRanges = []PriceRange{
+	{Key: "under_100k", Min: 0, Max: 100000},
+	{Key: "100k_250k", Min: 100000, Max: 250000},
+	{Key: "250k_500k", Min: 250000, Max: 500000},
+	{Key: "500k_1m", Min: 500000, Max: 1000000},
+	{Key: "above_1m", Min: 1000000},
+}
+
+// popularityExpr adalah skor popularitas: unit terjual ditambah jumlah favorit.
+// Satu penjualan dihitung setara dua favorit. sold_count disimpan oleh
+// inventoryRepository (lihat refreshSoldCount).
+const popularityExpr = "products.sold_count * 2 + products.favorite_count"
+
+// publicProductQuery membangun query produk publik dengan semua filter kecuali
+// dimensi exclude (kosong berarti semua filter diterapkan)
+func (r *productRepository) publicProductQuery(filter ProductFilter, exclude string) *gorm.DB {
+	query := r.db.Model(&entity.ProductCard{}).
+		Joins(storeJoin).
+		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
+		Joins(promotionJoin).
+		Where("products.status = ?", entity.ProductStatusPublished)
+
+	if filter.InStockOnly {
+		query = query.Where(inStockExpr)
+	}
+
+	if filter.Search != "" {
+		// Pencarian lewat full-text index (nama, kategori, toko, atribut, deskripsi)
+		query = query.Where("products.id IN ?", hitIDs(filter.searchHits))
+	}
+
+	if exclude != facetPrice {
+		if filter.MinPrice > 0 {
+			query = query.Where(effectivePriceExpr+" >= ?", filter.MinPrice)
+		}
+		if filter.MaxPrice > 0 {
+			query = query.Where(effectivePriceExpr+" <= ?", filter.MaxPrice)
+		}
+	}
+
+	if exclude != facetCategory && len(filter.CategorySlugs) > 0 {
+		// Produk dari subkategori ikut tampil di kategori induknya
+		query = query.Where("products.category_id IN ?", filter.categoryIDs)
+	}
+
+	if exclude != facetStore && filter.StoreID > 0 {
+		query = query.Where("products.store_id = ?", filter.StoreID)
+	}
+
+	if exclude != facetRegion && len(filter.Regions) > 0 {
+		query = query.Where(
+			"products.id IN (SELECT product_id FROM product_attributes WHERE code = ? AND value IN ?)",
+			RegionAttributeCode, filter.Regions,
+		)
+	}
+
+	attributes := filter.Attributes
+	if strings.HasPrefix(exclude, facetAttribute) {
+		attributes = make(map[string][]string, len(filter.Attributes))
+		for code, values := range filter.Attributes {
+			if facetAttribute+code != exclude {
+				attributes[code] = values
+			}
+		}
+	}
+
+	return applyAttributeFilters(query, attributes)
+}
+
+// applyAttributeFilters menambahkan filter atribut ke query produk. Nilai
+// multi_enum disimpan dipisah koma, jadi setiap nilai juga dicocokkan dengan
+// FIND_IN_SET selain kesamaan penuh.
+func applyAttributeFilters(query *gorm.DB, attributes map[string][]string) *gorm.DB {
+	for code, values := range attributes {
+		if len(values) == 0 {
+			continue
+		}
+		conditions := make([]string, len(values))
+		vars := []interface{}{code}
+		for i, value := range values {
+			conditions[i] = "FIND_IN_SET(?, value) > 0"
+			vars = append(vars, value)
+		}
+		query = query.Where(
+			"products.id IN (SELECT product_id FROM product_attributes WHERE code = ? AND ("+
+				strings.Join(conditions, " OR ")+"))",
+			vars...,
+		)
+	}
+	return query
+}
+
+// filterableAttributeCodes mengembalikan code atribut yang boleh dipakai
+// sebagai filter: atribut filterable, dan bila kategori dipilih hanya atribut
+// yang ada di skema kategori tersebut
+func (r *productRepository) filterableAttributeCodes(categoryIDs []int) (map[string]bool, error) {
+	query := r.db.Model(&entity.Attribute{}).Where("filterable = ?", true)
+	if len(categoryIDs) > 0 {
+		query = query.Where("id IN (SELECT attribute_id FROM category_attributes WHERE category_id IN ?)", categoryIDs)
+	}
+
+	var codes []string
+	if err := query.Pluck("code", &codes).Error; err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		allowed[code] = true
+	}
+	return allowed, nil
+}
+
+// outOfStockExpr menandai produk yang stoknya dilacak dan sudah habis, juga
+// dipakai sebagai kolom OutOfStock di productCardSelect
+const outOfStockExpr = "(products.available_stock IS NOT NULL AND products.available_stock <= 0)"
+
+// productSort adalah urutan listing produk: kolom tambahan yang dipilih sebagai
+// SortKey, keyset urutan, dan cara membaca nilai keyset dari sebuah ProductCard
+type productSort struct {
+	joinSQL     string
+	joinVars    []interface{}
+	sortKeySQL  string
+	sortKeyVars []interface{}
+	keyset      utils.Keyset
+	keyOf       func(p entity.ProductCard) []interface{}
+}
+
+// newProductSort membangun urutan sesuai filter.Sort. Produk yang stoknya habis
+// selalu ditaruh di belakang, dan ID selalu menjadi kolom terakhir agar urutan
+// total (syarat paginasi cursor).
+func newProductSort(filter ProductFilter) productSort {
+	outOfStock := utils.KeysetColumn{Expr: outOfStockExpr, Alias: "OutOfStock"}
+	createdAtDesc := utils.KeysetColumn{Expr: "products.created_at", Desc: true}
+	idDesc := utils.KeysetColumn{Expr: "products.id", Desc: true}
+
+	newest := productSort{
+		keyset: utils.Keyset{outOfStock, createdAtDesc, idDesc},
+		keyOf: func(p entity.ProductCard) []interface{} {
+			return []interface{}{p.OutOfStock, p.CreatedAt, p.ID}
+		},
+	}
+
+	// sortKeyed membuat urutan berdasarkan ekspresi SortKey lalu terbaru
+	sortKeyed := func(expr string, vars []interface{}, desc bool) productSort {
+		return productSort{
+			sortKeySQL:  expr,
+			sortKeyVars: vars,
+			keyset: utils.Keyset{
+				outOfStock,
+				{Expr: expr, Vars: vars, Alias: "SortKey", Desc: desc},
+				createdAtDesc,
+				idDesc,
+			},
+			keyOf: func(p entity.ProductCard) []interface{} {
+				return []interface{}{p.OutOfStock, p.SortKey, p.CreatedAt, p.ID}
+			},
+		}
+	}
+
+	switch filter.Sort {
+	case ProductSortNewest:
+		return newest
+	case ProductSortPriceAsc, ProductSortPriceDesc:
+		return productSort{
+			keyset: utils.Keyset{
+				outOfStock,
+				{Expr: effectivePriceExpr, Alias: "EffectivePrice", Desc: filter.Sort == ProductSortPriceDesc},
+				idDesc,
+			},
+			keyOf: func(p entity.ProductCard) []interface{} {
+				return []interface{}{p.OutOfStock, p.EffectivePrice, p.ID}
+			},
+		}
+	case ProductSortRating:
+		// Rating tertinggi dulu; jumlah ulasan memisahkan rating yang sama
+		return productSort{
+			keyset: utils.Keyset{
+				outOfStock,
+				{Expr: "products.rating_avg", Desc: true},
+				{Expr: "products.rating_count", Desc: true},
+				idDesc,
+			},
+			keyOf: func(p entity.ProductCard) []interface{} {
+				return []interface{}{p.OutOfStock, p.RatingAvg, p.RatingCount, p.ID}
+			},
+		}
+	case ProductSortPopularity:
+		return sortKeyed(popularityExpr, nil, true)
+	case ProductSortRelevance:
+		if filter.Search == "" || len(filter.searchHits) == 0 {
+			return newest
+		}
+		// Peringkat BM25 dari search index: posisi hit ke-1 paling relevan
+		expr, vars := rankExpr("products.id", filter.searchHits)
+		return sortKeyed(expr, vars, false)
+	}
+
+	// Urutan acak tetapi deterministik berdasarkan hash CRC32 dari seed dan ID
+	// produk. Berbeda dengan RAND(), urutan ini stabil antar halaman. Hash untuk
+	// seed harian disimpan di product_shuffle_keys (lihat RefreshShuffleKeys)
+	// sehingga tidak dihitung ulang per request; produk yang belum punya kunci
+	// memakai hash yang sama secara langsung, jadi urutannya tetap konsisten.
+	shuffleExpr := "COALESCE(shuffle.shuffle_key, CRC32(CONCAT(?, '-', products.id)))"
+	shuffleVars := []interface{}{filter.Seed}
+	return productSort{
+		joinSQL:     "LEFT JOIN product_shuffle_keys AS shuffle ON shuffle.product_id = products.id AND shuffle.seed = ?",
+		joinVars:    []interface{}{filter.Seed},
+		sortKeySQL:  shuffleExpr,
+		sortKeyVars: shuffleVars,
+		keyset: utils.Keyset{
+			outOfStock,
+			{Expr: shuffleExpr, Vars: shuffleVars, Alias: "SortKey"},
+			{Expr: "products.id"},
+		},
+		keyOf: func(p entity.ProductCard) []interface{} {
+			return []interface{}{p.OutOfStock, p.SortKey, p.ID}
+		},
+	}
+}
+
+// selectColumns memilih kolom ProductCard beserta SortKey jika urutan memerlukannya
+func (ps productSort) selectColumns(query *gorm.DB) *gorm.DB {
+	if ps.joinSQL != "" {
+		query = query.Joins(ps.joinSQL, ps.joinVars...)
+	}
+	if ps.sortKeySQL == "" {
+		return query.Select(productCardSelect)
+	}
+	return query.Select(productCardSelect+", "+ps.sortKeySQL+" AS SortKey", ps.sortKeyVars...)
+}
+
+// maxSearchHits membatasi jumlah hasil full-text yang dipakai untuk filter SQL
+const maxSearchHits = 1000
+
+// resolveFilter menjalankan pencarian full-text untuk filter.Search dan
+// mengambil subkategori filter.CategorySlugs serta menyaring filter.Attributes
+// sekali per request
+func (r *productRepository) resolveFilter(filter ProductFilter) (ProductFilter, error) {
+	if filter.Search != "" && !filter.searchResolved {
+		filter.searchHits = r.index.Search(filter.Search, maxSearchHits)
+		filter.searchResolved = true
+	}
+	if len(filter.CategorySlugs) > 0 && !filter.categoriesResolved {
+		ids, err := categoryDescendantIDs(r.db, filter.CategorySlugs)
+		if err != nil {
+			return filter, err
+		}
+		filter.categoryIDs = ids
+		filter.categoriesResolved = true
+	}
+	if len(filter.Attributes) > 0 && !filter.attributesResolved {
+		// Atribut yang tidak filterable diabaikan, bukan ditolak, agar tautan
+		// filter lama tetap bisa dibuka
+		allowed, err := r.filterableAttributeCodes(filter.categoryIDs)
+		if err != nil {
+			return filter, err
+		}
+		attributes := make(map[string][]string, len(filter.Attributes))
+		for code, values := range filter.Attributes {
+			if allowed[code] {
+				attributes[code] = values
+			}
+		}
+		filter.Attributes = attributes
+		filter.attributesResolved = true
+	}
+	return filter, nil
+}
+
+// hitIDs mengambil ID dokumen dari hasil pencarian
+func hitIDs(hits []search.Hit) []int {
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+// rankExpr membuat ekspresi FIELD(column, id1, id2, ...) yang bernilai posisi
+// ID di hasil pencarian, sehingga ORDER BY ekspresi ini ASC mengikuti skor BM25
+func rankExpr(column string, hits []search.Hit) (string, []interface{}) {
+	vars := make([]interface{}, len(hits))
+	for i, hit := range hits {
+		vars[i] = hit.ID
+	}
+	return "FIELD(" + column + strings.Repeat(", ?", len(hits)) + ")", vars
+}
@@ -2,44 +2,103 @@ package repository
 
 import (
 	"batik/entity"
+	"batik/search"
+	"batik/utils"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type ProductRepository interface {
-	GetAllProductByStore(storeID, page, limit int, search string) ([]entity.Product, int64, error)
+	GetAllProductByStore(storeID, page, limit int, search, status string) ([]entity.Product, int64, error)
 	Create(product entity.Product) (entity.Product, error)
 	FindByID(id int) (entity.Product, error)
 	FindBySlug(slug string) (entity.Product, error)
 	Update(product entity.Product) (entity.Product, error)
 	Delete(id int) error
 	IsSlugExists(slug string) bool
-	GetAllPublicProduct(page, limit int, search string) ([]entity.ProductCard, int64, error)
+	GetAllPublicProduct(page, limit int, filter ProductFilter) ([]entity.ProductCard, int64, error)
+	GetAllPublicProductCursor(cursor *utils.Cursor, limit int, filter ProductFilter) ([]entity.ProductCard, *utils.CursorPagination, error)
+	GetProductFacets(filter ProductFilter) (ProductFacets, error)
 	GetLatestProduct()([]entity.ProductCard, error)
 	GetDetailProduct(slug string)(entity.ProductCard, error)
-	GetAllPublicProductByCategory(slug string, page, limit int) ([]entity.ProductCard, int64, error)
+	GetAllPublicProductByCategory(slug string, page, limit int, filter ProductFilter) ([]entity.ProductCard, int64, error)
+	PublishScheduled(now time.Time) (int64, error)
+	RefreshShuffleKeys(seeds []string) (int64, error)
+	FindDeletedBySlug(slug string) (entity.Product, error)
+	GetTrashByStore(storeID, page, limit int) ([]entity.Product, int64, error)
+	Restore(id int) error
+	FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Product, error)
+	ForceDelete(id int) error
+	FindAllByStoreUnscoped(storeID int) ([]entity.Product, error)
+	GetIndexableProducts(afterID, limit int) ([]entity.ProductCard, error)
+	GetIndexableProductsByIDs(ids []int) ([]entity.ProductCard, error)
+	GetIndexableProductsByStore(storeID int) ([]entity.ProductCard, error)
+	GetPublicCardsByIDs(ids []int) ([]entity.ProductCard, error)
 }
 
+// productCardSelect adalah kolom yang dipakai untuk ProductCard publik.
+// OutOfStock hanya true untuk produk yang stoknya dilacak dan sudah habis.
+const productCardSelect = "products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName, category_catalog.slug AS CategorySlug, " +
+	outOfStockExpr + " AS OutOfStock, " +
+	effectivePriceExpr + " AS EffectivePrice, products.harga AS OriginalPrice, " +
+	"COALESCE(product_promotions.badge, '') AS PromoBadge, product_promotions.ends_at AS PromoEndsAt"
+
+// publicProductCards memilih ProductCard yang tampil di katalog publik tanpa
+// filter listing, untuk daftar produk milik buyer seperti favorit dan wishlist
+func publicProductCards(db *gorm.DB) *gorm.DB {
+	return db.Model(&entity.ProductCard{}).
+		Select(productCardSelect).
+		Joins(storeJoin).
+		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
+		Joins(promotionJoin).
+		Where("products.status = ?", entity.ProductStatusPublished)
+}
+
+// storeJoin mengabaikan toko yang sudah dihapus (soft delete)
+const storeJoin = "JOIN stores ON stores.id = products.store_id AND stores.deleted_at IS NULL"
+
+// products.available_stock adalah total stok tersedia dari semua varian,
+// disimpan oleh inventoryRepository (lihat refreshAvailableStock). NULL berarti
+// stok produk tidak dilacak sehingga selalu dianggap tersedia.
+const inStockExpr = "(products.available_stock IS NULL OR products.available_stock > 0)"
+
+// promotionJoin mengambil harga efektif dari promosi yang menang. Baris yang
+// sudah berakhir atau dihitung dari harga lama diabaikan sampai job berikutnya.
+const promotionJoin = "LEFT JOIN product_promotions ON product_promotions.product_id = products.id " +
+	"AND product_promotions.original_price = products.harga AND product_promotions.ends_at > NOW()"
+
+// effectivePriceExpr adalah harga setelah promosi, dipakai untuk filter,
+// urutan, dan facet harga
+const effectivePriceExpr = "COALESCE(product_promotions.effective_price, products.harga)"
+
 type productRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	index search.SearchIndex
 }
 
-func NewProductRepository(db *gorm.DB) ProductRepository {
+func NewProductRepository(db *gorm.DB, index search.SearchIndex) ProductRepository {
 	return &productRepository {
-		db: db,
+		db:    db,
+		index: index,
 	}
 }
 
-func (r *productRepository) GetAllProductByStore(storeID, page, limit int, search string) ([]entity.Product, int64, error) {
+func (r *productRepository) GetAllProductByStore(storeID, page, limit int, search, status string) ([]entity.Product, int64, error) {
 	var products []entity.Product
 	var total int64
 
 	query := r.db.Model(&entity.Product{}).Where("store_id = ?", storeID)
+
+	// Filter status jika diberikan (katalog publik hanya menampilkan published)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
 	
-	// Apply search filter if provided
+	// Apply search filter if provided (full-text index, termasuk koreksi typo)
 	if search != "" {
-		query = query.Where("name LIKE ? OR description LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where("id IN ?", hitIDs(r.index.Search(search, maxSearchHits)))
 	}
 	
 	// Count total records
@@ -94,42 +153,36 @@ func (r *productRepository) Delete(id int) error {
 
 func (r *productRepository) IsSlugExists(slug string) bool {
 	var count int64
-	r.db.Model(&entity.Product{}).Where("slug = ?", slug).Count(&count)
+	// Unscoped: slug produk di trash tetap dianggap terpakai agar bisa di-restore
+	r.db.Unscoped().Model(&entity.Product{}).Where("slug = ?", slug).Count(&count)
 
 	return count > 0
 }
 
-func (r *productRepository) GetAllPublicProduct(page, limit int, search string) ([]entity.ProductCard, int64, error) {
+func (r *productRepository) GetAllPublicProduct(page, limit int, filter ProductFilter) ([]entity.ProductCard, int64, error) {
 	var (
 		products []entity.ProductCard
 		total    int64
 		err      error
 	)
 
-	query := r.db.Model(&entity.ProductCard{}).
-		Joins("JOIN stores ON stores.id = products.store_id").
-		Joins("JOIN category_catalog ON category_catalog.id = products.category_id")
-
-	if search != "" {
-		searchQuery := "%" + search + "%"
-		// Melakukan pencarian pada nama produk, deskripsi, nama toko, dan nama kategori
-		query = query.Where(
-			"products.name LIKE ? OR products.description LIKE ? OR stores.name LIKE ? OR category_catalog.category_name LIKE ?",
-			searchQuery, searchQuery, searchQuery, searchQuery,
-		)
+	filter, err = r.resolveFilter(filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Menghitung total data yang sesuai dengan filter
-	err = query.Count(&total).Error
+	err = r.publicProductQuery(filter, "").Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
 	offset := (page - 1) * limit
 
-	// Mengambil data produk dengan paginasi dan filter yang sudah diterapkan
-	err = query.Select("products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName, category_catalog.slug AS CategorySlug").
-		Order("RAND()").
+	// Mengambil data produk dengan paginasi, filter, dan urutan yang diminta
+	sort := newProductSort(filter)
+	err = sort.keyset.Order(sort.selectColumns(r.publicProductQuery(filter, ""))).
+		Preload("Attributes").
 		Offset(offset).
 		Limit(limit).
 		Find(&products).Error
@@ -146,13 +199,151 @@ func (r *productRepository) GetAllPublicProduct(page, limit int, search string)
 	return products, total, nil
 }
 
+// GetAllPublicProductCursor sama seperti GetAllPublicProduct tetapi memakai
+// paginasi keyset sehingga halaman tidak bergeser saat produk baru masuk
+func (r *productRepository) GetAllPublicProductCursor(cursor *utils.Cursor, limit int, filter ProductFilter) ([]entity.ProductCard, *utils.CursorPagination, error) {
+	var products []entity.ProductCard
+
+	// Scope dihitung dari filter request sebelum di-resolve
+	scope := filter.cursorScope()
+	filter, err := r.resolveFilter(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort := newProductSort(filter)
+	query, err := sort.keyset.Apply(sort.selectColumns(r.publicProductQuery(filter, "")), cursor, scope, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := query.Preload("Attributes").Find(&products).Error; err != nil {
+		return nil, nil, err
+	}
+
+	products, pagination := utils.NewCursorPagination(products, limit, cursor, scope, sort.keyOf)
+	return products, pagination, nil
+}
+
+// GetProductFacets menghitung jumlah produk per kategori, toko, daerah, atribut,
+// dan rentang harga. Setiap facet dihitung tanpa filter dimensinya sendiri agar
+// pilihan lain di dimensi yang sama tetap terlihat.
+func (r *productRepository) GetProductFacets(filter ProductFilter) (ProductFacets, error) {
+	facets := ProductFacets{Attributes: make(map[string][]FacetCount)}
+	filter, err := r.resolveFilter(filter)
+	if err != nil {
+		return ProductFacets{}, err
+	}
+
+	err = r.publicProductQuery(filter, facetCategory).
+		Select("category_catalog.slug AS value, category_catalog.category_name AS label, COUNT(DISTINCT products.id) AS count").
+		Group("category_catalog.id, category_catalog.slug, category_catalog.category_name").
+		Order("count DESC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return ProductFacets{}, err
+	}
+
+	err = r.publicProductQuery(filter, facetStore).
+		Select("CAST(stores.id AS CHAR) AS value, stores.name AS label, COUNT(DISTINCT products.id) AS count").
+		Group("stores.id, stores.name").
+		Order("count DESC").
+		Limit(maxFacetValues).
+		Scan(&facets.Stores).Error
+	if err != nil {
+		return ProductFacets{}, err
+	}
+
+	facets.Regions, err = r.attributeFacet(filter, facetRegion, RegionAttributeCode)
+	if err != nil {
+		return ProductFacets{}, err
+	}
+
+	var codes []string
+	err = r.db.Model(&entity.Attribute{}).
+		Where("filterable = ? AND type = ? AND code <> ?", true, entity.AttributeTypeEnum, RegionAttributeCode).
+		Order("sort_order ASC").
+		Pluck("code", &codes).Error
+	if err != nil {
+		return ProductFacets{}, err
+	}
+	for _, code := range codes {
+		counts, err := r.attributeFacet(filter, facetAttribute+code, code)
+		if err != nil {
+			return ProductFacets{}, err
+		}
+		facets.Attributes[code] = counts
+	}
+
+	facets.PriceRanges, err = r.priceFacet(filter)
+	if err != nil {
+		return ProductFacets{}, err
+	}
+
+	return facets, nil
+}
+
+// maxFacetValues membatasi jumlah nilai per facet bertipe bebas (toko, daerah)
+const maxFacetValues = 20
+
+func (r *productRepository) attributeFacet(filter ProductFilter, exclude, code string) ([]FacetCount, error) {
+	var counts []FacetCount
+	err := r.publicProductQuery(filter, exclude).
+		Joins("JOIN product_attributes AS facet_attr ON facet_attr.product_id = products.id AND facet_attr.code = ?", code).
+		Select("facet_attr.value AS value, COUNT(DISTINCT products.id) AS count").
+		Group("facet_attr.value").
+		Order("count DESC").
+		Limit(maxFacetValues).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *productRepository) priceFacet(filter ProductFilter) ([]PriceRange, error) {
+	// Setiap produk dipetakan ke indeks bucket di priceRanges
+	bucketSQL := "CASE"
+	var vars []interface{}
+	for i, pr := range priceRanges {
+		if pr.Max > 0 {
+			bucketSQL += " WHEN " + effectivePriceExpr + " < ? THEN ?"
+			vars = append(vars, pr.Max, i)
+		} else {
+			bucketSQL += " ELSE ?"
+			vars = append(vars, i)
+		}
+	}
+	bucketSQL += " END AS bucket, COUNT(DISTINCT products.id) AS count"
+
+	var rows []struct {
+		Bucket int   `gorm:"column:bucket"`
+		Count  int64 `gorm:"column:count"`
+	}
+	err := r.publicProductQuery(filter, facetPrice).
+		Select(bucketSQL, vars...).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]PriceRange, len(priceRanges))
+	copy(ranges, priceRanges)
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(ranges) {
+			ranges[row.Bucket].Count = row.Count
+		}
+	}
+	return ranges, nil
+}
+
 func (r *productRepository) GetLatestProduct()([]entity.ProductCard, error) {
 	var products []entity.ProductCard
 
 	if err := r.db.Debug().Model(&entity.ProductCard{}).
-		Select("products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName, category_catalog.slug AS CategorySlug").
-		Joins("JOIN stores ON stores.id = products.store_id").
+		Select(productCardSelect).
+		Joins(storeJoin).
 		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
+		Joins(promotionJoin).
+		Where(inStockExpr).
+		Where("products.status = ?", entity.ProductStatusPublished).
 		// Preload("Images").
 		Order("products.created_at DESC").
 		Limit(8).
@@ -167,13 +358,17 @@ func (r *productRepository) GetDetailProduct(slug string)(entity.ProductCard, er
 	var product entity.ProductCard
 
 	if err := r.db.Debug().Model(&entity.ProductCard{}).
-		Select("products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName, category_catalog.slug AS CategorySlug").
-		Joins("JOIN stores ON stores.id = products.store_id").
+		Select(productCardSelect).
+		Joins(storeJoin).
 		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
+		Joins(promotionJoin).
 		Preload("Images").
 		Preload("Store").
 		Preload("Category").
+		Preload("Attributes").
+		Preload("Questions", detailQuestions).
 		Where("products.slug = ?", slug).
+		Where("products.status = ?", entity.ProductStatusPublished).
 		First(&product).Error; err != nil {
 			return entity.ProductCard{}, err
 		}
@@ -181,36 +376,180 @@ func (r *productRepository) GetDetailProduct(slug string)(entity.ProductCard, er
 		return product, nil
 }
 
-func (r *productRepository) GetAllPublicProductByCategory(slug string, page, limit int) ([]entity.ProductCard, int64, error) {
+// GetPublicCardsByIDs mengambil ProductCard publik beserta harga efektifnya.
+// Produk yang tidak lagi tampil di katalog tidak ikut dikembalikan.
+func (r *productRepository) GetPublicCardsByIDs(ids []int) ([]entity.ProductCard, error) {
 	var products []entity.ProductCard
-	var total int64
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := publicProductCards(r.db).Where("products.id IN ?", ids).Find(&products).Error
+	return products, err
+}
 
-	err := r.db.Debug().Model(&entity.ProductCard{}).
-		Joins("JOIN stores ON stores.id = products.store_id").
-		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
-		Count(&total).Error
+// detailQuestionLimit adalah jumlah pertanyaan terjawab di detail produk;
+// selebihnya diambil dari endpoint daftar pertanyaan
+const detailQuestionLimit = 5
+
+// detailQuestions memuat pertanyaan yang sudah dijawab, upvote terbanyak dulu
+func detailQuestions(db *gorm.DB) *gorm.DB {
+	return db.Select("product_questions.*, users.name AS asker_name").
+		Joins("LEFT JOIN users ON users.id = product_questions.user_id").
+		Where("product_questions.status = ? AND product_questions.answered_at IS NOT NULL", entity.QuestionStatusPublished).
+		Order("product_questions.upvote_count DESC, product_questions.answered_at DESC").
+		Limit(detailQuestionLimit)
+}
+
+func (r *productRepository) GetAllPublicProductByCategory(slug string, page, limit int, filter ProductFilter) ([]entity.ProductCard, int64, error) {
+	// Total dihitung dengan filter kategori yang sama dengan listing, termasuk subkategori
+	filter.CategorySlugs = []string{slug}
+	filter.categoriesResolved = false
+	return r.GetAllPublicProduct(page, limit, filter)
+}
+
+// PublishScheduled mempublikasikan produk terjadwal yang waktu tayangnya sudah lewat
+func (r *productRepository) PublishScheduled(now time.Time) (int64, error) {
+	result := r.db.Model(&entity.Product{}).
+		Where("status = ? AND publish_at <= ?", entity.ProductStatusScheduled, now).
+		Updates(map[string]interface{}{
+			"status":     entity.ProductStatusPublished,
+			"updated_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// RefreshShuffleKeys menyimpan kunci urutan acak untuk setiap seed aktif dan
+// menghapus kunci dari seed yang sudah tidak dipakai. Hanya produk yang belum
+// punya kunci untuk seed tersebut yang dihitung, sehingga pemanggilan berkala
+// cukup menambahkan produk baru.
+func (r *productRepository) RefreshShuffleKeys(seeds []string) (int64, error) {
+	var inserted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_shuffle_keys WHERE seed NOT IN ?", seeds).Error; err != nil {
+			return err
+		}
+		for _, seed := range seeds {
+			result := tx.Exec("INSERT IGNORE INTO product_shuffle_keys (seed, product_id, shuffle_key) "+
+				"SELECT ?, products.id, CRC32(CONCAT(?, '-', products.id)) FROM products "+
+				"LEFT JOIN product_shuffle_keys AS shuffle ON shuffle.product_id = products.id AND shuffle.seed = ? "+
+				"WHERE shuffle.product_id IS NULL AND products.deleted_at IS NULL",
+				seed, seed, seed)
+			if result.Error != nil {
+				return result.Error
+			}
+			inserted += result.RowsAffected
+		}
+		return nil
+	})
+	return inserted, err
+}
+
+func (r *productRepository) FindDeletedBySlug(slug string) (entity.Product, error) {
+	var product entity.Product
+
+	err := r.db.Unscoped().Where("slug = ? AND deleted_at IS NOT NULL", slug).First(&product).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return product, errors.New("product not found in trash")
+		}
+		return product, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) GetTrashByStore(storeID, page, limit int) ([]entity.Product, int64, error) {
+	var products []entity.Product
+	var total int64
+
+	query := r.db.Unscoped().Model(&entity.Product{}).Where("deleted_at IS NOT NULL")
+
+	// storeID 0 dipakai admin untuk melihat trash semua toko
+	if storeID > 0 {
+		query = query.Where("store_id = ?", storeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	offset := (page - 1) * limit
-	err = r.db.Debug().Model(&entity.ProductCard{}).
-		Select("products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName, category_catalog.slug AS CategorySlug").
-		Joins("JOIN stores ON stores.id = products.store_id").
-		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
-		// Preload("Images").
-		Order("RAND()").
-		Offset(offset).
-		Where("category_catalog.slug = ?", slug).
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+func (r *productRepository) Restore(id int) error {
+	return r.db.Unscoped().Model(&entity.Product{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// FindPurgeable mengambil produk yang sudah di trash sebelum deletedBefore,
+// termasuk semua gambarnya (juga yang sudah di-soft delete)
+func (r *productRepository) FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Product, error) {
+	var products []entity.Product
+	err := r.db.Unscoped().
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
 		Limit(limit).
 		Find(&products).Error
+	return products, err
+}
 
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return []entity.ProductCard{}, 0, nil // Kembalikan slice kosong jika tidak ada data
+// ForceDelete menghapus produk beserta gambar dan atributnya secara permanen
+func (r *productRepository) ForceDelete(id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("product_id = ?", id).Delete(&entity.ProductImage{}).Error; err != nil {
+			return err
 		}
-		return nil, 0, err
-	}
-	return products, total, nil
+		if err := tx.Where("product_id = ?", id).Delete(&entity.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&entity.Product{}, id).Error
+	})
 }
 
+// FindAllByStoreUnscoped mengambil semua produk toko, termasuk yang di trash,
+// beserta semua gambarnya. Dipakai saat toko dihapus permanen.
+func (r *productRepository) FindAllByStoreUnscoped(storeID int) ([]entity.Product, error) {
+	var products []entity.Product
+	err := r.db.Unscoped().
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("store_id = ?", storeID).
+		Find(&products).Error
+	return products, err
+}
+
+// indexableProductQuery memilih produk beserta nama toko dan kategori untuk
+// search index. Semua status ikut di-index (dashboard penjual juga mencari
+// draft); katalog publik tetap memfilter status dan toko yang dihapus.
+func (r *productRepository) indexableProductQuery() *gorm.DB {
+	return r.db.Model(&entity.ProductCard{}).
+		Select("products.*, stores.name AS StoreName, category_catalog.category_name AS CategoryName").
+		Joins("LEFT JOIN stores ON stores.id = products.store_id").
+		Joins("LEFT JOIN category_catalog ON category_catalog.id = products.category_id").
+		Preload("Attributes")
+}
+
+// GetIndexableProducts mengambil produk per batch berdasarkan ID untuk membangun ulang index
+func (r *productRepository) GetIndexableProducts(afterID, limit int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := r.indexableProductQuery().
+		Where("products.id > ?", afterID).
+		Order("products.id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) GetIndexableProductsByIDs(ids []int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := r.indexableProductQuery().Where("products.id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) GetIndexableProductsByStore(storeID int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := r.indexableProductQuery().Where("products.store_id = ?", storeID).Find(&products).Error
+	return products, err
+}
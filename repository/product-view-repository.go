@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProductViewRepository interface {
+	RecordBatch(views []entity.ProductView, dedupeWindow time.Duration) error
+	GetRecentProducts(viewerKey string, limit int) ([]entity.ProductCard, error)
+	Clear(viewerKey string) error
+	Merge(fromKey, toKey string, userID uint64) error
+	GetStoreTotals(storeID int, since time.Time) (int64, int64, error)
+	GetStoreStats(storeID int, since time.Time, limit int) ([]entity.ProductViewStat, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+type productViewRepository struct {
+	db *gorm.DB
+}
+
+func NewProductViewRepository(db *gorm.DB) ProductViewRepository {
+	return &productViewRepository{
+		db: db,
+	}
+}
+
+// RecordBatch mencatat banyak kunjungan sekaligus dalam satu transaksi.
+// Kunjungan ulang oleh viewer yang sama dalam dedupeWindow hanya memperbarui
+// waktu kunjungan terakhir, supaya refresh halaman tidak terhitung sebagai
+// kunjungan baru; kunjungan baru disimpan dengan batch insert.
+func (r *productViewRepository) RecordBatch(views []entity.ProductView, dedupeWindow time.Duration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var inserts []entity.ProductView
+		for _, view := range views {
+			result := tx.Model(&entity.ProductView{}).
+				Where("viewer_key = ? AND product_id = ? AND viewed_at >= ?",
+					view.ViewerKey, view.ProductID, view.ViewedAt.Add(-dedupeWindow)).
+				Update("viewed_at", view.ViewedAt)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				inserts = append(inserts, view)
+			}
+		}
+		if len(inserts) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&inserts, 100).Error
+	})
+}
+
+// GetRecentProducts mengembalikan produk yang masih tampil di katalog,
+// yang terakhir dilihat lebih dulu
+func (r *productViewRepository) GetRecentProducts(viewerKey string, limit int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := publicProductCards(r.db).
+		Joins("JOIN (SELECT product_id, MAX(viewed_at) AS last_viewed_at FROM product_views WHERE viewer_key = ? GROUP BY product_id) AS recent "+
+			"ON recent.product_id = products.id", viewerKey).
+		Preload("Attributes").
+		Order("recent.last_viewed_at DESC, products.id DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productViewRepository) Clear(viewerKey string) error {
+	return r.db.Where("viewer_key = ?", viewerKey).Delete(&entity.ProductView{}).Error
+}
+
+// Merge memindahkan riwayat pengunjung anonim ke akun user
+func (r *productViewRepository) Merge(fromKey, toKey string, userID uint64) error {
+	return r.db.Model(&entity.ProductView{}).
+		Where("viewer_key = ?", fromKey).
+		Updates(map[string]interface{}{
+			"viewer_key": toKey,
+			"user_id":    userID,
+		}).Error
+}
+
+// GetStoreTotals menghitung total kunjungan dan jumlah pengunjung unik toko
+func (r *productViewRepository) GetStoreTotals(storeID int, since time.Time) (int64, int64, error) {
+	var totals struct {
+		Views   int64 `gorm:"column:views"`
+		Viewers int64 `gorm:"column:viewers"`
+	}
+	err := r.db.Model(&entity.ProductView{}).
+		Select("COUNT(*) AS views, COUNT(DISTINCT viewer_key) AS viewers").
+		Where("store_id = ? AND viewed_at >= ?", storeID, since).
+		Scan(&totals).Error
+	return totals.Views, totals.Viewers, err
+}
+
+// GetStoreStats mengembalikan produk toko yang paling banyak dikunjungi
+func (r *productViewRepository) GetStoreStats(storeID int, since time.Time, limit int) ([]entity.ProductViewStat, error) {
+	var stats []entity.ProductViewStat
+	err := r.db.Model(&entity.ProductView{}).
+		Select("product_views.product_id, products.name, products.slug, "+
+			"COUNT(*) AS views, COUNT(DISTINCT product_views.viewer_key) AS viewers").
+		Joins("JOIN products ON products.id = product_views.product_id").
+		Where("product_views.store_id = ? AND product_views.viewed_at >= ?", storeID, since).
+		Group("product_views.product_id, products.name, products.slug").
+		Order("views DESC, product_views.product_id DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// DeleteBefore menghapus riwayat kunjungan yang lebih lama dari batas retensi
+func (r *productViewRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("viewed_at < ?", before).Delete(&entity.ProductView{})
+	return result.RowsAffected, result.Error
+}
@@ -4,6 +4,7 @@ import (
 	"batik/entity"
 	"fmt"
 	"log"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,6 +18,8 @@ type ProductImageRepository interface {
 	DeleteMultiple(ids []int) error // ✅ NEW: Batch delete
 	FindByID(id int) (entity.ProductImage, error)
 	DeleteByImagePath(imagePath string) error // ✅ NEW: Delete by path
+	FindDeletedBefore(deletedBefore time.Time, limit int) ([]entity.ProductImage, error)
+	ForceDeleteMultiple(ids []int) error
 }
 
 type productImageRepository struct {
@@ -164,4 +167,22 @@ func (r *productImageRepository) FindByID(id int) (entity.ProductImage, error) {
 	var image entity.ProductImage
 	err := r.db.Where("id = ?", id).First(&image).Error
 	return image, err
-}
\ No newline at end of file
+}
+
+// FindDeletedBefore mengambil gambar yang di-soft delete sebelum deletedBefore
+func (r *productImageRepository) FindDeletedBefore(deletedBefore time.Time, limit int) ([]entity.ProductImage, error) {
+	var images []entity.ProductImage
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Limit(limit).
+		Find(&images).Error
+	return images, err
+}
+
+// ForceDeleteMultiple menghapus gambar secara permanen
+func (r *productImageRepository) ForceDeleteMultiple(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Unscoped().Where("id IN ?", ids).Delete(&entity.ProductImage{}).Error
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromotionItem adalah data produk yang dibutuhkan untuk memilih promosi
+type PromotionItem struct {
+	ID         int     `gorm:"column:id"`
+	StoreID    int     `gorm:"column:store_id"`
+	CategoryID int     `gorm:"column:category_id"`
+	Harga      float64 `gorm:"column:harga"`
+}
+
+type PromotionRepository interface {
+	FindByID(id uint64) (entity.Promotion, error)
+	GetByStore(storeID int) ([]entity.Promotion, error)
+	GetCampaigns() ([]entity.Promotion, error)
+	GetRunningCampaigns(now time.Time) ([]entity.Promotion, error)
+	GetRunning(now time.Time) ([]entity.Promotion, error)
+	CountStoreProducts(storeID int, productIDs []int) (int64, error)
+	Create(promotion entity.Promotion) (entity.Promotion, error)
+	Update(promotion entity.Promotion) (entity.Promotion, error)
+	Delete(id uint64) error
+	GetPromotionItems() ([]PromotionItem, error)
+	GetCategoryParents() (map[int]*int, error)
+	ReplaceProductPromotions(prices []entity.ProductPromotion) error
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+func (r *promotionRepository) FindByID(id uint64) (entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := r.db.Preload("Products").First(&promotion, id).Error
+	return promotion, err
+}
+
+// GetByStore mengembalikan semua promosi toko, yang terbaru dulu
+func (r *promotionRepository) GetByStore(storeID int) ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Preload("Products").
+		Where("store_id = ?", storeID).
+		Order("starts_at DESC, id DESC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// GetCampaigns mengembalikan semua kampanye platform untuk admin
+func (r *promotionRepository) GetCampaigns() ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Where("store_id IS NULL").
+		Order("starts_at DESC, id DESC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// GetRunningCampaigns mengembalikan kampanye platform yang sedang berjalan
+func (r *promotionRepository) GetRunningCampaigns(now time.Time) ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Where("store_id IS NULL AND active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Order("ends_at ASC, id ASC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// GetRunning mengembalikan semua promosi toko dan kampanye platform yang
+// sedang berjalan beserta produknya
+func (r *promotionRepository) GetRunning(now time.Time) ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Preload("Products").
+		Where("active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// CountStoreProducts menghitung berapa dari productIDs yang milik toko
+func (r *promotionRepository) CountStoreProducts(storeID int, productIDs []int) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Product{}).
+		Where("store_id = ? AND id IN ?", storeID, productIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// Create menyimpan promosi beserta daftar produknya
+func (r *promotionRepository) Create(promotion entity.Promotion) (entity.Promotion, error) {
+	err := r.db.Create(&promotion).Error
+	return promotion, err
+}
+
+// Update menyimpan perubahan promosi dan mengganti daftar produknya
+func (r *promotionRepository) Update(promotion entity.Promotion) (entity.Promotion, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Promotion{}).Where("id = ?", promotion.ID).
+			Updates(map[string]interface{}{
+				"name":           promotion.Name,
+				"badge":          promotion.Badge,
+				"discount_type":  promotion.DiscountType,
+				"discount_value": promotion.DiscountValue,
+				"max_discount":   promotion.MaxDiscount,
+				"scope":          promotion.Scope,
+				"category_id":    promotion.CategoryID,
+				"starts_at":      promotion.StartsAt,
+				"ends_at":        promotion.EndsAt,
+				"active":         promotion.Active,
+				"updated_at":     time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("promotion_id = ?", promotion.ID).Delete(&entity.PromotionProduct{}).Error; err != nil {
+			return err
+		}
+		if len(promotion.Products) == 0 {
+			return nil
+		}
+		for i := range promotion.Products {
+			promotion.Products[i].ID = 0
+			promotion.Products[i].PromotionID = promotion.ID
+		}
+		return tx.Create(&promotion.Products).Error
+	})
+	return promotion, err
+}
+
+// Delete menghapus promosi beserta produk dan harga efektif yang dihasilkannya
+func (r *promotionRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", id).Delete(&entity.PromotionProduct{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", id).Delete(&entity.ProductPromotion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Promotion{}, id).Error
+	})
+}
+
+// GetPromotionItems mengambil produk publik yang bisa mendapat promosi
+func (r *promotionRepository) GetPromotionItems() ([]PromotionItem, error) {
+	var items []PromotionItem
+	err := r.db.Table("products").
+		Select("products.id, products.store_id, products.category_id, products.harga").
+		Joins(storeJoin).
+		Where(publicProductCondition, entity.ProductStatusPublished).
+		Where("products.harga > 0").
+		Scan(&items).Error
+	return items, err
+}
+
+// GetCategoryParents memetakan ID kategori ke induknya, untuk promosi
+// kategori yang juga berlaku di subkategori
+func (r *promotionRepository) GetCategoryParents() (map[int]*int, error) {
+	var rows []struct {
+		ID       int  `gorm:"column:id"`
+		ParentID *int `gorm:"column:parent_id"`
+	}
+	if err := r.db.Table("category_catalog").Select("id, parent_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	parents := make(map[int]*int, len(rows))
+	for _, row := range rows {
+		parents[row.ID] = row.ParentID
+	}
+	return parents, nil
+}
+
+// ReplaceProductPromotions mengganti seluruh harga efektif dengan hasil
+// perhitungan baru
+func (r *promotionRepository) ReplaceProductPromotions(prices []entity.ProductPromotion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_promotions").Error; err != nil {
+			return err
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&prices, 500).Error
+	})
+}
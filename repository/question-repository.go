@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"batik/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrQuestionAlreadyFlagged = errors.New("pertanyaan ini sudah Anda laporkan")
+
+// Urutan daftar pertanyaan
+const (
+	QuestionSortTop    = "top"
+	QuestionSortNewest = "newest"
+)
+
+// Filter status jawaban pertanyaan
+const (
+	QuestionAnswered   = "answered"
+	QuestionUnanswered = "unanswered"
+)
+
+// QuestionStatusFlagged dipakai di daftar moderasi untuk pertanyaan yang
+// pernah dilaporkan, apa pun statusnya
+const QuestionStatusFlagged = "flagged"
+
+// QuestionFilter adalah filter daftar pertanyaan produk
+type QuestionFilter struct {
+	Answered string
+	Sort     string
+}
+
+type QuestionRepository interface {
+	FindByID(id uint64) (entity.ProductQuestion, error)
+	GetByProduct(productID int, filter QuestionFilter, page, limit int) ([]entity.ProductQuestion, int64, error)
+	GetByStore(storeID int, filter QuestionFilter, page, limit int) ([]entity.ProductQuestion, int64, error)
+	GetForModeration(status string, page, limit int) ([]entity.ProductQuestion, int64, error)
+	Create(question entity.ProductQuestion) (entity.ProductQuestion, error)
+	Delete(id uint64) error
+	UpdateAnswer(id uint64, answer string, answeredBy *uint64, answeredAt *time.Time) error
+	Upvote(questionID, userID uint64) error
+	RemoveUpvote(questionID, userID uint64) error
+	AddFlag(flag entity.ProductQuestionFlag, hideThreshold int) error
+	Moderate(id uint64, status string) error
+}
+
+type questionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &questionRepository{
+		db: db,
+	}
+}
+
+// questionQuery memilih pertanyaan beserta nama penanya
+func (r *questionRepository) questionQuery() *gorm.DB {
+	return r.db.Model(&entity.ProductQuestion{}).
+		Select("product_questions.*, users.name AS asker_name").
+		Joins("LEFT JOIN users ON users.id = product_questions.user_id")
+}
+
+func (r *questionRepository) FindByID(id uint64) (entity.ProductQuestion, error) {
+	var question entity.ProductQuestion
+	err := r.questionQuery().Where("product_questions.id = ?", id).First(&question).Error
+	return question, err
+}
+
+// GetByProduct mengembalikan pertanyaan produk yang tampil untuk publik
+func (r *questionRepository) GetByProduct(productID int, filter QuestionFilter, page, limit int) ([]entity.ProductQuestion, int64, error) {
+	query := r.db.Where("product_questions.product_id = ? AND product_questions.status = ?", productID, entity.QuestionStatusPublished)
+	return r.paginate(query, filter, page, limit)
+}
+
+// GetByStore mengembalikan pertanyaan semua produk toko untuk dijawab
+// penjual. Tanpa urutan khusus, pertanyaan yang belum dijawab tampil dulu.
+func (r *questionRepository) GetByStore(storeID int, filter QuestionFilter, page, limit int) ([]entity.ProductQuestion, int64, error) {
+	query := r.db.Where("product_questions.product_id IN (SELECT id FROM products WHERE store_id = ?) AND product_questions.status = ?",
+		storeID, entity.QuestionStatusPublished)
+
+	order := "product_questions.answered_at IS NOT NULL, product_questions.created_at DESC, product_questions.id DESC"
+	if filter.Sort != "" {
+		return r.paginate(query, filter, page, limit)
+	}
+	return r.paginateOrdered(query, filter, order, page, limit)
+}
+
+// GetForModeration mengembalikan pertanyaan berdasarkan status moderasi.
+// Status flagged mengembalikan pertanyaan yang pernah dilaporkan, terbanyak dulu.
+func (r *questionRepository) GetForModeration(status string, page, limit int) ([]entity.ProductQuestion, int64, error) {
+	if status == QuestionStatusFlagged {
+		query := r.db.Where("product_questions.flag_count > 0")
+		return r.paginateOrdered(query, QuestionFilter{}, "product_questions.flag_count DESC, product_questions.id DESC", page, limit)
+	}
+	query := r.db.Where("product_questions.status = ?", status)
+	return r.paginate(query, QuestionFilter{Sort: QuestionSortNewest}, page, limit)
+}
+
+func (r *questionRepository) paginate(conds *gorm.DB, filter QuestionFilter, page, limit int) ([]entity.ProductQuestion, int64, error) {
+	order := "product_questions.created_at DESC, product_questions.id DESC"
+	if filter.Sort != QuestionSortNewest {
+		order = "product_questions.upvote_count DESC, " + order
+	}
+	return r.paginateOrdered(conds, filter, order, page, limit)
+}
+
+func (r *questionRepository) paginateOrdered(conds *gorm.DB, filter QuestionFilter, order string, page, limit int) ([]entity.ProductQuestion, int64, error) {
+	var (
+		questions []entity.ProductQuestion
+		total     int64
+	)
+
+	apply := func(query *gorm.DB) *gorm.DB {
+		query = query.Where(conds)
+		switch filter.Answered {
+		case QuestionAnswered:
+			query = query.Where("product_questions.answered_at IS NOT NULL")
+		case QuestionUnanswered:
+			query = query.Where("product_questions.answered_at IS NULL")
+		}
+		return query
+	}
+
+	if err := apply(r.db.Model(&entity.ProductQuestion{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := apply(r.questionQuery()).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *questionRepository) Create(question entity.ProductQuestion) (entity.ProductQuestion, error) {
+	err := r.db.Create(&question).Error
+	return question, err
+}
+
+// Delete menghapus pertanyaan beserta upvote dan laporannya
+func (r *questionRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("question_id = ?", id).Delete(&entity.ProductQuestionVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&entity.ProductQuestionFlag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.ProductQuestion{}, id).Error
+	})
+}
+
+// UpdateAnswer menyimpan jawaban toko. Answer kosong menghapus jawaban.
+func (r *questionRepository) UpdateAnswer(id uint64, answer string, answeredBy *uint64, answeredAt *time.Time) error {
+	return r.db.Model(&entity.ProductQuestion{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"answer":      answer,
+			"answered_by": answeredBy,
+			"answered_at": answeredAt,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+// Upvote mencatat upvote user; upvote yang sudah ada diabaikan
+func (r *questionRepository) Upvote(questionID, userID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("INSERT IGNORE INTO product_question_votes (question_id, user_id, created_at) VALUES (?, ?, ?)",
+			questionID, userID, time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return refreshUpvoteCount(tx, questionID)
+	})
+}
+
+func (r *questionRepository) RemoveUpvote(questionID, userID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("question_id = ? AND user_id = ?", questionID, userID).
+			Delete(&entity.ProductQuestionVote{}).Error
+		if err != nil {
+			return err
+		}
+		return refreshUpvoteCount(tx, questionID)
+	})
+}
+
+// AddFlag mencatat laporan pertanyaan. Setelah jumlah laporan mencapai
+// hideThreshold, pertanyaan yang masih tampil menjadi pending sampai dimoderasi admin.
+func (r *questionRepository) AddFlag(flag entity.ProductQuestionFlag, hideThreshold int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := questionModeration.addFlag(tx, flag.QuestionID, flag.UserID, &flag, hideThreshold)
+		return err
+	})
+}
+
+// Moderate menetapkan status pertanyaan oleh admin. Menyetujui pertanyaan
+// (published) menghapus laporan yang sudah ada.
+func (r *questionRepository) Moderate(id uint64, status string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return questionModeration.moderate(tx, id, status)
+	})
+}
+
+// refreshUpvoteCount menghitung ulang jumlah upvote yang disimpan di
+// product_questions
+func refreshUpvoteCount(tx *gorm.DB, questionID uint64) error {
+	return tx.Exec("UPDATE product_questions SET "+
+		"upvote_count = (SELECT COUNT(*) FROM product_question_votes WHERE question_id = ?) "+
+		"WHERE id = ?",
+		questionID, questionID).Error
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"batik/entity"
+	"batik/search"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RelatedProductRepository interface {
+	GetRelatedItems() ([]search.RelatedItem, error)
+	GetCoViews(since time.Time) ([]search.CoView, error)
+	Replace(related []entity.RelatedProduct) error
+	GetRelated(productID, limit int) ([]entity.ProductCard, error)
+	GetSameCategory(product entity.Product, limit int) ([]entity.ProductCard, error)
+}
+
+type relatedProductRepository struct {
+	db *gorm.DB
+}
+
+func NewRelatedProductRepository(db *gorm.DB) RelatedProductRepository {
+	return &relatedProductRepository{
+		db: db,
+	}
+}
+
+// GetRelatedItems mengambil produk publik beserta induk kategori, harga, dan
+// nilai atributnya. Nilai multi pilihan dipecah menjadi nilai tunggal.
+func (r *relatedProductRepository) GetRelatedItems() ([]search.RelatedItem, error) {
+	var rows []struct {
+		ID               int     `gorm:"column:id"`
+		StoreID          int     `gorm:"column:store_id"`
+		CategoryID       int     `gorm:"column:category_id"`
+		ParentCategoryID *int    `gorm:"column:parent_id"`
+		Harga            float64 `gorm:"column:harga"`
+	}
+	err := r.db.Table("products").
+		Select("products.id, products.store_id, products.category_id, category_catalog.parent_id, products.harga").
+		Joins(storeJoin).
+		Joins("JOIN category_catalog ON category_catalog.id = products.category_id").
+		Where(publicProductCondition, entity.ProductStatusPublished).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes []entity.ProductAttribute
+	err = r.db.Model(&entity.ProductAttribute{}).
+		Select("product_attributes.product_id, product_attributes.code, product_attributes.value").
+		Joins("JOIN products ON products.id = product_attributes.product_id").
+		Joins(storeJoin).
+		Where(publicProductCondition, entity.ProductStatusPublished).
+		Scan(&attributes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int][]string)
+	for _, attr := range attributes {
+		for _, value := range strings.Split(attr.Value, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values[attr.ProductID] = append(values[attr.ProductID], attr.Code+"="+value)
+			}
+		}
+	}
+
+	items := make([]search.RelatedItem, 0, len(rows))
+	for _, row := range rows {
+		item := search.RelatedItem{
+			ID:         row.ID,
+			StoreID:    row.StoreID,
+			CategoryID: row.CategoryID,
+			Price:      row.Harga,
+			Attributes: values[row.ID],
+		}
+		if row.ParentCategoryID != nil {
+			item.ParentCategoryID = *row.ParentCategoryID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetCoViews menghitung pasangan produk yang dilihat oleh pengunjung yang
+// sama, ditambah pasangan yang diklik dari kata kunci pencarian yang sama,
+// sejak waktu tertentu
+func (r *relatedProductRepository) GetCoViews(since time.Time) ([]search.CoView, error) {
+	var viewed []search.CoView
+	err := r.db.Raw("SELECT a.product_id AS product_a, b.product_id AS product_b, COUNT(DISTINCT a.viewer_key) AS count "+
+		"FROM product_views a "+
+		"JOIN product_views b ON b.viewer_key = a.viewer_key AND b.product_id > a.product_id "+
+		"WHERE a.viewed_at >= ? AND b.viewed_at >= ? "+
+		"GROUP BY a.product_id, b.product_id",
+		since, since).
+		Scan(&viewed).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var clicked []search.CoView
+	err = r.db.Raw("SELECT a.target_id AS product_a, b.target_id AS product_b, COUNT(DISTINCT a.normalized_query) AS count "+
+		"FROM search_clicks a "+
+		"JOIN search_clicks b ON b.source = a.source AND b.normalized_query = a.normalized_query AND b.target_id > a.target_id "+
+		"WHERE a.source = ? AND a.created_at >= ? AND b.created_at >= ? "+
+		"GROUP BY a.target_id, b.target_id",
+		entity.SearchSourceProduct, since, since).
+		Scan(&clicked).Error
+	return append(viewed, clicked...), err
+}
+
+// Replace mengganti seluruh isi tabel produk terkait dengan hasil perhitungan baru
+func (r *relatedProductRepository) Replace(related []entity.RelatedProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM related_products").Error; err != nil {
+			return err
+		}
+		if len(related) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&related, 500).Error
+	})
+}
+
+// GetRelated mengembalikan produk terkait yang masih tampil di katalog,
+// skor tertinggi dulu
+func (r *relatedProductRepository) GetRelated(productID, limit int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := publicProductCards(r.db).
+		Joins("JOIN related_products ON related_products.related_id = products.id").
+		Where("related_products.product_id = ?", productID).
+		Preload("Attributes").
+		Order("related_products.score DESC, products.id DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetSameCategory mengembalikan produk terbaru di kategori yang sama, dipakai
+// untuk produk yang belum dihitung job produk terkait
+func (r *relatedProductRepository) GetSameCategory(product entity.Product, limit int) ([]entity.ProductCard, error) {
+	var products []entity.ProductCard
+	err := publicProductCards(r.db).
+		Where("products.category_id = ? AND products.id <> ?", product.CategoryID, product.ID).
+		Preload("Attributes").
+		Order("products.created_at DESC, products.id DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"batik/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrReviewAlreadyFlagged = errors.New("ulasan ini sudah Anda laporkan")
+
+// Urutan daftar ulasan
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
+// ReviewStatusFlagged dipakai di daftar moderasi untuk ulasan yang pernah
+// dilaporkan, apa pun statusnya
+const ReviewStatusFlagged = "flagged"
+
+// ReviewFilter adalah filter daftar ulasan produk
+type ReviewFilter struct {
+	Rating     int
+	WithPhotos bool
+	Sort       string
+}
+
+type ReviewRepository interface {
+	FindByID(id uint64) (entity.ProductReview, error)
+	FindByProductAndUser(productID int, userID uint64) (entity.ProductReview, error)
+	GetByProduct(productID int, filter ReviewFilter, page, limit int) ([]entity.ProductReview, int64, error)
+	GetByStore(storeID int, filter ReviewFilter, page, limit int) ([]entity.ProductReview, int64, error)
+	GetForModeration(status string, page, limit int) ([]entity.ProductReview, int64, error)
+	GetRatingCounts(productID int) ([]entity.RatingCount, error)
+	Create(review entity.ProductReview) (entity.ProductReview, error)
+	Update(review entity.ProductReview, newImages []entity.ProductReviewImage, deleteImageIDs []uint64) error
+	Delete(review entity.ProductReview) error
+	UpdateReply(id uint64, reply string, repliedAt *time.Time) error
+	AddFlag(flag entity.ProductReviewFlag, hideThreshold int) error
+	Moderate(review entity.ProductReview, status string) error
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+// reviewQuery memilih ulasan beserta nama pengulas dan fotonya
+func (r *reviewRepository) reviewQuery() *gorm.DB {
+	return r.db.Model(&entity.ProductReview{}).
+		Select("product_reviews.*, users.name AS reviewer_name").
+		Joins("LEFT JOIN users ON users.id = product_reviews.user_id").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		})
+}
+
+func (r *reviewRepository) FindByID(id uint64) (entity.ProductReview, error) {
+	var review entity.ProductReview
+	err := r.reviewQuery().Where("product_reviews.id = ?", id).First(&review).Error
+	return review, err
+}
+
+func (r *reviewRepository) FindByProductAndUser(productID int, userID uint64) (entity.ProductReview, error) {
+	var review entity.ProductReview
+	err := r.reviewQuery().
+		Where("product_reviews.product_id = ? AND product_reviews.user_id = ?", productID, userID).
+		First(&review).Error
+	return review, err
+}
+
+// GetByProduct mengembalikan ulasan produk yang tampil untuk publik
+func (r *reviewRepository) GetByProduct(productID int, filter ReviewFilter, page, limit int) ([]entity.ProductReview, int64, error) {
+	query := r.db.Where("product_reviews.product_id = ? AND product_reviews.status = ?", productID, entity.ReviewStatusPublished)
+	return r.paginate(query, filter, page, limit)
+}
+
+// GetByStore mengembalikan ulasan semua produk toko untuk dibalas penjual,
+// termasuk yang sedang dimoderasi
+func (r *reviewRepository) GetByStore(storeID int, filter ReviewFilter, page, limit int) ([]entity.ProductReview, int64, error) {
+	query := r.db.Where("product_reviews.product_id IN (SELECT id FROM products WHERE store_id = ?)", storeID)
+	return r.paginate(query, filter, page, limit)
+}
+
+// GetForModeration mengembalikan ulasan berdasarkan status moderasi. Status
+// flagged mengembalikan ulasan yang pernah dilaporkan, terbanyak dulu.
+func (r *reviewRepository) GetForModeration(status string, page, limit int) ([]entity.ProductReview, int64, error) {
+	if status == ReviewStatusFlagged {
+		query := r.db.Where("product_reviews.flag_count > 0")
+		return r.paginateOrdered(query, ReviewFilter{}, "product_reviews.flag_count DESC, product_reviews.id DESC", page, limit)
+	}
+	query := r.db.Where("product_reviews.status = ?", status)
+	return r.paginate(query, ReviewFilter{}, page, limit)
+}
+
+func (r *reviewRepository) paginate(conds *gorm.DB, filter ReviewFilter, page, limit int) ([]entity.ProductReview, int64, error) {
+	order := "product_reviews.created_at DESC, product_reviews.id DESC"
+	switch filter.Sort {
+	case ReviewSortHighest:
+		order = "product_reviews.rating DESC, " + order
+	case ReviewSortLowest:
+		order = "product_reviews.rating ASC, " + order
+	}
+	return r.paginateOrdered(conds, filter, order, page, limit)
+}
+
+func (r *reviewRepository) paginateOrdered(conds *gorm.DB, filter ReviewFilter, order string, page, limit int) ([]entity.ProductReview, int64, error) {
+	var (
+		reviews []entity.ProductReview
+		total   int64
+	)
+
+	apply := func(query *gorm.DB) *gorm.DB {
+		query = query.Where(conds)
+		if filter.Rating > 0 {
+			query = query.Where("product_reviews.rating = ?", filter.Rating)
+		}
+		if filter.WithPhotos {
+			query = query.Where("EXISTS (SELECT 1 FROM product_review_images WHERE product_review_images.review_id = product_reviews.id)")
+		}
+		return query
+	}
+
+	if err := apply(r.db.Model(&entity.ProductReview{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := apply(r.reviewQuery()).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetRatingCounts menghitung jumlah ulasan tampil per nilai rating
+func (r *reviewRepository) GetRatingCounts(productID int) ([]entity.RatingCount, error) {
+	var counts []entity.RatingCount
+	err := r.db.Model(&entity.ProductReview{}).
+		Select("rating, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, entity.ReviewStatusPublished).
+		Group("rating").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// Create menyimpan ulasan beserta fotonya dan memperbarui rating produk
+func (r *reviewRepository) Create(review entity.ProductReview) (entity.ProductReview, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
+	return review, err
+}
+
+// Update menyimpan perubahan rating dan teks ulasan, menambah foto baru, dan
+// menghapus foto yang dipilih
+func (r *reviewRepository) Update(review entity.ProductReview, newImages []entity.ProductReviewImage, deleteImageIDs []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.ProductReview{}).Where("id = ?", review.ID).
+			Updates(map[string]interface{}{
+				"rating":     review.Rating,
+				"body":       review.Body,
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(deleteImageIDs) > 0 {
+			err := tx.Where("review_id = ? AND id IN ?", review.ID, deleteImageIDs).
+				Delete(&entity.ProductReviewImage{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(newImages) > 0 {
+			for i := range newImages {
+				newImages[i].ReviewID = review.ID
+			}
+			if err := tx.Create(&newImages).Error; err != nil {
+				return err
+			}
+		}
+
+		return refreshProductRating(tx, review.ProductID)
+	})
+}
+
+// Delete menghapus ulasan beserta foto dan laporannya
+func (r *reviewRepository) Delete(review entity.ProductReview) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", review.ID).Delete(&entity.ProductReviewImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", review.ID).Delete(&entity.ProductReviewFlag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity.ProductReview{}, review.ID).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
+}
+
+// UpdateReply menyimpan balasan penjual. Reply kosong menghapus balasan.
+func (r *reviewRepository) UpdateReply(id uint64, reply string, repliedAt *time.Time) error {
+	return r.db.Model(&entity.ProductReview{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"seller_reply":      reply,
+			"seller_replied_at": repliedAt,
+		}).Error
+}
+
+// AddFlag mencatat laporan ulasan. Setelah jumlah laporan mencapai
+// hideThreshold, ulasan yang masih tampil menjadi pending sampai dimoderasi admin.
+func (r *reviewRepository) AddFlag(flag entity.ProductReviewFlag, hideThreshold int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		hidden, err := reviewModeration.addFlag(tx, flag.ReviewID, flag.UserID, &flag, hideThreshold)
+		if err != nil || !hidden {
+			return err
+		}
+
+		// Ulasan yang disembunyikan tidak lagi dihitung di rating produk
+		var review entity.ProductReview
+		if err := tx.Select("id", "product_id").First(&review, flag.ReviewID).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
+}
+
+// Moderate menetapkan status ulasan oleh admin. Menyetujui ulasan
+// (published) menghapus laporan yang sudah ada.
+func (r *reviewRepository) Moderate(review entity.ProductReview, status string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := reviewModeration.moderate(tx, review.ID, status); err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
+}
+
+// refreshProductRating menghitung ulang rata-rata rating dan jumlah ulasan
+// tampil yang disimpan di tabel products
+func refreshProductRating(tx *gorm.DB, productID int) error {
+	return tx.Exec("UPDATE products SET "+
+		"rating_avg = (SELECT COALESCE(AVG(rating), 0) FROM product_reviews WHERE product_id = ? AND status = ?), "+
+		"rating_count = (SELECT COUNT(*) FROM product_reviews WHERE product_id = ? AND status = ?) "+
+		"WHERE id = ?",
+		productID, entity.ReviewStatusPublished, productID, entity.ReviewStatusPublished, productID).Error
+}
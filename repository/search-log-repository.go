@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SearchLogRepository interface {
+	Create(log entity.SearchLog) (entity.SearchLog, error)
+	FindByID(id uint64) (entity.SearchLog, error)
+	FindRecent(source, normalizedQuery, actorKey string, since time.Time) (entity.SearchLog, error)
+	CreateClick(click entity.SearchClick) error
+	TopQueries(source string, since time.Time, limit int) ([]entity.SearchQueryStat, error)
+	ZeroResultQueries(source string, since time.Time, limit int) ([]entity.SearchQueryStat, error)
+	StoreClickQueries(storeID int, since time.Time, limit int) ([]entity.SearchQueryStat, error)
+}
+
+type searchLogRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchLogRepository(db *gorm.DB) SearchLogRepository {
+	return &searchLogRepository{
+		db: db,
+	}
+}
+
+func (r *searchLogRepository) Create(log entity.SearchLog) (entity.SearchLog, error) {
+	err := r.db.Create(&log).Error
+	return log, err
+}
+
+func (r *searchLogRepository) FindByID(id uint64) (entity.SearchLog, error) {
+	var log entity.SearchLog
+	err := r.db.Where("id = ?", id).First(&log).Error
+	return log, err
+}
+
+// FindRecent mengembalikan pencarian terakhir dengan kata kunci yang sama dari
+// pelaku yang sama sejak waktu tertentu
+func (r *searchLogRepository) FindRecent(source, normalizedQuery, actorKey string, since time.Time) (entity.SearchLog, error) {
+	var log entity.SearchLog
+	err := r.db.
+		Where("actor_key = ? AND source = ? AND normalized_query = ? AND created_at >= ?", actorKey, source, normalizedQuery, since).
+		Order("created_at DESC").
+		First(&log).Error
+	return log, err
+}
+
+// CreateClick mengabaikan klik kedua ke target yang sama dari satu pencarian
+// (unique index search_log_id + target_id)
+func (r *searchLogRepository) CreateClick(click entity.SearchClick) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&click).Error
+}
+
+// queryStats mengelompokkan search log per kata kunci dan menggabungkan jumlah
+// klik dari search_clicks pada periode yang sama. storeID > 0 membatasi klik
+// ke produk toko tersebut.
+func (r *searchLogRepository) queryStats(source string, since time.Time, storeID int) *gorm.DB {
+	clicks := r.db.Model(&entity.SearchClick{}).
+		Select("normalized_query, COUNT(*) AS clicks").
+		Where("source = ? AND created_at >= ?", source, since).
+		Group("normalized_query")
+	if storeID > 0 {
+		clicks = clicks.Where("store_id = ?", storeID)
+	}
+
+	return r.db.Table("search_logs AS l").
+		Select("l.normalized_query AS query, COUNT(*) AS searches, COUNT(DISTINCT l.actor_key) AS searchers, "+
+			"AVG(l.result_count) AS avg_results, "+
+			"COALESCE(MAX(c.clicks), 0) AS clicks, MAX(l.created_at) AS last_searched_at").
+		Joins("LEFT JOIN (?) AS c ON c.normalized_query = l.normalized_query", clicks).
+		Where("l.source = ? AND l.created_at >= ?", source, since).
+		Group("l.normalized_query")
+}
+
+// TopQueries mengembalikan kata kunci yang dicari paling banyak pelaku berbeda
+func (r *searchLogRepository) TopQueries(source string, since time.Time, limit int) ([]entity.SearchQueryStat, error) {
+	var stats []entity.SearchQueryStat
+	err := r.queryStats(source, since, 0).
+		Order("searchers DESC, searches DESC, query ASC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// ZeroResultQueries mengembalikan kata kunci yang tidak pernah menemukan hasil
+// selama periode laporan
+func (r *searchLogRepository) ZeroResultQueries(source string, since time.Time, limit int) ([]entity.SearchQueryStat, error) {
+	var stats []entity.SearchQueryStat
+	err := r.queryStats(source, since, 0).
+		Having("MAX(l.result_count) = 0").
+		Order("searches DESC, query ASC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// StoreClickQueries mengembalikan kata kunci yang menghasilkan klik ke produk toko
+func (r *searchLogRepository) StoreClickQueries(storeID int, since time.Time, limit int) ([]entity.SearchQueryStat, error) {
+	var stats []entity.SearchQueryStat
+	err := r.queryStats(entity.SearchSourceProduct, since, storeID).
+		Having("clicks > 0").
+		Order("clicks DESC, searches DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"batik/entity"
+	"batik/utils"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,6 +16,13 @@ type StoreRepository interface {
     FindAll() ([]entity.Store, error)  
     Update(store entity.Store) (entity.Store, error)
     GetAllStoreData(page, limit int, search string) ([]entity.Store, int64, error)
+    GetAllStoreDataCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Store, *utils.CursorPagination, error)
+    Delete(id uint64) error
+    Restore(id uint64) error
+    FindDeletedByID(id string) (entity.Store, error)
+    GetTrash(page, limit int) ([]entity.Store, int64, error)
+    FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Store, error)
+    ForceDelete(id uint64) error
 }
 
 type storeRepository struct {
@@ -49,7 +58,8 @@ func (r *storeRepository) FindAll() ([]entity.Store, error) {
 
 func (r *storeRepository) CreateStore(store entity.Store) (entity.Store, error) {
     var existingStore entity.Store
-    result := r.db.Where("name = ?", store.Name).First(&existingStore)
+    // Unscoped: nama toko di trash tetap dicadangkan agar toko bisa di-restore
+    result := r.db.Unscoped().Where("name = ?", store.Name).First(&existingStore)
     
     if result.Error == nil {
         return entity.Store{}, errors.New("store with this name already exists")
@@ -100,4 +110,78 @@ func (r *storeRepository) GetAllStoreData(page, limit int, search string) ([]ent
 	}
 	
 	return stores, total, nil
-}
\ No newline at end of file
+}
+
+// storeKeyset adalah urutan listing toko: terbaru dulu
+var storeKeyset = utils.Keyset{
+	{Expr: "created_at", Desc: true},
+	{Expr: "id", Desc: true},
+}
+
+func (r *storeRepository) GetAllStoreDataCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Store, *utils.CursorPagination, error) {
+	var stores []entity.Store
+
+	query := r.db.Model(&entity.Store{})
+	if search != "" {
+		query = query.Where("name LIKE ?", "%"+search+"%")
+	}
+
+	scope := utils.CursorScope("stores", search)
+	query, err := storeKeyset.Apply(query, cursor, scope, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := query.Find(&stores).Error; err != nil {
+		return nil, nil, err
+	}
+
+	stores, pagination := utils.NewCursorPagination(stores, limit, cursor, scope, func(s entity.Store) []interface{} {
+		return []interface{}{s.CreatedAt, s.ID}
+	})
+	return stores, pagination, nil
+}
+
+func (r *storeRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.Store{}, id).Error
+}
+
+func (r *storeRepository) Restore(id uint64) error {
+	return r.db.Unscoped().Model(&entity.Store{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *storeRepository) FindDeletedByID(id string) (entity.Store, error) {
+	var store entity.Store
+	err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&store).Error
+	return store, err
+}
+
+func (r *storeRepository) GetTrash(page, limit int) ([]entity.Store, int64, error) {
+	var stores []entity.Store
+	var total int64
+
+	query := r.db.Unscoped().Model(&entity.Store{}).Where("deleted_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&stores).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return stores, total, nil
+}
+
+func (r *storeRepository) FindPurgeable(deletedBefore time.Time, limit int) ([]entity.Store, error) {
+	var stores []entity.Store
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Limit(limit).
+		Find(&stores).Error
+	return stores, err
+}
+
+func (r *storeRepository) ForceDelete(id uint64) error {
+	return r.db.Unscoped().Delete(&entity.Store{}, id).Error
+}
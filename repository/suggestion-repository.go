@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"batik/entity"
+	"batik/search"
+
+	"gorm.io/gorm"
+)
+
+// MotifAttributeCode adalah kode atribut motif yang ikut menjadi sumber saran pencarian
+const MotifAttributeCode = "motif"
+
+// publicProductCondition membatasi sumber saran ke produk yang tampil di katalog publik
+const publicProductCondition = "products.status = ? AND products.deleted_at IS NULL"
+
+type SuggestionRepository interface {
+	GetSuggestions() ([]search.Suggestion, error)
+}
+
+type suggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuggestionRepository(db *gorm.DB) SuggestionRepository {
+	return &suggestionRepository{
+		db: db,
+	}
+}
+
+// GetSuggestions mengumpulkan nama produk, kategori, toko, dan motif beserta
+// bobotnya (jumlah produk publik yang terkait)
+func (r *suggestionRepository) GetSuggestions() ([]search.Suggestion, error) {
+	var suggestions []search.Suggestion
+
+	var products []search.Suggestion
+	err := r.db.Table("products").
+		Select("products.name AS text, ? AS kind, 1 AS weight", search.SuggestionProduct).
+		Joins(storeJoin).
+		Where(publicProductCondition, entity.ProductStatusPublished).
+		Scan(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	suggestions = append(suggestions, products...)
+
+	var categories []search.Suggestion
+	err = r.db.Table("category_catalog").
+		Select("category_catalog.category_name AS text, ? AS kind, COUNT(products.id) AS weight", search.SuggestionCategory).
+		Joins("LEFT JOIN products ON products.category_id = category_catalog.id AND "+publicProductCondition, entity.ProductStatusPublished).
+		Group("category_catalog.id, category_catalog.category_name").
+		Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	suggestions = append(suggestions, categories...)
+
+	var stores []search.Suggestion
+	err = r.db.Table("stores").
+		Select("stores.name AS text, ? AS kind, COUNT(products.id) AS weight", search.SuggestionStore).
+		Joins("LEFT JOIN products ON products.store_id = stores.id AND "+publicProductCondition, entity.ProductStatusPublished).
+		Where("stores.deleted_at IS NULL").
+		Group("stores.id, stores.name").
+		Scan(&stores).Error
+	if err != nil {
+		return nil, err
+	}
+	suggestions = append(suggestions, stores...)
+
+	var motifs []search.Suggestion
+	err = r.db.Table("product_attributes").
+		Select("product_attributes.value AS text, ? AS kind, COUNT(*) AS weight", search.SuggestionMotif).
+		Joins("JOIN products ON products.id = product_attributes.product_id").
+		Joins(storeJoin).
+		Where("product_attributes.code = ?", MotifAttributeCode).
+		Where(publicProductCondition, entity.ProductStatusPublished).
+		Group("product_attributes.value").
+		Scan(&motifs).Error
+	if err != nil {
+		return nil, err
+	}
+	suggestions = append(suggestions, motifs...)
+
+	// Pilihan motif yang belum dipakai produk tetap bisa disarankan
+	var motifAttribute entity.Attribute
+	err = r.db.Where("code = ?", MotifAttributeCode).Limit(1).Find(&motifAttribute).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range motifAttribute.Options {
+		suggestions = append(suggestions, search.Suggestion{Text: option, Kind: search.SuggestionMotif})
+	}
+
+	return suggestions, nil
+}
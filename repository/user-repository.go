@@ -2,6 +2,7 @@ package repository
 
 import (
 	"batik/entity"
+	"batik/utils"
 	"log"
 
 	"golang.org/x/crypto/bcrypt"
@@ -16,6 +17,7 @@ type UserRepository interface {
 	FindByEmail(email string) entity.User
 	ProfileUser(email string) entity.User
 	GetAllUser(page, limit int, search string) ([]entity.User, int64, error)
+	GetAllUserCursor(cursor *utils.Cursor, limit int, search string) ([]entity.User, *utils.CursorPagination, error)
 	FindByID(id string) (entity.User, error)
 }
 
@@ -101,6 +103,35 @@ func (db *userConnection) GetAllUser(page, limit int, search string) ([]entity.U
 	return users, total, nil
 }
 
+// userKeyset adalah urutan listing user: terbaru dulu
+var userKeyset = utils.Keyset{
+	{Expr: "created_at", Desc: true},
+	{Expr: "id", Desc: true},
+}
+
+func (db *userConnection) GetAllUserCursor(cursor *utils.Cursor, limit int, search string) ([]entity.User, *utils.CursorPagination, error) {
+	var users []entity.User
+
+	query := db.connection.Model(&entity.User{})
+	if search != "" {
+		query = query.Where("name LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	scope := utils.CursorScope("users", search)
+	query, err := userKeyset.Apply(query, cursor, scope, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, nil, err
+	}
+
+	users, pagination := utils.NewCursorPagination(users, limit, cursor, scope, func(u entity.User) []interface{} {
+		return []interface{}{u.CreatedAt, u.ID}
+	})
+	return users, pagination, nil
+}
+
 func hashAndSalt(pwd []byte) string {
 	hash, err := bcrypt.GenerateFromPassword(pwd, bcrypt.MinCost)
 
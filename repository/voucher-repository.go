@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"batik/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRedemptionReleased = errors.New("pemakaian voucher untuk transaksi ini sudah dibatalkan")
+
+// VoucherCheck memeriksa voucher yang sudah dikunci beserta jumlah pemakaian
+// user tersebut, lalu mengembalikan potongannya
+type VoucherCheck func(voucher entity.Voucher, userUses int64) (float64, error)
+
+type VoucherRepository interface {
+	FindByID(id uint64) (entity.Voucher, error)
+	FindByCode(code string) (entity.Voucher, error)
+	CodeExists(code string, excludeID uint64) bool
+	GetByStore(storeID int) ([]entity.Voucher, error)
+	GetPlatform() ([]entity.Voucher, error)
+	CountUserRedemptions(voucherID, userID uint64) (int64, error)
+	Create(voucher entity.Voucher) (entity.Voucher, error)
+	Update(voucher entity.Voucher) (entity.Voucher, error)
+	Delete(id uint64) error
+	Redeem(code string, redemption entity.VoucherRedemption, check VoucherCheck) (entity.VoucherRedemption, error)
+	ReleaseByReference(reference string) (int, error)
+	GetStats(storeID *int, since time.Time) ([]entity.VoucherStat, error)
+	GetRedemptions(voucherID uint64, page, limit int) ([]entity.VoucherRedemption, int64, error)
+}
+
+type voucherRepository struct {
+	db *gorm.DB
+}
+
+func NewVoucherRepository(db *gorm.DB) VoucherRepository {
+	return &voucherRepository{
+		db: db,
+	}
+}
+
+func (r *voucherRepository) FindByID(id uint64) (entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := r.db.First(&voucher, id).Error
+	return voucher, err
+}
+
+func (r *voucherRepository) FindByCode(code string) (entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := r.db.Where("code = ?", code).First(&voucher).Error
+	return voucher, err
+}
+
+func (r *voucherRepository) CodeExists(code string, excludeID uint64) bool {
+	var count int64
+	r.db.Model(&entity.Voucher{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count)
+	return count > 0
+}
+
+func (r *voucherRepository) GetByStore(storeID int) ([]entity.Voucher, error) {
+	var vouchers []entity.Voucher
+	err := r.db.Where("store_id = ?", storeID).Order("created_at DESC, id DESC").Find(&vouchers).Error
+	return vouchers, err
+}
+
+func (r *voucherRepository) GetPlatform() ([]entity.Voucher, error) {
+	var vouchers []entity.Voucher
+	err := r.db.Where("store_id IS NULL").Order("created_at DESC, id DESC").Find(&vouchers).Error
+	return vouchers, err
+}
+
+// CountUserRedemptions menghitung pemakaian voucher oleh user yang belum dilepas
+func (r *voucherRepository) CountUserRedemptions(voucherID, userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.VoucherRedemption{}).
+		Where("voucher_id = ? AND user_id = ? AND status = ?", voucherID, userID, entity.RedemptionRedeemed).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *voucherRepository) Create(voucher entity.Voucher) (entity.Voucher, error) {
+	err := r.db.Create(&voucher).Error
+	return voucher, err
+}
+
+// Update menyimpan aturan voucher. used_count tidak ikut diubah karena
+// hanya diperbarui oleh Redeem dan ReleaseByReference.
+func (r *voucherRepository) Update(voucher entity.Voucher) (entity.Voucher, error) {
+	err := r.db.Model(&entity.Voucher{}).Where("id = ?", voucher.ID).
+		Updates(map[string]interface{}{
+			"code":           voucher.Code,
+			"description":    voucher.Description,
+			"discount_type":  voucher.DiscountType,
+			"discount_value": voucher.DiscountValue,
+			"max_discount":   voucher.MaxDiscount,
+			"min_spend":      voucher.MinSpend,
+			"per_user_limit": voucher.PerUserLimit,
+			"usage_limit":    voucher.UsageLimit,
+			"starts_at":      voucher.StartsAt,
+			"expires_at":     voucher.ExpiresAt,
+			"active":         voucher.Active,
+			"updated_at":     time.Now(),
+		}).Error
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	return r.FindByID(voucher.ID)
+}
+
+func (r *voucherRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.Voucher{}, id).Error
+}
+
+// Redeem mencatat pemakaian voucher secara atomik. Baris voucher dikunci
+// dengan SELECT ... FOR UPDATE sehingga pemakaian bersamaan diproses satu per
+// satu dan batas total maupun batas per user tidak bisa terlampaui. Pemakaian
+// ulang dengan reference yang sama mengembalikan pemakaian yang sudah ada.
+func (r *voucherRepository) Redeem(code string, redemption entity.VoucherRedemption, check VoucherCheck) (entity.VoucherRedemption, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var voucher entity.Voucher
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ?", code).
+			First(&voucher).Error
+		if err != nil {
+			return err
+		}
+
+		var existing entity.VoucherRedemption
+		err = tx.Where("voucher_id = ? AND reference = ?", voucher.ID, redemption.Reference).
+			First(&existing).Error
+		if err == nil {
+			if existing.Status == entity.RedemptionReleased {
+				return ErrRedemptionReleased
+			}
+			redemption = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var userUses int64
+		err = tx.Model(&entity.VoucherRedemption{}).
+			Where("voucher_id = ? AND user_id = ? AND status = ?", voucher.ID, redemption.UserID, entity.RedemptionRedeemed).
+			Count(&userUses).Error
+		if err != nil {
+			return err
+		}
+
+		discount, err := check(voucher, userUses)
+		if err != nil {
+			return err
+		}
+
+		redemption.VoucherID = voucher.ID
+		redemption.Discount = discount
+		redemption.Status = entity.RedemptionRedeemed
+		redemption.RedeemedAt = time.Now()
+		if err := tx.Create(&redemption).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Voucher{}).Where("id = ?", voucher.ID).
+			Update("used_count", gorm.Expr("used_count + 1")).Error
+	})
+	return redemption, err
+}
+
+// ReleaseByReference melepas semua pemakaian voucher untuk reference sehingga
+// kuota voucher dan batas per user kembali. Mengembalikan jumlah yang dilepas.
+func (r *voucherRepository) ReleaseByReference(reference string) (int, error) {
+	released := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var redemptions []entity.VoucherRedemption
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reference = ? AND status = ?", reference, entity.RedemptionRedeemed).
+			Find(&redemptions).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, redemption := range redemptions {
+			err := tx.Model(&entity.VoucherRedemption{}).Where("id = ?", redemption.ID).
+				Updates(map[string]interface{}{
+					"status":      entity.RedemptionReleased,
+					"released_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&entity.Voucher{}).Where("id = ? AND used_count > 0", redemption.VoucherID).
+				Update("used_count", gorm.Expr("used_count - 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		released = len(redemptions)
+		return nil
+	})
+	return released, err
+}
+
+// GetStats merangkum pemakaian voucher toko (atau voucher platform jika
+// storeID kosong) sejak waktu tertentu. Voucher tanpa pemakaian tetap tampil.
+func (r *voucherRepository) GetStats(storeID *int, since time.Time) ([]entity.VoucherStat, error) {
+	var stats []entity.VoucherStat
+	query := r.db.Model(&entity.Voucher{}).
+		Select("vouchers.id AS voucher_id, vouchers.code, "+
+			"COUNT(voucher_redemptions.id) AS redemptions, "+
+			"COUNT(DISTINCT voucher_redemptions.user_id) AS buyers, "+
+			"COALESCE(SUM(voucher_redemptions.discount), 0) AS total_discount, "+
+			"COALESCE(SUM(voucher_redemptions.subtotal), 0) AS total_subtotal, "+
+			"MAX(voucher_redemptions.redeemed_at) AS last_redeemed_at").
+		Joins("LEFT JOIN voucher_redemptions ON voucher_redemptions.voucher_id = vouchers.id "+
+			"AND voucher_redemptions.status = ? AND voucher_redemptions.redeemed_at >= ?",
+			entity.RedemptionRedeemed, since)
+	if storeID != nil {
+		query = query.Where("vouchers.store_id = ?", *storeID)
+	} else {
+		query = query.Where("vouchers.store_id IS NULL")
+	}
+	err := query.
+		Group("vouchers.id, vouchers.code").
+		Order("redemptions DESC, vouchers.id DESC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetRedemptions mengembalikan riwayat pemakaian voucher, terbaru dulu
+func (r *voucherRepository) GetRedemptions(voucherID uint64, page, limit int) ([]entity.VoucherRedemption, int64, error) {
+	var (
+		redemptions []entity.VoucherRedemption
+		total       int64
+	)
+	query := r.db.Model(&entity.VoucherRedemption{}).Where("voucher_redemptions.voucher_id = ?", voucherID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Model(&entity.VoucherRedemption{}).
+		Select("voucher_redemptions.*, users.name AS user_name").
+		Joins("LEFT JOIN users ON users.id = voucher_redemptions.user_id").
+		Where("voucher_redemptions.voucher_id = ?", voucherID).
+		Order("voucher_redemptions.redeemed_at DESC, voucher_redemptions.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&redemptions).Error
+	return redemptions, total, err
+}
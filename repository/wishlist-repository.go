@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"batik/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WishlistRepository interface {
+	GetByUser(userID uint64) ([]entity.Wishlist, error)
+	FindByID(id uint64) (entity.Wishlist, error)
+	FindByShareToken(token string) (entity.Wishlist, error)
+	Create(wishlist entity.Wishlist) (entity.Wishlist, error)
+	Update(wishlist entity.Wishlist) error
+	Delete(id uint64) error
+	AddItem(wishlistID uint64, productID int) error
+	RemoveItem(wishlistID uint64, productID int) error
+	GetProducts(wishlistID uint64, page, limit int) ([]entity.ProductCard, int64, error)
+}
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) WishlistRepository {
+	return &wishlistRepository{
+		db: db,
+	}
+}
+
+// wishlistQuery memilih wishlist beserta jumlah item-nya
+func (r *wishlistRepository) wishlistQuery() *gorm.DB {
+	return r.db.Model(&entity.Wishlist{}).
+		Select("wishlists.*, (SELECT COUNT(*) FROM wishlist_items WHERE wishlist_items.wishlist_id = wishlists.id) AS item_count")
+}
+
+func (r *wishlistRepository) GetByUser(userID uint64) ([]entity.Wishlist, error) {
+	var wishlists []entity.Wishlist
+	err := r.wishlistQuery().
+		Where("wishlists.user_id = ?", userID).
+		Order("wishlists.created_at ASC, wishlists.id ASC").
+		Find(&wishlists).Error
+	return wishlists, err
+}
+
+func (r *wishlistRepository) FindByID(id uint64) (entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	err := r.wishlistQuery().Where("wishlists.id = ?", id).First(&wishlist).Error
+	return wishlist, err
+}
+
+func (r *wishlistRepository) FindByShareToken(token string) (entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	err := r.wishlistQuery().Where("wishlists.share_token = ?", token).First(&wishlist).Error
+	return wishlist, err
+}
+
+func (r *wishlistRepository) Create(wishlist entity.Wishlist) (entity.Wishlist, error) {
+	err := r.db.Create(&wishlist).Error
+	return wishlist, err
+}
+
+func (r *wishlistRepository) Update(wishlist entity.Wishlist) error {
+	return r.db.Model(&wishlist).
+		Select("name", "shared", "share_token", "updated_at").
+		Updates(&wishlist).Error
+}
+
+// Delete menghapus wishlist beserta item-nya
+func (r *wishlistRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&entity.WishlistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Wishlist{}, id).Error
+	})
+}
+
+// AddItem menambah produk ke wishlist; produk yang sudah ada diabaikan
+func (r *wishlistRepository) AddItem(wishlistID uint64, productID int) error {
+	return r.db.Exec("INSERT IGNORE INTO wishlist_items (wishlist_id, product_id, created_at) VALUES (?, ?, ?)",
+		wishlistID, productID, time.Now()).Error
+}
+
+func (r *wishlistRepository) RemoveItem(wishlistID uint64, productID int) error {
+	return r.db.Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).
+		Delete(&entity.WishlistItem{}).Error
+}
+
+// GetProducts mengembalikan produk wishlist yang masih tampil di katalog,
+// yang terakhir ditambahkan lebih dulu
+func (r *wishlistRepository) GetProducts(wishlistID uint64, page, limit int) ([]entity.ProductCard, int64, error) {
+	var (
+		products []entity.ProductCard
+		total    int64
+	)
+
+	query := func() *gorm.DB {
+		return publicProductCards(r.db).
+			Joins("JOIN wishlist_items ON wishlist_items.product_id = products.id").
+			Where("wishlist_items.wishlist_id = ?", wishlistID)
+	}
+
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query().
+		Preload("Attributes").
+		Order("wishlist_items.created_at DESC, wishlist_items.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&products).Error
+	return products, total, err
+}
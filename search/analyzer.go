@@ -0,0 +1,60 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Analyzer mengubah teks menjadi daftar term yang disimpan di index
+type Analyzer interface {
+	Analyze(text string) []string
+}
+
+// indonesianStopwords adalah kata umum yang tidak membantu pencarian
+var indonesianStopwords = toSet(
+	"ada", "adalah", "agar", "akan", "aku", "anda", "antara", "apa", "atau",
+	"bagi", "bahwa", "banyak", "begitu", "belum", "bisa", "boleh", "buat",
+	"dan", "dari", "dengan", "di", "dia", "dalam", "demikian", "hal", "harus",
+	"hanya", "ia", "ini", "itu", "jadi", "jika", "juga", "kalau", "kami",
+	"kamu", "karena", "ke", "kita", "lagi", "lain", "lebih", "maka", "masih",
+	"mereka", "namun", "oleh", "pada", "para", "pun", "saat", "saja", "sama",
+	"sangat", "saya", "sebagai", "sebuah", "secara", "sedang", "sehingga",
+	"sejak", "semua", "seperti", "serta", "setelah", "sini", "situ", "sudah",
+	"supaya", "tapi", "tetapi", "tersebut", "tidak", "untuk", "yaitu", "yakni",
+	"yang",
+)
+
+// indonesianAnalyzer memecah teks menjadi token huruf kecil, membuang stopword,
+// lalu mengambil kata dasar dengan stemmer Indonesia sederhana
+type indonesianAnalyzer struct{}
+
+// NewIndonesianAnalyzer membuat analyzer untuk teks berbahasa Indonesia
+func NewIndonesianAnalyzer() Analyzer {
+	return indonesianAnalyzer{}
+}
+
+func (indonesianAnalyzer) Analyze(text string) []string {
+	var terms []string
+	for _, token := range Tokenize(text) {
+		if indonesianStopwords[token] {
+			continue
+		}
+		terms = append(terms, Stem(token))
+	}
+	return terms
+}
+
+// Tokenize memecah teks pada karakter selain huruf dan angka, dalam huruf kecil
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
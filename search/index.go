@@ -0,0 +1,332 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Parameter BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fuzzyPenalty mengurangi skor term hasil koreksi typo dibanding term yang persis sama
+const fuzzyPenalty = 0.6
+
+// Field adalah satu bagian teks dokumen dengan bobotnya, misalnya nama produk
+// berbobot lebih tinggi dari deskripsi
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document adalah satu entri di index
+type Document struct {
+	ID     int
+	Fields []Field
+}
+
+// Hit adalah hasil pencarian, diurutkan dari skor tertinggi
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+// SearchIndex adalah index full-text yang bisa diperbarui per dokumen
+type SearchIndex interface {
+	// Index menambah atau mengganti dokumen
+	Index(doc Document)
+	// Remove menghapus dokumen dari index
+	Remove(id int)
+	// Reset mengganti seluruh isi index sekaligus
+	Reset(docs []Document)
+	// Search mengembalikan maksimal limit dokumen yang paling relevan
+	Search(query string, limit int) []Hit
+	// Len adalah jumlah dokumen di index
+	Len() int
+}
+
+// memoryIndex adalah inverted index di memori dengan ranking BM25
+type memoryIndex struct {
+	mu       sync.RWMutex
+	analyzer Analyzer
+	// postings: term -> dokumen -> frekuensi term berbobot
+	postings map[string]map[int]float64
+	// docTerms menyimpan term per dokumen agar dokumen bisa dihapus/diganti
+	docTerms map[int]map[string]float64
+	docLen   map[int]float64
+	totalLen float64
+	// termsByLen mengelompokkan term berdasarkan panjang untuk pencarian typo
+	termsByLen map[int]map[string]bool
+}
+
+// NewIndex membuat index kosong
+func NewIndex(analyzer Analyzer) SearchIndex {
+	idx := &memoryIndex{analyzer: analyzer}
+	idx.clear()
+	return idx
+}
+
+func (idx *memoryIndex) clear() {
+	idx.postings = make(map[string]map[int]float64)
+	idx.docTerms = make(map[int]map[string]float64)
+	idx.docLen = make(map[int]float64)
+	idx.termsByLen = make(map[int]map[string]bool)
+	idx.totalLen = 0
+}
+
+func (idx *memoryIndex) Index(doc Document) {
+	terms := idx.analyzeDocument(doc)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.ID)
+	idx.add(doc.ID, terms)
+}
+
+func (idx *memoryIndex) Remove(id int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *memoryIndex) Reset(docs []Document) {
+	analyzed := make(map[int]map[string]float64, len(docs))
+	for _, doc := range docs {
+		analyzed[doc.ID] = idx.analyzeDocument(doc)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.clear()
+	for id, terms := range analyzed {
+		idx.add(id, terms)
+	}
+}
+
+func (idx *memoryIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docLen)
+}
+
+// analyzeDocument menghitung frekuensi term berbobot untuk semua field
+func (idx *memoryIndex) analyzeDocument(doc Document) map[string]float64 {
+	terms := make(map[string]float64)
+	for _, field := range doc.Fields {
+		weight := field.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		for _, term := range idx.analyzer.Analyze(field.Text) {
+			terms[term] += weight
+		}
+	}
+	return terms
+}
+
+func (idx *memoryIndex) add(id int, terms map[string]float64) {
+	var length float64
+	for term, tf := range terms {
+		docs, ok := idx.postings[term]
+		if !ok {
+			docs = make(map[int]float64)
+			idx.postings[term] = docs
+
+			byLen, ok := idx.termsByLen[len(term)]
+			if !ok {
+				byLen = make(map[string]bool)
+				idx.termsByLen[len(term)] = byLen
+			}
+			byLen[term] = true
+		}
+		docs[id] = tf
+		length += tf
+	}
+
+	idx.docTerms[id] = terms
+	idx.docLen[id] = length
+	idx.totalLen += length
+}
+
+func (idx *memoryIndex) remove(id int) {
+	terms, ok := idx.docTerms[id]
+	if !ok {
+		return
+	}
+
+	for term := range terms {
+		docs := idx.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(idx.postings, term)
+			delete(idx.termsByLen[len(term)], term)
+		}
+	}
+
+	idx.totalLen -= idx.docLen[id]
+	delete(idx.docTerms, id)
+	delete(idx.docLen, id)
+}
+
+func (idx *memoryIndex) Search(query string, limit int) []Hit {
+	queryTerms := uniqueTerms(idx.analyzer.Analyze(query))
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	docCount := float64(len(idx.docLen))
+	if docCount == 0 {
+		return nil
+	}
+	avgLen := idx.totalLen / docCount
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+
+	for _, qt := range queryTerms {
+		// Term yang tidak ada di index dicoba dikoreksi ke term terdekat
+		expansions := map[string]float64{qt: 1}
+		if _, ok := idx.postings[qt]; !ok {
+			expansions = idx.fuzzyTerms(qt)
+		}
+
+		seen := make(map[int]bool)
+		for term, boost := range expansions {
+			docs := idx.postings[term]
+			df := float64(len(docs))
+			idf := math.Log(1 + (docCount-df+0.5)/(df+0.5))
+
+			for id, tf := range docs {
+				norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*idx.docLen[id]/avgLen))
+				scores[id] += boost * idf * norm
+				if !seen[id] {
+					seen[id] = true
+					matched[id]++
+				}
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		// Dokumen yang cocok dengan lebih banyak kata query diutamakan
+		score *= float64(matched[id]) / float64(len(queryTerms))
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// maxEdits menentukan toleransi typo berdasarkan panjang term
+func maxEdits(term string) int {
+	switch n := len(term); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// fuzzyTerms mencari term di index dengan jarak edit kecil dari term query.
+// Boost berkurang untuk setiap edit.
+func (idx *memoryIndex) fuzzyTerms(term string) map[string]float64 {
+	edits := maxEdits(term)
+	expansions := make(map[string]float64)
+	if edits == 0 {
+		return expansions
+	}
+
+	for n := len(term) - edits; n <= len(term)+edits; n++ {
+		for candidate := range idx.termsByLen[n] {
+			if d := editDistance(term, candidate, edits); d <= edits {
+				expansions[candidate] = math.Pow(fuzzyPenalty, float64(d))
+			}
+		}
+	}
+	return expansions
+}
+
+// editDistance menghitung jarak Damerau-Levenshtein (optimal string alignment).
+// Perhitungan dihentikan lebih awal jika jarak pasti melebihi max.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return max + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var result []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
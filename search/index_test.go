@@ -0,0 +1,130 @@
+package search
+
+import "testing"
+
+func newTestIndex(docs ...Document) SearchIndex {
+	idx := NewIndex(NewIndonesianAnalyzer())
+	idx.Reset(docs)
+	return idx
+}
+
+func doc(id int, name, description string) Document {
+	return Document{ID: id, Fields: []Field{
+		{Text: name, Weight: 3},
+		{Text: description, Weight: 1},
+	}}
+}
+
+func hitIDs(hits []Hit) []int {
+	ids := make([]int, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestIndexSearchRanksByFieldWeight(t *testing.T) {
+	idx := newTestIndex(
+		doc(1, "Kemeja Pria", "kemeja dengan motif parang"),
+		doc(2, "Batik Parang Rusak", "kain tulis halus"),
+		doc(3, "Selendang Sutra", "warna alami"),
+	)
+
+	hits := idx.Search("parang", 10)
+	if got := hitIDs(hits); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Fatalf("Search(parang) = %v, ingin [2 1]", got)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("skor nama (%v) harus lebih tinggi dari skor deskripsi (%v)", hits[0].Score, hits[1].Score)
+	}
+}
+
+func TestIndexSearchPrefersRareTerms(t *testing.T) {
+	// "batik" ada di semua dokumen sehingga IDF-nya kecil; "kawung" yang
+	// jarang menentukan urutan
+	idx := newTestIndex(
+		doc(1, "Batik Kawung", ""),
+		doc(2, "Batik Parang", ""),
+		doc(3, "Batik Mega Mendung", ""),
+		doc(4, "Batik Truntum", ""),
+	)
+
+	hits := idx.Search("batik kawung", 10)
+	if len(hits) != 4 || hits[0].ID != 1 {
+		t.Fatalf("Search(batik kawung) = %v, ingin dokumen 1 di urutan pertama", hitIDs(hits))
+	}
+}
+
+func TestIndexSearchPrefersDocumentsMatchingMoreTerms(t *testing.T) {
+	idx := newTestIndex(
+		doc(1, "Kain Sutra", "kain sutra kain sutra kain sutra"),
+		doc(2, "Kain Sutra Tulis", ""),
+		doc(3, "Canting Tembaga", ""),
+	)
+
+	hits := idx.Search("sutra tulis", 10)
+	if len(hits) == 0 || hits[0].ID != 2 {
+		t.Fatalf("Search(sutra tulis) = %v, ingin dokumen 2 di urutan pertama", hitIDs(hits))
+	}
+}
+
+func TestIndexSearchStemsQueryAndDocuments(t *testing.T) {
+	idx := newTestIndex(doc(1, "Batik Tulis", "dibuat dengan canting"))
+
+	for _, query := range []string{"menulis", "canting", "pembuatan"} {
+		if hits := idx.Search(query, 10); len(hits) != 1 {
+			t.Errorf("Search(%q) = %v, ingin dokumen 1", query, hitIDs(hits))
+		}
+	}
+	if hits := idx.Search("dan yang", 10); hits != nil {
+		t.Errorf("query berisi stopword saja harus kosong, dapat %v", hitIDs(hits))
+	}
+}
+
+func TestIndexSearchCorrectsTypos(t *testing.T) {
+	idx := newTestIndex(
+		doc(1, "Batik Megamendung", ""),
+		doc(2, "Selendang Sutra", ""),
+	)
+
+	hits := idx.Search("megamendng", 10)
+	if len(hits) != 1 || hits[0].ID != 1 {
+		t.Fatalf("Search(megamendng) = %v, ingin [1]", hitIDs(hits))
+	}
+
+	exact := idx.Search("megamendung", 10)
+	if hits[0].Score >= exact[0].Score {
+		t.Errorf("skor typo (%v) harus lebih rendah dari skor persis (%v)", hits[0].Score, exact[0].Score)
+	}
+}
+
+func TestIndexUpdateAndRemove(t *testing.T) {
+	idx := newTestIndex(doc(1, "Batik Kawung", ""), doc(2, "Batik Parang", ""))
+
+	idx.Index(doc(1, "Batik Truntum", ""))
+	if hits := idx.Search("kawung", 10); len(hits) != 0 {
+		t.Errorf("term lama masih ditemukan setelah dokumen diganti: %v", hitIDs(hits))
+	}
+	if hits := idx.Search("truntum", 10); len(hits) != 1 || hits[0].ID != 1 {
+		t.Errorf("Search(truntum) = %v, ingin [1]", hitIDs(hits))
+	}
+
+	idx.Remove(2)
+	if idx.Len() != 1 {
+		t.Errorf("Len = %d, ingin 1", idx.Len())
+	}
+	if hits := idx.Search("parang", 10); len(hits) != 0 {
+		t.Errorf("dokumen yang dihapus masih ditemukan: %v", hitIDs(hits))
+	}
+}
+
+func TestIndexSearchLimit(t *testing.T) {
+	idx := newTestIndex(
+		doc(1, "Batik Satu", ""),
+		doc(2, "Batik Dua", ""),
+		doc(3, "Batik Tiga", ""),
+	)
+	if hits := idx.Search("batik", 2); len(hits) != 2 {
+		t.Errorf("jumlah hit = %d, ingin 2", len(hits))
+	}
+}
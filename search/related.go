@@ -0,0 +1,235 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bobot sinyal kemiripan produk terkait
+const (
+	relatedSameCategory    = 3.0
+	relatedSiblingCategory = 1.5
+	relatedSameStore       = 1.0
+	relatedSharedValue     = 1.0 // per nilai atribut yang sama
+	relatedSharedMotif     = 2.0 // motif lebih menentukan daripada atribut lain
+	relatedPriceClose      = 1.0 // harga dalam rentang 0,75x - 1,33x
+	relatedPriceNear       = 0.5 // harga dalam rentang 0,5x - 2x
+	relatedCoView          = 1.5 // dikali log(1 + jumlah)
+)
+
+// minRelatedScore menyaring pasangan yang hanya mirip dari satu sinyal lemah,
+// misalnya hanya rentang harga
+const minRelatedScore = 1.5
+
+// maxRelatedBucket membatasi jumlah kandidat yang diambil dari satu kelompok
+// (kategori, toko, nilai atribut) agar perhitungan tidak kuadratik. Dari
+// kelompok yang lebih besar hanya diambil maxRelatedBucket produk dengan harga
+// terdekat, karena kemiripan harga ikut menentukan skor.
+const maxRelatedBucket = 2000
+
+// RelatedItem adalah data produk yang dipakai untuk menghitung produk terkait.
+// Attributes berisi pasangan "kode=nilai".
+type RelatedItem struct {
+	ID               int
+	StoreID          int
+	CategoryID       int
+	ParentCategoryID int
+	Price            float64
+	Attributes       []string
+}
+
+// CoView adalah jumlah sinyal dua produk dilihat bersama
+type CoView struct {
+	ProductA int   `gorm:"column:product_a"`
+	ProductB int   `gorm:"column:product_b"`
+	Count    int64 `gorm:"column:count"`
+}
+
+// RelatedScore adalah skor satu produk terkait
+type RelatedScore struct {
+	ProductID int
+	RelatedID int
+	Score     float64
+}
+
+// MotifAttributePrefix adalah awalan atribut motif di RelatedItem.Attributes
+const MotifAttributePrefix = "motif="
+
+// ComputeRelated menghitung hingga limit produk terkait per produk. Kandidat
+// hanya diambil dari produk yang berbagi kategori, induk kategori, toko,
+// nilai atribut, atau sinyal dilihat bersama, lalu diberi skor gabungan.
+func ComputeRelated(items []RelatedItem, coViews []CoView, limit int) []RelatedScore {
+	byID := make(map[int]*RelatedItem, len(items))
+	buckets := make(map[string][]int)
+	for i := range items {
+		item := &items[i]
+		byID[item.ID] = item
+		buckets[bucketKey("c", item.CategoryID)] = append(buckets[bucketKey("c", item.CategoryID)], item.ID)
+		buckets[bucketKey("s", item.StoreID)] = append(buckets[bucketKey("s", item.StoreID)], item.ID)
+		buckets[bucketKey("p", parentOf(item))] = append(buckets[bucketKey("p", parentOf(item))], item.ID)
+		for _, attr := range item.Attributes {
+			buckets["a:"+attr] = append(buckets["a:"+attr], item.ID)
+		}
+	}
+
+	// Kelompok besar diurutkan berdasarkan harga agar kandidat terdekat bisa
+	// diambil dengan binary search
+	for key, ids := range buckets {
+		if len(ids) > maxRelatedBucket {
+			sort.Slice(ids, func(a, b int) bool {
+				return priceLess(byID[ids[a]], byID[ids[b]])
+			})
+			buckets[key] = ids
+		}
+	}
+
+	views := make(map[int]map[int]int64)
+	for _, cv := range coViews {
+		if _, ok := byID[cv.ProductA]; !ok {
+			continue
+		}
+		if _, ok := byID[cv.ProductB]; !ok {
+			continue
+		}
+		addCoView(views, cv.ProductA, cv.ProductB, cv.Count)
+		addCoView(views, cv.ProductB, cv.ProductA, cv.Count)
+	}
+
+	var result []RelatedScore
+	for i := range items {
+		item := &items[i]
+
+		candidates := make(map[int]bool)
+		addBucket := func(key string) {
+			for _, id := range nearestInBucket(buckets[key], byID, item) {
+				candidates[id] = true
+			}
+		}
+		addBucket(bucketKey("c", item.CategoryID))
+		addBucket(bucketKey("s", item.StoreID))
+		addBucket(bucketKey("p", parentOf(item)))
+		for _, attr := range item.Attributes {
+			addBucket("a:" + attr)
+		}
+		for id := range views[item.ID] {
+			candidates[id] = true
+		}
+		delete(candidates, item.ID)
+
+		scores := make([]RelatedScore, 0, len(candidates))
+		for id := range candidates {
+			score := relatedScore(item, byID[id], views[item.ID][id])
+			if score >= minRelatedScore {
+				scores = append(scores, RelatedScore{ProductID: item.ID, RelatedID: id, Score: score})
+			}
+		}
+
+		sort.Slice(scores, func(a, b int) bool {
+			if scores[a].Score != scores[b].Score {
+				return scores[a].Score > scores[b].Score
+			}
+			return scores[a].RelatedID > scores[b].RelatedID
+		})
+		if len(scores) > limit {
+			scores = scores[:limit]
+		}
+		result = append(result, scores...)
+	}
+	return result
+}
+
+func relatedScore(a, b *RelatedItem, coViews int64) float64 {
+	var score float64
+
+	switch {
+	case a.CategoryID == b.CategoryID:
+		score += relatedSameCategory
+	case parentOf(a) == parentOf(b):
+		score += relatedSiblingCategory
+	}
+
+	if a.StoreID == b.StoreID {
+		score += relatedSameStore
+	}
+
+	shared := make(map[string]bool, len(a.Attributes))
+	for _, attr := range a.Attributes {
+		shared[attr] = true
+	}
+	for _, attr := range b.Attributes {
+		if !shared[attr] {
+			continue
+		}
+		if strings.HasPrefix(attr, MotifAttributePrefix) {
+			score += relatedSharedMotif
+		} else {
+			score += relatedSharedValue
+		}
+	}
+
+	if a.Price > 0 && b.Price > 0 {
+		ratio := b.Price / a.Price
+		switch {
+		case ratio >= 0.75 && ratio <= 1/0.75:
+			score += relatedPriceClose
+		case ratio >= 0.5 && ratio <= 2:
+			score += relatedPriceNear
+		}
+	}
+
+	if coViews > 0 {
+		score += relatedCoView * math.Log1p(float64(coViews))
+	}
+	return math.Round(score*1000) / 1000
+}
+
+// nearestInBucket mengembalikan seluruh kelompok jika kecil, atau
+// maxRelatedBucket produk yang harganya paling dekat dengan item dari kelompok
+// besar (sudah terurut berdasarkan harga)
+func nearestInBucket(ids []int, byID map[int]*RelatedItem, item *RelatedItem) []int {
+	if len(ids) <= maxRelatedBucket {
+		return ids
+	}
+
+	pos := sort.Search(len(ids), func(i int) bool {
+		return !priceLess(byID[ids[i]], item)
+	})
+	start := pos - maxRelatedBucket/2
+	if start < 0 {
+		start = 0
+	}
+	if start+maxRelatedBucket > len(ids) {
+		start = len(ids) - maxRelatedBucket
+	}
+	return ids[start : start+maxRelatedBucket]
+}
+
+// priceLess mengurutkan produk berdasarkan harga lalu ID
+func priceLess(a, b *RelatedItem) bool {
+	if a.Price != b.Price {
+		return a.Price < b.Price
+	}
+	return a.ID < b.ID
+}
+
+// parentOf mengembalikan induk kategori, atau kategori itu sendiri untuk
+// kategori utama
+func parentOf(item *RelatedItem) int {
+	if item.ParentCategoryID > 0 {
+		return item.ParentCategoryID
+	}
+	return item.CategoryID
+}
+
+func addCoView(views map[int]map[int]int64, a, b int, count int64) {
+	if views[a] == nil {
+		views[a] = make(map[int]int64)
+	}
+	views[a][b] += count
+}
+
+func bucketKey(kind string, id int) string {
+	return kind + ":" + strconv.Itoa(id)
+}
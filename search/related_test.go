@@ -0,0 +1,124 @@
+package search
+
+import "testing"
+
+func relatedOf(scores []RelatedScore, productID int) []RelatedScore {
+	var result []RelatedScore
+	for _, s := range scores {
+		if s.ProductID == productID {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func TestRelatedScore(t *testing.T) {
+	base := &RelatedItem{ID: 1, StoreID: 1, CategoryID: 10, ParentCategoryID: 5, Price: 100000,
+		Attributes: []string{"motif=parang", "teknik=tulis"}}
+
+	cases := []struct {
+		name    string
+		other   RelatedItem
+		coViews int64
+		want    float64
+	}{
+		{"kategori sama", RelatedItem{ID: 2, StoreID: 2, CategoryID: 10, ParentCategoryID: 5}, 0, relatedSameCategory},
+		{"kategori saudara", RelatedItem{ID: 2, StoreID: 2, CategoryID: 11, ParentCategoryID: 5}, 0, relatedSiblingCategory},
+		{"toko sama", RelatedItem{ID: 2, StoreID: 1, CategoryID: 20}, 0, relatedSameStore},
+		{"motif dan atribut sama", RelatedItem{ID: 2, StoreID: 2, CategoryID: 20,
+			Attributes: []string{"motif=parang", "teknik=tulis", "warna=biru"}}, 0, relatedSharedMotif + relatedSharedValue},
+		{"harga dekat", RelatedItem{ID: 2, StoreID: 2, CategoryID: 20, Price: 120000}, 0, relatedPriceClose},
+		{"harga agak dekat", RelatedItem{ID: 2, StoreID: 2, CategoryID: 20, Price: 180000}, 0, relatedPriceNear},
+		{"harga jauh", RelatedItem{ID: 2, StoreID: 2, CategoryID: 20, Price: 500000}, 0, 0},
+		{"dilihat bersama", RelatedItem{ID: 2, StoreID: 2, CategoryID: 20}, 3, 2.079},
+	}
+	for _, tc := range cases {
+		if got := relatedScore(base, &tc.other, tc.coViews); got != tc.want {
+			t.Errorf("%s: skor = %v, ingin %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestComputeRelatedRanksAndFilters(t *testing.T) {
+	items := []RelatedItem{
+		{ID: 1, StoreID: 1, CategoryID: 10, Price: 100000, Attributes: []string{"motif=parang"}},
+		// kategori dan motif sama
+		{ID: 2, StoreID: 2, CategoryID: 10, Price: 300000, Attributes: []string{"motif=parang"}},
+		// hanya kategori sama
+		{ID: 3, StoreID: 3, CategoryID: 10, Price: 500000},
+		// hanya harga yang mirip, di bawah minRelatedScore
+		{ID: 4, StoreID: 4, CategoryID: 20, Price: 100000},
+		// kategori lain tetapi sering dilihat bersama
+		{ID: 5, StoreID: 5, CategoryID: 30, Price: 900000},
+	}
+	coViews := []CoView{
+		{ProductA: 5, ProductB: 1, Count: 20},
+		// produk yang tidak ada di daftar diabaikan
+		{ProductA: 1, ProductB: 99, Count: 100},
+	}
+
+	related := relatedOf(ComputeRelated(items, coViews, 10), 1)
+	want := []int{2, 5, 3}
+	if len(related) != len(want) {
+		t.Fatalf("produk terkait 1 = %+v, ingin ID %v", related, want)
+	}
+	for i, id := range want {
+		if related[i].RelatedID != id {
+			t.Fatalf("produk terkait 1 = %+v, ingin ID %v", related, want)
+		}
+	}
+
+	// Sinyal dilihat bersama berlaku dua arah
+	if back := relatedOf(ComputeRelated(items, coViews, 10), 5); len(back) != 1 || back[0].RelatedID != 1 {
+		t.Errorf("produk terkait 5 = %+v, ingin [1]", back)
+	}
+
+	if limited := relatedOf(ComputeRelated(items, coViews, 1), 1); len(limited) != 1 || limited[0].RelatedID != 2 {
+		t.Errorf("limit 1: %+v", limited)
+	}
+}
+
+func TestComputeRelatedSamplesLargeBuckets(t *testing.T) {
+	// Satu kategori berisi lebih dari maxRelatedBucket produk: kandidat tetap
+	// dihitung dari produk dengan harga terdekat, bukan dilewati
+	n := maxRelatedBucket + 500
+	items := make([]RelatedItem, n)
+	for i := range items {
+		items[i] = RelatedItem{ID: i + 1, StoreID: i + 1, CategoryID: 10, Price: float64(100000 + i*100)}
+	}
+
+	scores := ComputeRelated(items, nil, 3)
+	if len(scores) != 3*n {
+		t.Fatalf("jumlah skor = %d, ingin %d", len(scores), 3*n)
+	}
+
+	// Produk termurah: kandidat dengan skor tertinggi adalah harga terdekat
+	first := relatedOf(scores, 1)
+	for _, s := range first {
+		if s.Score != relatedSameCategory+relatedPriceClose {
+			t.Errorf("produk terkait 1 = %+v", first)
+			break
+		}
+	}
+
+	nearest := nearestInBucket(sortedBucket(items), indexItems(items), &items[n-1])
+	if len(nearest) != maxRelatedBucket || nearest[len(nearest)-1] != n {
+		t.Errorf("kandidat produk termahal harus berakhir di produk itu sendiri, dapat %d kandidat", len(nearest))
+	}
+}
+
+func sortedBucket(items []RelatedItem) []int {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func indexItems(items []RelatedItem) map[int]*RelatedItem {
+	byID := make(map[int]*RelatedItem, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+	return byID
+}
@@ -0,0 +1,140 @@
+package search
+
+import "strings"
+
+// minStemLength adalah panjang minimal kata dasar. Imbuhan tidak dibuang jika
+// sisa katanya lebih pendek dari ini, agar kata seperti "bahan" tidak rusak.
+const minStemLength = 4
+
+// Stem mengambil kata dasar dari kata berbahasa Indonesia dengan membuang
+// partikel (-lah, -kah, -pun), kata ganti milik (-ku, -mu, -nya), awalan
+// (me-, ber-, di-, ter-, pe-) dan akhiran (-kan, -an).
+//
+// Ini bukan stemmer lengkap berbasis kamus; yang penting kata pada index dan
+// kata pada query menghasilkan bentuk yang sama.
+func Stem(word string) string {
+	if len(word) <= minStemLength {
+		return word
+	}
+
+	word = stripSuffix(word, "lah", "kah", "tah", "pun")
+	word = stripSuffix(word, "nya", "ku", "mu")
+	word = stripPrefix(word)
+	word = stripDerivationSuffix(word)
+	return word
+}
+
+// kRoots adalah kata dasar yang diawali atau diakhiri huruf k. Tanpa kamus,
+// "mengukir" tidak bisa dibedakan dari "mengirim" dan "batikan" dari
+// "tuliskan", jadi huruf k hanya dipertahankan untuk kata dasar yang dikenal.
+var kRoots = map[string]bool{
+	// diawali k: meng- meluluhkan k (mengirim -> kirim)
+	"kirim": true, "kemas": true, "kenal": true, "kumpul": true, "kerja": true,
+	"kupas": true, "kunci": true, "kurang": true, "kait": true, "kelola": true,
+	"koleksi": true, "kombinasi": true, "kreasi": true,
+	// diakhiri k: akhiran -an tidak boleh dibaca sebagai -kan (batikan -> batik)
+	"batik": true, "cetak": true, "masak": true, "rusak": true, "corak": true,
+	"cocok": true, "pendek": true, "balik": true, "tarik": true, "cantik": true,
+}
+
+// isKRoot mengecek apakah word, dengan atau tanpa akhiran -kan/-an, adalah
+// kata dasar di kRoots
+func isKRoot(word string) bool {
+	if kRoots[word] {
+		return true
+	}
+	for _, suffix := range []string{"kan", "an"} {
+		if strings.HasSuffix(word, suffix) && kRoots[word[:len(word)-len(suffix)]] {
+			return true
+		}
+	}
+	return false
+}
+
+// stripDerivationSuffix membuang akhiran -kan atau -an. Kata dasar yang
+// berakhiran k hanya kehilangan -an (batikan -> batik, bukan bati).
+func stripDerivationSuffix(word string) string {
+	if strings.HasSuffix(word, "kan") && kRoots[word[:len(word)-2]] {
+		return word[:len(word)-2]
+	}
+	return stripSuffix(word, "kan", "an")
+}
+
+// stripSuffix membuang akhiran pertama yang cocok
+func stripSuffix(word string, suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= minStemLength {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+func isVowel(b byte) bool {
+	switch b {
+	case 'a', 'i', 'u', 'e', 'o':
+		return true
+	}
+	return false
+}
+
+// stripPrefix membuang satu awalan derivasi, termasuk peluluhan huruf awal
+// pada awalan me-/pe- (menulis -> tulis, memakai -> pakai, menyulam -> sulam)
+func stripPrefix(word string) string {
+	candidates := prefixCandidates(word)
+	for _, stem := range candidates {
+		if len(stem) >= minStemLength {
+			return stem
+		}
+	}
+	return word
+}
+
+// prefixCandidates mengembalikan kemungkinan kata dasar setelah awalan dibuang
+func prefixCandidates(word string) []string {
+	switch {
+	case strings.HasPrefix(word, "meng"), strings.HasPrefix(word, "peng"):
+		rest := word[4:]
+		if rest != "" && isVowel(rest[0]) && isKRoot("k"+rest) {
+			// mengirim -> kirim; selain kata dasar di kRoots huruf k tidak
+			// dikembalikan (mengukir -> ukir)
+			return []string{"k" + rest}
+		}
+		return []string{rest}
+	case strings.HasPrefix(word, "meny"), strings.HasPrefix(word, "peny"):
+		// menyulam -> sulam
+		return []string{"s" + word[4:]}
+	case strings.HasPrefix(word, "mem"), strings.HasPrefix(word, "pem"):
+		rest := word[3:]
+		if rest != "" && isVowel(rest[0]) {
+			// memakai -> pakai
+			return []string{"p" + rest}
+		}
+		// membatik -> batik
+		return []string{rest}
+	case strings.HasPrefix(word, "men"), strings.HasPrefix(word, "pen"):
+		rest := word[3:]
+		if rest != "" && isVowel(rest[0]) {
+			// menulis -> tulis
+			return []string{"t" + rest}
+		}
+		// mencanting -> canting, mendesain -> desain
+		return []string{rest}
+	case strings.HasPrefix(word, "me"), strings.HasPrefix(word, "pe"):
+		// melukis -> lukis, pewarna -> warna
+		if strings.HasPrefix(word, "per") {
+			return []string{word[3:], word[2:]}
+		}
+		return []string{word[2:]}
+	case strings.HasPrefix(word, "ber"):
+		// berbahan -> bahan
+		return []string{word[3:]}
+	case strings.HasPrefix(word, "ter"):
+		// termurah -> murah
+		return []string{word[3:]}
+	case strings.HasPrefix(word, "di"):
+		// dijual -> jual
+		return []string{word[2:]}
+	}
+	return nil
+}
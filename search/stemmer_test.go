@@ -0,0 +1,56 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	cases := map[string]string{
+		// awalan dengan peluluhan
+		"menulis":     "tulis",
+		"memakai":     "pakai",
+		"menyulam":    "sulam",
+		"mengirim":    "kirim",
+		"mengukir":    "ukir",
+		"pengukir":    "ukir",
+		"mengemas":    "kemas",
+		"mengerjakan": "kerja",
+		"mengambil":   "ambil",
+		"membatik":    "batik",
+		"melukis":     "lukis",
+		"pewarna":     "warna",
+		// awalan tanpa peluluhan
+		"berbahan": "bahan",
+		"termurah": "murah",
+		"dijual":   "jual",
+		// akhiran, partikel, dan kata ganti milik
+		"batiknya":  "batik",
+		"pakaian":   "pakai",
+		"bajumu":    "baju",
+		"murahkah":  "murah",
+		"dijualkan": "jual",
+		// kata dasar berakhiran k hanya kehilangan -an
+		"batikan":     "batik",
+		"cetakan":     "cetak",
+		"membatikkan": "batik",
+		"tuliskan":    "tulis",
+		// kata pendek dan kata yang sisanya terlalu pendek tidak diubah
+		"kain":  "kain",
+		"bahan": "bahan",
+		"tulis": "tulis",
+	}
+	for word, want := range cases {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, ingin %q", word, got, want)
+		}
+	}
+}
+
+func TestIndonesianAnalyzer(t *testing.T) {
+	got := NewIndonesianAnalyzer().Analyze("Kain Batik yang DITULIS dengan canting, motif-Parang!")
+	want := []string{"kain", "batik", "tulis", "canting", "motif", "parang"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze = %v, ingin %v", got, want)
+	}
+}
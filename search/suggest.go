@@ -0,0 +1,339 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Jenis sumber saran pencarian
+const (
+	SuggestionProduct  = "product"
+	SuggestionCategory = "category"
+	SuggestionStore    = "store"
+	SuggestionMotif    = "motif"
+	SuggestionQuery    = "query"
+)
+
+// maxNodeSuggestions adalah jumlah saran teratas yang disimpan di setiap node trie
+const maxNodeSuggestions = 10
+
+// minCorrectionLength adalah panjang kata minimal yang dicoba dikoreksi ("did you mean")
+const minCorrectionLength = 4
+
+// Suggestion adalah satu kandidat pelengkap kata kunci
+type Suggestion struct {
+	Text   string
+	Kind   string
+	Weight float64
+}
+
+// Suggester memberi saran pelengkap (prefix), koreksi ejaan, dan kata kunci populer.
+// Data katalog diganti seluruhnya lewat Reset; kata kunci populer terakumulasi
+// lewat RecordQuery atau diganti lewat SetPopular, dan tidak ikut terhapus saat Reset.
+type Suggester struct {
+	mu         sync.RWMutex
+	root       *trieNode
+	entries    []Suggestion
+	vocabulary map[string]float64
+
+	popularMu sync.RWMutex
+	popular   map[string]int
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	// top berisi indeks entries dengan bobot tertinggi yang melewati node ini
+	top []int
+}
+
+func NewSuggester() *Suggester {
+	return &Suggester{
+		root:       &trieNode{},
+		vocabulary: make(map[string]float64),
+		popular:    make(map[string]int),
+	}
+}
+
+// NormalizeQuery mengubah teks menjadi huruf kecil dengan satu spasi antar kata
+func NormalizeQuery(text string) string {
+	return strings.Join(Tokenize(text), " ")
+}
+
+// compact menghapus spasi agar "mega mendung" dan "megamendung" cocok
+func compact(text string) string {
+	return strings.ReplaceAll(text, " ", "")
+}
+
+// Reset membangun ulang trie dari daftar saran. Saran dengan teks dan jenis yang
+// sama digabung dan bobotnya dijumlahkan.
+func (s *Suggester) Reset(suggestions []Suggestion) {
+	merged := make(map[string]int)
+	var entries []Suggestion
+	vocabulary := make(map[string]float64)
+
+	for _, sg := range suggestions {
+		norm := NormalizeQuery(sg.Text)
+		if norm == "" {
+			continue
+		}
+		weight := sg.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		for _, word := range strings.Fields(norm) {
+			vocabulary[word] += weight
+		}
+
+		key := sg.Kind + "|" + norm
+		if i, ok := merged[key]; ok {
+			entries[i].Weight += weight
+			continue
+		}
+		merged[key] = len(entries)
+		entries = append(entries, Suggestion{Text: strings.TrimSpace(sg.Text), Kind: sg.Kind, Weight: weight})
+	}
+
+	root := &trieNode{}
+	for i, entry := range entries {
+		// Saran bisa dicari dari awal setiap kata, dengan atau tanpa spasi:
+		// "Batik Mega Mendung" cocok untuk "mega m", "megam", dan "mendung"
+		words := strings.Fields(NormalizeQuery(entry.Text))
+		for w := range words {
+			suffix := strings.Join(words[w:], " ")
+			root.insert(suffix, i, entries)
+			root.insert(compact(suffix), i, entries)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = root
+	s.entries = entries
+	s.vocabulary = vocabulary
+}
+
+func (n *trieNode) insert(key string, entry int, entries []Suggestion) {
+	node := n
+	node.addTop(entry, entries)
+	for _, r := range key {
+		if node.children == nil {
+			node.children = make(map[rune]*trieNode)
+		}
+		child, ok := node.children[r]
+		if !ok {
+			child = &trieNode{}
+			node.children[r] = child
+		}
+		node = child
+		node.addTop(entry, entries)
+	}
+}
+
+// addTop menyimpan entry di daftar teratas node, terurut dari bobot terbesar
+func (n *trieNode) addTop(entry int, entries []Suggestion) {
+	for _, existing := range n.top {
+		if existing == entry {
+			return
+		}
+	}
+	n.top = append(n.top, entry)
+	sort.SliceStable(n.top, func(i, j int) bool {
+		return entries[n.top[i]].Weight > entries[n.top[j]].Weight
+	})
+	if len(n.top) > maxNodeSuggestions {
+		n.top = n.top[:maxNodeSuggestions]
+	}
+}
+
+func (n *trieNode) find(key string) *trieNode {
+	node := n
+	for _, r := range key {
+		child, ok := node.children[r]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
+
+// Complete mengembalikan saran yang diawali prefix, terurut dari bobot terbesar
+func (s *Suggester) Complete(prefix string, limit int) []Suggestion {
+	norm := NormalizeQuery(prefix)
+	if norm == "" {
+		return []Suggestion{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[int]bool)
+	var candidates []int
+	for _, key := range []string{norm, compact(norm)} {
+		node := s.root.find(key)
+		if node == nil {
+			continue
+		}
+		for _, i := range node.top {
+			if !seen[i] {
+				seen[i] = true
+				candidates = append(candidates, i)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return s.entries[candidates[i]].Weight > s.entries[candidates[j]].Weight
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	result := make([]Suggestion, len(candidates))
+	for i, c := range candidates {
+		result[i] = s.entries[c]
+	}
+	return result
+}
+
+// DidYouMean mengoreksi kata yang tidak dikenal ke kata katalog terdekat.
+// Kata yang terpisah atau tersambung juga dicoba ("mega mendung" <-> "megamendung").
+// Mengembalikan string kosong jika tidak ada koreksi.
+func (s *Suggester) DidYouMean(query string) string {
+	words := strings.Fields(NormalizeQuery(query))
+	if len(words) == 0 {
+		return ""
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var corrected []string
+	changed := false
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if _, ok := s.vocabulary[word]; ok {
+			corrected = append(corrected, word)
+			continue
+		}
+
+		// Dua kata yang seharusnya disambung
+		if i+1 < len(words) {
+			if _, ok := s.vocabulary[word+words[i+1]]; ok {
+				corrected = append(corrected, word+words[i+1])
+				changed = true
+				i++
+				continue
+			}
+		}
+
+		// Satu kata yang seharusnya dipisah
+		if left, right, ok := s.split(word); ok {
+			corrected = append(corrected, left, right)
+			changed = true
+			continue
+		}
+
+		if best := s.closestWord(word); best != "" {
+			corrected = append(corrected, best)
+			changed = true
+			continue
+		}
+		corrected = append(corrected, word)
+	}
+
+	if !changed {
+		return ""
+	}
+	return strings.Join(corrected, " ")
+}
+
+func (s *Suggester) split(word string) (string, string, bool) {
+	for i := 2; i <= len(word)-2; i++ {
+		left, right := word[:i], word[i:]
+		if _, ok := s.vocabulary[left]; !ok {
+			continue
+		}
+		if _, ok := s.vocabulary[right]; ok {
+			return left, right, true
+		}
+	}
+	return "", "", false
+}
+
+// closestWord mencari kata katalog dengan jarak edit terkecil; jika seri,
+// kata yang lebih sering muncul dipilih
+func (s *Suggester) closestWord(word string) string {
+	if len(word) < minCorrectionLength {
+		return ""
+	}
+	edits := maxEdits(word)
+	best, bestDistance, bestWeight := "", edits+1, 0.0
+	for candidate, weight := range s.vocabulary {
+		d := editDistance(word, candidate, edits)
+		if d > edits {
+			continue
+		}
+		better := d < bestDistance ||
+			(d == bestDistance && weight > bestWeight) ||
+			(d == bestDistance && weight == bestWeight && candidate < best)
+		if better {
+			best, bestDistance, bestWeight = candidate, d, weight
+		}
+	}
+	return best
+}
+
+// RecordQuery mencatat kata kunci yang berhasil menemukan hasil
+func (s *Suggester) RecordQuery(query string) {
+	norm := NormalizeQuery(query)
+	if norm == "" {
+		return
+	}
+	s.popularMu.Lock()
+	defer s.popularMu.Unlock()
+	s.popular[norm]++
+}
+
+// SetPopular mengganti hitungan kata kunci populer, misalnya dari search log
+func (s *Suggester) SetPopular(counts map[string]int) {
+	popular := make(map[string]int, len(counts))
+	for query, count := range counts {
+		if norm := NormalizeQuery(query); norm != "" {
+			popular[norm] += count
+		}
+	}
+	s.popularMu.Lock()
+	defer s.popularMu.Unlock()
+	s.popular = popular
+}
+
+// Popular mengembalikan kata kunci yang paling sering dicari dan diawali prefix.
+// Prefix kosong mengembalikan kata kunci terpopuler secara umum.
+func (s *Suggester) Popular(prefix string, limit int) []Suggestion {
+	norm := NormalizeQuery(prefix)
+
+	s.popularMu.RLock()
+	var result []Suggestion
+	for query, count := range s.popular {
+		if norm == "" || strings.HasPrefix(query, norm) || strings.HasPrefix(compact(query), compact(norm)) {
+			result = append(result, Suggestion{Text: query, Kind: SuggestionQuery, Weight: float64(count)})
+		}
+	}
+	s.popularMu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Weight != result[j].Weight {
+			return result[i].Weight > result[j].Weight
+		}
+		return result[i].Text < result[j].Text
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	if result == nil {
+		result = []Suggestion{}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package search
+
+import "testing"
+
+func suggestionTexts(suggestions []Suggestion) []string {
+	texts := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+func newTestSuggester() *Suggester {
+	s := NewSuggester()
+	s.Reset([]Suggestion{
+		{Text: "Batik Mega Mendung", Kind: SuggestionProduct, Weight: 5},
+		{Text: "Batik Parang", Kind: SuggestionProduct, Weight: 10},
+		{Text: "Batik Parang", Kind: SuggestionProduct, Weight: 2},
+		{Text: "Batik Pekalongan", Kind: SuggestionStore, Weight: 1},
+		{Text: "Kemeja Batik", Kind: SuggestionCategory, Weight: 3},
+		{Text: "   ", Kind: SuggestionProduct, Weight: 100},
+	})
+	return s
+}
+
+func TestSuggesterCompleteOrdersByWeight(t *testing.T) {
+	s := newTestSuggester()
+
+	got := suggestionTexts(s.Complete("bat", 10))
+	want := []string{"Batik Parang", "Batik Mega Mendung", "Kemeja Batik", "Batik Pekalongan"}
+	if len(got) != len(want) {
+		t.Fatalf("Complete(bat) = %v, ingin %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Complete(bat) = %v, ingin %v", got, want)
+		}
+	}
+}
+
+func TestSuggesterCompleteMergesDuplicates(t *testing.T) {
+	got := newTestSuggester().Complete("batik par", 10)
+	if len(got) != 1 || got[0].Weight != 12 {
+		t.Fatalf("Complete(batik par) = %+v, ingin satu saran berbobot 12", got)
+	}
+}
+
+func TestSuggesterCompleteMatchesInnerWordsAndCompactForm(t *testing.T) {
+	s := newTestSuggester()
+
+	for _, prefix := range []string{"mendung", "mega m", "megam", "MEGA-MEN"} {
+		got := suggestionTexts(s.Complete(prefix, 10))
+		if len(got) != 1 || got[0] != "Batik Mega Mendung" {
+			t.Errorf("Complete(%q) = %v, ingin [Batik Mega Mendung]", prefix, got)
+		}
+	}
+	if got := s.Complete("truntum", 10); len(got) != 0 {
+		t.Errorf("Complete(truntum) = %v, ingin kosong", suggestionTexts(got))
+	}
+	if got := s.Complete("batik", 2); len(got) != 2 {
+		t.Errorf("Complete(batik, 2) mengembalikan %d saran", len(got))
+	}
+}
+
+func TestSuggesterDidYouMean(t *testing.T) {
+	s := newTestSuggester()
+
+	cases := map[string]string{
+		"batik parnag":       "batik parang",
+		"batk parang":        "batik parang",
+		"megamendung":        "mega mendung",
+		"kemeja batikparang": "kemeja batik parang",
+		"batik parang":       "",
+		"xyz":                "",
+	}
+	for query, want := range cases {
+		if got := s.DidYouMean(query); got != want {
+			t.Errorf("DidYouMean(%q) = %q, ingin %q", query, got, want)
+		}
+	}
+}
+
+func TestSuggesterPopularSurvivesReset(t *testing.T) {
+	s := newTestSuggester()
+	s.RecordQuery("Batik Tulis")
+	s.RecordQuery("batik   tulis")
+	s.RecordQuery("kain sutra")
+	s.Reset(nil)
+
+	got := s.Popular("bat", 10)
+	if len(got) != 1 || got[0].Text != "batik tulis" || got[0].Weight != 2 {
+		t.Fatalf("Popular(bat) = %+v", got)
+	}
+	if all := s.Popular("", 10); len(all) != 2 || all[0].Text != "batik tulis" {
+		t.Errorf("Popular() = %v", suggestionTexts(all))
+	}
+
+	s.SetPopular(map[string]int{"Kemeja": 4})
+	if got := suggestionTexts(s.Popular("", 10)); len(got) != 1 || got[0] != "kemeja" {
+		t.Errorf("Popular setelah SetPopular = %v", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 // ArticleService interface represents the article service contract
 type ArticleService interface {
 	GetAllArticles(page, limit int, search string) ([]entity.Article, *utils.Pagination, error)
+	GetAllArticlesCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Article, *utils.CursorPagination, error)
 	GetLatestArticles() ([]entity.Article, error)
 	GetArticleByID(id uint64) (entity.Article, error)
 	GetArticleBySlug(slug string) (entity.Article, error)
@@ -17,17 +18,21 @@ type ArticleService interface {
 	UpdateArticle(id uint64, article entity.Article) (entity.Article, error)
 	DeleteArticle(id uint64) error
 	SearchArticles(query string, page, limit int) ([]entity.Article, *utils.Pagination, error)
+	RestoreArticle(id uint64) (entity.Article, error)
+	GetArticleTrash(page, limit int) ([]entity.Article, *utils.Pagination, error)
 }
 
 // articleService is the implementation of ArticleService interface
 type articleService struct {
 	articleRepository repository.ArticleRepository
+	searchIndex       SearchIndexService
 }
 
 // NewArticleService creates a new instance of ArticleService
-func NewArticleService(repo repository.ArticleRepository) ArticleService {
+func NewArticleService(repo repository.ArticleRepository, searchIndex SearchIndexService) ArticleService {
 	return &articleService{
 		articleRepository: repo,
+		searchIndex:       searchIndex,
 	}
 }
 
@@ -53,6 +58,18 @@ func (s *articleService) GetAllArticles(page, limit int, search string) ([]entit
 	return articles, pagination, nil
 }
 
+// GetAllArticlesCursor retrieves articles with cursor pagination
+func (s *articleService) GetAllArticlesCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Article, *utils.CursorPagination, error) {
+	if limit < 1 || limit > utils.MaxCursorLimit {
+		limit = 10
+	}
+	articles, pagination, err := s.articleRepository.GetAllArticlesCursor(cursor, limit, search)
+	if err != nil {
+		return nil, nil, err
+	}
+	return articles, pagination, nil
+}
+
 // Get Latest Article
 func (s *articleService) GetLatestArticles() ([]entity.Article, error) {
 	articles, err := s.articleRepository.GetLatestArticles()
@@ -89,7 +106,12 @@ func (s *articleService) CreateArticle(article entity.Article) (entity.Article,
 	article.CreatedAt = now
 	article.UpdatedAt = now
 	
-	return s.articleRepository.CreateArticle(article)
+	created, err := s.articleRepository.CreateArticle(article)
+	if err != nil {
+		return entity.Article{}, err
+	}
+	s.searchIndex.IndexArticle(created.ID)
+	return created, nil
 }
 
 // UpdateArticle updates an existing article
@@ -132,12 +154,21 @@ func (s *articleService) UpdateArticle(id uint64, articleData entity.Article) (e
 	// Update timestamp
 	existingArticle.UpdatedAt = time.Now()
 	
-	return s.articleRepository.UpdateArticle(existingArticle)
+	updated, err := s.articleRepository.UpdateArticle(existingArticle)
+	if err != nil {
+		return entity.Article{}, err
+	}
+	s.searchIndex.IndexArticle(updated.ID)
+	return updated, nil
 }
 
 // DeleteArticle removes an article
 func (s *articleService) DeleteArticle(id uint64) error {
-	return s.articleRepository.DeleteArticle(id)
+	if err := s.articleRepository.DeleteArticle(id); err != nil {
+		return err
+	}
+	s.searchIndex.RemoveArticle(id)
+	return nil
 }
 
 // SearchArticles searches for articles by query
@@ -162,6 +193,38 @@ func (s *articleService) SearchArticles(query string, page, limit int) ([]entity
 	return articles, pagination, nil
 }
 
+// RestoreArticle restores a soft-deleted article from the trash
+func (s *articleService) RestoreArticle(id uint64) (entity.Article, error) {
+	if _, err := s.articleRepository.GetDeletedArticleByID(id); err != nil {
+		return entity.Article{}, err
+	}
+
+	if err := s.articleRepository.RestoreArticle(id); err != nil {
+		return entity.Article{}, err
+	}
+	s.searchIndex.IndexArticle(id)
+
+	return s.articleRepository.GetArticleByID(id)
+}
+
+// GetArticleTrash retrieves soft-deleted articles with pagination
+func (s *articleService) GetArticleTrash(page, limit int) ([]entity.Article, *utils.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	articles, total, err := s.articleRepository.GetTrash(page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := utils.NewPagination(page, limit, total)
+	return articles, pagination, nil
+}
+
 // type ArticleService interface {
 // 	GetAllArticle() []entity.Article
 // 	GetArticleByKey(title string) []entity.Article
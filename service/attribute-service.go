@@ -0,0 +1,366 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAttributeTextLength membatasi panjang nilai atribut bertipe text
+const maxAttributeTextLength = 100
+
+// AttributeValidationError berisi pesan kesalahan per kode atribut sehingga
+// form produk bisa menandai field yang salah
+type AttributeValidationError struct {
+	Fields map[string]string
+}
+
+func (e *AttributeValidationError) Error() string {
+	codes := make([]string, 0, len(e.Fields))
+	for code := range e.Fields {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	lines := make([]string, len(codes))
+	for i, code := range codes {
+		lines[i] = code + ": " + e.Fields[code]
+	}
+	return strings.Join(lines, "\n")
+}
+
+type AttributeService interface {
+	GetAttributes() ([]entity.Attribute, error)
+	GetCategorySchema(categoryID int) ([]entity.AttributeField, error)
+	UpdateCategorySchema(categoryID int, schemaDTO dto.UpdateCategorySchemaDTO) ([]entity.AttributeField, error)
+	ParseAttributeInput(raw string) (map[string]string, error)
+	ValidateProductAttributes(categoryID int, input map[string]string) ([]entity.ProductAttribute, error)
+	SaveProductAttributes(productID int, attributes []entity.ProductAttribute) error
+	GetProductAttributes(productID int) (map[string]string, error)
+	CarryOverAttributes(productID, categoryID int) ([]entity.ProductAttribute, error)
+}
+
+type attributeService struct {
+	attributeRepo repository.AttributeRepository
+}
+
+func NewAttributeService(attributeRepo repository.AttributeRepository) AttributeService {
+	return &attributeService{
+		attributeRepo: attributeRepo,
+	}
+}
+
+func (s *attributeService) GetAttributes() ([]entity.Attribute, error) {
+	return s.attributeRepo.GetAll()
+}
+
+// GetCategorySchema mengembalikan field atribut kategori untuk form produk
+func (s *attributeService) GetCategorySchema(categoryID int) ([]entity.AttributeField, error) {
+	schema, err := s.attributeRepo.GetCategorySchema(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]entity.AttributeField, len(schema))
+	for i, ca := range schema {
+		fields[i] = ca.Field()
+	}
+	return fields, nil
+}
+
+// UpdateCategorySchema mengganti skema atribut kategori. Nilai yang diizinkan
+// harus bagian dari pilihan atribut, dan batas angka hanya untuk atribut number.
+func (s *attributeService) UpdateCategorySchema(categoryID int, schemaDTO dto.UpdateCategorySchemaDTO) ([]entity.AttributeField, error) {
+	attributes, err := s.attributeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]entity.Attribute, len(attributes))
+	for _, attr := range attributes {
+		byCode[attr.Code] = attr
+	}
+
+	fieldErrors := make(map[string]string)
+	seen := make(map[string]bool, len(schemaDTO.Attributes))
+	schema := make([]entity.CategoryAttribute, 0, len(schemaDTO.Attributes))
+	for i, field := range schemaDTO.Attributes {
+		attr, ok := byCode[field.Code]
+		if !ok {
+			fieldErrors[field.Code] = "atribut tidak ditemukan"
+			continue
+		}
+		if seen[field.Code] {
+			fieldErrors[field.Code] = "atribut disebutkan lebih dari sekali"
+			continue
+		}
+		seen[field.Code] = true
+
+		if msg := validateSchemaField(attr, field); msg != "" {
+			fieldErrors[field.Code] = msg
+			continue
+		}
+
+		sortOrder := i + 1
+		if field.SortOrder != nil {
+			sortOrder = *field.SortOrder
+		}
+
+		var allowed []string
+		for _, value := range field.AllowedValues {
+			allowed = append(allowed, strings.ToLower(strings.TrimSpace(value)))
+		}
+
+		schema = append(schema, entity.CategoryAttribute{
+			AttributeID:   attr.ID,
+			Required:      field.Required,
+			AllowedValues: allowed,
+			MinValue:      field.Min,
+			MaxValue:      field.Max,
+			SortOrder:     sortOrder,
+		})
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &AttributeValidationError{Fields: fieldErrors}
+	}
+
+	if err := s.attributeRepo.ReplaceCategorySchema(categoryID, schema); err != nil {
+		return nil, err
+	}
+	return s.GetCategorySchema(categoryID)
+}
+
+func validateSchemaField(attr entity.Attribute, field dto.CategorySchemaFieldDTO) string {
+	if len(field.AllowedValues) > 0 {
+		if attr.Type != entity.AttributeTypeEnum && attr.Type != entity.AttributeTypeMultiEnum {
+			return "allowed_values hanya untuk atribut bertipe pilihan"
+		}
+		for _, value := range field.AllowedValues {
+			if !attr.HasOption(strings.ToLower(strings.TrimSpace(value))) {
+				return fmt.Sprintf("nilai '%s' bukan pilihan atribut %s", value, attr.Name)
+			}
+		}
+	}
+
+	if field.Min != nil || field.Max != nil {
+		if attr.Type != entity.AttributeTypeNumber {
+			return "min dan max hanya untuk atribut bertipe angka"
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return "min tidak boleh lebih besar dari max"
+		}
+	}
+	return ""
+}
+
+// ParseAttributeInput membaca field form "attributes" berupa objek JSON
+// seperti {"technique":"tulis","motif":"parang","fabric_length":2.5,"sizes":["m","l"]}.
+// Angka diubah menjadi teks dan array digabung dengan koma.
+func (s *attributeService) ParseAttributeInput(raw string) (map[string]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil, fmt.Errorf("validation: format attributes tidak valid: %v", err)
+	}
+
+	input := make(map[string]string, len(decoded))
+	fieldErrors := make(map[string]string)
+	for code, value := range decoded {
+		switch v := value.(type) {
+		case nil:
+			input[code] = ""
+		case string:
+			input[code] = v
+		case float64:
+			input[code] = strconv.FormatFloat(v, 'f', -1, 64)
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				str, ok := item.(string)
+				if !ok {
+					fieldErrors[code] = "daftar nilai harus berupa teks"
+					break
+				}
+				values = append(values, str)
+			}
+			input[code] = strings.Join(values, ",")
+		default:
+			fieldErrors[code] = "format nilai tidak valid"
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &AttributeValidationError{Fields: fieldErrors}
+	}
+	return input, nil
+}
+
+// ValidateProductAttributes memvalidasi atribut produk terhadap skema
+// kategorinya: atribut harus berlaku untuk kategori, atribut wajib harus
+// diisi, dan nilainya harus sesuai tipe serta aturan kategori. Semua
+// kesalahan dikumpulkan per field dalam AttributeValidationError.
+func (s *attributeService) ValidateProductAttributes(categoryID int, input map[string]string) ([]entity.ProductAttribute, error) {
+	schema, err := s.attributeRepo.GetCategorySchema(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]entity.AttributeField, len(schema))
+	for _, ca := range schema {
+		byCode[ca.Attribute.Code] = ca.Field()
+	}
+
+	fieldErrors := make(map[string]string)
+	for code := range input {
+		if _, ok := byCode[code]; !ok {
+			fieldErrors[code] = "atribut tidak berlaku untuk kategori ini"
+		}
+	}
+
+	var result []entity.ProductAttribute
+	for _, ca := range schema {
+		field := byCode[ca.Attribute.Code]
+
+		value := strings.TrimSpace(input[field.Code])
+		if value == "" {
+			if field.Required {
+				fieldErrors[field.Code] = fmt.Sprintf("%s wajib diisi", field.Name)
+			}
+			continue
+		}
+
+		value, msg := normalizeAttributeValue(field, value)
+		if msg != "" {
+			fieldErrors[field.Code] = msg
+			continue
+		}
+
+		result = append(result, entity.ProductAttribute{
+			AttributeID: field.ID,
+			Code:        field.Code,
+			Value:       value,
+		})
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &AttributeValidationError{Fields: fieldErrors}
+	}
+	return result, nil
+}
+
+// normalizeAttributeValue mengecek nilai sesuai tipe atribut dan mengembalikan
+// bentuk yang disimpan. Pesan kosong berarti nilai valid.
+func normalizeAttributeValue(field entity.AttributeField, value string) (string, string) {
+	switch field.Type {
+	case entity.AttributeTypeEnum:
+		value = strings.ToLower(value)
+		if !field.HasOption(value) {
+			return "", fmt.Sprintf("nilai '%s' tidak valid untuk %s", value, field.Name)
+		}
+
+	case entity.AttributeTypeMultiEnum:
+		seen := make(map[string]bool)
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item == "" || seen[item] {
+				continue
+			}
+			if !field.HasOption(item) {
+				return "", fmt.Sprintf("nilai '%s' tidak valid untuk %s", item, field.Name)
+			}
+			seen[item] = true
+			values = append(values, item)
+		}
+		if len(values) == 0 {
+			return "", fmt.Sprintf("%s wajib diisi", field.Name)
+		}
+		value = strings.Join(values, ",")
+
+	case entity.AttributeTypeNumber:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+		if err != nil {
+			return "", fmt.Sprintf("%s harus berupa angka", field.Name)
+		}
+		if field.Min != nil && number < *field.Min {
+			return "", fmt.Sprintf("%s minimal %s%s", field.Name, formatNumber(*field.Min), field.Unit)
+		}
+		if field.Max != nil && number > *field.Max {
+			return "", fmt.Sprintf("%s maksimal %s%s", field.Name, formatNumber(*field.Max), field.Unit)
+		}
+		value = formatNumber(number)
+
+	default:
+		if len(value) > maxAttributeTextLength {
+			return "", fmt.Sprintf("nilai %s terlalu panjang (maksimal %d karakter)", field.Name, maxAttributeTextLength)
+		}
+	}
+	return value, ""
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func (s *attributeService) SaveProductAttributes(productID int, attributes []entity.ProductAttribute) error {
+	return s.attributeRepo.ReplaceProductAttributes(productID, attributes)
+}
+
+func (s *attributeService) GetProductAttributes(productID int) (map[string]string, error) {
+	attributes, err := s.attributeRepo.GetProductAttributes(productID)
+	if err != nil {
+		return nil, err
+	}
+	return productAttributeMap(attributes), nil
+}
+
+// CarryOverAttributes menyiapkan atribut produk untuk kategori barunya:
+// atribut yang tidak berlaku dibuang dan sisanya divalidasi ulang terhadap
+// skema kategori baru, termasuk atribut wajib
+func (s *attributeService) CarryOverAttributes(productID, categoryID int) ([]entity.ProductAttribute, error) {
+	current, err := s.attributeRepo.GetProductAttributes(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := s.attributeRepo.GetCategorySchema(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedIDs := make(map[int]bool, len(schema))
+	for _, ca := range schema {
+		allowedIDs[ca.AttributeID] = true
+	}
+
+	input := make(map[string]string, len(current))
+	for _, attr := range current {
+		if allowedIDs[attr.AttributeID] {
+			input[attr.Code] = attr.Value
+		}
+	}
+	return s.ValidateProductAttributes(categoryID, input)
+}
+
+// productAttributeMap mengubah daftar atribut menjadi map code -> value untuk response
+func productAttributeMap(attributes []entity.ProductAttribute) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		res[attr.Code] = attr.Value
+	}
+	return res
+}
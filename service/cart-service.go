@@ -0,0 +1,434 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Batas keranjang dan masa simpan keranjang pengunjung anonim
+const (
+	cartMaxQuantity          = 99
+	cartMaxItems             = 100
+	visitorCartRetentionDays = 30
+)
+
+var (
+	ErrCartItemNotFound = errors.New("item keranjang tidak ditemukan")
+	ErrCartOwnStore     = errors.New("tidak dapat membeli produk dari toko sendiri")
+)
+
+// CartService mengelola keranjang belanja. Setiap kali keranjang dibaca,
+// harga dan stok divalidasi ulang terhadap katalog sehingga buyer melihat
+// perubahan sebelum checkout.
+type CartService interface {
+	GetCart(ownerKey string) (dto.CartResponse, error)
+	AddItem(ownerKey string, userID *uint64, addDTO dto.AddCartItemDTO) (dto.CartResponse, error)
+	UpdateItem(ownerKey string, id uint64, updateDTO dto.UpdateCartItemDTO) (dto.CartResponse, error)
+	RemoveItem(ownerKey string, id uint64) (dto.CartResponse, error)
+	Clear(ownerKey string) error
+	RemoveItems(ownerKey string, ids []uint64) error
+	ConfirmPrices(ownerKey string, items []dto.CartItemResponse) error
+	MergeVisitor(visitorID string, userID uint64) error
+	PurgeStale() error
+}
+
+type cartService struct {
+	cartRepo      repository.CartRepository
+	productRepo   repository.ProductRepository
+	inventoryRepo repository.InventoryRepository
+	storeRepo     repository.StoreRepository
+}
+
+func NewCartService(cartRepo repository.CartRepository, productRepo repository.ProductRepository, inventoryRepo repository.InventoryRepository, storeRepo repository.StoreRepository) CartService {
+	return &cartService{
+		cartRepo:      cartRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
+		storeRepo:     storeRepo,
+	}
+}
+
+func (s *cartService) GetCart(ownerKey string) (dto.CartResponse, error) {
+	if ownerKey == "" {
+		return dto.CartResponse{Stores: []dto.CartStoreGroup{}}, nil
+	}
+
+	items, err := s.cartRepo.GetItems(ownerKey)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	return s.buildCart(items)
+}
+
+// AddItem menambahkan produk ke keranjang. Produk yang sudah ada jumlahnya
+// ditambah. Stok diperiksa terhadap jumlah total di keranjang.
+func (s *cartService) AddItem(ownerKey string, userID *uint64, addDTO dto.AddCartItemDTO) (dto.CartResponse, error) {
+	variant := strings.TrimSpace(addDTO.Variant)
+	product, err := s.cartProduct(addDTO.ProductID)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+
+	if userID != nil && s.isOwnStore(product.StoreID, *userID) {
+		return dto.CartResponse{}, ErrCartOwnStore
+	}
+
+	existing, err := s.cartRepo.FindByProduct(ownerKey, product.ID, variant)
+	found := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.CartResponse{}, err
+	}
+
+	quantity := addDTO.Quantity
+	if found {
+		quantity += existing.Quantity
+	}
+	if quantity > cartMaxQuantity {
+		return dto.CartResponse{}, fmt.Errorf("jumlah per produk maksimal %d", cartMaxQuantity)
+	}
+	if err := s.checkStock(product.ID, variant, quantity); err != nil {
+		return dto.CartResponse{}, err
+	}
+
+	if found {
+		if err := s.cartRepo.UpdateQuantity(existing.ID, quantity, product.EffectivePrice); err != nil {
+			return dto.CartResponse{}, err
+		}
+		return s.GetCart(ownerKey)
+	}
+
+	count, err := s.cartRepo.CountItems(ownerKey)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	if count >= cartMaxItems {
+		return dto.CartResponse{}, fmt.Errorf("keranjang maksimal berisi %d produk", cartMaxItems)
+	}
+	_, err = s.cartRepo.Create(entity.CartItem{
+		OwnerKey:      ownerKey,
+		UserID:        userID,
+		ProductID:     product.ID,
+		Variant:       variant,
+		Quantity:      quantity,
+		PriceSnapshot: product.EffectivePrice,
+	})
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	return s.GetCart(ownerKey)
+}
+
+// UpdateItem mengubah jumlah item. Harga yang tersimpan ikut diperbarui ke
+// harga saat ini karena buyer sudah melihat perubahan harganya.
+func (s *cartService) UpdateItem(ownerKey string, id uint64, updateDTO dto.UpdateCartItemDTO) (dto.CartResponse, error) {
+	item, err := s.findItem(ownerKey, id)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+
+	product, err := s.cartProduct(item.ProductID)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	if err := s.checkStock(product.ID, item.Variant, updateDTO.Quantity); err != nil {
+		return dto.CartResponse{}, err
+	}
+
+	if err := s.cartRepo.UpdateQuantity(item.ID, updateDTO.Quantity, product.EffectivePrice); err != nil {
+		return dto.CartResponse{}, err
+	}
+	return s.GetCart(ownerKey)
+}
+
+func (s *cartService) RemoveItem(ownerKey string, id uint64) (dto.CartResponse, error) {
+	if _, err := s.findItem(ownerKey, id); err != nil {
+		return dto.CartResponse{}, err
+	}
+	if err := s.cartRepo.Delete(ownerKey, id); err != nil {
+		return dto.CartResponse{}, err
+	}
+	return s.GetCart(ownerKey)
+}
+
+func (s *cartService) Clear(ownerKey string) error {
+	if ownerKey == "" {
+		return nil
+	}
+	return s.cartRepo.Clear(ownerKey)
+}
+
+// RemoveItems menghapus item yang sudah menjadi order
+func (s *cartService) RemoveItems(ownerKey string, ids []uint64) error {
+	return s.cartRepo.DeleteItems(ownerKey, ids)
+}
+
+// ConfirmPrices menyimpan harga terbaru sebagai snapshot item keranjang,
+// sehingga status price_changed hilang setelah buyer melihat harga baru
+func (s *cartService) ConfirmPrices(ownerKey string, items []dto.CartItemResponse) error {
+	for _, item := range items {
+		if _, err := s.cartRepo.FindItem(ownerKey, item.ID); err != nil {
+			continue
+		}
+		if err := s.cartRepo.UpdateQuantity(item.ID, item.Quantity, item.Price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeVisitor memindahkan keranjang pengunjung anonim ke akun user setelah
+// login. Aturan AddItem tetap berlaku: produk dari toko milik user dan produk
+// baru yang melebihi cartMaxItems dibuang dari keranjang pengunjung.
+func (s *cartService) MergeVisitor(visitorID string, userID uint64) error {
+	if visitorID == "" {
+		return nil
+	}
+	fromKey, toKey := VisitorViewerKey(visitorID), UserViewerKey(userID)
+
+	items, err := s.cartRepo.GetItems(fromKey)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	userItems, err := s.cartRepo.GetItems(toKey)
+	if err != nil {
+		return err
+	}
+
+	inCart := make(map[string]bool, len(userItems))
+	for _, item := range userItems {
+		inCart[cartLineKey(item)] = true
+	}
+
+	ownStore := make(map[int]bool)
+	var dropped []uint64
+	for _, item := range items {
+		product, err := s.productRepo.FindByID(item.ProductID)
+		if err == nil {
+			own, checked := ownStore[product.StoreID]
+			if !checked {
+				own = s.isOwnStore(product.StoreID, userID)
+				ownStore[product.StoreID] = own
+			}
+			if own {
+				dropped = append(dropped, item.ID)
+				continue
+			}
+		}
+
+		// Produk yang sudah ada di keranjang user hanya dijumlahkan
+		if key := cartLineKey(item); !inCart[key] {
+			if len(inCart) >= cartMaxItems {
+				dropped = append(dropped, item.ID)
+				continue
+			}
+			inCart[key] = true
+		}
+	}
+
+	if len(dropped) > 0 {
+		if err := s.cartRepo.DeleteItems(fromKey, dropped); err != nil {
+			return err
+		}
+		log.Printf("🧺 %d item keranjang pengunjung tidak dipindahkan ke user %d", len(dropped), userID)
+	}
+	return s.cartRepo.Merge(fromKey, toKey, userID, cartMaxQuantity)
+}
+
+// isOwnStore mengecek apakah toko storeID milik user. Toko yang tidak
+// ditemukan dianggap bukan milik user.
+func (s *cartService) isOwnStore(storeID int, userID uint64) bool {
+	store, err := s.storeRepo.FindByID(strconv.Itoa(storeID))
+	return err == nil && uint64(store.UserID) == userID
+}
+
+// cartLineKey mengidentifikasi baris keranjang: produk dan varian yang sama
+// selalu digabung dalam satu baris
+func cartLineKey(item entity.CartItem) string {
+	return strconv.Itoa(item.ProductID) + "|" + item.Variant
+}
+
+// PurgeStale menghapus keranjang pengunjung anonim yang lama tidak diubah.
+// Keranjang user login disimpan tanpa batas waktu.
+func (s *cartService) PurgeStale() error {
+	deleted, err := s.cartRepo.DeleteStale(VisitorViewerKey(""), time.Now().AddDate(0, 0, -visitorCartRetentionDays))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("🗑️ %d item keranjang pengunjung dihapus", deleted)
+	}
+	return nil
+}
+
+func (s *cartService) findItem(ownerKey string, id uint64) (entity.CartItem, error) {
+	if ownerKey == "" {
+		return entity.CartItem{}, ErrCartItemNotFound
+	}
+	item, err := s.cartRepo.FindItem(ownerKey, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.CartItem{}, ErrCartItemNotFound
+		}
+		return entity.CartItem{}, err
+	}
+	return item, nil
+}
+
+// cartProduct mengambil produk yang masih dijual beserta harga efektifnya
+func (s *cartService) cartProduct(productID int) (entity.ProductCard, error) {
+	products, err := s.productRepo.GetPublicCardsByIDs([]int{productID})
+	if err != nil {
+		return entity.ProductCard{}, err
+	}
+	if len(products) == 0 {
+		return entity.ProductCard{}, ErrProductNotAvailable
+	}
+	return products[0], nil
+}
+
+// checkStock memastikan varian ada dan stoknya cukup. Produk tanpa data
+// inventori dianggap stoknya tidak dilacak dan hanya menerima varian kosong.
+func (s *cartService) checkStock(productID int, variant string, quantity int) error {
+	inventories, err := s.inventoryRepo.FindByProductID(productID)
+	if err != nil {
+		return err
+	}
+
+	available, tracked, ok := variantStock(inventories, variant)
+	if !ok {
+		return errors.New("varian produk tidak tersedia")
+	}
+	if tracked && available < quantity {
+		if available <= 0 {
+			return errors.New("stok produk habis")
+		}
+		return fmt.Errorf("stok tersedia hanya %d", available)
+	}
+	return nil
+}
+
+// variantStock mencari stok tersedia untuk varian. tracked false berarti
+// produk tidak memiliki data inventori; ok false berarti varian tidak dikenal.
+func variantStock(inventories []entity.ProductInventory, variant string) (available int, tracked bool, ok bool) {
+	if len(inventories) == 0 {
+		return 0, false, variant == ""
+	}
+	for _, inv := range inventories {
+		if inv.Variant == variant {
+			return inv.Available(), true, true
+		}
+	}
+	return 0, true, false
+}
+
+// buildCart memvalidasi ulang item terhadap katalog dan stok terkini lalu
+// mengelompokkannya per toko sesuai urutan item pertama ditambahkan
+func (s *cartService) buildCart(items []entity.CartItem) (dto.CartResponse, error) {
+	cart := dto.CartResponse{Stores: []dto.CartStoreGroup{}}
+	if len(items) == 0 {
+		return cart, nil
+	}
+
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	products, err := s.productRepo.GetPublicCardsByIDs(ids)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	productByID := make(map[int]entity.ProductCard, len(products))
+	for _, p := range products {
+		productByID[p.ID] = p
+	}
+
+	inventories, err := s.inventoryRepo.FindByProductIDs(ids)
+	if err != nil {
+		return dto.CartResponse{}, err
+	}
+	inventoryByProduct := map[int][]entity.ProductInventory{}
+	for _, inv := range inventories {
+		inventoryByProduct[inv.ProductID] = append(inventoryByProduct[inv.ProductID], inv)
+	}
+
+	groupIndex := map[int]int{}
+	for _, item := range items {
+		line := dto.CartItemResponse{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Variant:   item.Variant,
+			Quantity:  item.Quantity,
+			Status:    entity.CartItemOK,
+			AddedAt:   item.CreatedAt,
+		}
+
+		product, found := productByID[item.ProductID]
+		available, tracked, variantFound := variantStock(inventoryByProduct[item.ProductID], item.Variant)
+		if !found || !variantFound {
+			line.Status = entity.CartItemUnavailable
+			cart.Unavailable = append(cart.Unavailable, line)
+			cart.HasIssues = true
+			continue
+		}
+
+		line.Slug = product.Slug
+		line.Name = product.Name
+		line.Thumbnail = product.Thumbnail
+		line.Price = product.EffectivePrice
+		line.OriginalPrice = product.OriginalPrice
+		line.PromoBadge = product.PromoBadge
+		if tracked {
+			stock := available
+			if stock < 0 {
+				stock = 0
+			}
+			line.Available = &stock
+		}
+
+		switch {
+		case tracked && available <= 0:
+			line.Status = entity.CartItemOutOfStock
+		case tracked && available < item.Quantity:
+			line.Status = entity.CartItemInsufficientStock
+		case product.EffectivePrice != item.PriceSnapshot:
+			line.Status = entity.CartItemPriceChanged
+			previous := item.PriceSnapshot
+			line.PreviousPrice = &previous
+		}
+
+		purchasable := line.Status == entity.CartItemOK || line.Status == entity.CartItemPriceChanged
+		if line.Status != entity.CartItemOK {
+			cart.HasIssues = true
+		}
+
+		idx, ok := groupIndex[product.StoreID]
+		if !ok {
+			idx = len(cart.Stores)
+			groupIndex[product.StoreID] = idx
+			cart.Stores = append(cart.Stores, dto.CartStoreGroup{
+				StoreID:   product.StoreID,
+				StoreName: product.StoreName,
+				Items:     []dto.CartItemResponse{},
+			})
+		}
+
+		if purchasable {
+			line.LineTotal = line.Price * float64(line.Quantity)
+			cart.Stores[idx].Subtotal += line.LineTotal
+			cart.Subtotal += line.LineTotal
+			cart.ItemCount += line.Quantity
+		}
+		cart.Stores[idx].Items = append(cart.Stores[idx].Items, line)
+	}
+	return cart, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+)
+
+// maxFavoriteStats membatasi jumlah produk di laporan favorit penjual
+const maxFavoriteStats = 100
+
+var ErrProductNotAvailable = errors.New("produk tidak ditemukan atau belum dipublikasikan")
+
+type FavoriteService interface {
+	Favorite(userID uint64, slug string) error
+	Unfavorite(userID uint64, slug string) error
+	IsFavorite(userID uint64, slug string) (bool, error)
+	GetFavorites(userID uint64, page, limit int) ([]dto.PublicProductCard, *utils.Pagination, error)
+	GetStoreStats(storeID, limit int) ([]entity.ProductFavoriteStat, error)
+}
+
+type favoriteService struct {
+	favoriteRepo repository.FavoriteRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewFavoriteService(favoriteRepo repository.FavoriteRepository, productRepo repository.ProductRepository) FavoriteService {
+	return &favoriteService{
+		favoriteRepo: favoriteRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// Favorite menandai produk publik sebagai favorit user
+func (s *favoriteService) Favorite(userID uint64, slug string) error {
+	product, err := publishedProduct(s.productRepo, slug)
+	if err != nil {
+		return err
+	}
+	if err := s.favoriteRepo.Add(userID, product.ID); err != nil {
+		return fmt.Errorf("gagal menyimpan favorit: %v", err)
+	}
+	return nil
+}
+
+// Unfavorite tetap bisa dilakukan walaupun produk sudah tidak dipublikasikan
+func (s *favoriteService) Unfavorite(userID uint64, slug string) error {
+	product, err := s.productRepo.FindBySlug(slug)
+	if err != nil {
+		return ErrProductNotAvailable
+	}
+	if err := s.favoriteRepo.Remove(userID, product.ID); err != nil {
+		return fmt.Errorf("gagal menghapus favorit: %v", err)
+	}
+	return nil
+}
+
+func (s *favoriteService) IsFavorite(userID uint64, slug string) (bool, error) {
+	product, err := s.productRepo.FindBySlug(slug)
+	if err != nil {
+		return false, ErrProductNotAvailable
+	}
+	return s.favoriteRepo.IsFavorite(userID, product.ID)
+}
+
+func (s *favoriteService) GetFavorites(userID uint64, page, limit int) ([]dto.PublicProductCard, *utils.Pagination, error) {
+	page, limit = listPage(page, limit)
+	products, total, err := s.favoriteRepo.GetFavoriteProducts(userID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return toPublicProductCards(products), utils.NewPagination(page, limit, total), nil
+}
+
+func (s *favoriteService) GetStoreStats(storeID, limit int) ([]entity.ProductFavoriteStat, error) {
+	if limit < 1 || limit > maxFavoriteStats {
+		limit = maxFavoriteStats
+	}
+	return s.favoriteRepo.GetStoreStats(storeID, limit)
+}
+
+// publishedProduct mengambil produk yang tampil di katalog berdasarkan slug
+func publishedProduct(productRepo repository.ProductRepository, slug string) (entity.Product, error) {
+	product, err := productRepo.FindBySlug(slug)
+	if err != nil || product.Status != entity.ProductStatusPublished {
+		return entity.Product{}, ErrProductNotAvailable
+	}
+	return product, nil
+}
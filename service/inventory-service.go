@@ -0,0 +1,265 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// DefaultReservationTTL adalah lama stok ditahan sebelum reservasi kedaluwarsa
+const DefaultReservationTTL = 15 * time.Minute
+
+type InventoryService interface {
+	GetInventory(productID int) ([]dto.InventoryResponse, error)
+	GetAdjustments(productID, page, limit int) ([]entity.InventoryAdjustment, *utils.Pagination, error)
+	AdjustStock(productID int, adjustDTO dto.AdjustStockDTO, userID int) (dto.InventoryResponse, error)
+	SetThreshold(productID int, thresholdDTO dto.StockThresholdDTO) (dto.InventoryResponse, error)
+	GetLowStockByStore(storeID int) ([]dto.InventoryResponse, error)
+	Reserve(productID int, variant string, quantity int, reference string, userID int, ttl time.Duration) (entity.StockReservation, error)
+	ReleaseByReference(reference string) error
+	CommitByReference(reference string, userID int) error
+	ExpireReservations() (int, error)
+}
+
+type inventoryService struct {
+	inventoryRepo       repository.InventoryRepository
+	productRepo         repository.ProductRepository
+	storeRepo           repository.StoreRepository
+	notificationService NotificationService
+	alertService        ProductAlertService
+}
+
+func NewInventoryService(inventoryRepo repository.InventoryRepository, productRepo repository.ProductRepository, storeRepo repository.StoreRepository, notificationService NotificationService, alertService ProductAlertService) InventoryService {
+	return &inventoryService{
+		inventoryRepo:       inventoryRepo,
+		productRepo:         productRepo,
+		storeRepo:           storeRepo,
+		notificationService: notificationService,
+		alertService:        alertService,
+	}
+}
+
+func toInventoryResponse(inv entity.ProductInventory) dto.InventoryResponse {
+	return dto.InventoryResponse{
+		ID:                inv.ID,
+		ProductID:         inv.ProductID,
+		Variant:           inv.Variant,
+		Quantity:          inv.Quantity,
+		Reserved:          inv.Reserved,
+		Available:         inv.Available(),
+		LowStockThreshold: inv.LowStockThreshold,
+		LowStock:          inv.IsLowStock(),
+	}
+}
+
+func (s *inventoryService) GetInventory(productID int) ([]dto.InventoryResponse, error) {
+	inventories, err := s.inventoryRepo.FindByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []dto.InventoryResponse
+	for _, inv := range inventories {
+		res = append(res, toInventoryResponse(inv))
+	}
+	return res, nil
+}
+
+func (s *inventoryService) GetAdjustments(productID, page, limit int) ([]entity.InventoryAdjustment, *utils.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	adjustments, total, err := s.inventoryRepo.GetAdjustments(productID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := utils.NewPagination(page, limit, total)
+	return adjustments, pagination, nil
+}
+
+func (s *inventoryService) AdjustStock(productID int, adjustDTO dto.AdjustStockDTO, userID int) (dto.InventoryResponse, error) {
+	inventory, err := s.inventoryRepo.Adjust(productID, adjustDTO.Variant, adjustDTO.Change, adjustDTO.Reason, adjustDTO.Note, userID)
+	if err != nil {
+		return dto.InventoryResponse{}, err
+	}
+
+	s.checkLowStock(inventory)
+	s.checkRestock(inventory)
+	return toInventoryResponse(inventory), nil
+}
+
+func (s *inventoryService) SetThreshold(productID int, thresholdDTO dto.StockThresholdDTO) (dto.InventoryResponse, error) {
+	inventory, err := s.inventoryRepo.SetThreshold(productID, thresholdDTO.Variant, thresholdDTO.Threshold)
+	if err != nil {
+		return dto.InventoryResponse{}, err
+	}
+
+	s.checkLowStock(inventory)
+	return toInventoryResponse(inventory), nil
+}
+
+func (s *inventoryService) GetLowStockByStore(storeID int) ([]dto.InventoryResponse, error) {
+	inventories, err := s.inventoryRepo.GetLowStockByStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []dto.InventoryResponse
+	for _, inv := range inventories {
+		res = append(res, toInventoryResponse(inv))
+	}
+	return res, nil
+}
+
+func (s *inventoryService) Reserve(productID int, variant string, quantity int, reference string, userID int, ttl time.Duration) (entity.StockReservation, error) {
+	if quantity < 1 {
+		return entity.StockReservation{}, fmt.Errorf("jumlah reservasi harus lebih dari 0")
+	}
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	reservation, inventory, err := s.inventoryRepo.Reserve(productID, variant, quantity, reference, userID, time.Now().Add(ttl))
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	s.checkLowStock(inventory)
+	return reservation, nil
+}
+
+func (s *inventoryService) ReleaseByReference(reference string) error {
+	reservations, err := s.inventoryRepo.FindReservationsByReference(reference)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status != entity.ReservationActive {
+			continue
+		}
+		inventory, err := s.inventoryRepo.ReleaseReservation(reservation.ID, entity.ReservationReleased)
+		if err != nil && err != repository.ErrReservationNotActive {
+			return err
+		}
+		s.checkLowStock(inventory)
+		s.checkRestock(inventory)
+	}
+	return nil
+}
+
+func (s *inventoryService) CommitByReference(reference string, userID int) error {
+	reservations, err := s.inventoryRepo.FindReservationsByReference(reference)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status != entity.ReservationActive {
+			continue
+		}
+		inventory, err := s.inventoryRepo.CommitReservation(reservation.ID, userID)
+		if err != nil {
+			return err
+		}
+		s.checkLowStock(inventory)
+	}
+	return nil
+}
+
+// ExpireReservations melepas semua reservasi yang sudah lewat waktunya.
+// Dipanggil berkala oleh scheduler di main.
+func (s *inventoryService) ExpireReservations() (int, error) {
+	reservations, err := s.inventoryRepo.FindExpiredReservations(time.Now(), 500)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, reservation := range reservations {
+		inventory, err := s.inventoryRepo.ReleaseReservation(reservation.ID, entity.ReservationExpired)
+		if err != nil {
+			if err != repository.ErrReservationNotActive {
+				log.Printf("❌ Gagal melepas reservasi %d: %v", reservation.ID, err)
+			}
+			continue
+		}
+		s.checkLowStock(inventory)
+		s.checkRestock(inventory)
+		expired++
+	}
+	return expired, nil
+}
+
+// checkLowStock mengirim notifikasi ke penjual sekali saat stok menyentuh ambang
+// batas, dan mereset penanda saat stok kembali di atas ambang.
+func (s *inventoryService) checkLowStock(inventory entity.ProductInventory) {
+	if inventory.ID == 0 {
+		return
+	}
+
+	if !inventory.IsLowStock() {
+		if inventory.LowStockNotifiedAt != nil {
+			if err := s.inventoryRepo.SetLowStockNotified(inventory.ID, nil); err != nil {
+				log.Printf("❌ Gagal mereset penanda stok menipis %d: %v", inventory.ID, err)
+			}
+		}
+		return
+	}
+
+	if inventory.LowStockNotifiedAt != nil {
+		return
+	}
+
+	product, err := s.productRepo.FindByID(inventory.ProductID)
+	if err != nil {
+		log.Printf("❌ Produk %d tidak ditemukan untuk notifikasi stok: %v", inventory.ProductID, err)
+		return
+	}
+
+	store, err := s.storeRepo.FindByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		log.Printf("❌ Toko %d tidak ditemukan untuk notifikasi stok: %v", product.StoreID, err)
+		return
+	}
+
+	name := product.Name
+	if inventory.Variant != "" {
+		name = fmt.Sprintf("%s (%s)", product.Name, inventory.Variant)
+	}
+
+	title := "Stok menipis"
+	message := fmt.Sprintf("Stok %s tersisa %d.", name, inventory.Available())
+	if inventory.Available() <= 0 {
+		title = "Stok habis"
+		message = fmt.Sprintf("Stok %s sudah habis.", name)
+	}
+
+	if err := s.notificationService.Notify(store.UserID, entity.NotificationLowStock, title, message, "/product/detail/"+product.Slug); err != nil {
+		return
+	}
+
+	now := time.Now()
+	if err := s.inventoryRepo.SetLowStockNotified(inventory.ID, &now); err != nil {
+		log.Printf("❌ Gagal menandai notifikasi stok %d: %v", inventory.ID, err)
+	}
+}
+
+// checkRestock memberi tahu buyer yang menunggu produk tersedia kembali
+// setelah stok bertambah
+func (s *inventoryService) checkRestock(inventory entity.ProductInventory) {
+	if inventory.ID == 0 || inventory.Available() <= 0 {
+		return
+	}
+	s.alertService.NotifyBackInStock(inventory.ProductID)
+}
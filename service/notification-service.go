@@ -0,0 +1,68 @@
+package service
+
+import (
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"log"
+)
+
+type NotificationService interface {
+	Notify(userID int, notificationType, title, message, link string) error
+	GetNotifications(userID, page, limit int, unreadOnly bool) ([]entity.Notification, *utils.Pagination, error)
+	MarkAsRead(id uint64, userID int) error
+	MarkAllAsRead(userID int) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) Notify(userID int, notificationType, title, message, link string) error {
+	if userID == 0 {
+		return nil
+	}
+
+	_, err := s.notificationRepo.Create(entity.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Link:    link,
+	})
+	if err != nil {
+		log.Printf("❌ Gagal membuat notifikasi untuk user %d: %v", userID, err)
+	}
+	return err
+}
+
+func (s *notificationService) GetNotifications(userID, page, limit int, unreadOnly bool) ([]entity.Notification, *utils.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, total, err := s.notificationRepo.GetByUserID(userID, page, limit, unreadOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := utils.NewPagination(page, limit, total)
+	return notifications, pagination, nil
+}
+
+func (s *notificationService) MarkAsRead(id uint64, userID int) error {
+	return s.notificationRepo.MarkAsRead(id, userID)
+}
+
+func (s *notificationService) MarkAllAsRead(userID int) error {
+	return s.notificationRepo.MarkAllAsRead(userID)
+}
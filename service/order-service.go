@@ -0,0 +1,570 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/shipping"
+	"batik/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Batas waktu order. Reservasi stok dibuat sedikit lebih lama dari batas
+// pembayaran agar order dibatalkan job lebih dulu sebelum stoknya lepas.
+const (
+	orderPaymentWindow     = 24 * time.Hour
+	orderReservationTTL    = orderPaymentWindow + time.Hour
+	orderAutoCompleteAfter = 7 * 24 * time.Hour
+	orderJobBatch          = 200
+	// Efek samping yang belum selesai setelah orderSettlementRetry diulang job
+	orderSettlementRetry = time.Minute
+)
+
+var (
+	ErrOrderNotFound         = errors.New("order tidak ditemukan")
+	ErrOrderTransition       = errors.New("perubahan status order tidak diizinkan")
+	ErrCheckoutCartEmpty     = errors.New("keranjang kosong atau toko yang dipilih tidak ada di keranjang")
+	ErrCheckoutCartHasIssues = errors.New("ada produk di keranjang yang harganya berubah, stoknya tidak mencukupi, atau tidak tersedia")
+)
+
+// OrderService membuat order dari keranjang dan menjalankan state machine
+// order (lihat entity.CanTransitionOrder). Stok ditahan saat checkout,
+// menjadi penjualan saat order dibayar, dan dilepas jika order batal.
+type OrderService interface {
+	Checkout(user entity.User, checkoutDTO dto.CheckoutDTO) ([]entity.Order, error)
+	GetBuyerOrders(userID uint64, status string, page, limit int) ([]entity.Order, *utils.Pagination, error)
+	GetBuyerOrder(number string, userID uint64) (entity.Order, error)
+	GetStoreOrders(storeID int, status string, page, limit int) ([]entity.Order, *utils.Pagination, error)
+	GetStoreOrder(number string, storeID int) (entity.Order, error)
+	GetOrder(number string) (entity.Order, error)
+	UpdateStatus(order entity.Order, actor string, actorID *uint64, statusDTO dto.UpdateOrderStatusDTO) (entity.Order, error)
+	MarkPaid(number, note string) (entity.Order, error)
+	CancelOverdue() error
+	CompleteDelivered() error
+	SettlePending() error
+}
+
+type orderService struct {
+	orderRepo           repository.OrderRepository
+	storeRepo           repository.StoreRepository
+	cartService         CartService
+	inventoryService    InventoryService
+	voucherService      VoucherService
+	shippingService     ShippingService
+	notificationService NotificationService
+}
+
+func NewOrderService(orderRepo repository.OrderRepository, storeRepo repository.StoreRepository, cartService CartService, inventoryService InventoryService, voucherService VoucherService, shippingService ShippingService, notificationService NotificationService) OrderService {
+	return &orderService{
+		orderRepo:           orderRepo,
+		storeRepo:           storeRepo,
+		cartService:         cartService,
+		inventoryService:    inventoryService,
+		voucherService:      voucherService,
+		shippingService:     shippingService,
+		notificationService: notificationService,
+	}
+}
+
+// Checkout membuat satu order per toko dari keranjang user. Semua toko yang
+// dipilih harus bisa diproses; jika salah satu gagal, order yang sudah dibuat
+// dibatalkan kembali sehingga keranjang tetap utuh.
+func (s *orderService) Checkout(user entity.User, checkoutDTO dto.CheckoutDTO) ([]entity.Order, error) {
+	ownerKey := UserViewerKey(user.ID)
+	cart, err := s.cartService.GetCart(ownerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[int]bool{}
+	for _, id := range checkoutDTO.StoreIDs {
+		selected[id] = true
+	}
+	vouchers := map[int]string{}
+	for _, v := range checkoutDTO.Vouchers {
+		if _, dup := vouchers[v.StoreID]; dup {
+			return nil, errors.New("hanya satu voucher per toko")
+		}
+		vouchers[v.StoreID] = v.Code
+	}
+
+	var (
+		groups  []dto.CartStoreGroup
+		changed []dto.CartItemResponse
+	)
+	for _, group := range cart.Stores {
+		if len(selected) > 0 && !selected[group.StoreID] {
+			continue
+		}
+		for _, item := range group.Items {
+			switch item.Status {
+			case entity.CartItemOK:
+			case entity.CartItemPriceChanged:
+				changed = append(changed, item)
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrCheckoutCartHasIssues, item.Name)
+			}
+		}
+		groups = append(groups, group)
+	}
+	// Harga yang berubah harus dilihat buyer dulu. Snapshot diperbarui agar
+	// checkout berikutnya memakai harga baru yang sudah ditampilkan.
+	if len(changed) > 0 {
+		if err := s.cartService.ConfirmPrices(ownerKey, changed); err != nil {
+			log.Printf("❌ Gagal memperbarui harga keranjang user %d: %v", user.ID, err)
+		}
+		return nil, fmt.Errorf("%w: harga %s berubah, periksa kembali keranjang", ErrCheckoutCartHasIssues, changed[0].Name)
+	}
+	if len(groups) == 0 {
+		return nil, ErrCheckoutCartEmpty
+	}
+	for storeID := range vouchers {
+		if len(selected) > 0 && !selected[storeID] {
+			return nil, fmt.Errorf("voucher untuk toko %d tidak termasuk checkout", storeID)
+		}
+	}
+	couriers := map[int]dto.CheckoutCourierDTO{}
+	for _, c := range checkoutDTO.Couriers {
+		couriers[c.StoreID] = c
+	}
+	for _, group := range groups {
+		if _, ok := couriers[group.StoreID]; !ok {
+			return nil, fmt.Errorf("pilih kurir untuk toko %s", group.StoreName)
+		}
+	}
+
+	var (
+		orders  []entity.Order
+		itemIDs []uint64
+	)
+	for _, group := range groups {
+		order, err := s.createOrder(user, group, vouchers[group.StoreID], couriers[group.StoreID], checkoutDTO)
+		if err != nil {
+			s.rollbackCheckout(orders)
+			return nil, fmt.Errorf("gagal membuat order untuk toko %s: %w", group.StoreName, err)
+		}
+		orders = append(orders, order)
+		for _, item := range group.Items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+	}
+
+	if err := s.cartService.RemoveItems(ownerKey, itemIDs); err != nil {
+		log.Printf("❌ Gagal menghapus item keranjang setelah checkout user %d: %v", user.ID, err)
+	}
+	for _, order := range orders {
+		s.notifySeller(order, entity.NotificationNewOrder, "Order baru",
+			fmt.Sprintf("Order %s menunggu pembayaran buyer.", order.Number))
+	}
+	return orders, nil
+}
+
+// createOrder menghitung ongkir, menahan stok, memakai voucher, lalu
+// menyimpan order untuk satu toko. Jika salah satu langkah gagal, stok dan
+// voucher dilepas kembali.
+func (s *orderService) createOrder(user entity.User, group dto.CartStoreGroup, voucherCode string, courier dto.CheckoutCourierDTO, checkoutDTO dto.CheckoutDTO) (entity.Order, error) {
+	store, err := s.storeRepo.FindByID(strconv.Itoa(group.StoreID))
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("gagal mengambil toko: %w", err)
+	}
+	if uint64(store.UserID) == user.ID {
+		return entity.Order{}, ErrCartOwnStore
+	}
+
+	var shippingItems []ShippingItem
+	for _, item := range group.Items {
+		shippingItems = append(shippingItems, ShippingItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	parcel, quotes, err := s.shippingService.QuoteItems(group.StoreID, shippingItems, checkoutDTO.Destination)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	quote, ok := shipping.FindQuote(quotes, courier.Courier, courier.Service)
+	if !ok {
+		return entity.Order{}, shipping.ErrNoService
+	}
+
+	now := time.Now()
+	order := entity.Order{
+		Number:             newOrderNumber(now),
+		UserID:             user.ID,
+		StoreID:            group.StoreID,
+		Status:             entity.OrderPendingPayment,
+		ShippingCost:       quote.Cost,
+		RecipientName:      checkoutDTO.RecipientName,
+		RecipientPhone:     checkoutDTO.RecipientPhone,
+		ShippingAddress:    checkoutDTO.ShippingAddress,
+		ShippingProvince:   checkoutDTO.Destination.Province,
+		ShippingCity:       checkoutDTO.Destination.City,
+		ShippingPostalCode: checkoutDTO.Destination.PostalCode,
+		Courier:            quote.Courier,
+		CourierService:     quote.Service,
+		ShippingWeight:     parcel.ChargeableWeight(),
+		Note:               checkoutDTO.Note,
+		PaymentDueAt:       now.Add(orderPaymentWindow),
+		Events: []entity.OrderEvent{{
+			ToStatus:  entity.OrderPendingPayment,
+			Actor:     entity.OrderActorBuyer,
+			ActorID:   &user.ID,
+			CreatedAt: now,
+		}},
+	}
+
+	for _, item := range group.Items {
+		lineTotal := item.Price * float64(item.Quantity)
+		order.Subtotal += lineTotal
+		order.Items = append(order.Items, entity.OrderItem{
+			ProductID:     item.ProductID,
+			Variant:       item.Variant,
+			ProductName:   item.Name,
+			ProductSlug:   item.Slug,
+			ImageURL:      item.Thumbnail,
+			Price:         item.Price,
+			OriginalPrice: item.OriginalPrice,
+			Quantity:      item.Quantity,
+			LineTotal:     lineTotal,
+		})
+	}
+
+	// Pelepasan yang gagal dicatat ke outbox dan diulang job SettlePending
+	release := func() {
+		var failed []entity.OrderSettlement
+		for _, settlement := range orderSettlements(order, entity.OrderCancelled) {
+			if err := s.applySettlement(settlement); err != nil {
+				log.Printf("❌ Gagal %s order %s, akan diulang: %v", settlement.Action, order.Number, err)
+				failed = append(failed, settlement)
+			}
+		}
+		if err := s.orderRepo.AddSettlements(failed); err != nil {
+			log.Printf("❌ Gagal mencatat efek samping order %s: %v", order.Number, err)
+		}
+	}
+
+	// Produk yang stoknya tidak dilacak (Available kosong) tidak perlu ditahan
+	for _, item := range group.Items {
+		if item.Available == nil {
+			continue
+		}
+		if _, err := s.inventoryService.Reserve(item.ProductID, item.Variant, item.Quantity, order.Number, int(user.ID), orderReservationTTL); err != nil {
+			release()
+			return entity.Order{}, fmt.Errorf("%s: %w", item.Name, err)
+		}
+	}
+
+	if voucherCode != "" {
+		redemption, err := s.voucherService.Redeem(user, voucherCode, group.StoreID, order.Subtotal, order.Number)
+		if err != nil {
+			release()
+			return entity.Order{}, err
+		}
+		order.VoucherCode = strings.ToUpper(strings.TrimSpace(voucherCode))
+		order.Discount = redemption.Discount
+	}
+	order.Total = order.Subtotal - order.Discount + order.ShippingCost
+
+	order, err = s.orderRepo.Create(order)
+	if err != nil {
+		release()
+		return entity.Order{}, err
+	}
+	return order, nil
+}
+
+// rollbackCheckout membatalkan order yang sudah dibuat dalam checkout yang gagal
+func (s *orderService) rollbackCheckout(orders []entity.Order) {
+	for _, order := range orders {
+		_, err := s.transition(order, entity.OrderCancelled, entity.OrderActorSystem, nil,
+			"Checkout dibatalkan karena order toko lain gagal dibuat", nil)
+		if err != nil {
+			log.Printf("❌ Gagal membatalkan order %s: %v", order.Number, err)
+		}
+	}
+}
+
+// newOrderNumber membuat nomor order seperti BTK-261018-9F3A0C7B
+func newOrderNumber(now time.Time) string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("BTK-%s-%08X", now.Format("060102"), now.UnixNano()&0xFFFFFFFF)
+	}
+	return "BTK-" + now.Format("060102") + "-" + strings.ToUpper(hex.EncodeToString(b))
+}
+
+func (s *orderService) GetBuyerOrders(userID uint64, status string, page, limit int) ([]entity.Order, *utils.Pagination, error) {
+	if status != "" && !entity.IsValidOrderStatus(status) {
+		return nil, nil, errors.New("status order tidak valid")
+	}
+	page, limit = listPage(page, limit)
+	orders, total, err := s.orderRepo.GetByUser(userID, status, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orders, utils.NewPagination(page, limit, total), nil
+}
+
+func (s *orderService) GetBuyerOrder(number string, userID uint64) (entity.Order, error) {
+	order, err := s.GetOrder(number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	if order.UserID != userID {
+		return entity.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *orderService) GetStoreOrders(storeID int, status string, page, limit int) ([]entity.Order, *utils.Pagination, error) {
+	if status != "" && !entity.IsValidOrderStatus(status) {
+		return nil, nil, errors.New("status order tidak valid")
+	}
+	page, limit = listPage(page, limit)
+	orders, total, err := s.orderRepo.GetByStore(storeID, status, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orders, utils.NewPagination(page, limit, total), nil
+}
+
+func (s *orderService) GetStoreOrder(number string, storeID int) (entity.Order, error) {
+	order, err := s.GetOrder(number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	if order.StoreID != storeID {
+		return entity.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *orderService) GetOrder(number string) (entity.Order, error) {
+	order, err := s.orderRepo.FindByNumber(number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Order{}, ErrOrderNotFound
+		}
+		return entity.Order{}, err
+	}
+	return order, nil
+}
+
+// UpdateStatus mengubah status order oleh buyer, penjual, atau admin sesuai
+// state machine. Penjual wajib mengisi nomor resi saat mengirim order.
+func (s *orderService) UpdateStatus(order entity.Order, actor string, actorID *uint64, statusDTO dto.UpdateOrderStatusDTO) (entity.Order, error) {
+	var updates map[string]interface{}
+	if statusDTO.Status == entity.OrderShipped {
+		tracking := strings.TrimSpace(statusDTO.TrackingNumber)
+		if tracking == "" {
+			return entity.Order{}, errors.New("nomor resi wajib diisi saat mengirim order")
+		}
+		updates = map[string]interface{}{"tracking_number": tracking}
+	}
+	return s.transition(order, statusDTO.Status, actor, actorID, statusDTO.Note, updates)
+}
+
+// MarkPaid menandai order sudah dibayar. Dipanggil oleh proses pembayaran.
+func (s *orderService) MarkPaid(number, note string) (entity.Order, error) {
+	order, err := s.GetOrder(number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return s.transition(order, entity.OrderPaid, entity.OrderActorSystem, nil, note, nil)
+}
+
+// CancelOverdue membatalkan order yang tidak dibayar sampai batas waktunya
+func (s *orderService) CancelOverdue() error {
+	orders, err := s.orderRepo.FindOverdueUnpaid(time.Now(), orderJobBatch)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		_, err := s.transition(order, entity.OrderCancelled, entity.OrderActorSystem, nil, "Batas waktu pembayaran habis", nil)
+		if err != nil && !errors.Is(err, repository.ErrOrderStatusChanged) {
+			log.Printf("❌ Gagal membatalkan order %s: %v", order.Number, err)
+		}
+	}
+	return nil
+}
+
+// CompleteDelivered menyelesaikan order yang sudah diterima tetapi tidak
+// dikonfirmasi buyer dalam orderAutoCompleteAfter
+func (s *orderService) CompleteDelivered() error {
+	orders, err := s.orderRepo.FindDeliveredBefore(time.Now().Add(-orderAutoCompleteAfter), orderJobBatch)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		_, err := s.transition(order, entity.OrderCompleted, entity.OrderActorSystem, nil, "Diselesaikan otomatis", nil)
+		if err != nil && !errors.Is(err, repository.ErrOrderStatusChanged) {
+			log.Printf("❌ Gagal menyelesaikan order %s: %v", order.Number, err)
+		}
+	}
+	return nil
+}
+
+// transition menjalankan satu perpindahan status beserta efek sampingnya:
+// stok menjadi penjualan saat dibayar, stok dan voucher dilepas saat batal,
+// dan voucher dilepas saat refund. Stok order yang di-refund tidak otomatis
+// dikembalikan; penjual mencatat barang kembali lewat penyesuaian stok "return".
+// Efek samping ditulis ke outbox dalam transaksi status yang sama, lalu
+// dijalankan; yang gagal diulang job SettlePending.
+func (s *orderService) transition(order entity.Order, to, actor string, actorID *uint64, note string, updates map[string]interface{}) (entity.Order, error) {
+	if !entity.IsValidOrderStatus(to) {
+		return entity.Order{}, errors.New("status order tidak valid")
+	}
+	if !entity.CanTransitionOrder(order.Status, to, actor) {
+		return entity.Order{}, fmt.Errorf("%w: %s ke %s", ErrOrderTransition, order.Status, to)
+	}
+
+	now := time.Now()
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	switch to {
+	case entity.OrderPaid:
+		updates["paid_at"] = now
+	case entity.OrderShipped:
+		updates["shipped_at"] = now
+	case entity.OrderDelivered:
+		updates["delivered_at"] = now
+	case entity.OrderCompleted:
+		updates["completed_at"] = now
+	case entity.OrderCancelled:
+		updates["cancelled_at"] = now
+	}
+
+	event := entity.OrderEvent{
+		Actor:     actor,
+		ActorID:   actorID,
+		Note:      strings.TrimSpace(note),
+		CreatedAt: now,
+	}
+	settlements, err := s.orderRepo.Transition(order, to, event, updates, orderSettlements(order, to))
+	if err != nil {
+		return entity.Order{}, err
+	}
+	for _, settlement := range settlements {
+		s.settle(settlement)
+	}
+
+	s.notifyTransition(order, to, actor)
+	return s.GetOrder(order.Number)
+}
+
+// orderSettlements mengembalikan efek samping perpindahan order ke status to
+func orderSettlements(order entity.Order, to string) []entity.OrderSettlement {
+	var actions []string
+	switch to {
+	case entity.OrderPaid:
+		actions = []string{entity.SettlementCommitStock}
+	case entity.OrderCancelled:
+		actions = []string{entity.SettlementReleaseStock, entity.SettlementReleaseVoucher}
+	case entity.OrderRefunded:
+		actions = []string{entity.SettlementReleaseVoucher}
+	}
+
+	settlements := make([]entity.OrderSettlement, 0, len(actions))
+	for _, action := range actions {
+		settlements = append(settlements, entity.OrderSettlement{
+			OrderNumber: order.Number,
+			UserID:      order.UserID,
+			Action:      action,
+		})
+	}
+	return settlements
+}
+
+// applySettlement menjalankan satu efek samping. Semua aksi aman diulang:
+// reservasi dan pemakaian voucher yang sudah diproses dilewati.
+func (s *orderService) applySettlement(settlement entity.OrderSettlement) error {
+	switch settlement.Action {
+	case entity.SettlementCommitStock:
+		return s.inventoryService.CommitByReference(settlement.OrderNumber, int(settlement.UserID))
+	case entity.SettlementReleaseStock:
+		return s.inventoryService.ReleaseByReference(settlement.OrderNumber)
+	case entity.SettlementReleaseVoucher:
+		return s.voucherService.ReleaseByReference(settlement.OrderNumber)
+	}
+	return fmt.Errorf("aksi settlement tidak dikenal: %s", settlement.Action)
+}
+
+// settle menjalankan efek samping dari outbox dan menghapusnya jika berhasil
+func (s *orderService) settle(settlement entity.OrderSettlement) {
+	if err := s.applySettlement(settlement); err != nil {
+		log.Printf("❌ Gagal %s order %s, akan diulang: %v", settlement.Action, settlement.OrderNumber, err)
+		if err := s.orderRepo.FailSettlement(settlement.ID, truncate(err.Error(), 255)); err != nil {
+			log.Printf("❌ Gagal mencatat percobaan settlement %d: %v", settlement.ID, err)
+		}
+		return
+	}
+	if err := s.orderRepo.CompleteSettlement(settlement.ID); err != nil {
+		log.Printf("❌ Gagal menghapus settlement %d: %v", settlement.ID, err)
+	}
+}
+
+// SettlePending mengulang efek samping order yang belum berhasil. Dipanggil
+// berkala oleh scheduler di main.
+func (s *orderService) SettlePending() error {
+	settlements, err := s.orderRepo.FindPendingSettlements(time.Now().Add(-orderSettlementRetry), orderJobBatch)
+	if err != nil {
+		return err
+	}
+	for _, settlement := range settlements {
+		s.settle(settlement)
+	}
+	return nil
+}
+
+// notifyTransition memberi tahu pihak lain tentang perubahan status: buyer
+// untuk perubahan oleh penjual, admin, atau sistem; penjual untuk perubahan
+// oleh buyer dan pembayaran masuk
+func (s *orderService) notifyTransition(order entity.Order, to, actor string) {
+	message := fmt.Sprintf("Status order %s sekarang %s.", order.Number, orderStatusLabel(to))
+	if actor != entity.OrderActorBuyer {
+		s.notificationService.Notify(int(order.UserID), entity.NotificationOrderStatus,
+			"Status order diperbarui", message, "/orders/"+order.Number)
+	}
+	if actor == entity.OrderActorBuyer || to == entity.OrderPaid {
+		s.notifySeller(order, entity.NotificationOrderStatus, "Status order diperbarui", message)
+	}
+}
+
+func (s *orderService) notifySeller(order entity.Order, notificationType, title, message string) {
+	store, err := s.storeRepo.FindByID(strconv.Itoa(order.StoreID))
+	if err != nil {
+		log.Printf("❌ Gagal mengambil toko order %s: %v", order.Number, err)
+		return
+	}
+	s.notificationService.Notify(store.UserID, notificationType, title, message,
+		fmt.Sprintf("/my-store/%d/orders/%s", order.StoreID, order.Number))
+}
+
+func orderStatusLabel(status string) string {
+	switch status {
+	case entity.OrderPendingPayment:
+		return "menunggu pembayaran"
+	case entity.OrderPaid:
+		return "sudah dibayar"
+	case entity.OrderProcessing:
+		return "sedang diproses"
+	case entity.OrderShipped:
+		return "dikirim"
+	case entity.OrderDelivered:
+		return "sudah diterima"
+	case entity.OrderCompleted:
+		return "selesai"
+	case entity.OrderCancelled:
+		return "dibatalkan"
+	case entity.OrderRefunded:
+		return "dana dikembalikan"
+	}
+	return status
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeOrderRepository struct {
+	repository.OrderRepository
+	order       entity.Order
+	settlements map[uint64]*entity.OrderSettlement
+	nextID      uint64
+}
+
+func (r *fakeOrderRepository) FindByNumber(number string) (entity.Order, error) {
+	return r.order, nil
+}
+
+func (r *fakeOrderRepository) Transition(order entity.Order, to string, event entity.OrderEvent, updates map[string]interface{}, settlements []entity.OrderSettlement) ([]entity.OrderSettlement, error) {
+	if r.order.Status != order.Status {
+		return nil, repository.ErrOrderStatusChanged
+	}
+	r.order.Status = to
+	for i := range settlements {
+		r.nextID++
+		settlements[i].ID = r.nextID
+		saved := settlements[i]
+		r.settlements[saved.ID] = &saved
+	}
+	return settlements, nil
+}
+
+func (r *fakeOrderRepository) FindPendingSettlements(before time.Time, limit int) ([]entity.OrderSettlement, error) {
+	var pending []entity.OrderSettlement
+	for _, settlement := range r.settlements {
+		pending = append(pending, *settlement)
+	}
+	return pending, nil
+}
+
+func (r *fakeOrderRepository) CompleteSettlement(id uint64) error {
+	delete(r.settlements, id)
+	return nil
+}
+
+func (r *fakeOrderRepository) FailSettlement(id uint64, reason string) error {
+	r.settlements[id].Attempts++
+	r.settlements[id].LastError = reason
+	return nil
+}
+
+type fakeOrderStoreRepository struct {
+	repository.StoreRepository
+	store entity.Store
+	err   error
+}
+
+func (r *fakeOrderStoreRepository) FindByID(id string) (entity.Store, error) {
+	return r.store, r.err
+}
+
+type fakeOrderInventoryService struct {
+	InventoryService
+	commitErr error
+	committed []string
+}
+
+func (s *fakeOrderInventoryService) CommitByReference(reference string, userID int) error {
+	if s.commitErr != nil {
+		return s.commitErr
+	}
+	s.committed = append(s.committed, reference)
+	return nil
+}
+
+func newOrderTestService(status string) (*orderService, *fakeOrderRepository, *fakeOrderInventoryService) {
+	repo := &fakeOrderRepository{
+		order:       entity.Order{ID: 1, Number: "BTK-1", UserID: 7, StoreID: 3, Status: status},
+		settlements: map[uint64]*entity.OrderSettlement{},
+	}
+	inventory := &fakeOrderInventoryService{}
+	svc := &orderService{
+		orderRepo:           repo,
+		storeRepo:           &fakeOrderStoreRepository{store: entity.Store{UserID: 9}},
+		inventoryService:    inventory,
+		notificationService: &fakeNotificationService{},
+	}
+	return svc, repo, inventory
+}
+
+func TestMarkPaidRetriesFailedStockCommit(t *testing.T) {
+	svc, repo, inventory := newOrderTestService(entity.OrderPendingPayment)
+	inventory.commitErr = errors.New("koneksi terputus")
+
+	order, err := svc.MarkPaid("BTK-1", "")
+	if err != nil {
+		t.Fatalf("MarkPaid: %v", err)
+	}
+	if order.Status != entity.OrderPaid {
+		t.Errorf("status = %s, ingin paid", order.Status)
+	}
+	if len(repo.settlements) != 1 {
+		t.Fatalf("settlement tertunda = %d, ingin 1", len(repo.settlements))
+	}
+	for _, settlement := range repo.settlements {
+		if settlement.Action != entity.SettlementCommitStock || settlement.Attempts != 1 || settlement.LastError == "" {
+			t.Errorf("settlement gagal harus dicatat: %+v", settlement)
+		}
+	}
+
+	inventory.commitErr = nil
+	if err := svc.SettlePending(); err != nil {
+		t.Fatalf("SettlePending: %v", err)
+	}
+	if len(repo.settlements) != 0 || len(inventory.committed) != 1 {
+		t.Errorf("setelah diulang: tertunda=%d commit=%v", len(repo.settlements), inventory.committed)
+	}
+}
+
+func TestCheckoutFailsClosedWhenStoreLookupFails(t *testing.T) {
+	svc, _, _ := newOrderTestService(entity.OrderPendingPayment)
+	svc.storeRepo = &fakeOrderStoreRepository{err: errors.New("database tidak tersedia")}
+
+	_, err := svc.createOrder(entity.User{ID: 7}, dto.CartStoreGroup{StoreID: 3}, "", dto.CheckoutCourierDTO{}, dto.CheckoutDTO{})
+	if err == nil {
+		t.Error("checkout harus gagal jika toko tidak bisa diperiksa")
+	}
+
+	svc.storeRepo = &fakeOrderStoreRepository{store: entity.Store{UserID: 7}}
+	if _, err := svc.createOrder(entity.User{ID: 7}, dto.CartStoreGroup{StoreID: 3}, "", dto.CheckoutCourierDTO{}, dto.CheckoutDTO{}); !errors.Is(err, ErrCartOwnStore) {
+		t.Errorf("toko sendiri: err = %v, ingin ErrCartOwnStore", err)
+	}
+}
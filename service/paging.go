@@ -0,0 +1,41 @@
+package service
+
+import "fmt"
+
+// normalizePage menormalkan paginasi: page minimal 1, dan limit di luar
+// 1..maxLimit diganti defaultLimit
+func normalizePage(page, limit, defaultLimit, maxLimit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+// listPage menormalkan paginasi daftar milik buyer atau toko (favorit,
+// wishlist, voucher, pesanan)
+func listPage(page, limit int) (int, int) {
+	return normalizePage(page, limit, 20, 100)
+}
+
+// discussionPage menormalkan paginasi konten buatan pengguna di halaman
+// produk, yaitu ulasan dan pertanyaan
+func discussionPage(page, limit int) (int, int) {
+	return normalizePage(page, limit, 10, 50)
+}
+
+// moderationQueueStatus memvalidasi filter status antrean moderasi ulasan dan
+// pertanyaan. allowed[0] dipakai jika status kosong.
+func moderationQueueStatus(status string, allowed ...string) (string, error) {
+	if status == "" {
+		return allowed[0], nil
+	}
+	for _, s := range allowed {
+		if s == status {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("status tidak valid (pending, hidden, published, flagged)")
+}
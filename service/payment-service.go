@@ -0,0 +1,297 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/payment"
+	"batik/repository"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound   = errors.New("tagihan tidak ditemukan")
+	ErrOrderNotPayable   = errors.New("order tidak sedang menunggu pembayaran")
+	ErrSimulatorDisabled = errors.New("simulator pembayaran hanya tersedia untuk provider tiruan")
+	// ErrPaymentUnavailable dikembalikan jika payment gateway belum
+	// dikonfigurasi; fitur lain tetap berjalan
+	ErrPaymentUnavailable = errors.New("pembayaran sedang tidak tersedia")
+)
+
+// Tagihan lunas yang ordernya belum ditandai dibayar setelah
+// paymentOrderSyncRetry diulang job SyncPaidOrders
+const paymentOrderSyncRetry = time.Minute
+
+// PaymentService menerbitkan tagihan lewat PaymentProvider dan mencocokkan
+// webhook dari gateway dengan tagihan dan order. Order ditandai dibayar hanya
+// dari webhook yang tanda tangannya valid dan nominalnya sesuai.
+type PaymentService interface {
+	GetMethods() (map[string][]string, error)
+	CreatePayment(user entity.User, orderNumber string, paymentDTO dto.CreatePaymentDTO) (entity.Payment, error)
+	GetOrderPayments(user entity.User, orderNumber string) ([]entity.Payment, error)
+	HandleWebhook(header http.Header, body []byte) (dto.WebhookResult, error)
+	Simulate(paymentID uint64, simulateDTO dto.SimulatePaymentDTO) (dto.WebhookResult, error)
+	ExpirePending() error
+	SyncPaidOrders() error
+}
+
+type paymentService struct {
+	paymentRepo  repository.PaymentRepository
+	orderService OrderService
+	provider     payment.PaymentProvider
+}
+
+// NewPaymentService menerima provider nil jika payment gateway belum
+// dikonfigurasi; endpoint pembayaran lalu mengembalikan ErrPaymentUnavailable
+func NewPaymentService(paymentRepo repository.PaymentRepository, orderService OrderService, provider payment.PaymentProvider) PaymentService {
+	return &paymentService{
+		paymentRepo:  paymentRepo,
+		orderService: orderService,
+		provider:     provider,
+	}
+}
+
+func (s *paymentService) GetMethods() (map[string][]string, error) {
+	if s.provider == nil {
+		return nil, ErrPaymentUnavailable
+	}
+	return s.provider.Channels(), nil
+}
+
+// CreatePayment menerbitkan tagihan untuk order milik user. Permintaan ulang
+// dengan metode dan channel yang sama mengembalikan tagihan yang masih aktif.
+func (s *paymentService) CreatePayment(user entity.User, orderNumber string, paymentDTO dto.CreatePaymentDTO) (entity.Payment, error) {
+	if s.provider == nil {
+		return entity.Payment{}, ErrPaymentUnavailable
+	}
+	order, err := s.orderService.GetBuyerOrder(orderNumber, user.ID)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+	now := time.Now()
+	if order.Status != entity.OrderPendingPayment || !now.Before(order.PaymentDueAt) {
+		return entity.Payment{}, ErrOrderNotPayable
+	}
+	if !payment.SupportsChannel(s.provider, paymentDTO.Method, paymentDTO.Channel) {
+		return entity.Payment{}, payment.ErrUnsupportedChannel
+	}
+
+	existing, err := s.paymentRepo.FindPending(order.ID, paymentDTO.Method, paymentDTO.Channel, now)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.Payment{}, err
+	}
+
+	charge, err := s.provider.CreateCharge(payment.ChargeRequest{
+		Reference:     order.Number,
+		Amount:        order.Total,
+		Method:        paymentDTO.Method,
+		Channel:       paymentDTO.Channel,
+		CustomerName:  user.Name,
+		CustomerEmail: user.Email,
+		ExpiresAt:     order.PaymentDueAt,
+	})
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	return s.paymentRepo.Create(entity.Payment{
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		UserID:      user.ID,
+		Provider:    s.provider.Name(),
+		ProviderRef: charge.ProviderRef,
+		Method:      charge.Method,
+		Channel:     charge.Channel,
+		Amount:      charge.Amount,
+		Status:      charge.Status,
+		VANumber:    charge.VANumber,
+		QRString:    charge.QRString,
+		CheckoutURL: charge.CheckoutURL,
+		ExpiresAt:   charge.ExpiresAt,
+	})
+}
+
+func (s *paymentService) GetOrderPayments(user entity.User, orderNumber string) ([]entity.Payment, error) {
+	order, err := s.orderService.GetBuyerOrder(orderNumber, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.paymentRepo.GetByOrder(order.ID)
+}
+
+// HandleWebhook memverifikasi dan mencatat webhook, mengubah status tagihan,
+// lalu menandai order dibayar. Webhook yang dikirim ulang dengan EventID sama
+// tidak diproses lagi. Pembayaran dengan nominal berbeda, atau untuk order
+// yang sudah batal, dicatat apa adanya agar bisa ditindaklanjuti admin.
+// Status order dicocokkan di dalam transaksi webhook; jika order gagal
+// ditandai dibayar, tagihan tetap bertanda OrderSyncPending dan diulang job
+// SyncPaidOrders.
+func (s *paymentService) HandleWebhook(header http.Header, body []byte) (dto.WebhookResult, error) {
+	if s.provider == nil {
+		return dto.WebhookResult{}, ErrPaymentUnavailable
+	}
+	n, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		return dto.WebhookResult{}, err
+	}
+
+	if _, err := s.paymentRepo.FindByProviderRef(s.provider.Name(), n.ProviderRef); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("❌ Webhook %s untuk tagihan %s yang tidak dikenal", n.EventID, n.ProviderRef)
+			return dto.WebhookResult{}, ErrPaymentNotFound
+		}
+		return dto.WebhookResult{}, err
+	}
+
+	webhook := entity.PaymentWebhook{
+		Provider:    s.provider.Name(),
+		EventID:     n.EventID,
+		ProviderRef: n.ProviderRef,
+		Status:      n.Status,
+		Amount:      n.Amount,
+		Payload:     string(body),
+		CreatedAt:   time.Now(),
+	}
+	p, webhook, err := s.paymentRepo.ApplyWebhook(webhook, func(p entity.Payment, order entity.Order) (string, string) {
+		return reconcileWebhook(p, order, n)
+	})
+	if err != nil {
+		return dto.WebhookResult{}, err
+	}
+
+	result := dto.WebhookResult{
+		EventID:       webhook.EventID,
+		Result:        webhook.Result,
+		OrderNumber:   p.OrderNumber,
+		PaymentStatus: p.Status,
+	}
+	switch webhook.Result {
+	case entity.WebhookApplied:
+		if p.OrderSyncPending {
+			s.syncPaidOrder(p)
+		}
+	case entity.WebhookAmountMismatch:
+		log.Printf("❌ Nominal webhook %s (%.0f) tidak sama dengan tagihan %s (%.0f)", n.EventID, n.Amount, p.ProviderRef, p.Amount)
+	case entity.WebhookOrderNotPayable:
+		log.Printf("❌ Tagihan %s lunas untuk order %s yang tidak menunggu pembayaran, perlu pengembalian dana", p.ProviderRef, p.OrderNumber)
+	}
+	return result, nil
+}
+
+// reconcileWebhook menentukan status baru tagihan dan hasil pencocokan.
+// Tagihan yang sudah kedaluwarsa di sisi kita tetap bisa lunas jika
+// gateway masih menerima pembayarannya.
+func reconcileWebhook(p entity.Payment, order entity.Order, n payment.Notification) (string, string) {
+	if n.Reference != "" && n.Reference != p.OrderNumber {
+		return "", entity.WebhookIgnored
+	}
+
+	switch n.Status {
+	case payment.StatusPaid:
+		if p.Status == payment.StatusPaid {
+			return "", entity.WebhookIgnored
+		}
+		if math.Round(n.Amount) != math.Round(p.Amount) {
+			return "", entity.WebhookAmountMismatch
+		}
+		if order.Status != entity.OrderPendingPayment {
+			return payment.StatusPaid, entity.WebhookOrderNotPayable
+		}
+		return payment.StatusPaid, entity.WebhookApplied
+	case payment.StatusExpired, payment.StatusFailed:
+		if p.Status != payment.StatusPending {
+			return "", entity.WebhookIgnored
+		}
+		return n.Status, entity.WebhookApplied
+	}
+	return "", entity.WebhookIgnored
+}
+
+// Simulate membuat webhook bertanda tangan untuk sebuah tagihan lalu
+// memprosesnya lewat jalur yang sama dengan webhook gateway
+func (s *paymentService) Simulate(paymentID uint64, simulateDTO dto.SimulatePaymentDTO) (dto.WebhookResult, error) {
+	simulator, ok := s.provider.(payment.Simulator)
+	if !ok {
+		return dto.WebhookResult{}, ErrSimulatorDisabled
+	}
+
+	p, err := s.paymentRepo.FindByID(paymentID)
+	if err != nil {
+		return dto.WebhookResult{}, ErrPaymentNotFound
+	}
+
+	amount := p.Amount
+	if simulateDTO.Amount != nil {
+		amount = *simulateDTO.Amount
+	}
+	header, body, err := simulator.SimulateWebhook(payment.Notification{
+		ProviderRef: p.ProviderRef,
+		Reference:   p.OrderNumber,
+		Status:      simulateDTO.Status,
+		Amount:      amount,
+	})
+	if err != nil {
+		return dto.WebhookResult{}, err
+	}
+	return s.HandleWebhook(header, body)
+}
+
+// syncPaidOrder menandai order dari tagihan yang lunas sebagai dibayar lalu
+// menghapus tanda OrderSyncPending. Jika order ternyata sudah tidak bisa
+// dibayar (misalnya dibatalkan admin), tagihan ditandai perlu pengembalian
+// dana. Kegagalan lain dibiarkan agar diulang SyncPaidOrders.
+func (s *paymentService) syncPaidOrder(p entity.Payment) {
+	_, err := s.orderService.MarkPaid(p.OrderNumber, "Pembayaran diterima via "+p.Method+" "+p.Channel)
+	needsRefund := false
+	if errors.Is(err, ErrOrderTransition) {
+		order, getErr := s.orderService.GetOrder(p.OrderNumber)
+		if getErr != nil {
+			log.Printf("❌ Gagal mengambil order %s untuk tagihan %s: %v", p.OrderNumber, p.ProviderRef, getErr)
+			return
+		}
+		// Order yang sudah dibayar berarti percobaan sebelumnya berhasil
+		needsRefund = order.PaidAt == nil
+		if needsRefund {
+			log.Printf("❌ Tagihan %s lunas untuk order %s berstatus %s, perlu pengembalian dana", p.ProviderRef, p.OrderNumber, order.Status)
+		}
+	} else if err != nil {
+		log.Printf("❌ Tagihan %s lunas tetapi order %s gagal ditandai dibayar, akan diulang: %v", p.ProviderRef, p.OrderNumber, err)
+		return
+	}
+	if err := s.paymentRepo.MarkOrderSynced(p.ID, needsRefund); err != nil {
+		log.Printf("❌ Gagal menandai tagihan %s selesai disinkronkan: %v", p.ProviderRef, err)
+	}
+}
+
+// SyncPaidOrders mengulang penandaan order dibayar untuk tagihan lunas yang
+// sebelumnya gagal
+func (s *paymentService) SyncPaidOrders() error {
+	payments, err := s.paymentRepo.FindOrderSyncPending(time.Now().Add(-paymentOrderSyncRetry), orderJobBatch)
+	if err != nil {
+		return err
+	}
+	for _, p := range payments {
+		s.syncPaidOrder(p)
+	}
+	return nil
+}
+
+// ExpirePending menutup tagihan yang melewati batas bayar
+func (s *paymentService) ExpirePending() error {
+	count, err := s.paymentRepo.ExpirePending(time.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("🗑️ %d tagihan kedaluwarsa", count)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/payment"
+	"batik/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakePaymentRepository menyimpan tagihan dan webhook di memori dengan aturan
+// yang sama seperti paymentRepository.ApplyWebhook
+type fakePaymentRepository struct {
+	repository.PaymentRepository
+	payments map[string]*entity.Payment
+	webhooks []entity.PaymentWebhook
+	orders   *fakeOrderService
+}
+
+func (r *fakePaymentRepository) FindByID(id uint64) (entity.Payment, error) {
+	for _, p := range r.payments {
+		if p.ID == id {
+			return *p, nil
+		}
+	}
+	return entity.Payment{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakePaymentRepository) FindByProviderRef(provider, ref string) (entity.Payment, error) {
+	p, ok := r.payments[ref]
+	if !ok || p.Provider != provider {
+		return entity.Payment{}, gorm.ErrRecordNotFound
+	}
+	return *p, nil
+}
+
+func (r *fakePaymentRepository) ApplyWebhook(webhook entity.PaymentWebhook, apply repository.WebhookApply) (entity.Payment, entity.PaymentWebhook, error) {
+	p, ok := r.payments[webhook.ProviderRef]
+	if !ok {
+		return entity.Payment{}, webhook, gorm.ErrRecordNotFound
+	}
+	for _, existing := range r.webhooks {
+		if existing.Provider == webhook.Provider && existing.EventID == webhook.EventID {
+			existing.Result = entity.WebhookDuplicate
+			return *p, existing, nil
+		}
+	}
+
+	status, result := apply(*p, r.orders.order)
+	if status != "" {
+		p.Status = status
+		if status == payment.StatusPaid {
+			p.OrderSyncPending = result == entity.WebhookApplied
+			p.NeedsRefund = result == entity.WebhookOrderNotPayable
+		}
+	}
+	webhook.PaymentID = &p.ID
+	webhook.Result = result
+	r.webhooks = append(r.webhooks, webhook)
+	return *p, webhook, nil
+}
+
+func (r *fakePaymentRepository) FindOrderSyncPending(before time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	for _, p := range r.payments {
+		if p.OrderSyncPending {
+			payments = append(payments, *p)
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepository) MarkOrderSynced(id uint64, needsRefund bool) error {
+	for _, p := range r.payments {
+		if p.ID == id {
+			p.OrderSyncPending = false
+			p.NeedsRefund = needsRefund
+		}
+	}
+	return nil
+}
+
+// fakeOrderService hanya menyediakan GetOrder dan MarkPaid yang dipakai
+// paymentService saat memproses webhook
+type fakeOrderService struct {
+	OrderService
+	order    entity.Order
+	paidCall int
+	paidErr  error
+}
+
+func (s *fakeOrderService) GetOrder(number string) (entity.Order, error) {
+	if number != s.order.Number {
+		return entity.Order{}, ErrOrderNotFound
+	}
+	return s.order, nil
+}
+
+func (s *fakeOrderService) MarkPaid(number, note string) (entity.Order, error) {
+	s.paidCall++
+	if s.paidErr != nil {
+		return entity.Order{}, s.paidErr
+	}
+	if s.order.Status != entity.OrderPendingPayment {
+		return entity.Order{}, ErrOrderTransition
+	}
+	now := time.Now()
+	s.order.Status = entity.OrderPaid
+	s.order.PaidAt = &now
+	return s.order, nil
+}
+
+func newWebhookTestService(orderStatus string) (*paymentService, *fakePaymentRepository, *fakeOrderService) {
+	repo := &fakePaymentRepository{payments: map[string]*entity.Payment{
+		"mock_1": {
+			ID:          1,
+			OrderNumber: "ORD-1",
+			Provider:    payment.ProviderMock,
+			ProviderRef: "mock_1",
+			Method:      payment.MethodVirtualAccount,
+			Channel:     "bca",
+			Amount:      150000,
+			Status:      payment.StatusPending,
+		},
+	}}
+	orders := &fakeOrderService{order: entity.Order{Number: "ORD-1", Status: orderStatus}}
+	repo.orders = orders
+	svc := &paymentService{paymentRepo: repo, orderService: orders, provider: payment.NewMockProvider("secret-uji")}
+	return svc, repo, orders
+}
+
+func TestHandleWebhookDuplicateEventProcessedOnce(t *testing.T) {
+	svc, repo, orders := newWebhookTestService(entity.OrderPendingPayment)
+	header, body, err := svc.provider.(payment.Simulator).SimulateWebhook(payment.Notification{
+		EventID:     "evt_1",
+		ProviderRef: "mock_1",
+		Reference:   "ORD-1",
+		Status:      payment.StatusPaid,
+		Amount:      150000,
+	})
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+
+	first, err := svc.HandleWebhook(header, body)
+	if err != nil {
+		t.Fatalf("webhook pertama: %v", err)
+	}
+	if first.Result != entity.WebhookApplied || first.PaymentStatus != payment.StatusPaid {
+		t.Errorf("webhook pertama = %+v", first)
+	}
+
+	second, err := svc.HandleWebhook(header, body)
+	if err != nil {
+		t.Fatalf("webhook kedua: %v", err)
+	}
+	if second.Result != entity.WebhookDuplicate {
+		t.Errorf("webhook kedua: Result = %s, ingin %s", second.Result, entity.WebhookDuplicate)
+	}
+	if orders.paidCall != 1 {
+		t.Errorf("MarkPaid dipanggil %d kali, ingin 1", orders.paidCall)
+	}
+	if len(repo.webhooks) != 1 {
+		t.Errorf("webhook tercatat %d, ingin 1", len(repo.webhooks))
+	}
+}
+
+func TestHandleWebhookRetriesFailedMarkPaid(t *testing.T) {
+	svc, repo, orders := newWebhookTestService(entity.OrderPendingPayment)
+	orders.paidErr = errors.New("koneksi terputus")
+	header, body, _ := svc.provider.(payment.Simulator).SimulateWebhook(payment.Notification{
+		EventID:     "evt_1",
+		ProviderRef: "mock_1",
+		Status:      payment.StatusPaid,
+		Amount:      150000,
+	})
+
+	if _, err := svc.HandleWebhook(header, body); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if !repo.payments["mock_1"].OrderSyncPending {
+		t.Fatal("tagihan lunas yang ordernya gagal ditandai harus menunggu diulang")
+	}
+
+	orders.paidErr = nil
+	if err := svc.SyncPaidOrders(); err != nil {
+		t.Fatalf("SyncPaidOrders: %v", err)
+	}
+	if orders.order.Status != entity.OrderPaid || repo.payments["mock_1"].OrderSyncPending {
+		t.Errorf("setelah diulang: order=%s tertunda=%v", orders.order.Status, repo.payments["mock_1"].OrderSyncPending)
+	}
+
+	// Order yang sudah dibayar tidak diulang lagi
+	if err := svc.SyncPaidOrders(); err != nil || orders.paidCall != 2 {
+		t.Errorf("MarkPaid dipanggil %d kali, ingin 2 (err %v)", orders.paidCall, err)
+	}
+}
+
+func TestSyncPaidOrdersFlagsRefundForClosedOrder(t *testing.T) {
+	svc, repo, orders := newWebhookTestService(entity.OrderPendingPayment)
+	p := repo.payments["mock_1"]
+	p.Status = payment.StatusPaid
+	p.OrderSyncPending = true
+	// Order dibatalkan di antara transaksi webhook dan MarkPaid
+	orders.order.Status = entity.OrderCancelled
+
+	if err := svc.SyncPaidOrders(); err != nil {
+		t.Fatalf("SyncPaidOrders: %v", err)
+	}
+	if p.OrderSyncPending || !p.NeedsRefund {
+		t.Errorf("tagihan harus ditandai perlu pengembalian dana: tertunda=%v refund=%v", p.OrderSyncPending, p.NeedsRefund)
+	}
+}
+
+func TestHandleWebhookRejectsBadSignature(t *testing.T) {
+	svc, repo, orders := newWebhookTestService(entity.OrderPendingPayment)
+	_, body, err := payment.NewMockProvider("secret-lain").(payment.Simulator).SimulateWebhook(payment.Notification{
+		EventID:     "evt_1",
+		ProviderRef: "mock_1",
+		Status:      payment.StatusPaid,
+		Amount:      150000,
+	})
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+	forged, _, _ := payment.NewMockProvider("secret-lain").(payment.Simulator).SimulateWebhook(payment.Notification{EventID: "evt_1"})
+
+	if _, err := svc.HandleWebhook(forged, body); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Fatalf("err = %v, ingin ErrInvalidSignature", err)
+	}
+	if len(repo.webhooks) != 0 || orders.paidCall != 0 {
+		t.Errorf("webhook palsu tidak boleh diproses: webhooks=%d paidCall=%d", len(repo.webhooks), orders.paidCall)
+	}
+}
+
+func TestHandleWebhookUnknownPayment(t *testing.T) {
+	svc, _, _ := newWebhookTestService(entity.OrderPendingPayment)
+	header, body, _ := svc.provider.(payment.Simulator).SimulateWebhook(payment.Notification{
+		ProviderRef: "mock_tidak_ada",
+		Status:      payment.StatusPaid,
+		Amount:      150000,
+	})
+	if _, err := svc.HandleWebhook(header, body); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("err = %v, ingin ErrPaymentNotFound", err)
+	}
+}
+
+func TestReconcileWebhook(t *testing.T) {
+	pending := entity.Payment{OrderNumber: "ORD-1", Amount: 150000, Status: payment.StatusPending}
+	paid := pending
+	paid.Status = payment.StatusPaid
+	open := entity.Order{Number: "ORD-1", Status: entity.OrderPendingPayment}
+	cancelled := entity.Order{Number: "ORD-1", Status: entity.OrderCancelled}
+
+	cases := []struct {
+		name       string
+		p          entity.Payment
+		order      entity.Order
+		n          payment.Notification
+		wantStatus string
+		wantResult string
+	}{
+		{"lunas", pending, open, payment.Notification{Status: payment.StatusPaid, Amount: 150000}, payment.StatusPaid, entity.WebhookApplied},
+		{"pembulatan nominal", pending, open, payment.Notification{Status: payment.StatusPaid, Amount: 150000.2}, payment.StatusPaid, entity.WebhookApplied},
+		{"nominal berbeda", pending, open, payment.Notification{Status: payment.StatusPaid, Amount: 100000}, "", entity.WebhookAmountMismatch},
+		{"order sudah batal", pending, cancelled, payment.Notification{Status: payment.StatusPaid, Amount: 150000}, payment.StatusPaid, entity.WebhookOrderNotPayable},
+		{"sudah lunas", paid, open, payment.Notification{Status: payment.StatusPaid, Amount: 150000}, "", entity.WebhookIgnored},
+		{"referensi lain", pending, open, payment.Notification{Reference: "ORD-2", Status: payment.StatusPaid, Amount: 150000}, "", entity.WebhookIgnored},
+		{"kedaluwarsa", pending, open, payment.Notification{Status: payment.StatusExpired}, payment.StatusExpired, entity.WebhookApplied},
+		{"gagal setelah lunas", paid, open, payment.Notification{Status: payment.StatusFailed}, "", entity.WebhookIgnored},
+	}
+	for _, tc := range cases {
+		status, result := reconcileWebhook(tc.p, tc.order, tc.n)
+		if status != tc.wantStatus || result != tc.wantResult {
+			t.Errorf("%s: (%q, %q), ingin (%q, %q)", tc.name, status, result, tc.wantStatus, tc.wantResult)
+		}
+	}
+}
+
+func TestSimulateRequiresSimulator(t *testing.T) {
+	svc, _, _ := newWebhookTestService(entity.OrderPendingPayment)
+	svc.provider = struct{ payment.PaymentProvider }{svc.provider}
+
+	if _, err := svc.Simulate(1, dto.SimulatePaymentDTO{Status: payment.StatusPaid}); !errors.Is(err, ErrSimulatorDisabled) {
+		t.Errorf("err = %v, ingin ErrSimulatorDisabled", err)
+	}
+}
+
+func TestPaymentUnavailableWithoutProvider(t *testing.T) {
+	svc, _, _ := newWebhookTestService(entity.OrderPendingPayment)
+	svc.provider = nil
+
+	if _, err := svc.GetMethods(); !errors.Is(err, ErrPaymentUnavailable) {
+		t.Errorf("GetMethods: err = %v, ingin ErrPaymentUnavailable", err)
+	}
+	if _, err := svc.CreatePayment(entity.User{ID: 1}, "ORD-1", dto.CreatePaymentDTO{}); !errors.Is(err, ErrPaymentUnavailable) {
+		t.Errorf("CreatePayment: err = %v, ingin ErrPaymentUnavailable", err)
+	}
+	if _, err := svc.HandleWebhook(nil, []byte("{}")); !errors.Is(err, ErrPaymentUnavailable) {
+		t.Errorf("HandleWebhook: err = %v, ingin ErrPaymentUnavailable", err)
+	}
+}
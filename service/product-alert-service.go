@@ -0,0 +1,314 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Periode riwayat harga
+const (
+	defaultPriceHistoryDays = 90
+	maxPriceHistoryDays     = 365
+)
+
+var (
+	ErrAlertNotFound  = errors.New("alert tidak ditemukan")
+	ErrAlertForbidden = errors.New("anda tidak memiliki akses ke alert ini")
+)
+
+// ProductAlertService mencatat riwayat harga produk dan mengirim notifikasi
+// ke buyer yang berlangganan harga turun atau stok tersedia kembali
+type ProductAlertService interface {
+	RecordPriceChange(product entity.Product, previousPrice *float64)
+	NotifyPriceDrops(productIDs []int)
+	GetPriceHistory(slug string, days int) (dto.PriceHistoryResponse, error)
+	Subscribe(user entity.User, slug string, alertDTO dto.CreateProductAlertDTO) (entity.ProductAlert, error)
+	GetMyAlerts(userID uint64) ([]entity.ProductAlert, error)
+	DeleteAlert(id uint64, userID uint64) error
+	NotifyBackInStock(productID int)
+}
+
+type productAlertService struct {
+	alertRepo           repository.ProductAlertRepository
+	productRepo         repository.ProductRepository
+	inventoryRepo       repository.InventoryRepository
+	notificationService NotificationService
+}
+
+func NewProductAlertService(alertRepo repository.ProductAlertRepository, productRepo repository.ProductRepository, inventoryRepo repository.InventoryRepository, notificationService NotificationService) ProductAlertService {
+	return &productAlertService{
+		alertRepo:           alertRepo,
+		productRepo:         productRepo,
+		inventoryRepo:       inventoryRepo,
+		notificationService: notificationService,
+	}
+}
+
+// RecordPriceChange mencatat harga baru produk. previousPrice kosong untuk
+// produk baru. Setelah harga berubah, alert harga turun yang targetnya
+// terpenuhi dikirim. Kegagalan hanya dicatat ke log agar update produk tetap
+// berhasil.
+func (s *productAlertService) RecordPriceChange(product entity.Product, previousPrice *float64) {
+	err := s.alertRepo.RecordPrice(entity.ProductPriceHistory{
+		ProductID:     product.ID,
+		Price:         product.Harga,
+		PreviousPrice: previousPrice,
+		ChangedAt:     time.Now(),
+	})
+	if err != nil {
+		log.Printf("❌ Gagal mencatat riwayat harga produk %d: %v", product.ID, err)
+	}
+
+	if previousPrice == nil {
+		return
+	}
+	s.NotifyPriceDrops([]int{product.ID})
+}
+
+// NotifyPriceDrops mengirim alert harga turun yang terpenuhi oleh harga
+// efektif (setelah promosi) produk published. Dipanggil setelah harga
+// berubah, setelah harga promosi dihitung ulang, dan saat produk
+// dipublikasikan. productIDs kosong berarti semua produk.
+func (s *productAlertService) NotifyPriceDrops(productIDs []int) {
+	alerts, err := s.alertRepo.GetTriggeredPriceDrops(productIDs)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil alert harga produk %v: %v", productIDs, err)
+		return
+	}
+
+	for _, alert := range alerts {
+		message := fmt.Sprintf("Harga %s turun menjadi %s.", alert.ProductName, formatRupiah(alert.CurrentPrice))
+		if alert.ReferencePrice != nil && *alert.ReferencePrice > alert.CurrentPrice {
+			message = fmt.Sprintf("Harga %s turun dari %s menjadi %s.", alert.ProductName, formatRupiah(*alert.ReferencePrice), formatRupiah(alert.CurrentPrice))
+		}
+		s.sendAlerts([]entity.ProductAlert{alert}, entity.NotificationPriceDrop,
+			fmt.Sprintf("Harga %s turun", alert.ProductName), message,
+			"/product/"+alert.ProductSlug)
+	}
+}
+
+// NotifyBackInStock mengirim alert stok tersedia kembali jika stok produk
+// sudah ada. Dipanggil setelah perubahan stok.
+func (s *productAlertService) NotifyBackInStock(productID int) {
+	alerts, err := s.alertRepo.GetPendingBackInStock(productID)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil alert stok produk %d: %v", productID, err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	inStock, err := s.inStock(productID)
+	if err != nil {
+		log.Printf("❌ Gagal memeriksa stok produk %d: %v", productID, err)
+		return
+	}
+	if !inStock {
+		return
+	}
+
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil || product.Status != entity.ProductStatusPublished {
+		return
+	}
+	s.sendAlerts(alerts, entity.NotificationBackInStock,
+		fmt.Sprintf("%s tersedia kembali", product.Name),
+		fmt.Sprintf("Stok %s sudah tersedia lagi.", product.Name),
+		"/product/"+product.Slug)
+}
+
+// sendAlerts mengklaim setiap alert sebelum mengirim notifikasi, supaya
+// perubahan yang terjadi bersamaan tidak mengirim notifikasi ganda
+func (s *productAlertService) sendAlerts(alerts []entity.ProductAlert, notificationType, title, message, link string) {
+	now := time.Now()
+	for _, alert := range alerts {
+		claimed, err := s.alertRepo.Claim(alert.ID, now)
+		if err != nil {
+			log.Printf("❌ Gagal menandai alert %d: %v", alert.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.notificationService.Notify(int(alert.UserID), notificationType, title, message, link)
+	}
+}
+
+// GetPriceHistory mengembalikan riwayat harga produk publik dalam beberapa
+// hari terakhir, termasuk harga yang berlaku di awal periode
+func (s *productAlertService) GetPriceHistory(slug string, days int) (dto.PriceHistoryResponse, error) {
+	if days < 1 || days > maxPriceHistoryDays {
+		days = defaultPriceHistoryDays
+	}
+
+	product, err := publishedProduct(s.productRepo, slug)
+	if err != nil {
+		return dto.PriceHistoryResponse{}, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	history, err := s.alertRepo.GetPriceHistory(product.ID, since)
+	if err != nil {
+		return dto.PriceHistoryResponse{}, err
+	}
+
+	response := dto.PriceHistoryResponse{
+		Days:         days,
+		CurrentPrice: product.Harga,
+		LowestPrice:  product.Harga,
+		HighestPrice: product.Harga,
+		History:      []dto.PricePoint{},
+	}
+
+	before, err := s.alertRepo.GetPriceBefore(product.ID, since)
+	if err == nil {
+		response.History = append(response.History, dto.PricePoint{Price: before.Price, ChangedAt: since})
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.PriceHistoryResponse{}, err
+	}
+	for _, h := range history {
+		response.History = append(response.History, dto.PricePoint{Price: h.Price, ChangedAt: h.ChangedAt})
+	}
+
+	for _, point := range response.History {
+		if point.Price < response.LowestPrice {
+			response.LowestPrice = point.Price
+		}
+		if point.Price > response.HighestPrice {
+			response.HighestPrice = point.Price
+		}
+	}
+	return response, nil
+}
+
+// Subscribe membuat alert untuk produk publik. Alert stok hanya bisa dibuat
+// saat produk habis, dan target harga harus di bawah harga efektif saat ini.
+func (s *productAlertService) Subscribe(user entity.User, slug string, alertDTO dto.CreateProductAlertDTO) (entity.ProductAlert, error) {
+	product, err := publishedProduct(s.productRepo, slug)
+	if err != nil {
+		return entity.ProductAlert{}, err
+	}
+
+	alert := entity.ProductAlert{
+		UserID:    user.ID,
+		ProductID: product.ID,
+		Type:      alertDTO.Type,
+	}
+
+	switch alertDTO.Type {
+	case entity.ProductAlertPriceDrop:
+		price, err := s.effectivePrice(product)
+		if err != nil {
+			return entity.ProductAlert{}, err
+		}
+		if alertDTO.TargetPrice != nil && *alertDTO.TargetPrice >= price {
+			return entity.ProductAlert{}, fmt.Errorf("target harga harus di bawah harga saat ini (%s)", formatRupiah(price))
+		}
+		alert.TargetPrice = alertDTO.TargetPrice
+		alert.ReferencePrice = &price
+	case entity.ProductAlertBackInStock:
+		inStock, err := s.inStock(product.ID)
+		if err != nil {
+			return entity.ProductAlert{}, err
+		}
+		if inStock {
+			return entity.ProductAlert{}, errors.New("produk masih tersedia")
+		}
+	}
+
+	saved, err := s.alertRepo.Upsert(alert)
+	if err != nil {
+		return entity.ProductAlert{}, fmt.Errorf("gagal menyimpan alert: %v", err)
+	}
+	return saved, nil
+}
+
+func (s *productAlertService) GetMyAlerts(userID uint64) ([]entity.ProductAlert, error) {
+	alerts, err := s.alertRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		alerts = []entity.ProductAlert{}
+	}
+	return alerts, nil
+}
+
+func (s *productAlertService) DeleteAlert(id uint64, userID uint64) error {
+	alert, err := s.alertRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAlertNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if alert.UserID != userID {
+		return ErrAlertForbidden
+	}
+
+	if err := s.alertRepo.Delete(alert.ID); err != nil {
+		return fmt.Errorf("gagal menghapus alert: %v", err)
+	}
+	return nil
+}
+
+// effectivePrice mengembalikan harga produk setelah promosi yang sedang berjalan
+func (s *productAlertService) effectivePrice(product entity.Product) (float64, error) {
+	cards, err := s.productRepo.GetPublicCardsByIDs([]int{product.ID})
+	if err != nil {
+		return 0, err
+	}
+	if len(cards) == 0 {
+		return product.Harga, nil
+	}
+	return cards[0].EffectivePrice, nil
+}
+
+// inStock mengikuti aturan OutOfStock di katalog: produk tanpa data stok
+// dianggap tersedia
+func (s *productAlertService) inStock(productID int) (bool, error) {
+	inventories, err := s.inventoryRepo.FindByProductID(productID)
+	if err != nil {
+		return false, err
+	}
+	if len(inventories) == 0 {
+		return true, nil
+	}
+
+	available := 0
+	for _, inv := range inventories {
+		available += inv.Available()
+	}
+	return available > 0, nil
+}
+
+// formatRupiah menulis harga dengan pemisah ribuan, misalnya Rp150.000
+func formatRupiah(price float64) string {
+	digits := strconv.FormatInt(int64(math.Round(price)), 10)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	if negative {
+		return "-Rp" + b.String()
+	}
+	return "Rp" + b.String()
+}
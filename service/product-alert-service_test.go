@@ -0,0 +1,107 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeProductAlertRepository struct {
+	repository.ProductAlertRepository
+	triggered []entity.ProductAlert
+	claimed   map[uint64]bool
+	saved     entity.ProductAlert
+}
+
+func (r *fakeProductAlertRepository) GetTriggeredPriceDrops(productIDs []int) ([]entity.ProductAlert, error) {
+	return r.triggered, nil
+}
+
+func (r *fakeProductAlertRepository) Claim(id uint64, notifiedAt time.Time) (bool, error) {
+	if r.claimed[id] {
+		return false, nil
+	}
+	r.claimed[id] = true
+	return true, nil
+}
+
+func (r *fakeProductAlertRepository) Upsert(alert entity.ProductAlert) (entity.ProductAlert, error) {
+	r.saved = alert
+	return alert, nil
+}
+
+type fakeAlertProductRepository struct {
+	repository.ProductRepository
+	product        entity.Product
+	effectivePrice float64
+}
+
+func (r *fakeAlertProductRepository) FindBySlug(slug string) (entity.Product, error) {
+	return r.product, nil
+}
+
+func (r *fakeAlertProductRepository) GetPublicCardsByIDs(ids []int) ([]entity.ProductCard, error) {
+	return []entity.ProductCard{{EffectivePrice: r.effectivePrice}}, nil
+}
+
+type fakeNotificationService struct {
+	NotificationService
+	messages []string
+}
+
+func (s *fakeNotificationService) Notify(userID int, notificationType, title, message, link string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestNotifyPriceDropsUsesEffectivePrice(t *testing.T) {
+	reference := 200000.0
+	alertRepo := &fakeProductAlertRepository{
+		claimed: map[uint64]bool{},
+		triggered: []entity.ProductAlert{
+			{ID: 1, UserID: 7, ProductName: "Kemeja Parang", CurrentPrice: 150000, ReferencePrice: &reference},
+			{ID: 2, UserID: 8, ProductName: "Kain Kawung", CurrentPrice: 90000},
+		},
+	}
+	notifications := &fakeNotificationService{}
+	s := &productAlertService{alertRepo: alertRepo, notificationService: notifications}
+
+	s.NotifyPriceDrops(nil)
+	s.NotifyPriceDrops(nil)
+
+	if len(notifications.messages) != 2 {
+		t.Fatalf("notifikasi = %d, ingin 2 (alert yang sudah diklaim tidak dikirim lagi)", len(notifications.messages))
+	}
+	if !strings.Contains(notifications.messages[0], "dari Rp200.000 menjadi Rp150.000") {
+		t.Errorf("pesan harga turun = %q", notifications.messages[0])
+	}
+	if !strings.Contains(notifications.messages[1], "menjadi Rp90.000") {
+		t.Errorf("pesan tanpa harga acuan = %q", notifications.messages[1])
+	}
+}
+
+func TestSubscribePriceDropComparesEffectivePrice(t *testing.T) {
+	alertRepo := &fakeProductAlertRepository{claimed: map[uint64]bool{}}
+	productRepo := &fakeAlertProductRepository{
+		product:        entity.Product{ID: 3, Harga: 200000, Status: entity.ProductStatusPublished},
+		effectivePrice: 160000,
+	}
+	s := &productAlertService{alertRepo: alertRepo, productRepo: productRepo}
+	user := entity.User{ID: 7}
+
+	target := 170000.0
+	if _, err := s.Subscribe(user, "kemeja", dto.CreateProductAlertDTO{Type: entity.ProductAlertPriceDrop, TargetPrice: &target}); err == nil {
+		t.Error("target di atas harga promosi harus ditolak")
+	}
+
+	target = 150000
+	if _, err := s.Subscribe(user, "kemeja", dto.CreateProductAlertDTO{Type: entity.ProductAlertPriceDrop, TargetPrice: &target}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if alertRepo.saved.ReferencePrice == nil || *alertRepo.saved.ReferencePrice != 160000 {
+		t.Errorf("harga acuan = %v, ingin harga efektif 160000", alertRepo.saved.ReferencePrice)
+	}
+}
@@ -1,25 +1,311 @@
 package service
 
 import (
+	"batik/dto"
 	"batik/entity"
 	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
+// categoryIconMaxSize adalah ukuran maksimal file ikon kategori
+const categoryIconMaxSize = 2 * 1024 * 1024
+
 type ProductCategoryService interface {
-	GetProductCategory() ([]entity.ProductCategory, error)
+	GetProductCategory(tree bool) ([]entity.ProductCategory, error)
+	CreateCategory(c *gin.Context, categoryDTO dto.CreateCategoryDTO, icon *multipart.FileHeader) (entity.ProductCategory, error)
+	UpdateCategory(c *gin.Context, id int, categoryDTO dto.UpdateCategoryDTO, icon *multipart.FileHeader) (entity.ProductCategory, error)
+	ReorderCategories(categoryIDs []int) error
+	DeleteCategory(id, reassignTo int) (int, error)
+	UpdateCategorySchema(id int, schemaDTO dto.UpdateCategorySchemaDTO) ([]entity.AttributeField, error)
 }
 
 type productCategoryService struct {
-	pc repository.ProductCategoryRepository
+	pc               repository.ProductCategoryRepository
+	attributeService AttributeService
+	searchIndex      SearchIndexService
 }
 
-func NewProductCategoryService(pcRepo repository.ProductCategoryRepository) ProductCategoryService {
+func NewProductCategoryService(pcRepo repository.ProductCategoryRepository, attributeService AttributeService, searchIndex SearchIndexService) ProductCategoryService {
 	return &productCategoryService {
-		pc: pcRepo,
+		pc:               pcRepo,
+		attributeService: attributeService,
+		searchIndex:      searchIndex,
+	}
+}
+
+// GetProductCategory mengembalikan kategori beserta jumlah produk publiknya
+// (termasuk subkategori). tree=true mengembalikan kategori induk dengan
+// subkategori di Children; selain itu daftar datar dengan parent_id.
+func (serv *productCategoryService) GetProductCategory(tree bool) ([]entity.ProductCategory, error) {
+	categories, err := serv.pc.GetProductCategory()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := serv.pc.CountPublicProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range categories {
+		for _, id := range entity.CategoryDescendants(categories, []int{categories[i].ID}) {
+			categories[i].ProductCount += counts[id]
+		}
+	}
+
+	if !tree {
+		return categories, nil
 	}
+	return buildCategoryTree(categories), nil
 }
 
-func (serv *productCategoryService) GetProductCategory() ([]entity.ProductCategory, error) {
-	res, err := serv.pc.GetProductCategory()
-	return res, err
-}
\ No newline at end of file
+func (serv *productCategoryService) CreateCategory(c *gin.Context, categoryDTO dto.CreateCategoryDTO, icon *multipart.FileHeader) (entity.ProductCategory, error) {
+	var parentID *int
+	if categoryDTO.ParentID > 0 {
+		if _, err := serv.pc.FindByID(categoryDTO.ParentID); err != nil {
+			return entity.ProductCategory{}, fmt.Errorf("kategori induk tidak ditemukan: %v", err)
+		}
+		parentID = &categoryDTO.ParentID
+	}
+
+	now := time.Now()
+	category := entity.ProductCategory{
+		CategoryName: categoryDTO.CategoryName,
+		Slug:         utils.EnsureUniqueSlug(utils.GenerateSlug(categoryDTO.CategoryName, "category"), serv.pc.SlugExists),
+		ParentID:     parentID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if categoryDTO.SortOrder != nil {
+		category.SortOrder = *categoryDTO.SortOrder
+	} else {
+		sortOrder, err := serv.pc.NextSortOrder(parentID)
+		if err != nil {
+			return entity.ProductCategory{}, err
+		}
+		category.SortOrder = sortOrder
+	}
+
+	if icon != nil {
+		iconPath, err := uploadCategoryIcon(c, icon)
+		if err != nil {
+			return entity.ProductCategory{}, err
+		}
+		category.Icon = iconPath
+	}
+
+	created, err := serv.pc.Create(category)
+	if err != nil {
+		utils.DeleteFileIfExists(category.Icon)
+		return entity.ProductCategory{}, fmt.Errorf("gagal menyimpan kategori: %v", err)
+	}
+	return created, nil
+}
+
+func (serv *productCategoryService) UpdateCategory(c *gin.Context, id int, categoryDTO dto.UpdateCategoryDTO, icon *multipart.FileHeader) (entity.ProductCategory, error) {
+	category, err := serv.pc.FindByID(id)
+	if err != nil {
+		return entity.ProductCategory{}, fmt.Errorf("kategori tidak ditemukan: %v", err)
+	}
+
+	renamed := categoryDTO.CategoryName != "" && categoryDTO.CategoryName != category.CategoryName
+	if renamed {
+		category.CategoryName = categoryDTO.CategoryName
+		checkSlugExists := func(slug string) bool {
+			if slug == category.Slug {
+				return false
+			}
+			return serv.pc.SlugExists(slug)
+		}
+		category.Slug = utils.EnsureUniqueSlug(utils.GenerateSlug(categoryDTO.CategoryName, "category"), checkSlugExists)
+	}
+
+	if categoryDTO.ParentID != nil {
+		if err := serv.setParent(&category, *categoryDTO.ParentID); err != nil {
+			return entity.ProductCategory{}, err
+		}
+	}
+
+	if categoryDTO.SortOrder != nil {
+		category.SortOrder = *categoryDTO.SortOrder
+	}
+
+	oldIcon := category.Icon
+	if icon != nil {
+		iconPath, err := uploadCategoryIcon(c, icon)
+		if err != nil {
+			return entity.ProductCategory{}, err
+		}
+		category.Icon = iconPath
+	}
+
+	category.UpdatedAt = time.Now()
+	updated, err := serv.pc.Update(category)
+	if err != nil {
+		if icon != nil {
+			utils.DeleteFileIfExists(category.Icon)
+		}
+		return entity.ProductCategory{}, fmt.Errorf("gagal mengupdate kategori: %v", err)
+	}
+
+	if icon != nil {
+		utils.DeleteFileIfExists(oldIcon)
+	}
+
+	// Nama kategori ikut di-index pada dokumen produk
+	if renamed {
+		if productIDs, err := serv.pc.GetProductIDs([]int{category.ID}); err == nil {
+			serv.searchIndex.IndexProducts(productIDs)
+		}
+	}
+
+	return updated, nil
+}
+
+// setParent memindahkan kategori ke induk baru. parentID 0 menjadikannya
+// kategori induk. Kategori tidak boleh dipindah ke dirinya sendiri atau ke
+// salah satu subkategorinya.
+func (serv *productCategoryService) setParent(category *entity.ProductCategory, parentID int) error {
+	if parentID == 0 {
+		category.ParentID = nil
+		return nil
+	}
+
+	if _, err := serv.pc.FindByID(parentID); err != nil {
+		return fmt.Errorf("kategori induk tidak ditemukan: %v", err)
+	}
+
+	categories, err := serv.pc.GetProductCategory()
+	if err != nil {
+		return err
+	}
+	for _, id := range entity.CategoryDescendants(categories, []int{category.ID}) {
+		if id == parentID {
+			return errors.New("kategori tidak dapat dipindah ke dirinya sendiri atau subkategorinya")
+		}
+	}
+
+	category.ParentID = &parentID
+	return nil
+}
+
+func (serv *productCategoryService) ReorderCategories(categoryIDs []int) error {
+	seen := make(map[int]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if seen[id] {
+			return fmt.Errorf("kategori %d muncul lebih dari sekali", id)
+		}
+		seen[id] = true
+		if _, err := serv.pc.FindByID(id); err != nil {
+			return fmt.Errorf("kategori %d tidak ditemukan", id)
+		}
+	}
+	return serv.pc.UpdateSortOrder(categoryIDs)
+}
+
+// DeleteCategory menghapus kategori dan memindahkan produknya ke reassignTo.
+// Tanpa reassignTo, produk dipindah ke kategori induk; kategori induk yang
+// masih memiliki produk wajib diberi tujuan. Mengembalikan jumlah produk yang dipindahkan.
+func (serv *productCategoryService) DeleteCategory(id, reassignTo int) (int, error) {
+	category, err := serv.pc.FindByID(id)
+	if err != nil {
+		return 0, fmt.Errorf("kategori tidak ditemukan: %v", err)
+	}
+
+	targetID := reassignTo
+	if targetID == 0 && category.ParentID != nil {
+		targetID = *category.ParentID
+	}
+
+	if targetID != 0 {
+		if targetID == id {
+			return 0, errors.New("kategori tujuan tidak boleh kategori yang dihapus")
+		}
+		if _, err := serv.pc.FindByID(targetID); err != nil {
+			return 0, fmt.Errorf("kategori tujuan tidak ditemukan: %v", err)
+		}
+	}
+
+	productIDs, err := serv.pc.Delete(category, targetID)
+	if errors.Is(err, repository.ErrCategoryHasProducts) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("gagal menghapus kategori: %v", err)
+	}
+
+	utils.DeleteFileIfExists(category.Icon)
+	serv.searchIndex.IndexProducts(productIDs)
+	return len(productIDs), nil
+}
+
+// UpdateCategorySchema mengganti skema atribut kategori. Produk di kategori ini
+// di-index ulang karena nilai atribut yang tidak lagi berlaku ikut dihapus.
+func (serv *productCategoryService) UpdateCategorySchema(id int, schemaDTO dto.UpdateCategorySchemaDTO) ([]entity.AttributeField, error) {
+	if _, err := serv.pc.FindByID(id); err != nil {
+		return nil, fmt.Errorf("kategori tidak ditemukan: %v", err)
+	}
+
+	fields, err := serv.attributeService.UpdateCategorySchema(id, schemaDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	if productIDs, err := serv.pc.GetProductIDs([]int{id}); err == nil {
+		serv.searchIndex.IndexProducts(productIDs)
+	}
+	return fields, nil
+}
+
+func uploadCategoryIcon(c *gin.Context, icon *multipart.FileHeader) (string, error) {
+	if err := utils.FileValidator(icon, categoryIconMaxSize); err != nil {
+		return "", fmt.Errorf("validasi ikon gagal: %v", err)
+	}
+	iconPath, err := utils.UploadFile(c, icon, "uploads/category-icons")
+	if err != nil {
+		return "", fmt.Errorf("gagal upload ikon: %v", err)
+	}
+	return iconPath, nil
+}
+
+// buildCategoryTree menyusun kategori datar menjadi pohon. Kategori dengan
+// parent yang tidak ada diperlakukan sebagai kategori induk.
+func buildCategoryTree(categories []entity.ProductCategory) []entity.ProductCategory {
+	exists := make(map[int]bool, len(categories))
+	children := make(map[int][]entity.ProductCategory)
+	for _, c := range categories {
+		exists[c.ID] = true
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && exists[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	visited := make(map[int]bool)
+	var attach func(c entity.ProductCategory) entity.ProductCategory
+	attach = func(c entity.ProductCategory) entity.ProductCategory {
+		visited[c.ID] = true
+		for _, child := range children[c.ID] {
+			if !visited[child.ID] {
+				c.Children = append(c.Children, attach(child))
+			}
+		}
+		return c
+	}
+
+	roots := []entity.ProductCategory{}
+	for _, c := range categories {
+		if c.ParentID == nil || !exists[*c.ParentID] {
+			roots = append(roots, attach(c))
+		}
+	}
+	return roots
+}
@@ -10,12 +10,13 @@ import (
 	"fmt"
 	"log"
 	"mime/multipart"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ProductService interface {
-	GetAllProductByStore(storeID, page, limit int, search string) ([]entity.Product, *utils.Pagination, error)
+	GetAllProductByStore(storeID, page, limit int, search, status string) ([]entity.Product, *utils.Pagination, error)
 	CreateProduct(c *gin.Context, productDTO dto.CreateProductDTO, files []*multipart.FileHeader) (entity.Product, error)
 	GetProductByID(id int) (entity.Product, error)
 	GetProductBySlug(slug string) (entity.Product, error)
@@ -24,25 +25,41 @@ type ProductService interface {
 	DeleteProduct(slug string) error
 	AddProductImage(c *gin.Context, slug string, file *multipart.FileHeader) (entity.ProductImage, error)
 	DeleteProductImage(slug string, imageID int) error
-	GetAllPublicProduct(page, limit int, search string) ([]dto.PublicProductCard, *utils.Pagination, error)
+	GetAllPublicProduct(page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, error)
+	GetAllPublicProductCursor(cursor *utils.Cursor, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.CursorPagination, error)
+	SearchProducts(page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, repository.ProductFacets, error)
 	GetLatestProduct()([]entity.ProductCard, error)
 	GetDetailProduct(slug string)(entity.ProductCard, error)
-	GetAllPublicProductByCategory(slug string, page, limit int) ([]dto.PublicProductCard, *utils.Pagination, error)
+	GetAllPublicProductByCategory(slug string, page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, error)
+	GetProductAttributes(productID int) (map[string]string, error)
+	ChangeProductStatus(slug string, statusDTO dto.ProductStatusDTO) (entity.Product, error)
+	GetDeletedProductBySlug(slug string) (entity.Product, error)
+	GetProductTrash(storeID, page, limit int) ([]entity.Product, *utils.Pagination, error)
+	RestoreProduct(slug string) (entity.Product, error)
+	PurgeProduct(product entity.Product) error
+	PublishScheduledProducts() (int64, error)
+	RefreshShuffleKeys() error
 }
 
 type productService struct {
 	productRepo      repository.ProductRepository
 	productImageRepo repository.ProductImageRepository
+	attributeService AttributeService
+	searchIndex      SearchIndexService
+	alertService     ProductAlertService
 }
 
-func NewProductService(productRepo repository.ProductRepository, productImageRepo repository.ProductImageRepository) ProductService {
+func NewProductService(productRepo repository.ProductRepository, productImageRepo repository.ProductImageRepository, attributeService AttributeService, searchIndex SearchIndexService, alertService ProductAlertService) ProductService {
 	return &productService{
 		productRepo:      productRepo,
 		productImageRepo: productImageRepo,
+		attributeService: attributeService,
+		searchIndex:      searchIndex,
+		alertService:     alertService,
 	}
 }
 
-func (s *productService) GetAllProductByStore(storeID, page, limit int, search string) ([]entity.Product, *utils.Pagination, error) {
+func (s *productService) GetAllProductByStore(storeID, page, limit int, search, status string) ([]entity.Product, *utils.Pagination, error) {
 	// Validasi pagination parameters
 	if page < 1 {
 		page = 1
@@ -52,7 +69,7 @@ func (s *productService) GetAllProductByStore(storeID, page, limit int, search s
 	}
 	
 	// Get products dengan pagination
-	products, total, err := s.productRepo.GetAllProductByStore(storeID, page, limit, search)
+	products, total, err := s.productRepo.GetAllProductByStore(storeID, page, limit, search, status)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -69,6 +86,26 @@ func (s *productService) CreateProduct(c *gin.Context, productDTO dto.CreateProd
 		return entity.Product{}, errors.New("minimal satu gambar produk diperlukan")
 	}
 	
+	// Produk tanpa status eksplisit langsung dipublikasikan seperti sebelumnya
+	status := productDTO.Status
+	if status == "" {
+		status = entity.ProductStatusPublished
+	}
+	publishAt, err := validateProductStatus(status, productDTO.PublishAt)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	
+	// Validasi atribut sebelum upload agar tidak ada file yatim
+	attributeInput, err := s.attributeService.ParseAttributeInput(productDTO.Attributes)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	attributes, err := s.attributeService.ValidateProductAttributes(productDTO.CategoryID, attributeInput)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	
 	// Generate unique slug berdasarkan nama produk
 	baseSlug := utils.GenerateSlug(productDTO.Name, "product")
 	slug := utils.EnsureUniqueSlug(baseSlug, s.productRepo.IsSlugExists)
@@ -81,6 +118,12 @@ func (s *productService) CreateProduct(c *gin.Context, productDTO dto.CreateProd
 		Harga:       productDTO.Harga,
 		StoreID:     productDTO.StoreID,
 		CategoryID:  productDTO.CategoryID,
+		Status:      status,
+		PublishAt:   publishAt,
+		Weight:      productDTO.Weight,
+		Length:      productDTO.Length,
+		Width:       productDTO.Width,
+		Height:      productDTO.Height,
 	}
 	
 	// Upload gambar pertama sebagai thumbnail
@@ -105,6 +148,26 @@ func (s *productService) CreateProduct(c *gin.Context, productDTO dto.CreateProd
 		return entity.Product{}, fmt.Errorf("gagal menyimpan produk: %v", err)
 	}
 	
+	// Produk sudah tersimpan (bisa langsung published). Jika atribut atau
+	// gambar gagal disimpan, produk dihapus permanen lagi beserta file yang
+	// sudah diupload agar tidak ada produk setengah jadi di katalog.
+	uploadedPaths := []string{thumbnailPath}
+	discard := func(cause error) (entity.Product, error) {
+		if err := s.productRepo.ForceDelete(createdProduct.ID); err != nil {
+			log.Printf("❌ Gagal membatalkan produk %d: %v", createdProduct.ID, err)
+		}
+		for _, path := range uploadedPaths {
+			utils.DeleteFileIfExists(path)
+		}
+		return entity.Product{}, cause
+	}
+	
+	if len(attributes) > 0 {
+		if err := s.attributeService.SaveProductAttributes(createdProduct.ID, attributes); err != nil {
+			return discard(fmt.Errorf("gagal menyimpan atribut produk: %v", err))
+		}
+	}
+	
 	// Proses dan simpan semua gambar produk
 	var productImages []entity.ProductImage
 	
@@ -125,6 +188,7 @@ func (s *productService) CreateProduct(c *gin.Context, productDTO dto.CreateProd
 			if err != nil {
 				continue // Skip jika gagal upload
 			}
+			uploadedPaths = append(uploadedPaths, imagePath)
 		}
 		
 		// Buat entitas ProductImage
@@ -138,11 +202,13 @@ func (s *productService) CreateProduct(c *gin.Context, productDTO dto.CreateProd
 	
 	// Simpan semua gambar produk ke database
 	if err := s.productImageRepo.CreateBatch(productImages); err != nil {
-		// Jika gagal menyimpan gambar, tetap kembalikan produk
-		// gambar thumbnail sudah disimpan di tabel produk
-		return createdProduct, fmt.Errorf("sebagian gambar gagal disimpan: %v", err)
+		return discard(fmt.Errorf("gagal menyimpan gambar produk: %v", err))
 	}
 	
+	// Index pencarian dan riwayat harga baru dicatat setelah produk lengkap
+	s.searchIndex.IndexProduct(createdProduct.ID)
+	s.alertService.RecordPriceChange(createdProduct, nil)
+	
 	// Ambil produk lengkap dengan gambarnya
 	return s.GetProductByID(createdProduct.ID)
 }
@@ -183,13 +249,71 @@ func (s *productService) UpdateProductWithImages(c *gin.Context, slug string, pr
 		hasChanges = true
 	}
 	
+	// Harga lama disimpan untuk riwayat harga dan alert harga turun
+	var previousPrice *float64
 	if productDTO.Harga > 0 && productDTO.Harga != product.Harga {
+		oldPrice := product.Harga
+		previousPrice = &oldPrice
 		product.Harga = productDTO.Harga
 		hasChanges = true
 	}
 	
+	categoryChanged := false
 	if productDTO.CategoryID > 0 && productDTO.CategoryID != product.CategoryID {
 		product.CategoryID = productDTO.CategoryID
+		categoryChanged = true
+		hasChanges = true
+	}
+	
+	// Atribut hanya diganti jika field attributes dikirim
+	attributeInput, err := s.attributeService.ParseAttributeInput(productDTO.Attributes)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	var attributes []entity.ProductAttribute
+	if attributeInput != nil {
+		attributes, err = s.attributeService.ValidateProductAttributes(product.CategoryID, attributeInput)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	} else if categoryChanged {
+		// Atribut lama dibawa ke kategori baru dan harus lolos skemanya
+		attributes, err = s.attributeService.CarryOverAttributes(product.ID, product.CategoryID)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+	
+	wasPublished := product.Status == entity.ProductStatusPublished
+	if productDTO.Status != "" && (productDTO.Status != product.Status || productDTO.Status == entity.ProductStatusScheduled) {
+		publishAt, err := validateProductStatus(productDTO.Status, productDTO.PublishAt)
+		if err != nil {
+			return entity.Product{}, err
+		}
+		product.Status = productDTO.Status
+		product.PublishAt = publishAt
+		hasChanges = true
+	}
+	
+	// Berat dan dimensi untuk ongkir; nilai 0 berarti tidak diubah
+	for _, field := range []struct{ current *int; value int }{
+		{&product.Weight, productDTO.Weight},
+		{&product.Length, productDTO.Length},
+		{&product.Width, productDTO.Width},
+		{&product.Height, productDTO.Height},
+	} {
+		if field.value > 0 && field.value != *field.current {
+			*field.current = field.value
+			hasChanges = true
+		}
+	}
+	
+	if attributeInput != nil || categoryChanged {
+		if err := s.attributeService.SaveProductAttributes(product.ID, attributes); err != nil {
+			return entity.Product{}, fmt.Errorf("gagal menyimpan atribut produk: %v", err)
+		}
+		// Atribut ikut di-index pencarian, jadi produk harus disimpan dan
+		// di-index ulang walaupun field lain tidak berubah
 		hasChanges = true
 	}
 	
@@ -254,11 +378,8 @@ func (s *productService) UpdateProductWithImages(c *gin.Context, slug string, pr
 			
 			log.Printf("✅ Successfully batch deleted %d images", len(idsToDelete))
 			
-			// Clean up physical files
-			for _, imagePath := range deletedImagePaths {
-				utils.DeleteFileIfExistsProduct(imagePath)
-				log.Printf("🗑️ Deleted physical file: %s", imagePath)
-			}
+			// File fisik tidak langsung dihapus; retention job yang membersihkannya
+			log.Printf("🗑️ %d image files kept until retention purge", len(deletedImagePaths))
 		} else {
 			log.Printf("⚠️ No matching images found for deletion")
 		}
@@ -358,6 +479,13 @@ func (s *productService) UpdateProductWithImages(c *gin.Context, slug string, pr
 		}
 		
 		log.Printf("✅ Product updated in database")
+		s.searchIndex.IndexProduct(updatedProduct.ID)
+		if previousPrice != nil {
+			s.alertService.RecordPriceChange(updatedProduct, previousPrice)
+		} else if !wasPublished && updatedProduct.Status == entity.ProductStatusPublished {
+			// Harga bisa sudah diturunkan saat produk belum tayang
+			s.alertService.NotifyPriceDrops([]int{updatedProduct.ID})
+		}
 		
 		// ✅ Get fresh product data with images
 		finalProduct, err := s.productRepo.FindByID(updatedProduct.ID)
@@ -425,32 +553,71 @@ func (s *productService) DeleteProduct(slug string) error {
 		return fmt.Errorf("produk tidak ditemukan: %v", err)
 	}
 	
-	// Ambil semua gambar produk
-	images, err := s.productImageRepo.FindByProductID(product.ID)
+	// Soft delete: produk masuk trash, gambar dan file tetap disimpan sampai
+	// dibersihkan oleh retention job (lihat PurgeProduct)
+	if err := s.productRepo.Delete(product.ID); err != nil {
+		return err
+	}
+	s.searchIndex.RemoveProduct(product.ID)
+	return nil
+}
+
+func (s *productService) GetDeletedProductBySlug(slug string) (entity.Product, error) {
+	return s.productRepo.FindDeletedBySlug(slug)
+}
+
+func (s *productService) GetProductTrash(storeID, page, limit int) ([]entity.Product, *utils.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 12
+	}
+
+	products, total, err := s.productRepo.GetTrashByStore(storeID, page, limit)
 	if err != nil {
-		return fmt.Errorf("gagal mengambil gambar produk: %v", err)
+		return nil, nil, err
 	}
-	
-	// Hapus semua file fisik
-	for _, img := range images {
-		utils.DeleteFileIfExists(img.Image)
+
+	pagination := utils.NewPagination(page, limit, total)
+	return products, pagination, nil
+}
+
+func (s *productService) RestoreProduct(slug string) (entity.Product, error) {
+	product, err := s.productRepo.FindDeletedBySlug(slug)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("produk tidak ditemukan di trash: %v", err)
 	}
-	
-	// Juga hapus thumbnail jika bukan bagian dari images
+
+	if err := s.productRepo.Restore(product.ID); err != nil {
+		return entity.Product{}, fmt.Errorf("gagal mengembalikan produk: %v", err)
+	}
+	s.searchIndex.IndexProduct(product.ID)
+
+	return s.GetProductByID(product.ID)
+}
+
+// PurgeProduct menghapus produk secara permanen beserta semua file gambarnya.
+// Images harus dimuat tanpa scope soft delete (lihat ProductRepository.FindPurgeable).
+func (s *productService) PurgeProduct(product entity.Product) error {
+	if err := s.productRepo.ForceDelete(product.ID); err != nil {
+		return err
+	}
+	s.searchIndex.RemoveProduct(product.ID)
+
 	thumbnailExists := false
-	for _, img := range images {
+	for _, img := range product.Images {
+		utils.DeleteFileIfExists(img.Image)
 		if img.Image == product.Thumbnail {
 			thumbnailExists = true
-			break
 		}
 	}
-	
+
 	if !thumbnailExists {
 		utils.DeleteFileIfExists(product.Thumbnail)
 	}
-	
-	// Hapus produk dari database (akan menghapus semua gambar berkat ON DELETE CASCADE)
-	return s.productRepo.Delete(product.ID)
+
+	return nil
 }
 
 func (s *productService) AddProductImage(c *gin.Context, slug string, file *multipart.FileHeader) (entity.ProductImage, error) {
@@ -540,16 +707,13 @@ func (s *productService) DeleteProductImage(slug string, imageID int) error {
 		}
 	}
 	
-	// Hapus file fisik
-	utils.DeleteFileIfExists(targetImage.Image)
-	
-	// Hapus gambar dari database
+	// Soft delete gambar; file fisik dihapus oleh retention job
 	return s.productImageRepo.Delete(imageID)
 }
 
 // service/product-service.go
 
-func (s *productService) GetAllPublicProduct(page, limit int, search string) ([]dto.PublicProductCard, *utils.Pagination, error) {
+func (s *productService) GetAllPublicProduct(page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -557,12 +721,56 @@ func (s *productService) GetAllPublicProduct(page, limit int, search string) ([]
 		limit = 40 // Batas default, bisa disesuaikan
 	}
 
-	// Teruskan parameter 'search' ke pemanggilan repositori
-	products, total, err := s.productRepo.GetAllPublicProduct(page, limit, search)
+	// Teruskan filter (search, stok, atribut) ke pemanggilan repositori
+	products, total, err := s.productRepo.GetAllPublicProduct(page, limit, filter)
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mendapatkan semua produk dengan detail lengkap: %w", err)
 	}
 
+	pagination := utils.NewPagination(page, limit, total)
+	return toPublicProductCards(products), pagination, nil
+}
+
+// GetAllPublicProductCursor adalah GetAllPublicProduct dengan paginasi cursor
+func (s *productService) GetAllPublicProductCursor(cursor *utils.Cursor, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.CursorPagination, error) {
+	if limit < 1 || limit > utils.MaxCursorLimit {
+		limit = 40
+	}
+
+	products, pagination, err := s.productRepo.GetAllPublicProductCursor(cursor, limit, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return toPublicProductCards(products), pagination, nil
+}
+
+// SearchProducts sama seperti GetAllPublicProduct tetapi juga mengembalikan
+// jumlah produk per facet untuk filter yang sedang aktif
+func (s *productService) SearchProducts(page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, repository.ProductFacets, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 40
+	}
+
+	products, total, err := s.productRepo.GetAllPublicProduct(page, limit, filter)
+	if err != nil {
+		return nil, nil, repository.ProductFacets{}, fmt.Errorf("gagal mencari produk: %w", err)
+	}
+
+	facets, err := s.productRepo.GetProductFacets(filter)
+	if err != nil {
+		return nil, nil, repository.ProductFacets{}, fmt.Errorf("gagal menghitung facet produk: %w", err)
+	}
+
+
+	pagination := utils.NewPagination(page, limit, total)
+	return toPublicProductCards(products), pagination, facets, nil
+}
+
+// toPublicProductCards mengubah ProductCard menjadi card publik yang ringkas
+func toPublicProductCards(products []entity.ProductCard) []dto.PublicProductCard {
 	var publicProductCard []dto.PublicProductCard
 	for _, p := range products {
 		publicProductCard = append(publicProductCard, dto.PublicProductCard{
@@ -576,12 +784,23 @@ func (s *productService) GetAllPublicProduct(page, limit int, search string) ([]
 			CategoryName: p.CategoryName,
 			CategorySlug: p.CategorySlug,
 			Thumbnail:    p.Thumbnail,
+			OutOfStock:   p.OutOfStock,
+			RatingAvg:    p.RatingAvg,
+			RatingCount:  p.RatingCount,
+			FavoriteCount: p.FavoriteCount,
+			EffectivePrice: p.EffectivePrice,
+			OriginalPrice: p.OriginalPrice,
+			PromoBadge:   p.PromoBadge,
+			PromoEndsAt:  p.PromoEndsAt,
+			Attributes:   productAttributeMap(p.Attributes),
 			CreatedAt:    p.CreatedAt,
 		})
 	}
+	return publicProductCard
+}
 
-	pagination := utils.NewPagination(page, limit, total)
-	return publicProductCard, pagination, nil
+func (s *productService) GetProductAttributes(productID int) (map[string]string, error) {
+	return s.attributeService.GetProductAttributes(productID)
 }
 
 func (s *productService) GetLatestProduct()([]entity.ProductCard, error) {
@@ -594,7 +813,7 @@ func (s *productService) GetDetailProduct(slug string)(entity.ProductCard, error
 	return res, err
 }
 
-func (s *productService) GetAllPublicProductByCategory(slug string, page, limit int) ([]dto.PublicProductCard, *utils.Pagination, error) {
+func (s *productService) GetAllPublicProductByCategory(slug string, page, limit int, filter repository.ProductFilter) ([]dto.PublicProductCard, *utils.Pagination, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -602,28 +821,87 @@ func (s *productService) GetAllPublicProductByCategory(slug string, page, limit
 		limit = 40 // Batas default, bisa disesuaikan
 	}
 
-	products, total, err := s.productRepo.GetAllPublicProductByCategory(slug, page, limit)
+	products, total, err := s.productRepo.GetAllPublicProductByCategory(slug, page, limit, filter)
 	if err != nil {
 		return nil, nil, fmt.Errorf("gagal mendapatkan semua produk dengan detail lengkap: %w", err)
 	}
 
-	var publicProductCard []dto.PublicProductCard
-	for _, p := range products {
-		publicProductCard = append(publicProductCard, dto.PublicProductCard{
-			ID:            p.ID,
-			Slug:          p.Slug,
-			Name:          p.Name,
-			Harga:         p.Harga,
-			StoreID:       p.StoreID,
-			StoreName:     p.StoreName,   
-			CategoryID:    p.CategoryID,
-			CategoryName:  p.CategoryName,
-			CategorySlug:  p.CategorySlug,
-			Thumbnail:     p.Thumbnail,
-			CreatedAt:     p.CreatedAt,
-		})
+	pagination := utils.NewPagination(page, limit, total)
+	return toPublicProductCards(products), pagination, nil
+}
+
+// validateProductStatus memastikan status valid dan mengembalikan publish_at yang
+// harus disimpan: waktu sekarang untuk produk published, waktu terjadwal untuk
+// produk scheduled, dan kosong untuk draft atau archived.
+func validateProductStatus(status string, publishAt *time.Time) (*time.Time, error) {
+	switch status {
+	case entity.ProductStatusDraft, entity.ProductStatusArchived:
+		return nil, nil
+	case entity.ProductStatusPublished:
+		now := time.Now()
+		return &now, nil
+	case entity.ProductStatusScheduled:
+		if publishAt == nil {
+			return nil, errors.New("validation: publish_at wajib diisi untuk produk terjadwal")
+		}
+		if !publishAt.After(time.Now()) {
+			return nil, errors.New("validation: publish_at harus di masa depan")
+		}
+		return publishAt, nil
+	default:
+		return nil, fmt.Errorf("validation: status produk '%s' tidak dikenal", status)
 	}
+}
 
-	pagination := utils.NewPagination(page, limit, total)
-	return publicProductCard, pagination, nil
-}
\ No newline at end of file
+func (s *productService) ChangeProductStatus(slug string, statusDTO dto.ProductStatusDTO) (entity.Product, error) {
+	product, err := s.productRepo.FindBySlug(slug)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("produk tidak ditemukan: %v", err)
+	}
+
+	publishAt, err := validateProductStatus(statusDTO.Status, statusDTO.PublishAt)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	wasPublished := product.Status == entity.ProductStatusPublished
+	product.Status = statusDTO.Status
+	product.PublishAt = publishAt
+
+	updatedProduct, err := s.productRepo.Update(product)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("gagal mengubah status produk: %v", err)
+	}
+
+	if !wasPublished && updatedProduct.Status == entity.ProductStatusPublished {
+		// Harga bisa sudah diturunkan saat produk belum tayang
+		s.alertService.NotifyPriceDrops([]int{updatedProduct.ID})
+	}
+	return updatedProduct, nil
+}
+
+// RefreshShuffleKeys menyimpan kunci urutan acak untuk seed harian yang aktif,
+// dipanggil berkala oleh scheduler di main agar produk baru ikut mendapat kunci
+func (s *productService) RefreshShuffleKeys() error {
+	inserted, err := s.productRepo.RefreshShuffleKeys(utils.ShuffleSeeds(time.Now()))
+	if err != nil {
+		return err
+	}
+	if inserted > 0 {
+		log.Printf("🔀 %d kunci urutan acak produk disimpan", inserted)
+	}
+	return nil
+}
+
+// PublishScheduledProducts dipanggil berkala oleh scheduler di main
+func (s *productService) PublishScheduledProducts() (int64, error) {
+	published, err := s.productRepo.PublishScheduled(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if published > 0 {
+		log.Printf("📢 %d produk terjadwal dipublikasikan", published)
+		s.alertService.NotifyPriceDrops(nil)
+	}
+	return published, nil
+}
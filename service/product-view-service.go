@@ -0,0 +1,189 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Riwayat produk yang dilihat
+const (
+	viewDedupeWindow         = 30 * time.Minute
+	defaultRecentlyViewed    = 20
+	maxRecentlyViewed        = 50
+	productViewRetentionDays = 180
+	// maxPendingViews membatasi antrean kunjungan yang belum ditulis; jika
+	// penuh, kunjungan berikutnya dibuang sampai antrean di-flush
+	maxPendingViews = 5000
+)
+
+// ProductViewService mencatat kunjungan detail produk untuk riwayat "terakhir
+// dilihat", sinyal produk terkait, dan laporan kunjungan toko
+type ProductViewService interface {
+	RecordView(product entity.ProductCard, viewerKey string, userID *uint64)
+	Flush() error
+	GetRecentlyViewed(viewerKey string, limit int) ([]dto.PublicProductCard, error)
+	ClearHistory(viewerKey string) error
+	MergeVisitor(visitorID string, userID uint64) error
+	GetStoreReport(storeID, days, limit int) (dto.StoreViewReport, error)
+	PurgeOld() error
+}
+
+type productViewService struct {
+	viewRepo repository.ProductViewRepository
+
+	// pending menampung kunjungan yang belum ditulis ke database, satu entri
+	// per viewer dan produk; ditulis berkala oleh Flush
+	mu      sync.Mutex
+	pending map[string]entity.ProductView
+}
+
+func NewProductViewService(viewRepo repository.ProductViewRepository) ProductViewService {
+	return &productViewService{
+		viewRepo: viewRepo,
+		pending:  make(map[string]entity.ProductView),
+	}
+}
+
+// UserViewerKey dan VisitorViewerKey membentuk viewer_key riwayat kunjungan
+func UserViewerKey(userID uint64) string {
+	return "u:" + strconv.FormatUint(userID, 10)
+}
+
+func VisitorViewerKey(visitorID string) string {
+	return "v:" + visitorID
+}
+
+// RecordView mencatat kunjungan detail produk. Kunjungan pemilik toko ke
+// produknya sendiri tidak dicatat. Kunjungan hanya dimasukkan ke antrean di
+// memori agar halaman detail tidak menunggu database; Flush menuliskannya.
+func (s *productViewService) RecordView(product entity.ProductCard, viewerKey string, userID *uint64) {
+	if viewerKey == "" {
+		return
+	}
+	if userID != nil && product.Store.UserID != 0 && uint64(product.Store.UserID) == *userID {
+		return
+	}
+
+	key := viewerKey + "|" + strconv.Itoa(product.ID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= maxPendingViews {
+		return
+	}
+	// Kunjungan ulang sebelum flush cukup memperbarui waktu kunjungan
+	s.pending[key] = entity.ProductView{
+		ProductID: product.ID,
+		StoreID:   product.StoreID,
+		ViewerKey: viewerKey,
+		UserID:    userID,
+		ViewedAt:  time.Now(),
+	}
+}
+
+// Flush menulis antrean kunjungan ke database dalam satu batch. Jika gagal,
+// kunjungan dibuang agar antrean tidak terus membesar.
+func (s *productViewService) Flush() error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	views := make([]entity.ProductView, 0, len(s.pending))
+	for _, view := range s.pending {
+		views = append(views, view)
+	}
+	s.pending = make(map[string]entity.ProductView)
+	s.mu.Unlock()
+
+	if err := s.viewRepo.RecordBatch(views, viewDedupeWindow); err != nil {
+		return fmt.Errorf("gagal mencatat %d kunjungan produk: %v", len(views), err)
+	}
+	return nil
+}
+
+func (s *productViewService) GetRecentlyViewed(viewerKey string, limit int) ([]dto.PublicProductCard, error) {
+	if limit < 1 || limit > maxRecentlyViewed {
+		limit = defaultRecentlyViewed
+	}
+
+	products, err := s.viewRepo.GetRecentProducts(viewerKey, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := toPublicProductCards(products)
+	if cards == nil {
+		cards = []dto.PublicProductCard{}
+	}
+	return cards, nil
+}
+
+func (s *productViewService) ClearHistory(viewerKey string) error {
+	if err := s.viewRepo.Clear(viewerKey); err != nil {
+		return fmt.Errorf("gagal menghapus riwayat: %v", err)
+	}
+	return nil
+}
+
+// MergeVisitor memindahkan riwayat pengunjung anonim ke akun user setelah login
+func (s *productViewService) MergeVisitor(visitorID string, userID uint64) error {
+	if visitorID == "" {
+		return nil
+	}
+	// Kunjungan pengunjung yang masih di antrean ditulis dulu agar ikut pindah
+	if err := s.Flush(); err != nil {
+		log.Printf("❌ %v", err)
+	}
+	return s.viewRepo.Merge(VisitorViewerKey(visitorID), UserViewerKey(userID), userID)
+}
+
+func (s *productViewService) GetStoreReport(storeID, days, limit int) (dto.StoreViewReport, error) {
+	days, limit = reportBounds(days, limit)
+	since := time.Now().AddDate(0, 0, -days)
+
+	views, viewers, err := s.viewRepo.GetStoreTotals(storeID, since)
+	if err != nil {
+		return dto.StoreViewReport{}, err
+	}
+
+	stats, err := s.viewRepo.GetStoreStats(storeID, since, limit)
+	if err != nil {
+		return dto.StoreViewReport{}, err
+	}
+
+	report := dto.StoreViewReport{
+		Days:        days,
+		Views:       views,
+		Viewers:     viewers,
+		TopProducts: make([]dto.ProductViewStat, len(stats)),
+	}
+	for i, stat := range stats {
+		report.TopProducts[i] = dto.ProductViewStat{
+			ProductID: stat.ProductID,
+			Name:      stat.Name,
+			Slug:      stat.Slug,
+			Views:     stat.Views,
+			Viewers:   stat.Viewers,
+		}
+	}
+	return report, nil
+}
+
+// PurgeOld menghapus riwayat kunjungan yang lebih lama dari masa retensi
+func (s *productViewService) PurgeOld() error {
+	deleted, err := s.viewRepo.DeleteBefore(time.Now().AddDate(0, 0, -productViewRetentionDays))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("🗑️ %d riwayat kunjungan produk dihapus", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Batas potongan persen. Potongan di atas 90% ditolak agar tidak ada produk
+// yang dijual hampir gratis karena salah input.
+const (
+	minPromotionPercent = 1
+	maxPromotionPercent = 90
+)
+
+var (
+	ErrPromotionNotFound  = errors.New("promosi tidak ditemukan")
+	ErrPromotionForbidden = errors.New("anda tidak memiliki akses ke promosi ini")
+)
+
+// PromotionService mengelola promosi toko dan kampanye platform serta
+// menghitung harga efektif produk.
+//
+// Aturan pemenang jika beberapa promosi berlaku untuk satu produk (promosi
+// tidak pernah digabung):
+//  1. potongan rupiah terbesar menang;
+//  2. jika sama, cakupan paling spesifik menang (produk > kategori > toko > semua);
+//  3. jika masih sama, promosi toko menang atas kampanye platform;
+//  4. terakhir, promosi yang dibuat paling akhir menang.
+type PromotionService interface {
+	Refresh() error
+	GetStorePromotions(storeID int) ([]entity.Promotion, error)
+	CreateStorePromotion(store entity.Store, user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error)
+	UpdateStorePromotion(id uint64, user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error)
+	DeleteStorePromotion(id uint64, user entity.User) error
+	GetCampaigns() ([]entity.Promotion, error)
+	GetRunningCampaigns() ([]entity.Promotion, error)
+	CreateCampaign(user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error)
+	UpdateCampaign(id uint64, promotionDTO dto.PromotionDTO) (entity.Promotion, error)
+	DeleteCampaign(id uint64) error
+}
+
+type promotionService struct {
+	promotionRepo repository.PromotionRepository
+	storeRepo     repository.StoreRepository
+	categoryRepo  repository.ProductCategoryRepository
+	alertService  ProductAlertService
+	// refreshMu mencegah background job dan perubahan promosi menulis ulang
+	// product_promotions bersamaan
+	refreshMu sync.Mutex
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository, storeRepo repository.StoreRepository, categoryRepo repository.ProductCategoryRepository, alertService ProductAlertService) PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+		storeRepo:     storeRepo,
+		categoryRepo:  categoryRepo,
+		alertService:  alertService,
+	}
+}
+
+// Refresh menghitung ulang harga efektif semua produk publik dari promosi
+// yang sedang berjalan. Dijalankan background job setiap menit agar promosi
+// yang baru dimulai atau berakhir segera terlihat.
+func (s *promotionService) Refresh() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	now := time.Now()
+	promotions, err := s.promotionRepo.GetRunning(now)
+	if err != nil {
+		return err
+	}
+	items, err := s.promotionRepo.GetPromotionItems()
+	if err != nil {
+		return err
+	}
+	parents, err := s.promotionRepo.GetCategoryParents()
+	if err != nil {
+		return err
+	}
+
+	index := newPromotionIndex(promotions)
+	prices := make([]entity.ProductPromotion, 0)
+	for _, item := range items {
+		promotion, discount, ok := index.best(item, parents)
+		if !ok {
+			continue
+		}
+		prices = append(prices, entity.ProductPromotion{
+			ProductID:      item.ID,
+			PromotionID:    promotion.ID,
+			OriginalPrice:  item.Harga,
+			EffectivePrice: item.Harga - discount,
+			Badge:          promotion.Badge,
+			EndsAt:         promotion.EndsAt,
+			ComputedAt:     now,
+		})
+	}
+
+	if err := s.promotionRepo.ReplaceProductPromotions(prices); err != nil {
+		return err
+	}
+
+	// Promosi yang baru berjalan bisa memenuhi target alert harga turun
+	s.alertService.NotifyPriceDrops(nil)
+	return nil
+}
+
+// refreshAfterChange menghitung ulang harga setelah promosi berubah. Jika
+// gagal, harga diperbarui oleh job berikutnya.
+func (s *promotionService) refreshAfterChange() {
+	if err := s.Refresh(); err != nil {
+		log.Printf("❌ Gagal menghitung ulang harga promosi: %v", err)
+	}
+}
+
+// promotionIndex mengelompokkan promosi berjalan berdasarkan cakupannya
+type promotionIndex struct {
+	byProduct  map[int][]entity.Promotion
+	byCategory map[int][]entity.Promotion
+	byStore    map[int][]entity.Promotion
+	global     []entity.Promotion
+}
+
+func newPromotionIndex(promotions []entity.Promotion) promotionIndex {
+	index := promotionIndex{
+		byProduct:  map[int][]entity.Promotion{},
+		byCategory: map[int][]entity.Promotion{},
+		byStore:    map[int][]entity.Promotion{},
+	}
+	for _, p := range promotions {
+		switch p.Scope {
+		case entity.PromotionScopeProduct:
+			for _, pp := range p.Products {
+				index.byProduct[pp.ProductID] = append(index.byProduct[pp.ProductID], p)
+			}
+		case entity.PromotionScopeCategory:
+			if p.CategoryID != nil {
+				index.byCategory[*p.CategoryID] = append(index.byCategory[*p.CategoryID], p)
+			}
+		case entity.PromotionScopeStore:
+			if p.StoreID != nil {
+				index.byStore[*p.StoreID] = append(index.byStore[*p.StoreID], p)
+			}
+		case entity.PromotionScopeAll:
+			index.global = append(index.global, p)
+		}
+	}
+	return index
+}
+
+// best memilih promosi pemenang untuk produk sesuai aturan di PromotionService
+func (idx promotionIndex) best(item repository.PromotionItem, parents map[int]*int) (entity.Promotion, float64, bool) {
+	var (
+		winner   entity.Promotion
+		discount float64
+		found    bool
+	)
+
+	consider := func(p entity.Promotion) {
+		// Promosi toko hanya berlaku untuk produk toko itu sendiri
+		if !p.IsPlatform() && *p.StoreID != item.StoreID {
+			return
+		}
+		d := p.Discount(item.Harga)
+		if d <= 0 {
+			return
+		}
+		if !found || promotionBeats(p, d, winner, discount) {
+			winner, discount, found = p, d, true
+		}
+	}
+
+	for _, p := range idx.byProduct[item.ID] {
+		consider(p)
+	}
+	// Promosi kategori juga berlaku untuk semua subkategorinya. visited
+	// mencegah loop jika data parent_id rusak.
+	visited := map[int]bool{}
+	for categoryID := &item.CategoryID; categoryID != nil && !visited[*categoryID]; categoryID = parents[*categoryID] {
+		visited[*categoryID] = true
+		for _, p := range idx.byCategory[*categoryID] {
+			consider(p)
+		}
+	}
+	for _, p := range idx.byStore[item.StoreID] {
+		consider(p)
+	}
+	for _, p := range idx.global {
+		consider(p)
+	}
+	return winner, discount, found
+}
+
+// promotionBeats menentukan apakah promosi a dengan potongan da mengalahkan b
+func promotionBeats(a entity.Promotion, da float64, b entity.Promotion, db float64) bool {
+	if da != db {
+		return da > db
+	}
+	if a.Specificity() != b.Specificity() {
+		return a.Specificity() > b.Specificity()
+	}
+	if a.IsPlatform() != b.IsPlatform() {
+		return !a.IsPlatform()
+	}
+	return a.ID > b.ID
+}
+
+func (s *promotionService) GetStorePromotions(storeID int) ([]entity.Promotion, error) {
+	return s.promotionRepo.GetByStore(storeID)
+}
+
+func (s *promotionService) CreateStorePromotion(store entity.Store, user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error) {
+	storeID := int(store.ID)
+	promotion := entity.Promotion{
+		StoreID:   &storeID,
+		CreatedBy: user.ID,
+	}
+	if err := s.applyPromotionDTO(&promotion, promotionDTO); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion, err := s.promotionRepo.Create(promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	s.refreshAfterChange()
+	return promotion, nil
+}
+
+func (s *promotionService) UpdateStorePromotion(id uint64, user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error) {
+	promotion, err := s.storePromotion(id, user)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	if err := s.applyPromotionDTO(&promotion, promotionDTO); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion, err = s.promotionRepo.Update(promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	s.refreshAfterChange()
+	return promotion, nil
+}
+
+func (s *promotionService) DeleteStorePromotion(id uint64, user entity.User) error {
+	if _, err := s.storePromotion(id, user); err != nil {
+		return err
+	}
+	if err := s.promotionRepo.Delete(id); err != nil {
+		return err
+	}
+	s.refreshAfterChange()
+	return nil
+}
+
+// storePromotion mengambil promosi toko milik user. Kampanye platform tidak
+// bisa diubah lewat endpoint toko.
+func (s *promotionService) storePromotion(id uint64, user entity.User) (entity.Promotion, error) {
+	promotion, err := s.promotionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Promotion{}, ErrPromotionNotFound
+		}
+		return entity.Promotion{}, err
+	}
+	if promotion.IsPlatform() {
+		return entity.Promotion{}, ErrPromotionForbidden
+	}
+
+	store, err := s.storeRepo.FindByID(strconv.Itoa(*promotion.StoreID))
+	if err != nil || uint64(store.UserID) != user.ID {
+		return entity.Promotion{}, ErrPromotionForbidden
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) GetCampaigns() ([]entity.Promotion, error) {
+	return s.promotionRepo.GetCampaigns()
+}
+
+// GetRunningCampaigns mengembalikan kampanye platform yang sedang berjalan
+// untuk ditampilkan di halaman publik
+func (s *promotionService) GetRunningCampaigns() ([]entity.Promotion, error) {
+	return s.promotionRepo.GetRunningCampaigns(time.Now())
+}
+
+func (s *promotionService) CreateCampaign(user entity.User, promotionDTO dto.PromotionDTO) (entity.Promotion, error) {
+	promotion := entity.Promotion{CreatedBy: user.ID}
+	if err := s.applyPromotionDTO(&promotion, promotionDTO); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion, err := s.promotionRepo.Create(promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	s.refreshAfterChange()
+	return promotion, nil
+}
+
+func (s *promotionService) UpdateCampaign(id uint64, promotionDTO dto.PromotionDTO) (entity.Promotion, error) {
+	promotion, err := s.campaign(id)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	if err := s.applyPromotionDTO(&promotion, promotionDTO); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion, err = s.promotionRepo.Update(promotion)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	s.refreshAfterChange()
+	return promotion, nil
+}
+
+func (s *promotionService) DeleteCampaign(id uint64) error {
+	if _, err := s.campaign(id); err != nil {
+		return err
+	}
+	if err := s.promotionRepo.Delete(id); err != nil {
+		return err
+	}
+	s.refreshAfterChange()
+	return nil
+}
+
+func (s *promotionService) campaign(id uint64) (entity.Promotion, error) {
+	promotion, err := s.promotionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Promotion{}, ErrPromotionNotFound
+		}
+		return entity.Promotion{}, err
+	}
+	if !promotion.IsPlatform() {
+		return entity.Promotion{}, ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+// applyPromotionDTO memvalidasi input dan mengisinya ke promotion. Promosi
+// toko bercakupan product, category, atau store; kampanye platform
+// bercakupan category atau all.
+func (s *promotionService) applyPromotionDTO(promotion *entity.Promotion, promotionDTO dto.PromotionDTO) error {
+	switch promotionDTO.DiscountType {
+	case entity.PromotionTypePercent:
+		if promotionDTO.DiscountValue < minPromotionPercent || promotionDTO.DiscountValue > maxPromotionPercent {
+			return fmt.Errorf("diskon persen harus antara %d dan %d", minPromotionPercent, maxPromotionPercent)
+		}
+	case entity.PromotionTypeFixed:
+		if promotionDTO.MaxDiscount != nil {
+			return errors.New("max_discount hanya untuk diskon persen")
+		}
+	}
+
+	if !promotionDTO.EndsAt.After(promotionDTO.StartsAt) {
+		return errors.New("waktu berakhir harus setelah waktu mulai")
+	}
+
+	if promotion.IsPlatform() {
+		if promotionDTO.Scope != entity.PromotionScopeCategory && promotionDTO.Scope != entity.PromotionScopeAll {
+			return errors.New("cakupan kampanye platform harus category atau all")
+		}
+	} else if promotionDTO.Scope == entity.PromotionScopeAll {
+		return errors.New("cakupan promosi toko harus product, category, atau store")
+	}
+
+	promotion.CategoryID = nil
+	promotion.Products = nil
+	switch promotionDTO.Scope {
+	case entity.PromotionScopeCategory:
+		if promotionDTO.CategoryID == nil {
+			return errors.New("category_id wajib diisi untuk promosi kategori")
+		}
+		if _, err := s.categoryRepo.FindByID(*promotionDTO.CategoryID); err != nil {
+			return errors.New("kategori tidak ditemukan")
+		}
+		promotion.CategoryID = promotionDTO.CategoryID
+	case entity.PromotionScopeProduct:
+		products, err := s.promotionProducts(*promotion.StoreID, promotionDTO.ProductIDs)
+		if err != nil {
+			return err
+		}
+		promotion.Products = products
+	}
+
+	promotion.Name = promotionDTO.Name
+	promotion.DiscountType = promotionDTO.DiscountType
+	promotion.DiscountValue = promotionDTO.DiscountValue
+	promotion.MaxDiscount = promotionDTO.MaxDiscount
+	promotion.Scope = promotionDTO.Scope
+	promotion.StartsAt = promotionDTO.StartsAt
+	promotion.EndsAt = promotionDTO.EndsAt
+	promotion.Active = promotionDTO.Active == nil || *promotionDTO.Active
+	promotion.Badge = promotionDTO.Badge
+	if promotion.Badge == "" {
+		promotion.Badge = defaultPromotionBadge(*promotion)
+	}
+	return nil
+}
+
+// promotionProducts memastikan semua produk promosi milik toko
+func (s *promotionService) promotionProducts(storeID int, productIDs []int) ([]entity.PromotionProduct, error) {
+	seen := map[int]bool{}
+	var products []entity.PromotionProduct
+	for _, id := range productIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		products = append(products, entity.PromotionProduct{ProductID: id})
+	}
+	if len(products) == 0 {
+		return nil, errors.New("product_ids wajib diisi untuk promosi produk")
+	}
+
+	ids := make([]int, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ProductID)
+	}
+	count, err := s.promotionRepo.CountStoreProducts(storeID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if count != int64(len(ids)) {
+		return nil, errors.New("semua produk promosi harus milik toko ini")
+	}
+	return products, nil
+}
+
+// defaultPromotionBadge membuat label promo jika penjual tidak mengisinya.
+// Kampanye platform memakai nama kampanye, misalnya "Hari Batik Nasional".
+func defaultPromotionBadge(promotion entity.Promotion) string {
+	if promotion.IsPlatform() {
+		if name := []rune(promotion.Name); len(name) > 50 {
+			return string(name[:50])
+		}
+		return promotion.Name
+	}
+	if promotion.DiscountType == entity.PromotionTypePercent {
+		return fmt.Sprintf("Diskon %s%%", strconv.FormatFloat(promotion.DiscountValue, 'f', -1, 64))
+	}
+	return "Hemat " + formatRupiah(promotion.DiscountValue)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"batik/entity"
+	"batik/repository"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
+
+func percentPromotion(id uint64, storeID *int, scope string, percent float64) entity.Promotion {
+	return entity.Promotion{
+		ID:            id,
+		StoreID:       storeID,
+		DiscountType:  entity.PromotionTypePercent,
+		DiscountValue: percent,
+		Scope:         scope,
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	cases := []struct {
+		name      string
+		promotion entity.Promotion
+		price     float64
+		want      float64
+	}{
+		{"persen", entity.Promotion{DiscountType: entity.PromotionTypePercent, DiscountValue: 15}, 200000, 30000},
+		{"persen dibulatkan", entity.Promotion{DiscountType: entity.PromotionTypePercent, DiscountValue: 10}, 99999, 10000},
+		{"nominal", entity.Promotion{DiscountType: entity.PromotionTypeFixed, DiscountValue: 25000}, 200000, 25000},
+		{"dibatasi max_discount", entity.Promotion{DiscountType: entity.PromotionTypePercent, DiscountValue: 50, MaxDiscount: floatPtr(40000)}, 200000, 40000},
+		{"nominal dibatasi 90% harga", entity.Promotion{DiscountType: entity.PromotionTypeFixed, DiscountValue: 150000}, 100000, 90000},
+		{"harga nol", entity.Promotion{DiscountType: entity.PromotionTypeFixed, DiscountValue: 5000}, 0, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.promotion.Discount(tc.price); got != tc.want {
+			t.Errorf("%s: Discount = %v, ingin %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPromotionIndexBestPicksLargestDiscount(t *testing.T) {
+	store := intPtr(7)
+	product := percentPromotion(1, store, entity.PromotionScopeProduct, 10)
+	product.Products = []entity.PromotionProduct{{ProductID: 100}}
+	storeWide := percentPromotion(2, store, entity.PromotionScopeStore, 20)
+	campaign := percentPromotion(3, nil, entity.PromotionScopeAll, 15)
+
+	index := newPromotionIndex([]entity.Promotion{product, storeWide, campaign})
+	winner, discount, ok := index.best(repository.PromotionItem{ID: 100, StoreID: 7, CategoryID: 1, Harga: 100000}, nil)
+	if !ok || winner.ID != 2 || discount != 20000 {
+		t.Fatalf("pemenang = %d (potongan %v), ingin promosi toko 2 dengan potongan 20000", winner.ID, discount)
+	}
+}
+
+func TestPromotionIndexBestTieBreakers(t *testing.T) {
+	store := intPtr(7)
+	item := repository.PromotionItem{ID: 100, StoreID: 7, CategoryID: 5, Harga: 100000}
+	parents := map[int]*int{5: intPtr(1), 1: nil}
+
+	category := percentPromotion(1, store, entity.PromotionScopeCategory, 10)
+	category.CategoryID = intPtr(1)
+	storeWide := percentPromotion(2, store, entity.PromotionScopeStore, 10)
+	product := percentPromotion(3, store, entity.PromotionScopeProduct, 10)
+	product.Products = []entity.PromotionProduct{{ProductID: 100}}
+	campaignCategory := percentPromotion(4, nil, entity.PromotionScopeCategory, 10)
+	campaignCategory.CategoryID = intPtr(5)
+	newerStoreWide := percentPromotion(5, store, entity.PromotionScopeStore, 10)
+
+	cases := []struct {
+		name       string
+		promotions []entity.Promotion
+		want       uint64
+	}{
+		// Potongan sama: cakupan paling spesifik menang
+		{"produk atas kategori dan toko", []entity.Promotion{storeWide, category, product}, 3},
+		{"kategori induk atas toko", []entity.Promotion{storeWide, category}, 1},
+		// Cakupan sama: promosi toko menang atas kampanye platform
+		{"toko atas kampanye", []entity.Promotion{campaignCategory, category}, 1},
+		// Semua sama: promosi terbaru menang
+		{"terbaru menang", []entity.Promotion{storeWide, newerStoreWide}, 5},
+	}
+	for _, tc := range cases {
+		winner, _, ok := newPromotionIndex(tc.promotions).best(item, parents)
+		if !ok || winner.ID != tc.want {
+			t.Errorf("%s: pemenang = %d, ingin %d", tc.name, winner.ID, tc.want)
+		}
+	}
+}
+
+func TestPromotionIndexBestScope(t *testing.T) {
+	otherStore := percentPromotion(1, intPtr(8), entity.PromotionScopeStore, 50)
+	otherCategory := percentPromotion(2, nil, entity.PromotionScopeCategory, 50)
+	otherCategory.CategoryID = intPtr(9)
+	// Data parent_id yang melingkar tidak membuat loop tanpa akhir
+	parents := map[int]*int{5: intPtr(6), 6: intPtr(5)}
+
+	index := newPromotionIndex([]entity.Promotion{otherStore, otherCategory})
+	if winner, _, ok := index.best(repository.PromotionItem{ID: 100, StoreID: 7, CategoryID: 5, Harga: 100000}, parents); ok {
+		t.Fatalf("promosi toko lain atau kategori lain tidak boleh berlaku, dapat %d", winner.ID)
+	}
+
+	// Kampanye kategori induk berlaku untuk subkategori
+	campaign := percentPromotion(3, nil, entity.PromotionScopeCategory, 5)
+	campaign.CategoryID = intPtr(6)
+	winner, discount, ok := newPromotionIndex([]entity.Promotion{campaign}).
+		best(repository.PromotionItem{ID: 100, StoreID: 7, CategoryID: 5, Harga: 100000}, parents)
+	if !ok || winner.ID != 3 || discount != 5000 {
+		t.Errorf("pemenang = %d (potongan %v), ingin kampanye 3 dengan potongan 5000", winner.ID, discount)
+	}
+}
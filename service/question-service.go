@@ -0,0 +1,264 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// questionFlagThreshold adalah jumlah laporan sebelum pertanyaan
+// disembunyikan otomatis
+const questionFlagThreshold = 3
+
+var (
+	ErrQuestionNotFound  = errors.New("pertanyaan tidak ditemukan")
+	ErrQuestionForbidden = errors.New("anda tidak memiliki akses ke pertanyaan ini")
+)
+
+type QuestionService interface {
+	GetProductQuestions(productID int, filter repository.QuestionFilter, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error)
+	GetStoreQuestions(storeID int, filter repository.QuestionFilter, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error)
+	GetModerationQueue(status string, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error)
+	AskQuestion(product entity.Product, user entity.User, questionDTO dto.CreateQuestionDTO) (entity.ProductQuestion, error)
+	AnswerQuestion(id uint64, user entity.User, answerDTO dto.AnswerQuestionDTO) (entity.ProductQuestion, error)
+	DeleteQuestion(id uint64, user entity.User) error
+	Upvote(id uint64, user entity.User) (entity.ProductQuestion, error)
+	RemoveUpvote(id uint64, user entity.User) (entity.ProductQuestion, error)
+	FlagQuestion(id uint64, user entity.User, flagDTO dto.QuestionFlagDTO) error
+	ModerateQuestion(id uint64, moderateDTO dto.ModerateQuestionDTO) (entity.ProductQuestion, error)
+}
+
+type questionService struct {
+	questionRepo        repository.QuestionRepository
+	productRepo         repository.ProductRepository
+	storeRepo           repository.StoreRepository
+	notificationService NotificationService
+}
+
+func NewQuestionService(questionRepo repository.QuestionRepository, productRepo repository.ProductRepository, storeRepo repository.StoreRepository, notificationService NotificationService) QuestionService {
+	return &questionService{
+		questionRepo:        questionRepo,
+		productRepo:         productRepo,
+		storeRepo:           storeRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *questionService) GetProductQuestions(productID int, filter repository.QuestionFilter, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error) {
+	page, limit = discussionPage(page, limit)
+	questions, total, err := s.questionRepo.GetByProduct(productID, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return questions, utils.NewPagination(page, limit, total), nil
+}
+
+func (s *questionService) GetStoreQuestions(storeID int, filter repository.QuestionFilter, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error) {
+	page, limit = discussionPage(page, limit)
+	questions, total, err := s.questionRepo.GetByStore(storeID, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return questions, utils.NewPagination(page, limit, total), nil
+}
+
+func (s *questionService) GetModerationQueue(status string, page, limit int) ([]entity.ProductQuestion, *utils.Pagination, error) {
+	status, err := moderationQueueStatus(status,
+		entity.QuestionStatusPending, entity.QuestionStatusHidden, entity.QuestionStatusPublished, repository.QuestionStatusFlagged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page, limit = discussionPage(page, limit)
+	questions, total, err := s.questionRepo.GetForModeration(status, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return questions, utils.NewPagination(page, limit, total), nil
+}
+
+// AskQuestion menyimpan pertanyaan publik dan memberi tahu pemilik toko
+func (s *questionService) AskQuestion(product entity.Product, user entity.User, questionDTO dto.CreateQuestionDTO) (entity.ProductQuestion, error) {
+	if product.Status != entity.ProductStatusPublished {
+		return entity.ProductQuestion{}, errors.New("produk belum dapat ditanyakan")
+	}
+
+	store, err := s.storeRepo.FindByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("toko tidak ditemukan: %v", err)
+	}
+
+	question, err := s.questionRepo.Create(entity.ProductQuestion{
+		ProductID: product.ID,
+		UserID:    user.ID,
+		Body:      strings.TrimSpace(questionDTO.Body),
+		Status:    entity.QuestionStatusPublished,
+	})
+	if err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("gagal menyimpan pertanyaan: %v", err)
+	}
+
+	if uint64(store.UserID) != user.ID {
+		s.notificationService.Notify(store.UserID, entity.NotificationNewQuestion,
+			fmt.Sprintf("Pertanyaan baru untuk %s", product.Name),
+			fmt.Sprintf("%s bertanya: %s", user.Name, question.Body),
+			"/product/"+product.Slug)
+	}
+
+	return s.questionRepo.FindByID(question.ID)
+}
+
+// AnswerQuestion menyimpan jawaban toko pemilik produk. Toko belum memiliki
+// anggota selain pemiliknya, jadi hanya store.UserID yang dapat menjawab.
+func (s *questionService) AnswerQuestion(id uint64, user entity.User, answerDTO dto.AnswerQuestionDTO) (entity.ProductQuestion, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	product, err := s.productRepo.FindByID(question.ProductID)
+	if err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("produk tidak ditemukan: %v", err)
+	}
+	store, err := s.storeRepo.FindByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("toko tidak ditemukan: %v", err)
+	}
+	if uint64(store.UserID) != user.ID {
+		return entity.ProductQuestion{}, ErrQuestionForbidden
+	}
+
+	answer := strings.TrimSpace(answerDTO.Answer)
+	var (
+		answeredBy *uint64
+		answeredAt *time.Time
+	)
+	if answer != "" {
+		now := time.Now()
+		answeredBy = &user.ID
+		answeredAt = &now
+	}
+
+	if err := s.questionRepo.UpdateAnswer(question.ID, answer, answeredBy, answeredAt); err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("gagal menyimpan jawaban: %v", err)
+	}
+
+	if answer != "" && question.AnsweredAt == nil && question.UserID != user.ID {
+		s.notificationService.Notify(int(question.UserID), entity.NotificationQuestionAnswered,
+			fmt.Sprintf("Pertanyaan Anda tentang %s sudah dijawab", product.Name),
+			answer,
+			"/product/"+product.Slug)
+	}
+
+	return s.questionRepo.FindByID(question.ID)
+}
+
+// DeleteQuestion menghapus pertanyaan. Penanya hanya boleh menghapus
+// pertanyaan yang belum dijawab; admin boleh menghapus kapan saja.
+func (s *questionService) DeleteQuestion(id uint64, user entity.User) error {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != entity.RoleAdmin {
+		if question.UserID != user.ID {
+			return ErrQuestionForbidden
+		}
+		if question.AnsweredAt != nil {
+			return errors.New("pertanyaan yang sudah dijawab tidak dapat dihapus")
+		}
+	}
+
+	if err := s.questionRepo.Delete(question.ID); err != nil {
+		return fmt.Errorf("gagal menghapus pertanyaan: %v", err)
+	}
+	return nil
+}
+
+func (s *questionService) Upvote(id uint64, user entity.User) (entity.ProductQuestion, error) {
+	question, err := s.findPublishedQuestion(id)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	if question.UserID == user.ID {
+		return entity.ProductQuestion{}, errors.New("anda tidak dapat memberi upvote pada pertanyaan sendiri")
+	}
+
+	if err := s.questionRepo.Upvote(question.ID, user.ID); err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("gagal menyimpan upvote: %v", err)
+	}
+	return s.questionRepo.FindByID(question.ID)
+}
+
+func (s *questionService) RemoveUpvote(id uint64, user entity.User) (entity.ProductQuestion, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	if err := s.questionRepo.RemoveUpvote(question.ID, user.ID); err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("gagal menghapus upvote: %v", err)
+	}
+	return s.questionRepo.FindByID(question.ID)
+}
+
+// FlagQuestion mencatat laporan pertanyaan. Penanya tidak dapat melaporkan
+// pertanyaannya sendiri.
+func (s *questionService) FlagQuestion(id uint64, user entity.User, flagDTO dto.QuestionFlagDTO) error {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return err
+	}
+	if question.UserID == user.ID {
+		return errors.New("anda tidak dapat melaporkan pertanyaan sendiri")
+	}
+
+	return s.questionRepo.AddFlag(entity.ProductQuestionFlag{
+		QuestionID: question.ID,
+		UserID:     user.ID,
+		Reason:     strings.TrimSpace(flagDTO.Reason),
+		CreatedAt:  time.Now(),
+	}, questionFlagThreshold)
+}
+
+func (s *questionService) ModerateQuestion(id uint64, moderateDTO dto.ModerateQuestionDTO) (entity.ProductQuestion, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	if err := s.questionRepo.Moderate(question.ID, moderateDTO.Status); err != nil {
+		return entity.ProductQuestion{}, fmt.Errorf("gagal memoderasi pertanyaan: %v", err)
+	}
+	return s.questionRepo.FindByID(question.ID)
+}
+
+func (s *questionService) findQuestion(id uint64) (entity.ProductQuestion, error) {
+	question, err := s.questionRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ProductQuestion{}, ErrQuestionNotFound
+	}
+	return question, err
+}
+
+// findPublishedQuestion seperti findQuestion, tetapi pertanyaan yang sedang
+// disembunyikan dianggap tidak ada
+func (s *questionService) findPublishedQuestion(id uint64) (entity.ProductQuestion, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	if question.Status != entity.QuestionStatusPublished {
+		return entity.ProductQuestion{}, ErrQuestionNotFound
+	}
+	return question, nil
+}
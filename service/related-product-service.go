@@ -0,0 +1,100 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/search"
+	"log"
+	"time"
+)
+
+// Produk terkait yang disimpan per produk dan yang ditampilkan per permintaan
+const (
+	relatedStoredLimit  = 24
+	defaultRelatedLimit = 12
+	relatedCoViewDays   = 90
+)
+
+// RelatedProductService menghitung produk terkait secara offline (Refresh,
+// dijalankan background job) dan membacanya dari tabel related_products
+type RelatedProductService interface {
+	Refresh() error
+	GetRelated(slug string, limit int) ([]dto.PublicProductCard, error)
+}
+
+type relatedProductService struct {
+	relatedRepo repository.RelatedProductRepository
+	productRepo repository.ProductRepository
+}
+
+func NewRelatedProductService(relatedRepo repository.RelatedProductRepository, productRepo repository.ProductRepository) RelatedProductService {
+	return &relatedProductService{
+		relatedRepo: relatedRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Refresh menghitung ulang produk terkait untuk semua produk publik dari
+// kategori, toko, atribut, rentang harga, dan sinyal dilihat bersama
+func (s *relatedProductService) Refresh() error {
+	items, err := s.relatedRepo.GetRelatedItems()
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().AddDate(0, 0, -relatedCoViewDays)
+	coViews, err := s.relatedRepo.GetCoViews(since)
+	if err != nil {
+		return err
+	}
+
+	scores := search.ComputeRelated(items, coViews, relatedStoredLimit)
+
+	now := time.Now()
+	related := make([]entity.RelatedProduct, 0, len(scores))
+	for _, score := range scores {
+		related = append(related, entity.RelatedProduct{
+			ProductID:  score.ProductID,
+			RelatedID:  score.RelatedID,
+			Score:      score.Score,
+			ComputedAt: now,
+		})
+	}
+
+	if err := s.relatedRepo.Replace(related); err != nil {
+		return err
+	}
+	log.Printf("🧩 Produk terkait dihitung: %d pasangan dari %d produk", len(related), len(items))
+	return nil
+}
+
+// GetRelated mengembalikan produk terkait. Produk baru yang belum dihitung
+// job memakai produk terbaru di kategori yang sama.
+func (s *relatedProductService) GetRelated(slug string, limit int) ([]dto.PublicProductCard, error) {
+	if limit < 1 || limit > relatedStoredLimit {
+		limit = defaultRelatedLimit
+	}
+
+	product, err := publishedProduct(s.productRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.relatedRepo.GetRelated(product.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		products, err = s.relatedRepo.GetSameCategory(product, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cards := toPublicProductCards(products)
+	if cards == nil {
+		cards = []dto.PublicProductCard{}
+	}
+	return cards, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"batik/repository"
+	"batik/utils"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultRetentionDays adalah lama data berada di trash sebelum dihapus permanen
+const defaultRetentionDays = 30
+
+// purgeBatchSize membatasi jumlah baris yang dibersihkan per entitas tiap job
+const purgeBatchSize = 200
+
+type RetentionService interface {
+	PurgeExpired() error
+}
+
+type retentionService struct {
+	retention        time.Duration
+	productService   ProductService
+	productRepo      repository.ProductRepository
+	productImageRepo repository.ProductImageRepository
+	storeRepo        repository.StoreRepository
+	articleRepo      repository.ArticleRepository
+}
+
+func NewRetentionService(productService ProductService, productRepo repository.ProductRepository, productImageRepo repository.ProductImageRepository, storeRepo repository.StoreRepository, articleRepo repository.ArticleRepository) RetentionService {
+	return &retentionService{
+		retention:        time.Duration(getRetentionDays()) * 24 * time.Hour,
+		productService:   productService,
+		productRepo:      productRepo,
+		productImageRepo: productImageRepo,
+		storeRepo:        storeRepo,
+		articleRepo:      articleRepo,
+	}
+}
+
+// getRetentionDays membaca TRASH_RETENTION_DAYS, default 30 hari
+func getRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		return defaultRetentionDays
+	}
+	return days
+}
+
+// PurgeExpired menghapus permanen produk, gambar, toko, dan artikel yang sudah
+// berada di trash lebih lama dari masa retensi, beserta file di uploads/.
+func (s *retentionService) PurgeExpired() error {
+	cutoff := time.Now().Add(-s.retention)
+
+	products, err := s.productRepo.FindPurgeable(cutoff, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := s.productService.PurgeProduct(product); err != nil {
+			log.Printf("❌ Gagal purge produk %d: %v", product.ID, err)
+		}
+	}
+
+	images, err := s.productImageRepo.FindDeletedBefore(cutoff, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	var imageIDs []int
+	for _, img := range images {
+		imageIDs = append(imageIDs, img.ID)
+	}
+	if err := s.productImageRepo.ForceDeleteMultiple(imageIDs); err != nil {
+		return err
+	}
+	for _, img := range images {
+		utils.DeleteFileIfExists(img.Image)
+	}
+
+	stores, err := s.storeRepo.FindPurgeable(cutoff, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, store := range stores {
+		storeProducts, err := s.productRepo.FindAllByStoreUnscoped(int(store.ID))
+		if err != nil {
+			log.Printf("❌ Gagal mengambil produk toko %d: %v", store.ID, err)
+			continue
+		}
+		for _, product := range storeProducts {
+			if err := s.productService.PurgeProduct(product); err != nil {
+				log.Printf("❌ Gagal purge produk %d: %v", product.ID, err)
+			}
+		}
+		if err := s.storeRepo.ForceDelete(store.ID); err != nil {
+			log.Printf("❌ Gagal purge toko %d: %v", store.ID, err)
+			continue
+		}
+		utils.DeleteFileIfExists(store.Avatar)
+		utils.DeleteFileIfExists(store.Banner)
+	}
+
+	articles, err := s.articleRepo.FindPurgeable(cutoff, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if err := s.articleRepo.ForceDeleteArticle(article.ID); err != nil {
+			log.Printf("❌ Gagal purge artikel %d: %v", article.ID, err)
+			continue
+		}
+		utils.DeleteFileIfExists(article.ImageURL)
+	}
+
+	if total := len(products) + len(images) + len(stores) + len(articles); total > 0 {
+		log.Printf("🧹 Retention purge: %d produk, %d gambar, %d toko, %d artikel", len(products), len(images), len(stores), len(articles))
+	}
+	return nil
+}
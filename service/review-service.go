@@ -0,0 +1,349 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Batas foto ulasan; ukuran dan tipe file mengikuti gambar produk
+const (
+	maxReviewImages     = 5
+	reviewImageMaxSize  = 5 * 1024 * 1024
+	reviewImageDir      = "uploads/review-images"
+	reviewFlagThreshold = 3 // jumlah laporan sebelum ulasan disembunyikan otomatis
+)
+
+var (
+	ErrReviewNotFound  = errors.New("ulasan tidak ditemukan")
+	ErrReviewForbidden = errors.New("anda tidak memiliki akses ke ulasan ini")
+	ErrReviewExists    = errors.New("anda sudah memberi ulasan untuk produk ini")
+)
+
+type ReviewService interface {
+	GetProductReviews(productID int, filter repository.ReviewFilter, page, limit int) ([]entity.ProductReview, *utils.Pagination, error)
+	GetReviewSummary(productID int) (dto.ReviewSummary, error)
+	GetStoreReviews(storeID int, filter repository.ReviewFilter, page, limit int) ([]entity.ProductReview, *utils.Pagination, error)
+	GetModerationQueue(status string, page, limit int) ([]entity.ProductReview, *utils.Pagination, error)
+	CreateReview(c *gin.Context, product entity.Product, user entity.User, reviewDTO dto.CreateReviewDTO, files []*multipart.FileHeader) (entity.ProductReview, error)
+	UpdateReview(c *gin.Context, id uint64, user entity.User, reviewDTO dto.UpdateReviewDTO, files []*multipart.FileHeader) (entity.ProductReview, error)
+	DeleteReview(id uint64, user entity.User) error
+	ReplyReview(id uint64, user entity.User, replyDTO dto.ReviewReplyDTO) (entity.ProductReview, error)
+	FlagReview(id uint64, user entity.User, flagDTO dto.ReviewFlagDTO) error
+	ModerateReview(id uint64, moderateDTO dto.ModerateReviewDTO) (entity.ProductReview, error)
+}
+
+type reviewService struct {
+	reviewRepo          repository.ReviewRepository
+	productRepo         repository.ProductRepository
+	storeRepo           repository.StoreRepository
+	notificationService NotificationService
+}
+
+func NewReviewService(reviewRepo repository.ReviewRepository, productRepo repository.ProductRepository, storeRepo repository.StoreRepository, notificationService NotificationService) ReviewService {
+	return &reviewService{
+		reviewRepo:          reviewRepo,
+		productRepo:         productRepo,
+		storeRepo:           storeRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *reviewService) GetProductReviews(productID int, filter repository.ReviewFilter, page, limit int) ([]entity.ProductReview, *utils.Pagination, error) {
+	page, limit = discussionPage(page, limit)
+	reviews, total, err := s.reviewRepo.GetByProduct(productID, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reviews, utils.NewPagination(page, limit, total), nil
+}
+
+// GetReviewSummary menghitung rata-rata dan sebaran rating ulasan yang tampil
+func (s *reviewService) GetReviewSummary(productID int) (dto.ReviewSummary, error) {
+	counts, err := s.reviewRepo.GetRatingCounts(productID)
+	if err != nil {
+		return dto.ReviewSummary{}, err
+	}
+
+	summary := dto.ReviewSummary{Distribution: make(map[string]int64, 5)}
+	for rating := 1; rating <= 5; rating++ {
+		summary.Distribution[strconv.Itoa(rating)] = 0
+	}
+
+	var sum int64
+	for _, c := range counts {
+		summary.Distribution[strconv.Itoa(c.Rating)] = c.Count
+		summary.Count += c.Count
+		sum += int64(c.Rating) * c.Count
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(sum*100/summary.Count) / 100
+	}
+	return summary, nil
+}
+
+func (s *reviewService) GetStoreReviews(storeID int, filter repository.ReviewFilter, page, limit int) ([]entity.ProductReview, *utils.Pagination, error) {
+	page, limit = discussionPage(page, limit)
+	reviews, total, err := s.reviewRepo.GetByStore(storeID, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reviews, utils.NewPagination(page, limit, total), nil
+}
+
+func (s *reviewService) GetModerationQueue(status string, page, limit int) ([]entity.ProductReview, *utils.Pagination, error) {
+	status, err := moderationQueueStatus(status,
+		entity.ReviewStatusPending, entity.ReviewStatusHidden, entity.ReviewStatusPublished, repository.ReviewStatusFlagged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page, limit = discussionPage(page, limit)
+	reviews, total, err := s.reviewRepo.GetForModeration(status, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reviews, utils.NewPagination(page, limit, total), nil
+}
+
+// CreateReview menyimpan ulasan buyer. Satu buyer hanya boleh memberi satu
+// ulasan per produk, dan pemilik toko tidak dapat mengulas produknya sendiri.
+func (s *reviewService) CreateReview(c *gin.Context, product entity.Product, user entity.User, reviewDTO dto.CreateReviewDTO, files []*multipart.FileHeader) (entity.ProductReview, error) {
+	if product.Status != entity.ProductStatusPublished {
+		return entity.ProductReview{}, errors.New("produk belum dapat diulas")
+	}
+
+	store, err := s.storeRepo.FindByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		return entity.ProductReview{}, fmt.Errorf("toko tidak ditemukan: %v", err)
+	}
+	if uint64(store.UserID) == user.ID {
+		return entity.ProductReview{}, errors.New("anda tidak dapat mengulas produk toko sendiri")
+	}
+
+	if _, err := s.reviewRepo.FindByProductAndUser(product.ID, user.ID); err == nil {
+		return entity.ProductReview{}, ErrReviewExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ProductReview{}, err
+	}
+
+	if len(files) > maxReviewImages {
+		return entity.ProductReview{}, fmt.Errorf("maksimal %d foto per ulasan", maxReviewImages)
+	}
+	images, err := uploadReviewImages(c, files)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	review, err := s.reviewRepo.Create(entity.ProductReview{
+		ProductID: product.ID,
+		UserID:    user.ID,
+		Rating:    reviewDTO.Rating,
+		Body:      strings.TrimSpace(reviewDTO.Body),
+		Status:    entity.ReviewStatusPublished,
+		Images:    images,
+	})
+	if err != nil {
+		deleteReviewImages(images)
+		return entity.ProductReview{}, fmt.Errorf("gagal menyimpan ulasan: %v", err)
+	}
+
+	s.notificationService.Notify(store.UserID, entity.NotificationNewReview,
+		fmt.Sprintf("Ulasan baru untuk %s", product.Name),
+		fmt.Sprintf("%s memberi rating %d untuk %s.", user.Name, review.Rating, product.Name),
+		"/product/"+product.Slug)
+
+	return s.reviewRepo.FindByID(review.ID)
+}
+
+// UpdateReview mengubah ulasan milik user. Jumlah foto setelah perubahan
+// tetap dibatasi maxReviewImages.
+func (s *reviewService) UpdateReview(c *gin.Context, id uint64, user entity.User, reviewDTO dto.UpdateReviewDTO, files []*multipart.FileHeader) (entity.ProductReview, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+	if review.UserID != user.ID {
+		return entity.ProductReview{}, ErrReviewForbidden
+	}
+
+	deleteIDs, err := parseImageIDs(reviewDTO.DeleteImages)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	var deletedPaths []string
+	deleteSet := make(map[uint64]bool, len(deleteIDs))
+	for _, imageID := range deleteIDs {
+		deleteSet[imageID] = true
+	}
+	for _, img := range review.Images {
+		if deleteSet[img.ID] {
+			deletedPaths = append(deletedPaths, img.Image)
+		}
+	}
+
+	if len(review.Images)-len(deletedPaths)+len(files) > maxReviewImages {
+		return entity.ProductReview{}, fmt.Errorf("maksimal %d foto per ulasan", maxReviewImages)
+	}
+
+	newImages, err := uploadReviewImages(c, files)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	review.Rating = reviewDTO.Rating
+	review.Body = strings.TrimSpace(reviewDTO.Body)
+	if err := s.reviewRepo.Update(review, newImages, deleteIDs); err != nil {
+		deleteReviewImages(newImages)
+		return entity.ProductReview{}, fmt.Errorf("gagal mengupdate ulasan: %v", err)
+	}
+
+	for _, path := range deletedPaths {
+		utils.DeleteFileIfExists(path)
+	}
+
+	return s.reviewRepo.FindByID(review.ID)
+}
+
+// DeleteReview menghapus ulasan. Hanya penulis ulasan atau admin yang boleh.
+func (s *reviewService) DeleteReview(id uint64, user entity.User) error {
+	review, err := s.findReview(id)
+	if err != nil {
+		return err
+	}
+	if review.UserID != user.ID && user.Role != entity.RoleAdmin {
+		return ErrReviewForbidden
+	}
+
+	if err := s.reviewRepo.Delete(review); err != nil {
+		return fmt.Errorf("gagal menghapus ulasan: %v", err)
+	}
+	deleteReviewImages(review.Images)
+	return nil
+}
+
+// ReplyReview menyimpan balasan pemilik toko produk yang diulas
+func (s *reviewService) ReplyReview(id uint64, user entity.User, replyDTO dto.ReviewReplyDTO) (entity.ProductReview, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	product, err := s.productRepo.FindByID(review.ProductID)
+	if err != nil {
+		return entity.ProductReview{}, fmt.Errorf("produk tidak ditemukan: %v", err)
+	}
+	store, err := s.storeRepo.FindByID(strconv.Itoa(product.StoreID))
+	if err != nil {
+		return entity.ProductReview{}, fmt.Errorf("toko tidak ditemukan: %v", err)
+	}
+	if uint64(store.UserID) != user.ID {
+		return entity.ProductReview{}, ErrReviewForbidden
+	}
+
+	reply := strings.TrimSpace(replyDTO.Reply)
+	var repliedAt *time.Time
+	if reply != "" {
+		now := time.Now()
+		repliedAt = &now
+	}
+
+	if err := s.reviewRepo.UpdateReply(review.ID, reply, repliedAt); err != nil {
+		return entity.ProductReview{}, fmt.Errorf("gagal menyimpan balasan: %v", err)
+	}
+	return s.reviewRepo.FindByID(review.ID)
+}
+
+// FlagReview mencatat laporan ulasan. Penulis ulasan tidak dapat melaporkan
+// ulasannya sendiri.
+func (s *reviewService) FlagReview(id uint64, user entity.User, flagDTO dto.ReviewFlagDTO) error {
+	review, err := s.findReview(id)
+	if err != nil {
+		return err
+	}
+	if review.UserID == user.ID {
+		return errors.New("anda tidak dapat melaporkan ulasan sendiri")
+	}
+
+	return s.reviewRepo.AddFlag(entity.ProductReviewFlag{
+		ReviewID:  review.ID,
+		UserID:    user.ID,
+		Reason:    strings.TrimSpace(flagDTO.Reason),
+		CreatedAt: time.Now(),
+	}, reviewFlagThreshold)
+}
+
+func (s *reviewService) ModerateReview(id uint64, moderateDTO dto.ModerateReviewDTO) (entity.ProductReview, error) {
+	review, err := s.findReview(id)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	if err := s.reviewRepo.Moderate(review, moderateDTO.Status); err != nil {
+		return entity.ProductReview{}, fmt.Errorf("gagal memoderasi ulasan: %v", err)
+	}
+	return s.reviewRepo.FindByID(review.ID)
+}
+
+func (s *reviewService) findReview(id uint64) (entity.ProductReview, error) {
+	review, err := s.reviewRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ProductReview{}, ErrReviewNotFound
+	}
+	return review, err
+}
+
+// uploadReviewImages memvalidasi dan mengupload foto ulasan. Jika salah satu
+// gagal, foto yang sudah terupload dihapus kembali.
+func uploadReviewImages(c *gin.Context, files []*multipart.FileHeader) ([]entity.ProductReviewImage, error) {
+	for _, file := range files {
+		if err := utils.FileValidator(file, reviewImageMaxSize); err != nil {
+			return nil, fmt.Errorf("validasi foto %s gagal: %v", file.Filename, err)
+		}
+	}
+
+	var images []entity.ProductReviewImage
+	for _, file := range files {
+		imagePath, err := utils.UploadFile(c, file, reviewImageDir)
+		if err != nil {
+			deleteReviewImages(images)
+			return nil, fmt.Errorf("gagal mengupload foto: %v", err)
+		}
+		images = append(images, entity.ProductReviewImage{Image: imagePath})
+	}
+	return images, nil
+}
+
+func deleteReviewImages(images []entity.ProductReviewImage) {
+	for _, img := range images {
+		utils.DeleteFileIfExists(img.Image)
+	}
+}
+
+// parseImageIDs membaca daftar ID foto yang dipisah koma
+func parseImageIDs(raw string) ([]uint64, error) {
+	var ids []uint64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ID foto tidak valid: %s", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/search"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Batas periode dan jumlah baris laporan pencarian
+const (
+	defaultReportDays  = 30
+	maxReportDays      = 365
+	defaultReportLimit = 20
+	maxReportLimit     = 100
+)
+
+// Pencarian yang sama dari pelaku yang sama dalam searchDedupeWindow hanya
+// dicatat sekali; klik hanya diterima sampai searchClickWindow setelah pencarian
+const (
+	searchDedupeWindow = 30 * time.Minute
+	searchClickWindow  = 2 * time.Hour
+)
+
+var (
+	ErrSearchNotFound = errors.New("pencarian tidak ditemukan")
+	ErrSearchExpired  = errors.New("pencarian sudah kedaluwarsa")
+)
+
+// SearchAnalyticsService mencatat pencarian dan klik hasil pencarian, lalu
+// merangkumnya menjadi laporan untuk admin dan penjual
+type SearchAnalyticsService interface {
+	LogSearch(source, query string, resultCount int64, actorKey string) uint64
+	RecordClick(actorKey string, clickDTO dto.SearchClickDTO) error
+	GetReport(source string, days, limit int) (dto.SearchReport, error)
+	GetStoreReport(storeID, days, limit int) (dto.SearchReport, error)
+}
+
+type searchAnalyticsService struct {
+	searchLogRepo repository.SearchLogRepository
+	productRepo   repository.ProductRepository
+}
+
+func NewSearchAnalyticsService(searchLogRepo repository.SearchLogRepository, productRepo repository.ProductRepository) SearchAnalyticsService {
+	return &searchAnalyticsService{
+		searchLogRepo: searchLogRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// LogSearch mencatat satu pencarian dan mengembalikan ID-nya untuk dikirim
+// kembali saat buyer mengklik hasil. Pencarian ulang dengan kata kunci yang
+// sama dari pelaku yang sama dalam searchDedupeWindow memakai log yang sudah
+// ada. Kegagalan hanya dicatat ke log agar pencarian buyer tetap berjalan.
+func (s *searchAnalyticsService) LogSearch(source, query string, resultCount int64, actorKey string) uint64 {
+	normalized := truncate(search.NormalizeQuery(query), 255)
+	if normalized == "" || actorKey == "" {
+		return 0
+	}
+
+	now := time.Now()
+	if recent, err := s.searchLogRepo.FindRecent(source, normalized, actorKey, now.Add(-searchDedupeWindow)); err == nil {
+		return recent.ID
+	}
+
+	saved, err := s.searchLogRepo.Create(entity.SearchLog{
+		Source:          source,
+		Query:           truncate(query, 255),
+		NormalizedQuery: normalized,
+		ResultCount:     resultCount,
+		ActorKey:        actorKey,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		log.Printf("❌ Gagal mencatat pencarian %q: %v", query, err)
+		return 0
+	}
+	return saved.ID
+}
+
+// RecordClick mencatat klik pada hasil pencarian yang dibuat server untuk
+// pelaku yang sama. Klik ulang ke target yang sama dari satu pencarian tidak
+// dihitung lagi. Klik produk disimpan bersama ID tokonya untuk laporan penjual.
+func (s *searchAnalyticsService) RecordClick(actorKey string, clickDTO dto.SearchClickDTO) error {
+	searchLog, err := s.searchLogRepo.FindByID(clickDTO.SearchID)
+	if err != nil || actorKey == "" || searchLog.ActorKey != actorKey {
+		return ErrSearchNotFound
+	}
+	if time.Since(searchLog.CreatedAt) > searchClickWindow {
+		return ErrSearchExpired
+	}
+
+	click := entity.SearchClick{
+		SearchLogID:     searchLog.ID,
+		Source:          searchLog.Source,
+		NormalizedQuery: searchLog.NormalizedQuery,
+		TargetID:        clickDTO.TargetID,
+		CreatedAt:       time.Now(),
+	}
+
+	if searchLog.Source == entity.SearchSourceProduct {
+		product, err := s.productRepo.FindByID(int(clickDTO.TargetID))
+		if err != nil {
+			return fmt.Errorf("produk tidak ditemukan: %v", err)
+		}
+		click.StoreID = product.StoreID
+	}
+
+	return s.searchLogRepo.CreateClick(click)
+}
+
+// GetReport merangkum kata kunci teratas dan kata kunci tanpa hasil
+func (s *searchAnalyticsService) GetReport(source string, days, limit int) (dto.SearchReport, error) {
+	days, limit = reportBounds(days, limit)
+	since := time.Now().AddDate(0, 0, -days)
+
+	top, err := s.searchLogRepo.TopQueries(source, since, limit)
+	if err != nil {
+		return dto.SearchReport{}, err
+	}
+
+	zero, err := s.searchLogRepo.ZeroResultQueries(source, since, limit)
+	if err != nil {
+		return dto.SearchReport{}, err
+	}
+
+	return dto.SearchReport{
+		Source:            source,
+		Days:              days,
+		TopQueries:        toSearchQueryStats(top),
+		ZeroResultQueries: toSearchQueryStats(zero),
+	}, nil
+}
+
+// GetStoreReport adalah laporan pencarian produk untuk penjual: kata kunci
+// teratas dan tanpa hasil di seluruh katalog (untuk melihat celah produk),
+// ditambah kata kunci yang menghasilkan klik ke produk tokonya
+func (s *searchAnalyticsService) GetStoreReport(storeID, days, limit int) (dto.SearchReport, error) {
+	days, limit = reportBounds(days, limit)
+	report, err := s.GetReport(entity.SearchSourceProduct, days, limit)
+	if err != nil {
+		return dto.SearchReport{}, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	storeQueries, err := s.searchLogRepo.StoreClickQueries(storeID, since, limit)
+	if err != nil {
+		return dto.SearchReport{}, err
+	}
+
+	report.StoreQueries = toSearchQueryStats(storeQueries)
+	return report, nil
+}
+
+func reportBounds(days, limit int) (int, int) {
+	if days < 1 || days > maxReportDays {
+		days = defaultReportDays
+	}
+	if limit < 1 || limit > maxReportLimit {
+		limit = defaultReportLimit
+	}
+	return days, limit
+}
+
+func toSearchQueryStats(stats []entity.SearchQueryStat) []dto.SearchQueryStat {
+	result := make([]dto.SearchQueryStat, len(stats))
+	for i, stat := range stats {
+		result[i] = dto.SearchQueryStat{
+			Query:          stat.Query,
+			Searches:       stat.Searches,
+			Searchers:      stat.Searchers,
+			AvgResults:     stat.AvgResults,
+			Clicks:         stat.Clicks,
+			LastSearchedAt: stat.LastSearchedAt,
+		}
+		if stat.Searches > 0 {
+			result[i].ClickThrough = float64(stat.Clicks) / float64(stat.Searches)
+		}
+	}
+	return result
+}
+
+// truncate memotong teks agar muat di kolom VARCHAR
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakeSearchLogRepository struct {
+	repository.SearchLogRepository
+	logs   []entity.SearchLog
+	clicks map[[2]uint64]entity.SearchClick
+}
+
+func (r *fakeSearchLogRepository) Create(log entity.SearchLog) (entity.SearchLog, error) {
+	log.ID = uint64(len(r.logs) + 1)
+	r.logs = append(r.logs, log)
+	return log, nil
+}
+
+func (r *fakeSearchLogRepository) FindByID(id uint64) (entity.SearchLog, error) {
+	for _, log := range r.logs {
+		if log.ID == id {
+			return log, nil
+		}
+	}
+	return entity.SearchLog{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSearchLogRepository) FindRecent(source, normalizedQuery, actorKey string, since time.Time) (entity.SearchLog, error) {
+	for i := len(r.logs) - 1; i >= 0; i-- {
+		log := r.logs[i]
+		if log.Source == source && log.NormalizedQuery == normalizedQuery && log.ActorKey == actorKey && !log.CreatedAt.Before(since) {
+			return log, nil
+		}
+	}
+	return entity.SearchLog{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSearchLogRepository) CreateClick(click entity.SearchClick) error {
+	key := [2]uint64{click.SearchLogID, click.TargetID}
+	if _, exists := r.clicks[key]; !exists {
+		r.clicks[key] = click
+	}
+	return nil
+}
+
+func newSearchAnalyticsTestService() (*searchAnalyticsService, *fakeSearchLogRepository) {
+	repo := &fakeSearchLogRepository{clicks: map[[2]uint64]entity.SearchClick{}}
+	return &searchAnalyticsService{searchLogRepo: repo}, repo
+}
+
+func TestLogSearchDedupesPerActor(t *testing.T) {
+	s, repo := newSearchAnalyticsTestService()
+
+	first := s.LogSearch(entity.SearchSourceArticle, "Batik Tulis", 3, "ip:a")
+	again := s.LogSearch(entity.SearchSourceArticle, "batik  tulis", 3, "ip:a")
+	other := s.LogSearch(entity.SearchSourceArticle, "batik tulis", 3, "ip:b")
+
+	if first == 0 || again != first {
+		t.Errorf("pencarian ulang pelaku sama: id=%d, ingin %d", again, first)
+	}
+	if other == first {
+		t.Error("pelaku lain harus mendapat search log sendiri")
+	}
+	if len(repo.logs) != 2 {
+		t.Errorf("jumlah log = %d, ingin 2", len(repo.logs))
+	}
+
+	// Di luar jendela dedupe pencarian dicatat lagi
+	repo.logs[0].CreatedAt = time.Now().Add(-searchDedupeWindow - time.Minute)
+	if id := s.LogSearch(entity.SearchSourceArticle, "batik tulis", 3, "ip:a"); id == first {
+		t.Error("pencarian setelah jendela dedupe harus dicatat sebagai log baru")
+	}
+
+	if id := s.LogSearch(entity.SearchSourceArticle, "batik", 3, ""); id != 0 {
+		t.Error("pencarian tanpa pelaku tidak boleh dicatat")
+	}
+}
+
+func TestRecordClickRequiresServerSearch(t *testing.T) {
+	s, repo := newSearchAnalyticsTestService()
+	searchID := s.LogSearch(entity.SearchSourceArticle, "batik tulis", 3, "ip:a")
+
+	cases := map[string]struct {
+		actor string
+		click dto.SearchClickDTO
+		want  error
+	}{
+		"search tidak ada": {"ip:a", dto.SearchClickDTO{SearchID: 99, TargetID: 1}, ErrSearchNotFound},
+		"pelaku lain":      {"ip:b", dto.SearchClickDTO{SearchID: searchID, TargetID: 1}, ErrSearchNotFound},
+		"tanpa pelaku":     {"", dto.SearchClickDTO{SearchID: searchID, TargetID: 1}, ErrSearchNotFound},
+	}
+	for name, tc := range cases {
+		if err := s.RecordClick(tc.actor, tc.click); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, ingin %v", name, err, tc.want)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := s.RecordClick("ip:a", dto.SearchClickDTO{SearchID: searchID, TargetID: 7}); err != nil {
+			t.Fatalf("klik valid: %v", err)
+		}
+	}
+	click, ok := repo.clicks[[2]uint64{searchID, 7}]
+	if len(repo.clicks) != 1 || !ok {
+		t.Fatalf("klik berulang harus tercatat sekali, tercatat %d", len(repo.clicks))
+	}
+	if click.NormalizedQuery != "batik tulis" || click.Source != entity.SearchSourceArticle {
+		t.Errorf("kata kunci dan source harus diambil dari search log: %+v", click)
+	}
+
+	repo.logs[0].CreatedAt = time.Now().Add(-searchClickWindow - time.Minute)
+	if err := s.RecordClick("ip:a", dto.SearchClickDTO{SearchID: searchID, TargetID: 8}); !errors.Is(err, ErrSearchExpired) {
+		t.Errorf("klik setelah jendela: err = %v, ingin ErrSearchExpired", err)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"batik/entity"
+	"batik/repository"
+	"batik/search"
+	"log"
+)
+
+// indexBatchSize adalah jumlah baris yang dibaca per query saat membangun ulang index
+const indexBatchSize = 500
+
+// Bobot field dokumen produk dan artikel di search index
+const (
+	weightProductName     = 3
+	weightProductCategory = 2
+	weightProductStore    = 1.5
+	weightProductAttr     = 1.5
+	weightProductDesc     = 1
+	weightArticleTitle    = 3
+	weightArticleExcerpt  = 1.5
+	weightArticleBody     = 1
+)
+
+// SearchIndexService menjaga full-text index produk dan artikel tetap sinkron
+// dengan database. Kegagalan indexing hanya dicatat ke log agar tidak
+// menggagalkan request yang memicunya; Rebuild berkala memperbaiki selisihnya.
+type SearchIndexService interface {
+	Rebuild() error
+	IndexProduct(productID int)
+	RemoveProduct(productID int)
+	IndexStoreProducts(storeID int)
+	IndexProducts(productIDs []int)
+	IndexArticle(articleID uint64)
+	RemoveArticle(articleID uint64)
+}
+
+type searchIndexService struct {
+	productIndex search.SearchIndex
+	articleIndex search.SearchIndex
+	productRepo  repository.ProductRepository
+	articleRepo  repository.ArticleRepository
+}
+
+func NewSearchIndexService(productIndex, articleIndex search.SearchIndex, productRepo repository.ProductRepository, articleRepo repository.ArticleRepository) SearchIndexService {
+	return &searchIndexService{
+		productIndex: productIndex,
+		articleIndex: articleIndex,
+		productRepo:  productRepo,
+		articleRepo:  articleRepo,
+	}
+}
+
+// Rebuild membaca ulang semua produk dan artikel lalu mengganti isi index
+func (s *searchIndexService) Rebuild() error {
+	var productDocs []search.Document
+	afterID := 0
+	for {
+		products, err := s.productRepo.GetIndexableProducts(afterID, indexBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, p := range products {
+			productDocs = append(productDocs, productDocument(p))
+		}
+		if len(products) < indexBatchSize {
+			break
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	var articleDocs []search.Document
+	var afterArticleID uint64
+	for {
+		articles, err := s.articleRepo.GetIndexableArticles(afterArticleID, indexBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, a := range articles {
+			articleDocs = append(articleDocs, articleDocument(a))
+		}
+		if len(articles) < indexBatchSize {
+			break
+		}
+		afterArticleID = articles[len(articles)-1].ID
+	}
+
+	s.productIndex.Reset(productDocs)
+	s.articleIndex.Reset(articleDocs)
+	log.Printf("🔎 Search index dibangun: %d produk, %d artikel", len(productDocs), len(articleDocs))
+	return nil
+}
+
+func (s *searchIndexService) IndexProduct(productID int) {
+	products, err := s.productRepo.GetIndexableProductsByIDs([]int{productID})
+	if err != nil {
+		log.Printf("❌ Gagal meng-index produk %d: %v", productID, err)
+		return
+	}
+	if len(products) == 0 {
+		// Produk sudah dihapus
+		s.productIndex.Remove(productID)
+		return
+	}
+	s.productIndex.Index(productDocument(products[0]))
+}
+
+func (s *searchIndexService) RemoveProduct(productID int) {
+	s.productIndex.Remove(productID)
+}
+
+// IndexStoreProducts meng-index ulang produk toko, misalnya setelah nama toko berubah
+func (s *searchIndexService) IndexStoreProducts(storeID int) {
+	products, err := s.productRepo.GetIndexableProductsByStore(storeID)
+	if err != nil {
+		log.Printf("❌ Gagal meng-index produk toko %d: %v", storeID, err)
+		return
+	}
+	for _, p := range products {
+		s.productIndex.Index(productDocument(p))
+	}
+}
+
+// IndexProducts meng-index ulang beberapa produk sekaligus, misalnya setelah
+// kategorinya diganti nama atau dihapus
+func (s *searchIndexService) IndexProducts(productIDs []int) {
+	if len(productIDs) == 0 {
+		return
+	}
+	products, err := s.productRepo.GetIndexableProductsByIDs(productIDs)
+	if err != nil {
+		log.Printf("❌ Gagal meng-index %d produk: %v", len(productIDs), err)
+		return
+	}
+	for _, p := range products {
+		s.productIndex.Index(productDocument(p))
+	}
+}
+
+func (s *searchIndexService) IndexArticle(articleID uint64) {
+	article, err := s.articleRepo.GetArticleByID(articleID)
+	if err != nil {
+		// Artikel tidak ditemukan berarti sudah dihapus
+		s.articleIndex.Remove(int(articleID))
+		return
+	}
+	s.articleIndex.Index(articleDocument(article))
+}
+
+func (s *searchIndexService) RemoveArticle(articleID uint64) {
+	s.articleIndex.Remove(int(articleID))
+}
+
+func productDocument(p entity.ProductCard) search.Document {
+	fields := []search.Field{
+		{Text: p.Name, Weight: weightProductName},
+		{Text: p.CategoryName, Weight: weightProductCategory},
+		{Text: p.StoreName, Weight: weightProductStore},
+		{Text: p.Description, Weight: weightProductDesc},
+	}
+	for _, attr := range p.Attributes {
+		fields = append(fields, search.Field{Text: attr.Value, Weight: weightProductAttr})
+	}
+	return search.Document{ID: p.ID, Fields: fields}
+}
+
+func articleDocument(a entity.Article) search.Document {
+	return search.Document{
+		ID: int(a.ID),
+		Fields: []search.Field{
+			{Text: a.Title, Weight: weightArticleTitle},
+			{Text: a.Excerpt, Weight: weightArticleExcerpt},
+			{Text: a.Description, Weight: weightArticleBody},
+		},
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/shipping"
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+var ErrStoreOriginMissing = errors.New("toko belum melengkapi alamat pengiriman")
+
+// ShippingItem adalah satu produk dalam paket beserta jumlahnya
+type ShippingItem struct {
+	ProductID int
+	Quantity  int
+}
+
+// ShippingService menghitung ongkir dari alamat toko ke alamat buyer lewat
+// ShippingRateProvider. Setiap toko dikirim sebagai satu paket.
+type ShippingService interface {
+	QuoteProduct(slug string, query dto.ProductShippingQuery) (dto.StoreShippingQuotes, error)
+	QuoteCart(ownerKey string, destination dto.ShippingDestinationDTO) ([]dto.StoreShippingQuotes, error)
+	QuoteItems(storeID int, items []ShippingItem, destination dto.ShippingDestinationDTO) (shipping.Parcel, []shipping.Quote, error)
+}
+
+type shippingService struct {
+	provider    shipping.ShippingRateProvider
+	productRepo repository.ProductRepository
+	storeRepo   repository.StoreRepository
+	cartService CartService
+}
+
+func NewShippingService(provider shipping.ShippingRateProvider, productRepo repository.ProductRepository, storeRepo repository.StoreRepository, cartService CartService) ShippingService {
+	return &shippingService{
+		provider:    provider,
+		productRepo: productRepo,
+		storeRepo:   storeRepo,
+		cartService: cartService,
+	}
+}
+
+// QuoteProduct menghitung ongkir satu produk untuk ditampilkan sebelum
+// buyer memasukkannya ke keranjang
+func (s *shippingService) QuoteProduct(slug string, query dto.ProductShippingQuery) (dto.StoreShippingQuotes, error) {
+	product, err := s.productRepo.GetDetailProduct(slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.StoreShippingQuotes{}, ErrProductNotAvailable
+		}
+		return dto.StoreShippingQuotes{}, err
+	}
+
+	quantity := query.Quantity
+	if quantity < 1 {
+		quantity = 1
+	}
+	items := []ShippingItem{{ProductID: product.ID, Quantity: quantity}}
+	return s.quoteStore(product.StoreID, items, query.ShippingDestinationDTO)
+}
+
+// QuoteCart menghitung ongkir untuk setiap toko di keranjang. Produk yang
+// sudah tidak tersedia tidak ikut dihitung.
+func (s *shippingService) QuoteCart(ownerKey string, destination dto.ShippingDestinationDTO) ([]dto.StoreShippingQuotes, error) {
+	result := []dto.StoreShippingQuotes{}
+	if ownerKey == "" {
+		return result, nil
+	}
+	cart, err := s.cartService.GetCart(ownerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range cart.Stores {
+		var items []ShippingItem
+		for _, item := range group.Items {
+			items = append(items, ShippingItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		quotes, err := s.quoteStore(group.StoreID, items, destination)
+		if errors.Is(err, ErrStoreOriginMissing) {
+			// Toko lain tetap bisa dihitung
+			quotes = dto.StoreShippingQuotes{
+				StoreID:   group.StoreID,
+				StoreName: group.StoreName,
+				Quotes:    []dto.ShippingQuote{},
+				Error:     err.Error(),
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, quotes)
+	}
+	return result, nil
+}
+
+func (s *shippingService) quoteStore(storeID int, items []ShippingItem, destination dto.ShippingDestinationDTO) (dto.StoreShippingQuotes, error) {
+	store, err := s.storeRepo.FindByID(strconv.Itoa(storeID))
+	if err != nil {
+		return dto.StoreShippingQuotes{}, err
+	}
+	parcel, quotes, err := s.quote(store, items, destination)
+	if err != nil {
+		return dto.StoreShippingQuotes{}, err
+	}
+
+	result := dto.StoreShippingQuotes{
+		StoreID:    storeID,
+		StoreName:  store.Name,
+		OriginCity: store.City,
+		Weight:     parcel.ChargeableWeight(),
+		Quotes:     []dto.ShippingQuote{},
+	}
+	for _, q := range quotes {
+		result.Quotes = append(result.Quotes, dto.ShippingQuote{
+			Courier:     q.Courier,
+			CourierName: q.CourierName,
+			Service:     q.Service,
+			Description: q.Description,
+			Cost:        q.Cost,
+			EtdMinDays:  q.EtdMinDays,
+			EtdMaxDays:  q.EtdMaxDays,
+		})
+	}
+	return result, nil
+}
+
+// QuoteItems menghitung ongkir paket dari satu toko, dipakai saat checkout
+func (s *shippingService) QuoteItems(storeID int, items []ShippingItem, destination dto.ShippingDestinationDTO) (shipping.Parcel, []shipping.Quote, error) {
+	store, err := s.storeRepo.FindByID(strconv.Itoa(storeID))
+	if err != nil {
+		return shipping.Parcel{}, nil, err
+	}
+	return s.quote(store, items, destination)
+}
+
+func (s *shippingService) quote(store entity.Store, items []ShippingItem, destination dto.ShippingDestinationDTO) (shipping.Parcel, []shipping.Quote, error) {
+	origin := shipping.Address{Province: store.Province, City: store.City, PostalCode: store.PostalCode}
+	if !origin.Complete() {
+		return shipping.Parcel{}, nil, ErrStoreOriginMissing
+	}
+
+	parcel, err := s.buildParcel(items)
+	if err != nil {
+		return shipping.Parcel{}, nil, err
+	}
+	quotes, err := s.provider.Quote(shipping.RateRequest{
+		Origin: origin,
+		Destination: shipping.Address{
+			Province:   destination.Province,
+			City:       destination.City,
+			PostalCode: destination.PostalCode,
+		},
+		Parcel: parcel,
+	})
+	return parcel, quotes, err
+}
+
+// buildParcel menjumlahkan berat dan volume barang. Produk tanpa berat
+// dianggap seberat shipping.DefaultWeightGrams; produk tanpa dimensi lengkap
+// tidak menambah volume.
+func (s *shippingService) buildParcel(items []ShippingItem) (shipping.Parcel, error) {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	products, err := s.productRepo.GetPublicCardsByIDs(ids)
+	if err != nil {
+		return shipping.Parcel{}, err
+	}
+	byID := make(map[int]entity.ProductCard, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	var parcel shipping.Parcel
+	for _, item := range items {
+		p, ok := byID[item.ProductID]
+		if !ok {
+			continue
+		}
+		weight := p.Weight
+		if weight <= 0 {
+			weight = shipping.DefaultWeightGrams
+		}
+		parcel.WeightGrams += weight * item.Quantity
+		parcel.VolumeCm3 += p.Length * p.Width * p.Height * item.Quantity
+	}
+	return parcel, nil
+}
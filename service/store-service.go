@@ -4,10 +4,12 @@ import (
 	"batik/dto"
 	"batik/entity"
 	"batik/repository"
+	"batik/shipping"
 	"batik/utils"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,18 +22,24 @@ type StoreService interface {
 	GetStoreByID(id string) (entity.Store, error)
 	GetStoreByUserID(userID int) (entity.Store, error) 
 	GetAllStores() ([]entity.Store, error)   
-	GetAllStoreData(page, limit int, search string) ([]entity.Store, *utils.Pagination, error)          
+	GetAllStoreData(page, limit int, search string) ([]entity.Store, *utils.Pagination, error)
+	GetAllStoreDataCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Store, *utils.CursorPagination, error)          
+	DeleteStore(storeID string, userID string) error
+	RestoreStore(storeID string, user entity.User) (entity.Store, error)
+	GetStoreTrash(page, limit int) ([]entity.Store, *utils.Pagination, error)
 }
 
 // storeService is the implementation of StoreService interface
 type storeService struct {
 	storeRepository repository.StoreRepository
+	searchIndex     SearchIndexService
 }
 
 // NewStoreService creates a new instance of StoreService
-func NewStoreService(repo repository.StoreRepository) StoreService {
+func NewStoreService(repo repository.StoreRepository, searchIndex SearchIndexService) StoreService {
 	return &storeService{
 		storeRepository: repo,
+		searchIndex:     searchIndex,
 	}
 }
 
@@ -47,6 +55,11 @@ func (s *storeService) GetAllStores() ([]entity.Store, error) {
 
 // CreateStore transforms DTO to entity and creates a new store
 func (s *storeService) CreateStore(storeDTO dto.StoreDTO) (entity.Store, error) {
+	province, err := normalizeStoreProvince(storeDTO.Province)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
 	// Transform DTO to entity
 	now := time.Now()
 	store := entity.Store{
@@ -54,6 +67,10 @@ func (s *storeService) CreateStore(storeDTO dto.StoreDTO) (entity.Store, error)
 		Description: storeDTO.Description,
 		Whatsapp:    storeDTO.Whatsapp,
 		Alamat:      storeDTO.Alamat,
+		Province:    province,
+		City:        strings.TrimSpace(storeDTO.City),
+		District:    strings.TrimSpace(storeDTO.District),
+		PostalCode:  storeDTO.PostalCode,
 		UserID:      storeDTO.UserID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -75,6 +92,8 @@ func (s *storeService) Update(c *gin.Context, storeID string, userID string, sto
 		return entity.Store{}, fmt.Errorf("anda tidak memiliki akses untuk mengubah toko ini")
 	}
 
+	nameChanged := storeDTO.Name != "" && storeDTO.Name != store.Name
+
 	// Update data toko
 	if storeDTO.Name != "" {
 		store.Name = storeDTO.Name
@@ -89,6 +108,24 @@ func (s *storeService) Update(c *gin.Context, storeID string, userID string, sto
 		store.Alamat = storeDTO.Alamat
 	}
 
+	// Alamat terstruktur dipakai sebagai asal pengiriman
+	if storeDTO.Province != "" {
+		province, err := normalizeStoreProvince(storeDTO.Province)
+		if err != nil {
+			return entity.Store{}, err
+		}
+		store.Province = province
+	}
+	if city := strings.TrimSpace(storeDTO.City); city != "" {
+		store.City = city
+	}
+	if district := strings.TrimSpace(storeDTO.District); district != "" {
+		store.District = district
+	}
+	if storeDTO.PostalCode != "" {
+		store.PostalCode = storeDTO.PostalCode
+	}
+
 	// ✅ PERBAIKAN: Tambah logging dan debugging untuk file upload
 	log.Printf("🔍 Checking for avatar file...")
 	
@@ -174,10 +211,28 @@ func (s *storeService) Update(c *gin.Context, storeID string, userID string, sto
 	log.Printf("✅ Store updated successfully in database")
 	log.Printf("Updated store data: Avatar=%s, Banner=%s", updatedStore.Avatar, updatedStore.Banner)
 
+	// Nama toko ikut di-index pada dokumen produk
+	if nameChanged {
+		s.searchIndex.IndexStoreProducts(int(updatedStore.ID))
+	}
+
 	return updatedStore, nil
 }
 
 
+// normalizeStoreProvince memastikan nama provinsi dikenali perhitungan
+// ongkir. Provinsi kosong dibiarkan kosong.
+func normalizeStoreProvince(province string) (string, error) {
+	province = strings.TrimSpace(province)
+	if province == "" {
+		return "", nil
+	}
+	if _, ok := shipping.NormalizeProvince(province); !ok {
+		return "", fmt.Errorf("%w: %s", shipping.ErrUnknownProvince, province)
+	}
+	return province, nil
+}
+
 func (s *storeService) GetStoreByID(id string) (entity.Store, error) {
 	store, err := s.storeRepository.FindByID(id)
 
@@ -204,4 +259,61 @@ func (s *storeService) GetAllStoreData(page, limit int, search string) ([]entity
 	pagination := utils.NewPagination(page, limit, total)
 	
 	return users, pagination, nil
- }
\ No newline at end of file
+ }
+
+func (s *storeService) GetAllStoreDataCursor(cursor *utils.Cursor, limit int, search string) ([]entity.Store, *utils.CursorPagination, error) {
+	if limit < 1 || limit > utils.MaxCursorLimit {
+		limit = 10
+	}
+	return s.storeRepository.GetAllStoreDataCursor(cursor, limit, search)
+}
+
+// DeleteStore memindahkan toko ke trash. Produk toko ikut tersembunyi dari
+// katalog publik selama toko berada di trash.
+func (s *storeService) DeleteStore(storeID string, userID string) error {
+	store, err := s.storeRepository.FindByID(storeID)
+	if err != nil {
+		return fmt.Errorf("toko tidak ditemukan: %v", err)
+	}
+
+	if strconv.Itoa(store.UserID) != userID {
+		return fmt.Errorf("anda tidak memiliki akses untuk menghapus toko ini")
+	}
+
+	return s.storeRepository.Delete(store.ID)
+}
+
+// RestoreStore mengembalikan toko dari trash (pemilik toko atau admin)
+func (s *storeService) RestoreStore(storeID string, user entity.User) (entity.Store, error) {
+	store, err := s.storeRepository.FindDeletedByID(storeID)
+	if err != nil {
+		return entity.Store{}, fmt.Errorf("toko tidak ditemukan di trash: %v", err)
+	}
+
+	if uint64(store.UserID) != user.ID && user.Role != entity.RoleAdmin {
+		return entity.Store{}, fmt.Errorf("anda tidak memiliki akses untuk mengembalikan toko ini")
+	}
+
+	if err := s.storeRepository.Restore(store.ID); err != nil {
+		return entity.Store{}, fmt.Errorf("gagal mengembalikan toko: %v", err)
+	}
+
+	return s.storeRepository.FindByID(storeID)
+}
+
+func (s *storeService) GetStoreTrash(page, limit int) ([]entity.Store, *utils.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	stores, total, err := s.storeRepository.GetTrash(page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := utils.NewPagination(page, limit, total)
+	return stores, pagination, nil
+}
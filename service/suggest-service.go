@@ -0,0 +1,110 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/search"
+	"log"
+	"strings"
+	"time"
+)
+
+// Batas jumlah saran per bagian respons
+const (
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+	popularQueryLimit   = 5
+)
+
+// Kata kunci populer diambil dari search log beberapa hari terakhir
+const (
+	popularQueryDays = 30
+	popularQueryPool = 200
+	// Kata kunci baru disarankan setelah dicari beberapa pelaku berbeda agar
+	// satu script tidak bisa mengisi daftar pencarian populer
+	popularMinSearchers = 3
+)
+
+// SuggestService memberi saran pencarian dari trie di memori yang dibangun
+// ulang berkala dari katalog (lihat Refresh)
+type SuggestService interface {
+	Refresh() error
+	Suggest(query string, limit int) dto.SuggestResponse
+}
+
+type suggestService struct {
+	suggestionRepo repository.SuggestionRepository
+	searchLogRepo  repository.SearchLogRepository
+	suggester      *search.Suggester
+}
+
+func NewSuggestService(suggestionRepo repository.SuggestionRepository, searchLogRepo repository.SearchLogRepository) SuggestService {
+	return &suggestService{
+		suggestionRepo: suggestionRepo,
+		searchLogRepo:  searchLogRepo,
+		suggester:      search.NewSuggester(),
+	}
+}
+
+// Refresh membaca ulang nama produk, kategori, toko, dan motif dari database,
+// serta kata kunci populer dari search log
+func (s *suggestService) Refresh() error {
+	suggestions, err := s.suggestionRepo.GetSuggestions()
+	if err != nil {
+		return err
+	}
+	for i := range suggestions {
+		// Nilai motif disimpan sebagai slug ("mega-mendung")
+		if suggestions[i].Kind == search.SuggestionMotif {
+			suggestions[i].Text = strings.ReplaceAll(suggestions[i].Text, "-", " ")
+		}
+	}
+	s.suggester.Reset(suggestions)
+
+	since := time.Now().AddDate(0, 0, -popularQueryDays)
+	stats, err := s.searchLogRepo.TopQueries(entity.SearchSourceProduct, since, popularQueryPool)
+	if err != nil {
+		return err
+	}
+	popular := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		// Kata kunci yang tidak menemukan produk tidak disarankan
+		if stat.AvgResults > 0 && stat.Searchers >= popularMinSearchers {
+			popular[stat.Query] = int(stat.Searchers)
+		}
+	}
+	s.suggester.SetPopular(popular)
+	log.Printf("🔎 Saran pencarian dibangun: %d entri", len(suggestions))
+	return nil
+}
+
+func (s *suggestService) Suggest(query string, limit int) dto.SuggestResponse {
+	if limit < 1 || limit > maxSuggestLimit {
+		limit = defaultSuggestLimit
+	}
+
+	query = strings.TrimSpace(query)
+	response := dto.SuggestResponse{
+		Query:          query,
+		Completions:    []dto.SuggestionItem{},
+		PopularQueries: []string{},
+	}
+
+	completions := s.suggester.Complete(query, limit)
+	if query != "" {
+		response.DidYouMean = s.suggester.DidYouMean(query)
+		// Tanpa hasil untuk query asli, tampilkan pelengkap dari hasil koreksi
+		if len(completions) == 0 && response.DidYouMean != "" {
+			completions = s.suggester.Complete(response.DidYouMean, limit)
+		}
+	}
+	for _, c := range completions {
+		response.Completions = append(response.Completions, dto.SuggestionItem{Text: c.Text, Kind: c.Kind})
+	}
+
+	for _, p := range s.suggester.Popular(query, popularQueryLimit) {
+		response.PopularQueries = append(response.PopularQueries, p.Text)
+	}
+	return response
+}
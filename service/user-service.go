@@ -9,6 +9,7 @@ import (
 
 type UserService interface {
 	GetAllUser(page, limit int, search string) ([]entity.User, *utils.Pagination, error)
+	GetAllUserCursor(cursor *utils.Cursor, limit int, search string) ([]entity.User, *utils.CursorPagination, error)
 }
 
 type userService struct {
@@ -40,4 +41,11 @@ func NewUserService(userRepo repository.UserRepository) UserService {
 	pagination := utils.NewPagination(page, limit, total)
 	
 	return users, pagination, nil
- }
\ No newline at end of file
+ }
+
+func (s userService) GetAllUserCursor(cursor *utils.Cursor, limit int, search string) ([]entity.User, *utils.CursorPagination, error) {
+	if limit < 1 || limit > utils.MaxCursorLimit {
+		limit = 10
+	}
+	return s.userRepository.GetAllUserCursor(cursor, limit, search)
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVoucherNotFound  = errors.New("voucher tidak ditemukan")
+	ErrVoucherForbidden = errors.New("anda tidak memiliki akses ke voucher ini")
+	ErrVoucherExhausted = errors.New("kuota voucher sudah habis")
+	ErrVoucherUserLimit = errors.New("batas pemakaian voucher untuk akun Anda sudah tercapai")
+)
+
+// VoucherService mengelola voucher kode toko dan platform. ValidateVoucher
+// hanya menghitung potongan; kuota baru terpakai lewat Redeem saat order
+// dibuat dan kembali lewat ReleaseByReference jika order batal.
+type VoucherService interface {
+	GetStoreVouchers(storeID int) ([]entity.Voucher, error)
+	CreateStoreVoucher(store entity.Store, user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error)
+	UpdateStoreVoucher(id uint64, user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error)
+	DeleteStoreVoucher(id uint64, user entity.User) error
+	GetPlatformVouchers() ([]entity.Voucher, error)
+	CreatePlatformVoucher(user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error)
+	UpdatePlatformVoucher(id uint64, voucherDTO dto.VoucherDTO) (entity.Voucher, error)
+	DeletePlatformVoucher(id uint64) error
+	ValidateVoucher(user entity.User, validateDTO dto.ValidateVoucherDTO) (dto.VoucherQuote, error)
+	Redeem(user entity.User, code string, storeID int, subtotal float64, reference string) (entity.VoucherRedemption, error)
+	ReleaseByReference(reference string) error
+	GetStoreReport(storeID, days int) (dto.VoucherReport, error)
+	GetPlatformReport(days int) (dto.VoucherReport, error)
+	GetRedemptions(id uint64, user entity.User, page, limit int) ([]entity.VoucherRedemption, *utils.Pagination, error)
+}
+
+type voucherService struct {
+	voucherRepo repository.VoucherRepository
+	storeRepo   repository.StoreRepository
+}
+
+func NewVoucherService(voucherRepo repository.VoucherRepository, storeRepo repository.StoreRepository) VoucherService {
+	return &voucherService{
+		voucherRepo: voucherRepo,
+		storeRepo:   storeRepo,
+	}
+}
+
+func (s *voucherService) GetStoreVouchers(storeID int) ([]entity.Voucher, error) {
+	return s.voucherRepo.GetByStore(storeID)
+}
+
+func (s *voucherService) CreateStoreVoucher(store entity.Store, user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error) {
+	storeID := int(store.ID)
+	voucher := entity.Voucher{
+		StoreID:   &storeID,
+		CreatedBy: user.ID,
+	}
+	if err := s.applyVoucherDTO(&voucher, voucherDTO); err != nil {
+		return entity.Voucher{}, err
+	}
+	return s.voucherRepo.Create(voucher)
+}
+
+func (s *voucherService) UpdateStoreVoucher(id uint64, user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error) {
+	voucher, err := s.ownedVoucher(id, user)
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	if voucher.IsPlatform() {
+		return entity.Voucher{}, ErrVoucherForbidden
+	}
+	if err := s.applyVoucherDTO(&voucher, voucherDTO); err != nil {
+		return entity.Voucher{}, err
+	}
+	return s.voucherRepo.Update(voucher)
+}
+
+func (s *voucherService) DeleteStoreVoucher(id uint64, user entity.User) error {
+	voucher, err := s.ownedVoucher(id, user)
+	if err != nil {
+		return err
+	}
+	if voucher.IsPlatform() {
+		return ErrVoucherForbidden
+	}
+	return s.deleteVoucher(voucher)
+}
+
+func (s *voucherService) GetPlatformVouchers() ([]entity.Voucher, error) {
+	return s.voucherRepo.GetPlatform()
+}
+
+func (s *voucherService) CreatePlatformVoucher(user entity.User, voucherDTO dto.VoucherDTO) (entity.Voucher, error) {
+	voucher := entity.Voucher{CreatedBy: user.ID}
+	if err := s.applyVoucherDTO(&voucher, voucherDTO); err != nil {
+		return entity.Voucher{}, err
+	}
+	return s.voucherRepo.Create(voucher)
+}
+
+func (s *voucherService) UpdatePlatformVoucher(id uint64, voucherDTO dto.VoucherDTO) (entity.Voucher, error) {
+	voucher, err := s.platformVoucher(id)
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	if err := s.applyVoucherDTO(&voucher, voucherDTO); err != nil {
+		return entity.Voucher{}, err
+	}
+	return s.voucherRepo.Update(voucher)
+}
+
+func (s *voucherService) DeletePlatformVoucher(id uint64) error {
+	voucher, err := s.platformVoucher(id)
+	if err != nil {
+		return err
+	}
+	return s.deleteVoucher(voucher)
+}
+
+// deleteVoucher hanya menghapus voucher yang belum pernah dipakai agar
+// laporan pemakaian tetap utuh. Voucher yang sudah dipakai cukup dinonaktifkan.
+func (s *voucherService) deleteVoucher(voucher entity.Voucher) error {
+	_, total, err := s.voucherRepo.GetRedemptions(voucher.ID, 1, 1)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return errors.New("voucher yang sudah pernah dipakai tidak bisa dihapus, nonaktifkan saja")
+	}
+	return s.voucherRepo.Delete(voucher.ID)
+}
+
+func (s *voucherService) findVoucher(id uint64) (entity.Voucher, error) {
+	voucher, err := s.voucherRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Voucher{}, ErrVoucherNotFound
+		}
+		return entity.Voucher{}, err
+	}
+	return voucher, nil
+}
+
+func (s *voucherService) platformVoucher(id uint64) (entity.Voucher, error) {
+	voucher, err := s.findVoucher(id)
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	if !voucher.IsPlatform() {
+		return entity.Voucher{}, ErrVoucherNotFound
+	}
+	return voucher, nil
+}
+
+// ownedVoucher mengambil voucher yang boleh dikelola user: voucher toko oleh
+// pemilik toko, voucher platform oleh admin
+func (s *voucherService) ownedVoucher(id uint64, user entity.User) (entity.Voucher, error) {
+	voucher, err := s.findVoucher(id)
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+
+	if voucher.IsPlatform() {
+		if user.Role != entity.RoleAdmin {
+			return entity.Voucher{}, ErrVoucherForbidden
+		}
+		return voucher, nil
+	}
+
+	store, err := s.storeRepo.FindByID(strconv.Itoa(*voucher.StoreID))
+	if err != nil || uint64(store.UserID) != user.ID {
+		return entity.Voucher{}, ErrVoucherForbidden
+	}
+	return voucher, nil
+}
+
+// applyVoucherDTO memvalidasi input dan mengisinya ke voucher
+func (s *voucherService) applyVoucherDTO(voucher *entity.Voucher, voucherDTO dto.VoucherDTO) error {
+	switch voucherDTO.DiscountType {
+	case entity.PromotionTypePercent:
+		if voucherDTO.DiscountValue < minPromotionPercent || voucherDTO.DiscountValue > maxPromotionPercent {
+			return fmt.Errorf("diskon persen harus antara %d dan %d", minPromotionPercent, maxPromotionPercent)
+		}
+	case entity.PromotionTypeFixed:
+		if voucherDTO.MaxDiscount != nil {
+			return errors.New("max_discount hanya untuk diskon persen")
+		}
+		if voucherDTO.MinSpend > 0 && voucherDTO.DiscountValue > voucherDTO.MinSpend {
+			return errors.New("potongan voucher tidak boleh melebihi minimal belanja")
+		}
+	}
+
+	if !voucherDTO.ExpiresAt.After(voucherDTO.StartsAt) {
+		return errors.New("waktu kedaluwarsa harus setelah waktu mulai")
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(voucherDTO.Code))
+	if s.voucherRepo.CodeExists(code, voucher.ID) {
+		return errors.New("kode voucher sudah dipakai")
+	}
+
+	if voucherDTO.UsageLimit != nil && *voucherDTO.UsageLimit < voucher.UsedCount {
+		return fmt.Errorf("batas pemakaian tidak boleh kurang dari pemakaian saat ini (%d)", voucher.UsedCount)
+	}
+
+	voucher.Code = code
+	voucher.Description = voucherDTO.Description
+	voucher.DiscountType = voucherDTO.DiscountType
+	voucher.DiscountValue = voucherDTO.DiscountValue
+	voucher.MaxDiscount = voucherDTO.MaxDiscount
+	voucher.MinSpend = voucherDTO.MinSpend
+	voucher.PerUserLimit = voucherDTO.PerUserLimit
+	if voucher.PerUserLimit == 0 {
+		voucher.PerUserLimit = 1
+	}
+	voucher.UsageLimit = voucherDTO.UsageLimit
+	voucher.StartsAt = voucherDTO.StartsAt
+	voucher.ExpiresAt = voucherDTO.ExpiresAt
+	voucher.Active = voucherDTO.Active == nil || *voucherDTO.Active
+	return nil
+}
+
+// checkVoucher memeriksa semua aturan voucher untuk belanja subtotal di toko
+// storeID dan mengembalikan potongannya. userUses adalah jumlah pemakaian
+// voucher oleh user yang belum dilepas.
+func checkVoucher(voucher entity.Voucher, storeID int, subtotal float64, userUses int64, now time.Time) (float64, error) {
+	switch {
+	case !voucher.Active:
+		return 0, errors.New("voucher tidak aktif")
+	case now.Before(voucher.StartsAt):
+		return 0, errors.New("voucher belum berlaku")
+	case !now.Before(voucher.ExpiresAt):
+		return 0, errors.New("voucher sudah kedaluwarsa")
+	case !voucher.IsPlatform() && *voucher.StoreID != storeID:
+		return 0, errors.New("voucher tidak berlaku untuk toko ini")
+	case voucher.UsageLimit != nil && voucher.UsedCount >= *voucher.UsageLimit:
+		return 0, ErrVoucherExhausted
+	case userUses >= int64(voucher.PerUserLimit):
+		return 0, ErrVoucherUserLimit
+	case subtotal < voucher.MinSpend:
+		return 0, fmt.Errorf("minimal belanja untuk voucher ini %s", formatRupiah(voucher.MinSpend))
+	}
+	return voucher.Discount(subtotal), nil
+}
+
+// ValidateVoucher menghitung potongan voucher tanpa memakai kuota
+func (s *voucherService) ValidateVoucher(user entity.User, validateDTO dto.ValidateVoucherDTO) (dto.VoucherQuote, error) {
+	voucher, err := s.voucherRepo.FindByCode(strings.ToUpper(strings.TrimSpace(validateDTO.Code)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.VoucherQuote{}, ErrVoucherNotFound
+		}
+		return dto.VoucherQuote{}, err
+	}
+
+	userUses, err := s.voucherRepo.CountUserRedemptions(voucher.ID, user.ID)
+	if err != nil {
+		return dto.VoucherQuote{}, err
+	}
+
+	discount, err := checkVoucher(voucher, validateDTO.StoreID, validateDTO.Subtotal, userUses, time.Now())
+	if err != nil {
+		return dto.VoucherQuote{}, err
+	}
+
+	return dto.VoucherQuote{
+		Code:     voucher.Code,
+		StoreID:  voucher.StoreID,
+		Subtotal: validateDTO.Subtotal,
+		Discount: discount,
+		Total:    validateDTO.Subtotal - discount,
+	}, nil
+}
+
+// Redeem memakai voucher untuk transaksi reference. Semua aturan diperiksa
+// ulang di dalam transaksi yang mengunci voucher, sehingga aman dipanggil
+// bersamaan. Pemanggilan ulang dengan reference yang sama tidak memakai kuota lagi.
+func (s *voucherService) Redeem(user entity.User, code string, storeID int, subtotal float64, reference string) (entity.VoucherRedemption, error) {
+	redemption := entity.VoucherRedemption{
+		UserID:    user.ID,
+		Reference: reference,
+		Subtotal:  subtotal,
+	}
+
+	redemption, err := s.voucherRepo.Redeem(strings.ToUpper(strings.TrimSpace(code)), redemption,
+		func(voucher entity.Voucher, userUses int64) (float64, error) {
+			return checkVoucher(voucher, storeID, subtotal, userUses, time.Now())
+		})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.VoucherRedemption{}, ErrVoucherNotFound
+	}
+	return redemption, err
+}
+
+// ReleaseByReference mengembalikan kuota voucher dari transaksi yang batal
+func (s *voucherService) ReleaseByReference(reference string) error {
+	released, err := s.voucherRepo.ReleaseByReference(reference)
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		log.Printf("🧩 %d pemakaian voucher untuk %s dilepas", released, reference)
+	}
+	return nil
+}
+
+func (s *voucherService) GetStoreReport(storeID, days int) (dto.VoucherReport, error) {
+	return s.report(&storeID, days)
+}
+
+func (s *voucherService) GetPlatformReport(days int) (dto.VoucherReport, error) {
+	return s.report(nil, days)
+}
+
+func (s *voucherService) report(storeID *int, days int) (dto.VoucherReport, error) {
+	days, _ = reportBounds(days, 0)
+	stats, err := s.voucherRepo.GetStats(storeID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return dto.VoucherReport{}, err
+	}
+
+	report := dto.VoucherReport{
+		Days:     days,
+		Vouchers: make([]dto.VoucherStat, len(stats)),
+	}
+	for i, stat := range stats {
+		report.Redemptions += stat.Redemptions
+		report.TotalDiscount += stat.TotalDiscount
+		report.Vouchers[i] = dto.VoucherStat{
+			VoucherID:      stat.VoucherID,
+			Code:           stat.Code,
+			Redemptions:    stat.Redemptions,
+			Buyers:         stat.Buyers,
+			TotalDiscount:  stat.TotalDiscount,
+			TotalSubtotal:  stat.TotalSubtotal,
+			LastRedeemedAt: stat.LastRedeemedAt,
+		}
+	}
+	return report, nil
+}
+
+// GetRedemptions menampilkan riwayat pemakaian voucher untuk pengelolanya
+func (s *voucherService) GetRedemptions(id uint64, user entity.User, page, limit int) ([]entity.VoucherRedemption, *utils.Pagination, error) {
+	if _, err := s.ownedVoucher(id, user); err != nil {
+		return nil, nil, err
+	}
+
+	page, limit = listPage(page, limit)
+	redemptions, total, err := s.voucherRepo.GetRedemptions(id, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return redemptions, utils.NewPagination(page, limit, total), nil
+}
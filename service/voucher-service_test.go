@@ -0,0 +1,249 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeVoucherRepository menyimpan voucher dan pemakaiannya di memori dengan
+// aturan yang sama seperti voucherRepository.Redeem/ReleaseByReference
+type fakeVoucherRepository struct {
+	repository.VoucherRepository
+	vouchers    map[string]*entity.Voucher
+	redemptions []entity.VoucherRedemption
+}
+
+func newFakeVoucherRepository(vouchers ...entity.Voucher) *fakeVoucherRepository {
+	repo := &fakeVoucherRepository{vouchers: make(map[string]*entity.Voucher)}
+	for i := range vouchers {
+		repo.vouchers[vouchers[i].Code] = &vouchers[i]
+	}
+	return repo
+}
+
+func (r *fakeVoucherRepository) FindByCode(code string) (entity.Voucher, error) {
+	voucher, ok := r.vouchers[code]
+	if !ok {
+		return entity.Voucher{}, gorm.ErrRecordNotFound
+	}
+	return *voucher, nil
+}
+
+func (r *fakeVoucherRepository) CountUserRedemptions(voucherID, userID uint64) (int64, error) {
+	var count int64
+	for _, rd := range r.redemptions {
+		if rd.VoucherID == voucherID && rd.UserID == userID && rd.Status == entity.RedemptionRedeemed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeVoucherRepository) Redeem(code string, redemption entity.VoucherRedemption, check repository.VoucherCheck) (entity.VoucherRedemption, error) {
+	voucher, ok := r.vouchers[code]
+	if !ok {
+		return entity.VoucherRedemption{}, gorm.ErrRecordNotFound
+	}
+	for _, rd := range r.redemptions {
+		if rd.VoucherID == voucher.ID && rd.Reference == redemption.Reference {
+			if rd.Status == entity.RedemptionReleased {
+				return entity.VoucherRedemption{}, repository.ErrRedemptionReleased
+			}
+			return rd, nil
+		}
+	}
+
+	userUses, _ := r.CountUserRedemptions(voucher.ID, redemption.UserID)
+	discount, err := check(*voucher, userUses)
+	if err != nil {
+		return entity.VoucherRedemption{}, err
+	}
+
+	redemption.ID = uint64(len(r.redemptions) + 1)
+	redemption.VoucherID = voucher.ID
+	redemption.Discount = discount
+	redemption.Status = entity.RedemptionRedeemed
+	r.redemptions = append(r.redemptions, redemption)
+	voucher.UsedCount++
+	return redemption, nil
+}
+
+func (r *fakeVoucherRepository) ReleaseByReference(reference string) (int, error) {
+	released := 0
+	for i := range r.redemptions {
+		rd := &r.redemptions[i]
+		if rd.Reference != reference || rd.Status != entity.RedemptionRedeemed {
+			continue
+		}
+		rd.Status = entity.RedemptionReleased
+		for _, voucher := range r.vouchers {
+			if voucher.ID == rd.VoucherID && voucher.UsedCount > 0 {
+				voucher.UsedCount--
+			}
+		}
+		released++
+	}
+	return released, nil
+}
+
+func testVoucher() entity.Voucher {
+	now := time.Now()
+	return entity.Voucher{
+		ID:            1,
+		Code:          "HEMAT10",
+		StoreID:       intPtr(7),
+		DiscountType:  entity.PromotionTypePercent,
+		DiscountValue: 10,
+		MaxDiscount:   floatPtr(50000),
+		MinSpend:      100000,
+		PerUserLimit:  1,
+		StartsAt:      now.Add(-time.Hour),
+		ExpiresAt:     now.Add(time.Hour),
+		Active:        true,
+	}
+}
+
+func TestVoucherDiscount(t *testing.T) {
+	cases := []struct {
+		name     string
+		voucher  entity.Voucher
+		subtotal float64
+		want     float64
+	}{
+		{"persen", entity.Voucher{DiscountType: entity.PromotionTypePercent, DiscountValue: 10}, 150000, 15000},
+		{"persen dibatasi max_discount", entity.Voucher{DiscountType: entity.PromotionTypePercent, DiscountValue: 10, MaxDiscount: floatPtr(20000)}, 500000, 20000},
+		{"persen dibulatkan", entity.Voucher{DiscountType: entity.PromotionTypePercent, DiscountValue: 15}, 33333, 5000},
+		{"nominal", entity.Voucher{DiscountType: entity.PromotionTypeFixed, DiscountValue: 25000}, 150000, 25000},
+		{"nominal tidak melebihi subtotal", entity.Voucher{DiscountType: entity.PromotionTypeFixed, DiscountValue: 25000}, 20000, 20000},
+		{"subtotal nol", entity.Voucher{DiscountType: entity.PromotionTypeFixed, DiscountValue: 25000}, 0, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.voucher.Discount(tc.subtotal); got != tc.want {
+			t.Errorf("%s: Discount = %v, ingin %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// errAny menandai kasus yang cukup ditolak dengan error apa pun
+var errAny = errors.New("error apa pun")
+
+func TestCheckVoucher(t *testing.T) {
+	now := time.Now()
+	modify := func(fn func(v *entity.Voucher)) entity.Voucher {
+		v := testVoucher()
+		fn(&v)
+		return v
+	}
+
+	cases := []struct {
+		name     string
+		voucher  entity.Voucher
+		storeID  int
+		subtotal float64
+		userUses int64
+		want     float64
+		wantErr  error
+	}{
+		{"berlaku", testVoucher(), 7, 200000, 0, 20000, nil},
+		{"tidak aktif", modify(func(v *entity.Voucher) { v.Active = false }), 7, 200000, 0, 0, errAny},
+		{"belum berlaku", modify(func(v *entity.Voucher) { v.StartsAt = now.Add(time.Minute) }), 7, 200000, 0, 0, errAny},
+		{"kedaluwarsa", modify(func(v *entity.Voucher) { v.ExpiresAt = now }), 7, 200000, 0, 0, errAny},
+		{"toko lain", testVoucher(), 8, 200000, 0, 0, errAny},
+		{"voucher platform di toko mana pun", modify(func(v *entity.Voucher) { v.StoreID = nil }), 8, 200000, 0, 20000, nil},
+		{"kuota habis", modify(func(v *entity.Voucher) { v.UsageLimit = intPtr(5); v.UsedCount = 5 }), 7, 200000, 0, 0, ErrVoucherExhausted},
+		{"kuota tersisa", modify(func(v *entity.Voucher) { v.UsageLimit = intPtr(5); v.UsedCount = 4 }), 7, 200000, 0, 20000, nil},
+		{"batas per user", testVoucher(), 7, 200000, 1, 0, ErrVoucherUserLimit},
+		{"minimal belanja", testVoucher(), 7, 99999, 0, 0, errAny},
+	}
+	for _, tc := range cases {
+		got, err := checkVoucher(tc.voucher, tc.storeID, tc.subtotal, tc.userUses, now)
+		switch {
+		case tc.wantErr == nil && err != nil:
+			t.Errorf("%s: error tidak diharapkan: %v", tc.name, err)
+		case tc.wantErr == errAny && err == nil:
+			t.Errorf("%s: seharusnya ditolak", tc.name)
+		case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+			t.Errorf("%s: err = %v, ingin %v", tc.name, err, tc.wantErr)
+		case got != tc.want:
+			t.Errorf("%s: potongan = %v, ingin %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestVoucherRedeemAndRelease(t *testing.T) {
+	voucher := testVoucher()
+	voucher.UsageLimit = intPtr(2)
+	repo := newFakeVoucherRepository(voucher)
+	s := NewVoucherService(repo, nil)
+	buyer := entity.User{ID: 10}
+
+	redemption, err := s.Redeem(buyer, " hemat10 ", 7, 200000, "BTK-1")
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+	if redemption.Discount != 20000 || repo.vouchers["HEMAT10"].UsedCount != 1 {
+		t.Fatalf("redemption = %+v, used_count = %d", redemption, repo.vouchers["HEMAT10"].UsedCount)
+	}
+
+	// Reference yang sama tidak memakai kuota lagi
+	again, err := s.Redeem(buyer, "HEMAT10", 7, 200000, "BTK-1")
+	if err != nil || again.ID != redemption.ID || repo.vouchers["HEMAT10"].UsedCount != 1 {
+		t.Fatalf("Redeem ulang: %+v, %v, used_count = %d", again, err, repo.vouchers["HEMAT10"].UsedCount)
+	}
+
+	// Batas per user berlaku untuk transaksi lain
+	if _, err := s.Redeem(buyer, "HEMAT10", 7, 200000, "BTK-2"); !errors.Is(err, ErrVoucherUserLimit) {
+		t.Fatalf("transaksi kedua: err = %v, ingin ErrVoucherUserLimit", err)
+	}
+
+	// Buyer lain memakai sisa kuota, lalu kuota habis
+	if _, err := s.Redeem(entity.User{ID: 11}, "HEMAT10", 7, 200000, "BTK-3"); err != nil {
+		t.Fatalf("buyer kedua: %v", err)
+	}
+	if _, err := s.Redeem(entity.User{ID: 12}, "HEMAT10", 7, 200000, "BTK-4"); !errors.Is(err, ErrVoucherExhausted) {
+		t.Fatalf("buyer ketiga: err = %v, ingin ErrVoucherExhausted", err)
+	}
+
+	// Order batal: kuota dan batas per user kembali, reference lama tidak bisa dipakai lagi
+	if err := s.ReleaseByReference("BTK-1"); err != nil {
+		t.Fatalf("ReleaseByReference: %v", err)
+	}
+	if repo.vouchers["HEMAT10"].UsedCount != 1 {
+		t.Fatalf("used_count setelah dilepas = %d, ingin 1", repo.vouchers["HEMAT10"].UsedCount)
+	}
+	if _, err := s.Redeem(buyer, "HEMAT10", 7, 200000, "BTK-1"); !errors.Is(err, repository.ErrRedemptionReleased) {
+		t.Errorf("reference yang sudah dilepas: err = %v, ingin ErrRedemptionReleased", err)
+	}
+	if _, err := s.Redeem(buyer, "HEMAT10", 7, 200000, "BTK-5"); err != nil {
+		t.Errorf("Redeem setelah dilepas: %v", err)
+	}
+}
+
+func TestVoucherRedeemNotFound(t *testing.T) {
+	s := NewVoucherService(newFakeVoucherRepository(), nil)
+	if _, err := s.Redeem(entity.User{ID: 10}, "TIDAKADA", 7, 200000, "BTK-1"); !errors.Is(err, ErrVoucherNotFound) {
+		t.Errorf("err = %v, ingin ErrVoucherNotFound", err)
+	}
+}
+
+func TestValidateVoucherDoesNotUseQuota(t *testing.T) {
+	repo := newFakeVoucherRepository(testVoucher())
+	s := NewVoucherService(repo, nil)
+
+	quote, err := s.ValidateVoucher(entity.User{ID: 10}, dto.ValidateVoucherDTO{Code: "hemat10", StoreID: 7, Subtotal: 300000})
+	if err != nil {
+		t.Fatalf("ValidateVoucher: %v", err)
+	}
+	if quote.Discount != 30000 || quote.Total != 270000 {
+		t.Errorf("quote = %+v", quote)
+	}
+	if repo.vouchers["HEMAT10"].UsedCount != 0 || len(repo.redemptions) != 0 {
+		t.Error("ValidateVoucher tidak boleh memakai kuota")
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"batik/dto"
+	"batik/entity"
+	"batik/repository"
+	"batik/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxWishlistsPerUser membatasi jumlah wishlist per buyer
+const maxWishlistsPerUser = 20
+
+// ErrWishlistNotFound juga dipakai untuk wishlist milik user lain agar
+// keberadaannya tidak terbuka
+var ErrWishlistNotFound = errors.New("wishlist tidak ditemukan")
+
+type WishlistService interface {
+	GetMyWishlists(userID uint64) ([]entity.Wishlist, error)
+	CreateWishlist(userID uint64, wishlistDTO dto.CreateWishlistDTO) (entity.Wishlist, error)
+	UpdateWishlist(id, userID uint64, wishlistDTO dto.UpdateWishlistDTO) (entity.Wishlist, error)
+	DeleteWishlist(id, userID uint64) error
+	GetWishlistProducts(id, userID uint64, page, limit int) (entity.Wishlist, []dto.PublicProductCard, *utils.Pagination, error)
+	GetSharedWishlist(token string, page, limit int) (entity.Wishlist, []dto.PublicProductCard, *utils.Pagination, error)
+	AddItem(id, userID uint64, itemDTO dto.WishlistItemDTO) error
+	RemoveItem(id, userID uint64, productID int) error
+}
+
+type wishlistService struct {
+	wishlistRepo repository.WishlistRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewWishlistService(wishlistRepo repository.WishlistRepository, productRepo repository.ProductRepository) WishlistService {
+	return &wishlistService{
+		wishlistRepo: wishlistRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *wishlistService) GetMyWishlists(userID uint64) ([]entity.Wishlist, error) {
+	return s.wishlistRepo.GetByUser(userID)
+}
+
+func (s *wishlistService) CreateWishlist(userID uint64, wishlistDTO dto.CreateWishlistDTO) (entity.Wishlist, error) {
+	existing, err := s.wishlistRepo.GetByUser(userID)
+	if err != nil {
+		return entity.Wishlist{}, err
+	}
+	if len(existing) >= maxWishlistsPerUser {
+		return entity.Wishlist{}, fmt.Errorf("maksimal %d wishlist per akun", maxWishlistsPerUser)
+	}
+
+	now := time.Now()
+	wishlist, err := s.wishlistRepo.Create(entity.Wishlist{
+		UserID:     userID,
+		Name:       strings.TrimSpace(wishlistDTO.Name),
+		ShareToken: newShareToken(),
+		Shared:     wishlistDTO.Shared,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return entity.Wishlist{}, fmt.Errorf("gagal membuat wishlist: %v", err)
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) UpdateWishlist(id, userID uint64, wishlistDTO dto.UpdateWishlistDTO) (entity.Wishlist, error) {
+	wishlist, err := s.ownedWishlist(id, userID)
+	if err != nil {
+		return entity.Wishlist{}, err
+	}
+
+	if name := strings.TrimSpace(wishlistDTO.Name); name != "" {
+		wishlist.Name = name
+	}
+	if wishlistDTO.Shared != nil {
+		wishlist.Shared = *wishlistDTO.Shared
+	}
+	if wishlistDTO.RegenerateLink {
+		wishlist.ShareToken = newShareToken()
+	}
+	wishlist.UpdatedAt = time.Now()
+
+	if err := s.wishlistRepo.Update(wishlist); err != nil {
+		return entity.Wishlist{}, fmt.Errorf("gagal mengupdate wishlist: %v", err)
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) DeleteWishlist(id, userID uint64) error {
+	if _, err := s.ownedWishlist(id, userID); err != nil {
+		return err
+	}
+	return s.wishlistRepo.Delete(id)
+}
+
+func (s *wishlistService) GetWishlistProducts(id, userID uint64, page, limit int) (entity.Wishlist, []dto.PublicProductCard, *utils.Pagination, error) {
+	wishlist, err := s.ownedWishlist(id, userID)
+	if err != nil {
+		return entity.Wishlist{}, nil, nil, err
+	}
+	return s.wishlistProducts(wishlist, page, limit)
+}
+
+// GetSharedWishlist membuka wishlist lewat link. Wishlist yang tidak
+// dibagikan dianggap tidak ada.
+func (s *wishlistService) GetSharedWishlist(token string, page, limit int) (entity.Wishlist, []dto.PublicProductCard, *utils.Pagination, error) {
+	wishlist, err := s.wishlistRepo.FindByShareToken(token)
+	if err != nil || !wishlist.Shared {
+		return entity.Wishlist{}, nil, nil, ErrWishlistNotFound
+	}
+	return s.wishlistProducts(wishlist, page, limit)
+}
+
+func (s *wishlistService) AddItem(id, userID uint64, itemDTO dto.WishlistItemDTO) error {
+	if _, err := s.ownedWishlist(id, userID); err != nil {
+		return err
+	}
+
+	product, err := publishedProduct(s.productRepo, itemDTO.ProductSlug)
+	if err != nil {
+		return err
+	}
+
+	if err := s.wishlistRepo.AddItem(id, product.ID); err != nil {
+		return fmt.Errorf("gagal menambah produk ke wishlist: %v", err)
+	}
+	return nil
+}
+
+func (s *wishlistService) RemoveItem(id, userID uint64, productID int) error {
+	if _, err := s.ownedWishlist(id, userID); err != nil {
+		return err
+	}
+	return s.wishlistRepo.RemoveItem(id, productID)
+}
+
+func (s *wishlistService) ownedWishlist(id, userID uint64) (entity.Wishlist, error) {
+	wishlist, err := s.wishlistRepo.FindByID(id)
+	if err != nil || wishlist.UserID != userID {
+		return entity.Wishlist{}, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistService) wishlistProducts(wishlist entity.Wishlist, page, limit int) (entity.Wishlist, []dto.PublicProductCard, *utils.Pagination, error) {
+	page, limit = listPage(page, limit)
+	products, total, err := s.wishlistRepo.GetProducts(wishlist.ID, page, limit)
+	if err != nil {
+		return entity.Wishlist{}, nil, nil, err
+	}
+	return wishlist, toPublicProductCards(products), utils.NewPagination(page, limit, total), nil
+}
+
+// newShareToken membuat token acak untuk link wishlist
+func newShareToken() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
@@ -0,0 +1,96 @@
+package shipping
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Berat dan volume default untuk produk yang belum diisi penjual. Sehelai
+// kain batik terlipat kira-kira seberat ini.
+const (
+	DefaultWeightGrams = 500
+	// volumetricDivisor mengubah volume cm³ menjadi kg, sama seperti kurir darat
+	volumetricDivisor = 6000
+)
+
+var (
+	ErrUnknownProvince = errors.New("provinsi tidak dikenal")
+	ErrIncompleteArea  = errors.New("alamat pengiriman belum lengkap")
+	ErrNoService       = errors.New("layanan kurir tidak tersedia untuk rute ini")
+)
+
+// Address adalah titik asal atau tujuan pengiriman
+type Address struct {
+	Province   string `json:"province"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+}
+
+// Complete menandai alamat yang cukup untuk menghitung ongkir
+func (a Address) Complete() bool {
+	return strings.TrimSpace(a.Province) != "" && strings.TrimSpace(a.City) != ""
+}
+
+// Parcel adalah satu paket kiriman: total berat dan total volume barang
+type Parcel struct {
+	WeightGrams int
+	VolumeCm3   int
+}
+
+// ChargeableWeight adalah berat yang ditagih kurir dalam gram, yaitu yang
+// lebih besar antara berat asli dan berat volume
+func (p Parcel) ChargeableWeight() int {
+	volumetric := int(math.Ceil(float64(p.VolumeCm3) * 1000 / volumetricDivisor))
+	if volumetric > p.WeightGrams {
+		return volumetric
+	}
+	return p.WeightGrams
+}
+
+// ChargeableKg adalah berat tagih dibulatkan ke atas per kg, minimal 1 kg
+func (p Parcel) ChargeableKg() int {
+	kg := int(math.Ceil(float64(p.ChargeableWeight()) / 1000))
+	if kg < 1 {
+		return 1
+	}
+	return kg
+}
+
+// RateRequest adalah permintaan ongkir untuk satu paket dari satu toko
+type RateRequest struct {
+	Origin      Address
+	Destination Address
+	Parcel      Parcel
+}
+
+// Quote adalah satu pilihan layanan kurir beserta ongkos dan estimasinya
+type Quote struct {
+	Courier          string  `json:"courier"`
+	CourierName      string  `json:"courier_name"`
+	Service          string  `json:"service"`
+	Description      string  `json:"description"`
+	Cost             float64 `json:"cost"`
+	EtdMinDays       int     `json:"etd_min_days"`
+	EtdMaxDays       int     `json:"etd_max_days"`
+	ChargeableWeight int     `json:"chargeable_weight"` // gram
+}
+
+// ShippingRateProvider menghitung ongkir dari beberapa kurir. Implementasi
+// bisa berupa tabel lokal atau API agregator ongkir.
+type ShippingRateProvider interface {
+	// Name dipakai untuk mencatat asal perhitungan ongkir
+	Name() string
+	// Quote mengembalikan semua layanan yang tersedia, urut dari termurah
+	Quote(req RateRequest) ([]Quote, error)
+}
+
+// FindQuote mencari layanan tertentu dari hasil Quote
+func FindQuote(quotes []Quote, courier, service string) (Quote, bool) {
+	for _, q := range quotes {
+		if strings.EqualFold(q.Courier, courier) && strings.EqualFold(q.Service, service) {
+			return q, true
+		}
+	}
+	return Quote{}, false
+}
@@ -0,0 +1,79 @@
+package shipping
+
+import "testing"
+
+func TestParcelChargeableWeight(t *testing.T) {
+	cases := []struct {
+		name   string
+		parcel Parcel
+		want   int
+	}{
+		{"berat asli lebih besar", Parcel{WeightGrams: 1200, VolumeCm3: 30 * 20 * 2}, 1200},
+		// 40x30x20 cm = 24000 cm³ = 4 kg berat volume
+		{"berat volume lebih besar", Parcel{WeightGrams: 800, VolumeCm3: 40 * 30 * 20}, 4000},
+		{"berat volume dibulatkan ke atas", Parcel{WeightGrams: 0, VolumeCm3: 7}, 2},
+		{"kosong", Parcel{}, 0},
+	}
+	for _, tc := range cases {
+		if got := tc.parcel.ChargeableWeight(); got != tc.want {
+			t.Errorf("%s: ChargeableWeight = %d, ingin %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParcelChargeableKg(t *testing.T) {
+	cases := []struct {
+		parcel Parcel
+		want   int
+	}{
+		{Parcel{}, 1},
+		{Parcel{WeightGrams: 300}, 1},
+		{Parcel{WeightGrams: 1000}, 1},
+		{Parcel{WeightGrams: 1001}, 2},
+		{Parcel{WeightGrams: 2500}, 3},
+		{Parcel{WeightGrams: 500, VolumeCm3: 40 * 30 * 20}, 4},
+	}
+	for _, tc := range cases {
+		if got := tc.parcel.ChargeableKg(); got != tc.want {
+			t.Errorf("ChargeableKg(%+v) = %d, ingin %d", tc.parcel, got, tc.want)
+		}
+	}
+}
+
+func TestFindQuote(t *testing.T) {
+	quotes := []Quote{
+		{Courier: "jne", Service: "OKE", Cost: 7000},
+		{Courier: "jne", Service: "REG", Cost: 9000},
+		{Courier: "sicepat", Service: "REG", Cost: 8500},
+	}
+
+	q, ok := FindQuote(quotes, "JNE", "reg")
+	if !ok || q.Cost != 9000 {
+		t.Errorf("JNE REG = %+v, %v", q, ok)
+	}
+	q, ok = FindQuote(quotes, "sicepat", "REG")
+	if !ok || q.Cost != 8500 {
+		t.Errorf("SiCepat REG = %+v, %v", q, ok)
+	}
+	if _, ok := FindQuote(quotes, "jnt", "EZ"); ok {
+		t.Error("kurir yang tidak ada tidak boleh ditemukan")
+	}
+	if _, ok := FindQuote(quotes, "jne", "YES"); ok {
+		t.Error("layanan yang tidak ada tidak boleh ditemukan")
+	}
+	if _, ok := FindQuote(nil, "jne", "REG"); ok {
+		t.Error("daftar kosong tidak boleh menemukan layanan")
+	}
+}
+
+func TestAddressComplete(t *testing.T) {
+	if !(Address{Province: "Jawa Tengah", City: "Pekalongan"}).Complete() {
+		t.Error("alamat dengan provinsi dan kota harus lengkap")
+	}
+	if (Address{Province: "Jawa Tengah", City: "  "}).Complete() {
+		t.Error("kota berisi spasi tidak boleh dianggap lengkap")
+	}
+	if (Address{City: "Pekalongan"}).Complete() {
+		t.Error("alamat tanpa provinsi tidak boleh dianggap lengkap")
+	}
+}
@@ -0,0 +1,157 @@
+package shipping
+
+import "strings"
+
+// Wilayah pulau dipakai untuk menentukan jarak tarif antarprovinsi
+const (
+	regionSumatera    = "sumatera"
+	regionJawa        = "jawa"
+	regionBaliNusra   = "bali_nusra"
+	regionKalimantan  = "kalimantan"
+	regionSulawesi    = "sulawesi"
+	regionMalukuPapua = "maluku_papua"
+)
+
+// provinceRegions memetakan provinsi (huruf kecil) ke wilayahnya
+var provinceRegions = map[string]string{
+	"aceh":                      regionSumatera,
+	"sumatera utara":            regionSumatera,
+	"sumatera barat":            regionSumatera,
+	"riau":                      regionSumatera,
+	"kepulauan riau":            regionSumatera,
+	"jambi":                     regionSumatera,
+	"sumatera selatan":          regionSumatera,
+	"kepulauan bangka belitung": regionSumatera,
+	"bengkulu":                  regionSumatera,
+	"lampung":                   regionSumatera,
+	"banten":                    regionJawa,
+	"dki jakarta":               regionJawa,
+	"jawa barat":                regionJawa,
+	"jawa tengah":               regionJawa,
+	"di yogyakarta":             regionJawa,
+	"jawa timur":                regionJawa,
+	"bali":                      regionBaliNusra,
+	"nusa tenggara barat":       regionBaliNusra,
+	"nusa tenggara timur":       regionBaliNusra,
+	"kalimantan barat":          regionKalimantan,
+	"kalimantan tengah":         regionKalimantan,
+	"kalimantan selatan":        regionKalimantan,
+	"kalimantan timur":          regionKalimantan,
+	"kalimantan utara":          regionKalimantan,
+	"sulawesi utara":            regionSulawesi,
+	"gorontalo":                 regionSulawesi,
+	"sulawesi tengah":           regionSulawesi,
+	"sulawesi barat":            regionSulawesi,
+	"sulawesi selatan":          regionSulawesi,
+	"sulawesi tenggara":         regionSulawesi,
+	"maluku":                    regionMalukuPapua,
+	"maluku utara":              regionMalukuPapua,
+	"papua":                     regionMalukuPapua,
+	"papua barat":               regionMalukuPapua,
+	"papua barat daya":          regionMalukuPapua,
+	"papua tengah":              regionMalukuPapua,
+	"papua pegunungan":          regionMalukuPapua,
+	"papua selatan":             regionMalukuPapua,
+}
+
+// provinceAliases menerima penulisan provinsi yang umum dipakai
+var provinceAliases = map[string]string{
+	"jakarta":                    "dki jakarta",
+	"yogyakarta":                 "di yogyakarta",
+	"diy":                        "di yogyakarta",
+	"daerah istimewa yogyakarta": "di yogyakarta",
+	"nad":                        "aceh",
+	"nanggroe aceh darussalam":   "aceh",
+	"sumut":                      "sumatera utara",
+	"sumbar":                     "sumatera barat",
+	"sumsel":                     "sumatera selatan",
+	"kepri":                      "kepulauan riau",
+	"babel":                      "kepulauan bangka belitung",
+	"bangka belitung":            "kepulauan bangka belitung",
+	"jabar":                      "jawa barat",
+	"jateng":                     "jawa tengah",
+	"jatim":                      "jawa timur",
+	"ntb":                        "nusa tenggara barat",
+	"ntt":                        "nusa tenggara timur",
+}
+
+// nearRegions adalah pasangan wilayah yang terhubung rute reguler sehingga
+// tarifnya lebih murah dibanding wilayah jauh
+var nearRegions = map[[2]string]bool{
+	{regionJawa, regionSumatera}:        true,
+	{regionJawa, regionBaliNusra}:       true,
+	{regionJawa, regionKalimantan}:      true,
+	{regionSumatera, regionKalimantan}:  true,
+	{regionKalimantan, regionSulawesi}:  true,
+	{regionBaliNusra, regionSulawesi}:   true,
+	{regionSulawesi, regionMalukuPapua}: true,
+}
+
+// Tingkat jarak tarif, dari yang paling dekat
+const (
+	tierSameCity = iota
+	tierSameProvince
+	tierSameRegion
+	tierNearRegion
+	tierFarRegion
+	tierCount
+)
+
+// NormalizeProvince menyeragamkan nama provinsi, mis. "Prov. Jawa Tengah"
+// atau "JATENG" menjadi "jawa tengah". Mengembalikan false jika tidak dikenal.
+func NormalizeProvince(province string) (string, bool) {
+	name := normalizeName(province)
+	for _, prefix := range []string{"provinsi ", "prov. ", "prov "} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if alias, ok := provinceAliases[name]; ok {
+		name = alias
+	}
+	_, ok := provinceRegions[name]
+	return name, ok
+}
+
+// normalizeCity membuang awalan kota/kabupaten agar "Kota Pekalongan" dan
+// "Pekalongan" dianggap sama
+func normalizeCity(city string) string {
+	name := normalizeName(city)
+	for _, prefix := range []string{"kota ", "kabupaten ", "kab. ", "kab "} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// routeTier menentukan tingkat jarak antara asal dan tujuan
+func routeTier(origin, destination Address) (int, error) {
+	if !origin.Complete() || !destination.Complete() {
+		return 0, ErrIncompleteArea
+	}
+	from, ok := NormalizeProvince(origin.Province)
+	if !ok {
+		return 0, ErrUnknownProvince
+	}
+	to, ok := NormalizeProvince(destination.Province)
+	if !ok {
+		return 0, ErrUnknownProvince
+	}
+
+	switch {
+	case from == to && normalizeCity(origin.City) == normalizeCity(destination.City):
+		return tierSameCity, nil
+	case from == to:
+		return tierSameProvince, nil
+	}
+
+	fromRegion, toRegion := provinceRegions[from], provinceRegions[to]
+	switch {
+	case fromRegion == toRegion:
+		return tierSameRegion, nil
+	case nearRegions[[2]string{fromRegion, toRegion}], nearRegions[[2]string{toRegion, fromRegion}]:
+		return tierNearRegion, nil
+	}
+	return tierFarRegion, nil
+}
@@ -0,0 +1,76 @@
+package shipping
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeProvince(t *testing.T) {
+	cases := map[string]string{
+		"Jawa Tengah":                "jawa tengah",
+		"  JAWA   tengah ":           "jawa tengah",
+		"Prov. Jawa Tengah":          "jawa tengah",
+		"Provinsi Jawa Timur":        "jawa timur",
+		"JATENG":                     "jawa tengah",
+		"Jakarta":                    "dki jakarta",
+		"Daerah Istimewa Yogyakarta": "di yogyakarta",
+		"NTT":                        "nusa tenggara timur",
+	}
+	for input, want := range cases {
+		got, ok := NormalizeProvince(input)
+		if !ok || got != want {
+			t.Errorf("NormalizeProvince(%q) = %q, %v, ingin %q", input, got, ok, want)
+		}
+	}
+	for _, input := range []string{"", "Atlantis", "Jawa"} {
+		if _, ok := NormalizeProvince(input); ok {
+			t.Errorf("NormalizeProvince(%q) tidak boleh dikenal", input)
+		}
+	}
+}
+
+func TestRouteTier(t *testing.T) {
+	pekalongan := Address{Province: "Jawa Tengah", City: "Kota Pekalongan"}
+	cases := []struct {
+		name        string
+		destination Address
+		want        int
+	}{
+		{"satu kota", Address{Province: "jateng", City: "Pekalongan"}, tierSameCity},
+		{"kabupaten dengan nama sama", Address{Province: "Jawa Tengah", City: "Kabupaten Pekalongan"}, tierSameCity},
+		{"satu provinsi", Address{Province: "Jawa Tengah", City: "Solo"}, tierSameProvince},
+		{"satu wilayah", Address{Province: "Jawa Timur", City: "Surabaya"}, tierSameRegion},
+		{"wilayah dekat", Address{Province: "Lampung", City: "Bandar Lampung"}, tierNearRegion},
+		{"wilayah jauh", Address{Province: "Papua", City: "Jayapura"}, tierFarRegion},
+	}
+	for _, tc := range cases {
+		got, err := routeTier(pekalongan, tc.destination)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: tier = %d, err = %v, ingin %d", tc.name, got, err, tc.want)
+		}
+	}
+
+	// Wilayah dekat berlaku dua arah
+	if got, _ := routeTier(Address{Province: "Sulawesi Selatan", City: "Makassar"}, Address{Province: "Bali", City: "Denpasar"}); got != tierNearRegion {
+		t.Errorf("Sulawesi ke Bali: tier = %d, ingin %d", got, tierNearRegion)
+	}
+}
+
+func TestRouteTierErrors(t *testing.T) {
+	pekalongan := Address{Province: "Jawa Tengah", City: "Pekalongan"}
+	cases := []struct {
+		name                string
+		origin, destination Address
+		want                error
+	}{
+		{"asal tanpa kota", Address{Province: "Jawa Tengah"}, pekalongan, ErrIncompleteArea},
+		{"tujuan kosong", pekalongan, Address{}, ErrIncompleteArea},
+		{"provinsi asal tidak dikenal", Address{Province: "Atlantis", City: "X"}, pekalongan, ErrUnknownProvince},
+		{"provinsi tujuan tidak dikenal", pekalongan, Address{Province: "Atlantis", City: "X"}, ErrUnknownProvince},
+	}
+	for _, tc := range cases {
+		if _, err := routeTier(tc.origin, tc.destination); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, ingin %v", tc.name, err, tc.want)
+		}
+	}
+}
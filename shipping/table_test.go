@@ -0,0 +1,112 @@
+package shipping
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTableRateQuoteByTier(t *testing.T) {
+	provider := NewTableRateProvider(nil)
+	origin := Address{Province: "Jawa Tengah", City: "Pekalongan"}
+
+	cases := []struct {
+		destination Address
+		tier        int
+	}{
+		{Address{Province: "Jawa Tengah", City: "Pekalongan"}, tierSameCity},
+		{Address{Province: "Jawa Tengah", City: "Semarang"}, tierSameProvince},
+		{Address{Province: "DKI Jakarta", City: "Jakarta Selatan"}, tierSameRegion},
+		{Address{Province: "Bali", City: "Denpasar"}, tierNearRegion},
+		{Address{Province: "Maluku", City: "Ambon"}, tierFarRegion},
+	}
+	for _, tc := range cases {
+		quotes, err := provider.Quote(RateRequest{Origin: origin, Destination: tc.destination, Parcel: Parcel{WeightGrams: 500}})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.destination.Province, err)
+		}
+		q, ok := FindQuote(quotes, "jne", "REG")
+		if !ok {
+			t.Fatalf("%s: JNE REG tidak ada", tc.destination.Province)
+		}
+		if want := DefaultRates[1].PerKg[tc.tier]; q.Cost != want {
+			t.Errorf("%s: ongkir = %.0f, ingin %.0f", tc.destination.Province, q.Cost, want)
+		}
+		if q.EtdMinDays != DefaultRates[1].EtdDays[tc.tier][0] || q.EtdMaxDays != DefaultRates[1].EtdDays[tc.tier][1] {
+			t.Errorf("%s: estimasi = %d-%d hari", tc.destination.Province, q.EtdMinDays, q.EtdMaxDays)
+		}
+	}
+}
+
+func TestTableRateQuoteUsesChargeableKg(t *testing.T) {
+	provider := NewTableRateProvider([]ServiceRate{{
+		Courier: "uji", Service: "REG",
+		PerKg: [tierCount]float64{10000, 10000, 10000, 10000, 10000},
+	}})
+	req := RateRequest{
+		Origin:      Address{Province: "Jawa Tengah", City: "Pekalongan"},
+		Destination: Address{Province: "Jawa Tengah", City: "Pekalongan"},
+	}
+
+	cases := []struct {
+		name   string
+		parcel Parcel
+		cost   float64
+		weight int
+	}{
+		{"di bawah 1 kg tetap 1 kg", Parcel{WeightGrams: 200}, 10000, 200},
+		{"dibulatkan ke atas", Parcel{WeightGrams: 1500}, 20000, 1500},
+		{"berat volume", Parcel{WeightGrams: 500, VolumeCm3: 40 * 30 * 20}, 40000, 4000},
+	}
+	for _, tc := range cases {
+		req.Parcel = tc.parcel
+		quotes, err := provider.Quote(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if quotes[0].Cost != tc.cost || quotes[0].ChargeableWeight != tc.weight {
+			t.Errorf("%s: ongkir = %.0f, berat = %d, ingin %.0f dan %d", tc.name, quotes[0].Cost, quotes[0].ChargeableWeight, tc.cost, tc.weight)
+		}
+	}
+}
+
+func TestTableRateQuoteSortedByCost(t *testing.T) {
+	quotes, err := NewTableRateProvider(nil).Quote(RateRequest{
+		Origin:      Address{Province: "Jawa Tengah", City: "Pekalongan"},
+		Destination: Address{Province: "Sumatera Utara", City: "Medan"},
+		Parcel:      Parcel{WeightGrams: 1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotes) != len(DefaultRates) {
+		t.Errorf("jumlah layanan = %d, ingin %d", len(quotes), len(DefaultRates))
+	}
+	for i := 1; i < len(quotes); i++ {
+		if quotes[i].Cost < quotes[i-1].Cost {
+			t.Errorf("layanan tidak urut dari termurah: %v", quotes)
+			break
+		}
+	}
+}
+
+func TestTableRateQuoteErrors(t *testing.T) {
+	jateng := Address{Province: "Jawa Tengah", City: "Pekalongan"}
+	provider := NewTableRateProvider(nil)
+
+	if _, err := provider.Quote(RateRequest{Origin: jateng, Destination: Address{Province: "Atlantis", City: "X"}}); !errors.Is(err, ErrUnknownProvince) {
+		t.Errorf("provinsi tidak dikenal: err = %v", err)
+	}
+	if _, err := provider.Quote(RateRequest{Origin: Address{}, Destination: jateng}); !errors.Is(err, ErrIncompleteArea) {
+		t.Errorf("asal kosong: err = %v", err)
+	}
+
+	// Layanan tanpa tarif untuk tingkat jarak ini dilewati
+	sameCityOnly := NewTableRateProvider([]ServiceRate{{
+		Courier: "lokal", Service: "KILAT",
+		PerKg: [tierCount]float64{5000},
+	}})
+	_, err := sameCityOnly.Quote(RateRequest{Origin: jateng, Destination: Address{Province: "Papua", City: "Jayapura"}})
+	if !errors.Is(err, ErrNoService) {
+		t.Errorf("tanpa layanan: err = %v, ingin ErrNoService", err)
+	}
+}
@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor dikembalikan jika cursor rusak, dimodifikasi, atau tidak cocok
+// dengan urutan listing yang diminta
+var ErrInvalidCursor = errors.New("cursor tidak valid")
+
+// ErrCursorSecretMissing dikembalikan CheckCursorSecret jika CURSOR_SECRET dan
+// JWT_SECRET sama-sama kosong
+var ErrCursorSecretMissing = errors.New("CURSOR_SECRET atau JWT_SECRET wajib diisi untuk menandatangani cursor")
+
+// MaxCursorLimit membatasi jumlah item per halaman pada mode cursor
+const MaxCursorLimit = 100
+
+// Cursor menyimpan nilai kolom urutan dari item terakhir (atau pertama jika
+// Backward) yang sudah dilihat client. Scope adalah hash listing (urutan, seed,
+// dan filter) tempat cursor dibuat, lihat CursorScope.
+type Cursor struct {
+	Values   []interface{}
+	Backward bool
+	Scope    string
+}
+
+// CursorScope membuat hash pendek dari parameter listing. Cursor hanya berlaku
+// untuk scope yang sama, sehingga cursor dari urutan, seed, atau filter lain
+// ditolak alih-alih menghasilkan halaman yang melompat atau tumpang tindih.
+func CursorScope(parts ...interface{}) string {
+	raw, _ := json.Marshal(parts)
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// CursorPagination adalah metadata paginasi mode cursor (keyset)
+type CursorPagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+}
+
+// KeysetColumn adalah satu kolom urutan. Expr dipakai di WHERE; Alias (jika ada)
+// dipakai di ORDER BY untuk ekspresi yang sudah dipilih dengan AS.
+type KeysetColumn struct {
+	Expr  string
+	Vars  []interface{}
+	Alias string
+	Desc  bool
+}
+
+// Keyset adalah daftar kolom urutan. Kolom terakhir harus unik (biasanya ID)
+// agar urutan total dan halaman tidak tumpang tindih.
+type Keyset []KeysetColumn
+
+// Order menambahkan ORDER BY sesuai keyset, dipakai juga oleh mode page/offset
+func (k Keyset) Order(query *gorm.DB) *gorm.DB {
+	return k.order(query, false)
+}
+
+func (k Keyset) order(query *gorm.DB, reverse bool) *gorm.DB {
+	for _, col := range k {
+		name := col.Alias
+		if name == "" {
+			name = col.Expr
+		}
+		if col.Desc != reverse {
+			query = query.Order(name + " DESC")
+		} else {
+			query = query.Order(name + " ASC")
+		}
+	}
+	return query
+}
+
+// Apply menambahkan kondisi keyset, urutan, dan LIMIT limit+1 (satu baris ekstra
+// untuk mengetahui apakah masih ada halaman berikutnya). Cursor dari scope lain
+// ditolak dengan ErrInvalidCursor.
+func (k Keyset) Apply(query *gorm.DB, cursor *Cursor, scope string, limit int) (*gorm.DB, error) {
+	backward := false
+	if cursor != nil {
+		if len(cursor.Values) != len(k) || cursor.Scope != scope {
+			return nil, ErrInvalidCursor
+		}
+		backward = cursor.Backward
+
+		// (a > x) OR (a = x AND b > y) OR (a = x AND b = y AND c > z) ...
+		var (
+			conditions []string
+			vars       []interface{}
+		)
+		for i, col := range k {
+			var parts []string
+			for j := 0; j < i; j++ {
+				parts = append(parts, k[j].Expr+" = ?")
+				vars = append(vars, k[j].Vars...)
+				vars = append(vars, cursor.Values[j])
+			}
+
+			op := ">"
+			if col.Desc != backward {
+				op = "<"
+			}
+			parts = append(parts, col.Expr+" "+op+" ?")
+			vars = append(vars, col.Vars...)
+			vars = append(vars, cursor.Values[i])
+
+			conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+		}
+		query = query.Where(strings.Join(conditions, " OR "), vars...)
+	}
+
+	return k.order(query, backward).Limit(limit + 1), nil
+}
+
+// NewCursorPagination memotong baris ekstra dari hasil Keyset.Apply, membalik
+// urutan untuk halaman mundur, dan membuat next/prev cursor dari item pertama
+// dan terakhir. scope harus sama dengan yang dipakai di Keyset.Apply.
+func NewCursorPagination[T any](items []T, limit int, cursor *Cursor, scope string, keyOf func(T) []interface{}) ([]T, *CursorPagination) {
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	pagination := &CursorPagination{Limit: limit}
+	if backward {
+		pagination.HasPrev = hasMore
+		pagination.HasNext = true
+	} else {
+		pagination.HasNext = hasMore
+		pagination.HasPrev = cursor != nil
+	}
+
+	if len(items) > 0 {
+		if pagination.HasNext {
+			pagination.NextCursor = EncodeCursor(Cursor{Values: keyOf(items[len(items)-1]), Scope: scope})
+		}
+		if pagination.HasPrev {
+			pagination.PrevCursor = EncodeCursor(Cursor{Values: keyOf(items[0]), Backward: true, Scope: scope})
+		}
+	}
+
+	return items, pagination
+}
+
+// cursorPayload adalah bentuk cursor di dalam token. Setiap nilai disimpan
+// bersama tipenya agar waktu dan angka kembali ke tipe aslinya.
+type cursorPayload struct {
+	Values   [][2]string `json:"v"`
+	Backward bool        `json:"b,omitempty"`
+	Scope    string      `json:"s,omitempty"`
+}
+
+// EncodeCursor membuat token opaque: base64url(payload) + "." + base64url(HMAC-SHA256)
+func EncodeCursor(cursor Cursor) string {
+	payload := cursorPayload{Backward: cursor.Backward, Scope: cursor.Scope}
+	for _, v := range cursor.Values {
+		payload.Values = append(payload.Values, encodeCursorValue(v))
+	}
+
+	raw, _ := json.Marshal(payload)
+	body := base64.RawURLEncoding.EncodeToString(raw)
+	return body + "." + base64.RawURLEncoding.EncodeToString(signCursor(body))
+}
+
+// DecodeCursor memverifikasi tanda tangan dan membaca kembali nilai cursor
+func DecodeCursor(token string) (*Cursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, signCursor(parts[0])) {
+		return nil, ErrInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{Backward: payload.Backward, Scope: payload.Scope}
+	for _, v := range payload.Values {
+		value, err := decodeCursorValue(v)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	return cursor, nil
+}
+
+func encodeCursorValue(v interface{}) [2]string {
+	switch val := v.(type) {
+	case time.Time:
+		return [2]string{"t", val.Format(time.RFC3339Nano)}
+	case bool:
+		return [2]string{"b", strconv.FormatBool(val)}
+	case int:
+		return [2]string{"i", strconv.FormatInt(int64(val), 10)}
+	case int64:
+		return [2]string{"i", strconv.FormatInt(val, 10)}
+	case uint64:
+		return [2]string{"i", strconv.FormatUint(val, 10)}
+	case float64:
+		return [2]string{"n", strconv.FormatFloat(val, 'g', -1, 64)}
+	default:
+		return [2]string{"s", fmt.Sprint(val)}
+	}
+}
+
+func decodeCursorValue(v [2]string) (interface{}, error) {
+	switch v[0] {
+	case "t":
+		return time.Parse(time.RFC3339Nano, v[1])
+	case "b":
+		return strconv.ParseBool(v[1])
+	case "i":
+		return strconv.ParseInt(v[1], 10, 64)
+	case "n":
+		return strconv.ParseFloat(v[1], 64)
+	case "s":
+		return v[1], nil
+	}
+	return nil, ErrInvalidCursor
+}
+
+func signCursor(body string) []byte {
+	mac := hmac.New(sha256.New, cursorSecret())
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+// CheckCursorSecret memastikan secret cursor sudah dikonfigurasi. Dipanggil saat
+// start agar server tidak berjalan dengan cursor yang bisa dipalsukan.
+func CheckCursorSecret() error {
+	if len(cursorSecret()) == 0 {
+		return ErrCursorSecretMissing
+	}
+	return nil
+}
+
+// cursorSecret membaca CURSOR_SECRET, lalu JWT_SECRET sebagai cadangan
+func cursorSecret() []byte {
+	if secret := os.Getenv("CURSOR_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	return []byte(os.Getenv("JWT_SECRET"))
+}
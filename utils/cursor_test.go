@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", "test-secret")
+
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 123, time.UTC)
+	token := EncodeCursor(Cursor{
+		Values:   []interface{}{true, createdAt, int64(42), 12.5, "abc"},
+		Backward: true,
+		Scope:    CursorScope("products", "newest"),
+	})
+
+	cursor, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !cursor.Backward {
+		t.Error("Backward hilang setelah decode")
+	}
+	if cursor.Scope != CursorScope("products", "newest") {
+		t.Errorf("Scope = %q", cursor.Scope)
+	}
+	if len(cursor.Values) != 5 {
+		t.Fatalf("jumlah nilai = %d, ingin 5", len(cursor.Values))
+	}
+	if v, ok := cursor.Values[0].(bool); !ok || !v {
+		t.Errorf("nilai bool = %#v", cursor.Values[0])
+	}
+	if v, ok := cursor.Values[1].(time.Time); !ok || !v.Equal(createdAt) {
+		t.Errorf("nilai waktu = %#v", cursor.Values[1])
+	}
+	if v, ok := cursor.Values[2].(int64); !ok || v != 42 {
+		t.Errorf("nilai int = %#v", cursor.Values[2])
+	}
+	if v, ok := cursor.Values[3].(float64); !ok || v != 12.5 {
+		t.Errorf("nilai float = %#v", cursor.Values[3])
+	}
+	if v, ok := cursor.Values[4].(string); !ok || v != "abc" {
+		t.Errorf("nilai string = %#v", cursor.Values[4])
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", "test-secret")
+
+	token := EncodeCursor(Cursor{Values: []interface{}{int64(1)}})
+	body, sig, _ := strings.Cut(token, ".")
+	forged := EncodeCursor(Cursor{Values: []interface{}{int64(999)}})
+	forgedBody, _, _ := strings.Cut(forged, ".")
+
+	cases := map[string]string{
+		"tanpa tanda tangan":     body,
+		"body ditukar":           forgedBody + "." + sig,
+		"tanda tangan bukan b64": body + ".!!!",
+		"bagian berlebih":        token + ".x",
+		"kosong":                 "",
+	}
+	for name, tc := range cases {
+		if _, err := DecodeCursor(tc); err != ErrInvalidCursor {
+			t.Errorf("%s: err = %v, ingin ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsOtherSecret(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", "secret-lama")
+	token := EncodeCursor(Cursor{Values: []interface{}{int64(1)}})
+
+	t.Setenv("CURSOR_SECRET", "secret-baru")
+	if _, err := DecodeCursor(token); err != ErrInvalidCursor {
+		t.Fatalf("err = %v, ingin ErrInvalidCursor", err)
+	}
+}
+
+func TestKeysetApplyRejectsOtherScope(t *testing.T) {
+	keyset := Keyset{{Expr: "created_at", Desc: true}, {Expr: "id", Desc: true}}
+	cursor := &Cursor{
+		Values: []interface{}{time.Now(), int64(1)},
+		Scope:  CursorScope("products", "newest", "seed-a"),
+	}
+
+	if _, err := keyset.Apply(nil, cursor, CursorScope("products", "newest", "seed-b"), 10); err != ErrInvalidCursor {
+		t.Errorf("seed berbeda: err = %v, ingin ErrInvalidCursor", err)
+	}
+	if _, err := keyset.Apply(nil, cursor, CursorScope("products", "price_asc", "seed-a"), 10); err != ErrInvalidCursor {
+		t.Errorf("urutan berbeda: err = %v, ingin ErrInvalidCursor", err)
+	}
+
+	short := &Cursor{Values: []interface{}{int64(1)}, Scope: cursor.Scope}
+	if _, err := keyset.Apply(nil, short, cursor.Scope, 10); err != ErrInvalidCursor {
+		t.Errorf("jumlah nilai salah: err = %v, ingin ErrInvalidCursor", err)
+	}
+}
+
+func TestCursorScope(t *testing.T) {
+	type filter struct {
+		Sort       string
+		Seed       string
+		Attributes map[string][]string
+	}
+	a := filter{Sort: "", Seed: "20240301", Attributes: map[string][]string{"motif": {"parang"}, "teknik": {"tulis"}}}
+	b := filter{Sort: "", Seed: "20240301", Attributes: map[string][]string{"teknik": {"tulis"}, "motif": {"parang"}}}
+	if CursorScope("products", a) != CursorScope("products", b) {
+		t.Error("filter yang sama harus menghasilkan scope yang sama")
+	}
+
+	b.Attributes["motif"] = []string{"kawung"}
+	if CursorScope("products", a) == CursorScope("products", b) {
+		t.Error("filter berbeda harus menghasilkan scope berbeda")
+	}
+	if CursorScope("stores", "") == CursorScope("users", "") {
+		t.Error("listing berbeda harus menghasilkan scope berbeda")
+	}
+}
+
+func TestNewCursorPagination(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", "test-secret")
+	keyOf := func(v int) []interface{} { return []interface{}{int64(v)} }
+
+	items, page := NewCursorPagination([]int{5, 4, 3}, 2, nil, "scope", keyOf)
+	if len(items) != 2 || !page.HasNext || page.HasPrev {
+		t.Fatalf("halaman pertama: items=%v page=%+v", items, page)
+	}
+	next, err := DecodeCursor(page.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor next: %v", err)
+	}
+	if next.Scope != "scope" || next.Values[0] != int64(4) || next.Backward {
+		t.Errorf("next cursor = %+v", next)
+	}
+
+	// Halaman mundur: hasil query terbalik dikembalikan ke urutan semula
+	items, page = NewCursorPagination([]int{1, 2}, 2, &Cursor{Backward: true, Scope: "scope"}, "scope", keyOf)
+	if items[0] != 2 || items[1] != 1 || page.HasPrev || !page.HasNext {
+		t.Errorf("halaman mundur: items=%v page=%+v", items, page)
+	}
+}
+
+func TestCheckCursorSecret(t *testing.T) {
+	t.Setenv("CURSOR_SECRET", "")
+	t.Setenv("JWT_SECRET", "")
+	if err := CheckCursorSecret(); err != ErrCursorSecretMissing {
+		t.Errorf("tanpa secret: err = %v, ingin ErrCursorSecretMissing", err)
+	}
+
+	t.Setenv("JWT_SECRET", "jwt")
+	if err := CheckCursorSecret(); err != nil {
+		t.Errorf("dengan JWT_SECRET: err = %v", err)
+	}
+}
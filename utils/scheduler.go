@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"log"
+	"time"
+)
+
+// RunEvery menjalankan job secara berkala di goroutine terpisah.
+// Panic di dalam job dicatat ke log agar scheduler tetap berjalan.
+func RunEvery(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runJob(name, job)
+		}
+	}()
+}
+
+func runJob(name string, job func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Job %s panic: %v", name, r)
+		}
+	}()
+
+	if err := job(); err != nil {
+		log.Printf("❌ Job %s gagal: %v", name, err)
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// shuffleSeedLayout adalah format seed harian urutan acak
+const shuffleSeedLayout = "20060102"
+
+// ShuffleSeeds adalah seed yang kuncinya disimpan di database: seed hari ini
+// dan kemarin, agar client yang sedang membuka halaman saat pergantian hari
+// tetap mendapat urutan yang sama.
+func ShuffleSeeds(now time.Time) []string {
+	return []string{
+		now.Format(shuffleSeedLayout),
+		now.AddDate(0, 0, -1).Format(shuffleSeedLayout),
+	}
+}
+
+// ShuffleSeed mengembalikan seed untuk urutan acak yang stabil. Seed dari client
+// hanya dipakai jika masih termasuk ShuffleSeeds agar paginasi konsisten;
+// selain itu dipakai seed hari ini sehingga urutan katalog berganti setiap hari.
+func ShuffleSeed(raw string) string {
+	seeds := ShuffleSeeds(time.Now())
+	seed := strings.TrimSpace(raw)
+	for _, s := range seeds {
+		if seed == s {
+			return seed
+		}
+	}
+	return seeds[0]
+}
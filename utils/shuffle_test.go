@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShuffleSeeds(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 5, 0, 0, time.Local)
+	seeds := ShuffleSeeds(now)
+	if len(seeds) != 2 || seeds[0] != "20240301" || seeds[1] != "20240229" {
+		t.Errorf("ShuffleSeeds = %v", seeds)
+	}
+}
+
+func TestShuffleSeed(t *testing.T) {
+	seeds := ShuffleSeeds(time.Now())
+
+	for _, seed := range seeds {
+		if got := ShuffleSeed(" " + seed + " "); got != seed {
+			t.Errorf("ShuffleSeed(%q) = %q, ingin seed dipakai apa adanya", seed, got)
+		}
+	}
+	// Seed yang kuncinya tidak disimpan diganti seed hari ini
+	for _, raw := range []string{"", "abc", "20000101"} {
+		if got := ShuffleSeed(raw); got != seeds[0] {
+			t.Errorf("ShuffleSeed(%q) = %q, ingin %q", raw, got, seeds[0])
+		}
+	}
+}